@@ -0,0 +1,107 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"lp_tracker/version"
+)
+
+// Server is the operators HTTP server, it must not be exposed publicly
+type Server struct {
+	server *http.Server
+}
+
+// RuntimeStats is the snapshot of the Go runtime served on /debug/runtime
+type RuntimeStats struct {
+	Goroutines   int       `json:"goroutines"`
+	HeapAlloc    uint64    `json:"heapAllocBytes"`
+	HeapInuse    uint64    `json:"heapInuseBytes"`
+	HeapObjects  uint64    `json:"heapObjects"`
+	Sys          uint64    `json:"sysBytes"`
+	NumGC        uint32    `json:"numGc"`
+	GCPauseTotal string    `json:"gcPauseTotal"`
+	LastGC       time.Time `json:"lastGc"`
+}
+
+// NewServer creates the admin server listening on addr (ex: ":6060").
+// The pprof endpoints are only registered when enablePprof is set.
+func NewServer(addr string, enablePprof bool) *Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/runtime", handleRuntimeStats)
+	mux.HandleFunc("/version", handleVersion)
+
+	if enablePprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+
+	return &Server{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// Start serves the admin endpoints in the background
+func (s *Server) Start() {
+	go func() {
+		log.Printf("🩺 Admin server listening on %s", s.server.Addr)
+		err := s.server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Admin server stopped: %v", err)
+		}
+	}()
+}
+
+// Shutdown stops the admin server
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// ReadRuntimeStats reads the goroutine, heap and GC statistics of the process
+func ReadRuntimeStats() RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := RuntimeStats{
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    mem.HeapAlloc,
+		HeapInuse:    mem.HeapInuse,
+		HeapObjects:  mem.HeapObjects,
+		Sys:          mem.Sys,
+		NumGC:        mem.NumGC,
+		GCPauseTotal: time.Duration(mem.PauseTotalNs).String(),
+	}
+	if mem.LastGC > 0 {
+		stats.LastGC = time.Unix(0, int64(mem.LastGC))
+	}
+	return stats
+}
+
+func handleRuntimeStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(ReadRuntimeStats())
+	if err != nil {
+		log.Printf("Error encoding runtime stats: %v", err)
+	}
+}
+
+func handleVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(version.Get())
+	if err != nil {
+		log.Printf("Error encoding version: %v", err)
+	}
+}
@@ -2,16 +2,27 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Embed time zones to validate /set_timezone (missing in alpine images)
 
+	"lp_tracker/admin"
+	"lp_tracker/config"
 	"lp_tracker/container"
 	"lp_tracker/database"
 	"lp_tracker/discord"
+	"lp_tracker/selftest"
+	"lp_tracker/services"
+	"lp_tracker/telemetry"
+	"lp_tracker/version"
+	"lp_tracker/web"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/joho/godotenv"
@@ -22,6 +33,16 @@ const (
 )
 
 func main() {
+	selfTest := flag.Bool("selftest", false, "Check the configuration, MongoDB, the Riot API key and the Discord token, then exit")
+	printVersion := flag.Bool("version", false, "Print the version, commit and build date, then exit")
+	flag.Parse()
+
+	if *printVersion {
+		fmt.Println(version.Get())
+		return
+	}
+	log.Printf("🏷️ lp_tracker commands listener %s", version.Get())
+
 	if os.Getenv("DOCKER_ENV") != "true" {
 		err := godotenv.Load()
 		if err != nil {
@@ -29,26 +50,75 @@ func main() {
 		}
 	}
 
-	// Validate required environment variables
-	requiredEnvs := map[string]string{
-		"DISCORD_TOKEN":  os.Getenv("DISCORD_TOKEN"),
-		"RIOT_API_KEY":   os.Getenv("RIOT_API_KEY"),
-		"MONGO_URI":      os.Getenv("MONGO_LOCAL_URI"),
-		"MONGO_DATABASE": os.Getenv("MONGO_DATABASE"),
+	// Secrets mounted as files (DISCORD_TOKEN_FILE, RIOT_API_KEY_FILE...)
+	err := config.LoadSecretFiles()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Optional Vault or AWS Secrets Manager secret, its fields win over the environment
+	secretManager, err := loadSecretManager()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *selfTest {
+		os.Exit(selftest.Main(selftest.ConfigFromEnv()))
+	}
+
+	// Validate required environment variables, only their names are logged (they hold secrets)
+	var missingEnvs []string
+	for _, key := range []string{"DISCORD_TOKEN", "RIOT_API_KEY"} {
+		if os.Getenv(key) == "" {
+			missingEnvs = append(missingEnvs, key)
+		}
+	}
+	if len(missingEnvs) > 0 {
+		log.Fatalf("Missing required environment variables: %s", strings.Join(missingEnvs, ", "))
+	}
+
+	// MongoDB connection, from MONGO_URI, MONGO_LOCAL_URI or the MONGO_HOST parts
+	mongoConfig, err := config.MongoConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
 	}
-	fmt.Println(requiredEnvs)
+	log.Printf("Using MongoDB %s (database %s)", mongoConfig.Redacted(), mongoConfig.Database)
+
+	// Tracing, exported when OTEL_EXPORTER_OTLP_ENDPOINT is set
+	shutdownTracing, err := telemetry.Setup(context.Background(), "lp_tracker-commands_listener")
+	if err != nil {
+		log.Fatal("Failed to initialize tracing:", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		shutdownTracing(ctx)
+	}()
 
-	for key, value := range requiredEnvs {
-		if value == "" {
-			log.Fatalf("%s environment variable is required", key)
+	// Optional admin server for operators (runtime metrics, pprof)
+	if addr := os.Getenv("ADMIN_ADDR"); addr != "" {
+		enablePprof, err := config.EnvBool("ADMIN_PPROF", false)
+		if err != nil {
+			log.Fatal(err)
 		}
+		adminServer := admin.NewServer(addr, enablePprof)
+		adminServer.Start()
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			adminServer.Shutdown(ctx)
+		}()
 	}
 
 	// Database configuration
 	dbConfig := database.Config{
-		URI:          os.Getenv("MONGO_URI"),
-		DatabaseName: os.Getenv("MONGO_DATABASE"),
+		URI:          mongoConfig.URI,
+		DatabaseName: mongoConfig.Database,
 		Timeout:      30 * time.Second,
+
+		AnalyticsReadPreference: mongoConfig.AnalyticsReadPreference,
+		SlowQueryThreshold:      mongoConfig.SlowQueryThreshold,
+		IndexStatsInterval:      mongoConfig.IndexStatsInterval,
 	}
 
 	// Initialize database manager
@@ -73,7 +143,35 @@ func main() {
 	log.Println("Database health check passed!")
 
 	// Initialize service container
-	serviceContainer := container.NewContainer(dbManager, os.Getenv("RIOT_API_KEY"))
+	riotClient, err := services.RiotClientConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	serviceContainer := container.NewContainer(dbManager, os.Getenv("RIOT_API_KEY"), riotClient)
+	riotDebug, err := config.EnvBool("RIOT_DEBUG", false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if riotDebug {
+		serviceContainer.GetRiotService().SetDebug(true)
+	}
+
+	// Optional tenants of a shared instance, each with its own database, Riot API key and quota
+	tenants, err := config.TenantsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	tenantContainers := make([]*container.Container, 0, len(tenants))
+	for _, tenant := range tenants {
+		tenantCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		tenantContainer, err := container.NewTenantContainer(tenantCtx, serviceContainer, tenant, riotClient)
+		cancel()
+		if err != nil {
+			log.Fatal(err)
+		}
+		tenantContainers = append(tenantContainers, tenantContainer)
+		log.Printf("🏢 Tenant %s: %d guilds, database %s", tenant.ID, len(tenant.Guilds), tenant.DatabaseName(mongoConfig.Database))
+	}
 
 	// Create Discord session
 	dg, err := discordgo.New("Bot " + os.Getenv("DISCORD_TOKEN"))
@@ -81,40 +179,167 @@ func main() {
 		log.Fatal("Error creating Discord session:", err)
 	}
 
-	// Initialize command handler with service container
-	commandHandler := discord.NewCommandHandler(serviceContainer)
+	// Optional per-command timeouts, ex: COMMAND_TIMEOUTS=add_player=45s,default=15s
+	var commandTimeouts map[string]time.Duration
+	if spec := os.Getenv("COMMAND_TIMEOUTS"); spec != "" {
+		commandTimeouts, err = discord.ParseCommandTimeouts(spec)
+		if err != nil {
+			log.Fatal("Invalid COMMAND_TIMEOUTS:", err)
+		}
+	}
+
+	// Rank changes detected by /refresh, /admin poll_now and the API refreshes are announced by this process,
+	// the poller doesn't see them again. The dedupe store prevents a second announcement of the same game.
+	dispatcher := discord.NewDispatcher(dg, discord.DefaultDigestWindow, discord.DefaultDigestThreshold)
+	newNotifier := func(c *container.Container) *discord.Notifier {
+		return discord.NewNotifier(dispatcher, c.GetGuildConfigService(), c.GetDataDragonService(),
+			c.GetNotificationDedupeStore(), c.GetEnemyRankService(), c.GetFeatureFlagService())
+	}
+
+	// Every dataset has its command handler, the tenant handlers only differ by their container
+	newCommandHandler := func(c *container.Container) *discord.CommandHandler {
+		handler := discord.NewCommandHandler(c)
+		handler.SetNotifier(newNotifier(c))
+		if commandTimeouts != nil {
+			handler.SetCommandTimeouts(commandTimeouts)
+		}
+		// Owner of the bot, the only user allowed to use /admin
+		handler.SetOwnerID(os.Getenv("BOT_OWNER_ID"))
+		return handler
+	}
+	commandHandler := newCommandHandler(serviceContainer)
+	tenantHandlers := make([]*discord.CommandHandler, 0, len(tenants))
+	for _, tenantContainer := range tenantContainers {
+		tenantHandlers = append(tenantHandlers, newCommandHandler(tenantContainer))
+	}
+
+	// Optional public web dashboard serving the opt-in profile pages and the Clash calendar, of the default dataset only
+	commandHandler.SetPublicURL(os.Getenv("PUBLIC_URL"))
+	if addr := os.Getenv("WEB_ADDR"); addr != "" {
+		webServer := web.NewServer(addr, serviceContainer.GetProfileService(), serviceContainer.GetClashService(), os.Getenv("CLASH_FEED_TOKEN"),
+			serviceContainer.GetPlayerService(), serviceContainer.GetStandingsService(), serviceContainer.GetAPIKeyService(),
+			serviceContainer.GetJobService())
+		// Origins allowed to call the API from a browser, ex: API_CORS_ORIGINS=https://app.example.com,http://localhost:3000
+		if spec := os.Getenv("API_CORS_ORIGINS"); spec != "" {
+			origins, err := web.ParseCORSOrigins(spec)
+			if err != nil {
+				log.Fatal("Invalid API_CORS_ORIGINS:", err)
+			}
+			webServer.SetCORSOrigins(origins)
+		}
+		webServer.SetNotifier(newNotifier(serviceContainer))
+		webServer.Start()
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			webServer.Shutdown(ctx)
+		}()
+	}
+
+	// Jobs are run by this process, the ones left unfinished by a previous run cannot be resumed
+	for _, c := range append([]*container.Container{serviceContainer}, tenantContainers...) {
+		// Own timeout, the one of the health check is used up by the setup
+		jobsCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		failed, err := c.GetJobService().FailInterruptedJobs(jobsCtx)
+		cancel()
+		if err != nil {
+			log.Printf("Error failing interrupted jobs: %v", err)
+		} else if failed > 0 {
+			log.Printf("Marked %d interrupted jobs as failed", failed)
+		}
+	}
+
+	// Results of long jobs are posted as channel messages, interaction tokens expire after 15 minutes
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	// Background goroutines of the process, joined on shutdown
+	var background sync.WaitGroup
+
+	jobResults := discord.NewJobResultDispatcher(dg)
+	commandHandler.SetJobResultDispatcher(jobResults)
+	for _, handler := range tenantHandlers {
+		handler.SetJobResultDispatcher(jobResults)
+	}
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		jobResults.Run(jobCtx)
+	}()
+
+	// Stopped after the interactions and the jobs, the pending notifications are sent before it returns
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		dispatcher.Run(jobCtx)
+	}()
+
+	// Events are routed to the dataset of the guild, guild configs are kept in line with the guilds the bot is member of
+	router := discord.NewTenantRouter(commandHandler, discord.NewGuildSync(serviceContainer.GetGuildConfigService()))
+	for idx, tenant := range tenants {
+		router.AddTenant(tenant.Guilds, tenantHandlers[idx], discord.NewGuildSync(tenantContainers[idx].GetGuildConfigService()))
+	}
 
 	// Add handlers
-	dg.AddHandler(commandHandler.HandleInteraction)
+	dg.AddHandler(router.HandleInteraction)
+	dg.AddHandler(router.HandleReady)
+	dg.AddHandler(router.HandleGuildCreate)
+	dg.AddHandler(router.HandleGuildDelete)
+
+	// Rotated secrets are applied without a restart
+	if secretManager != nil {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			secretManager.Watch(jobCtx, func(name, value string) {
+				applySecret(append([]*container.Container{serviceContainer}, tenantContainers...), dg, name, value)
+			})
+		}()
+	}
 
 	// Optionnal: Logging of stats every 5 minutes
+	background.Add(1)
 	go func() {
+		defer background.Done()
 		ticker := time.NewTicker(STATS_INTERVAL)
 		defer ticker.Stop()
-		for range ticker.C {
-			total, active, avgTime := commandHandler.GetStats()
-			log.Printf("📊 Bot Stats - Total: %d, Active: %d, Avg Time: %v",
-				total, active, avgTime)
+		for {
+			select {
+			case <-jobCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			total, active, avgTime, failedFollowUps := commandHandler.GetStats()
+			log.Printf("📊 Bot Stats - Total: %d, Active: %d, Avg Time: %v, Failed followups: %d",
+				total, active, avgTime, failedFollowUps)
+			for idx, handler := range tenantHandlers {
+				total, active, avgTime, failedFollowUps := handler.GetStats()
+				log.Printf("📊 Bot Stats (tenant %s) - Total: %d, Active: %d, Avg Time: %v, Failed followups: %d",
+					tenants[idx].ID, total, active, avgTime, failedFollowUps)
+			}
 		}
 	}()
 
-	// Register commands AFTER connection is established
+	// Register commands AFTER connection is established, in a single bulk overwrite. Ready is received
+	// again on every new gateway session, the commands are only synced by the first one.
+	var registerCommands sync.Once
 	dg.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
 		log.Printf("Logged in as: %v#%v", s.State.User.Username, s.State.User.Discriminator)
 		log.Printf("Bot is ready and serving %d guilds", len(s.State.Guilds))
 
-		// Now register slash commands (bot is connected)
-		log.Println("Registering slash commands...")
-		err := commandHandler.RegisterCommands(s)
-		if err != nil {
-			log.Printf("Error registering commands: %v", err)
-		} else {
-			log.Println("✅ Slash commands registered successfully!")
-		}
+		registerCommands.Do(func() {
+			log.Println("Registering slash commands...")
+			count, err := commandHandler.SyncCommands(s)
+			if err != nil {
+				log.Printf("Error registering commands: %v", err)
+			} else {
+				log.Printf("✅ %d slash commands registered successfully!", count)
+			}
+		})
 	})
 
 	// Set intents
-	dg.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentsDirectMessages
+	dg.Identify.Intents = discordgo.IntentsGuilds | discordgo.IntentsGuildMessages | discordgo.IntentsDirectMessages
 
 	// Open connection
 	log.Println("🔄 Connecting to Discord...")
@@ -137,6 +362,22 @@ func main() {
 	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Stop the running interactions and jobs, then the goroutines posting their results
+	for _, handler := range append([]*discord.CommandHandler{commandHandler}, tenantHandlers...) {
+		err = handler.Shutdown(ctx)
+		if err != nil {
+			log.Printf("Error stopping interactions: %v", err)
+		}
+	}
+	for _, c := range append([]*container.Container{serviceContainer}, tenantContainers...) {
+		err = c.GetJobService().Shutdown(ctx)
+		if err != nil {
+			log.Printf("Error stopping jobs: %v", err)
+		}
+	}
+	stopJobs()
+	background.Wait()
+
 	// Close database connection
 	err = dbManager.Close(ctx)
 	if err != nil {
@@ -145,3 +386,39 @@ func main() {
 
 	log.Println("✅ Shutdown complete")
 }
+
+// loadSecretManager reads the credentials from Vault or AWS Secrets Manager when one is configured, nil otherwise
+func loadSecretManager() (*config.SecretManager, error) {
+	secretManager, err := config.SecretManagerFromEnv()
+	if err != nil || secretManager == nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	loaded, err := secretManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🔑 Loaded %s from %s", strings.Join(loaded, ", "), secretManager.Name())
+	return secretManager, nil
+}
+
+// applySecret applies a secret refreshed from the secret manager to the default dataset and the tenants,
+// the ones without hot reload need a restart
+func applySecret(containers []*container.Container, dg *discordgo.Session, name, value string) {
+	switch name {
+	case "RIOT_API_KEY":
+		for _, c := range containers {
+			c.RotateRiotAPIKey(value)
+		}
+	case "DISCORD_TOKEN":
+		err := discord.RotateToken(dg, value, true)
+		if err != nil {
+			log.Printf("Error applying the new Discord token: %v", err)
+		}
+	default:
+		log.Printf("⚠️ %s changed, restart to apply it", name)
+	}
+}
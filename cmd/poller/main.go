@@ -2,16 +2,63 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"lp_tracker/admin"
+	"lp_tracker/config"
+	"lp_tracker/container"
 	"lp_tracker/database"
+	"lp_tracker/discord"
+	"lp_tracker/export"
+	"lp_tracker/selftest"
+	"lp_tracker/services"
+	"lp_tracker/telemetry"
+	"lp_tracker/version"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
+	_ "time/tzdata" // Embed time zones for guild recaps (missing in alpine images)
 
+	"github.com/bwmarrin/discordgo"
 	"github.com/joho/godotenv"
 )
 
+const (
+	POLL_INTERVAL = 5 * time.Minute
+
+	// Games ingested per player on startup to catch up after a downtime (0 disables the backfill)
+	DEFAULT_BACKFILL_MAX_MATCHES = 20
+
+	// Clash registrations open days before the tournaments, a few syncs a day are enough (0 disables the sync)
+	DEFAULT_CLASH_SYNC_INTERVAL = 6 * time.Hour
+
+	// Challenge levels move slowly, two syncs a day are enough for the weekly recaps (0 disables the sync)
+	DEFAULT_CHALLENGES_SYNC_INTERVAL = 12 * time.Hour
+
+	// Streams are checked often enough to announce them shortly after they start
+	DEFAULT_TWITCH_POLL_INTERVAL = 2 * time.Minute
+
+	// Games in progress are checked often enough to wish good luck before the end of the loading screen (0 disables the checks)
+	DEFAULT_GOOD_LUCK_POLL_INTERVAL = 3 * time.Minute
+)
+
+var tracer = telemetry.Tracer("lp_tracker/poller")
+
 func main() {
+	selfTest := flag.Bool("selftest", false, "Check the configuration, MongoDB, the Riot API key and the Discord token, then exit")
+	printVersion := flag.Bool("version", false, "Print the version, commit and build date, then exit")
+	flag.Parse()
+
+	if *printVersion {
+		fmt.Println(version.Get())
+		return
+	}
+	log.Printf("🏷️ lp_tracker poller %s", version.Get())
+
 	if os.Getenv("DOCKER_ENV") != "true" {
 		err := godotenv.Load()
 		if err != nil {
@@ -19,26 +66,68 @@ func main() {
 		}
 	}
 
-	// Validate required environment variables
-	requiredEnvs := map[string]string{
-		"MONGO_URI":      os.Getenv("MONGO_LOCAL_URI"),
-		"MONGO_DATABASE": os.Getenv("MONGO_DATABASE"),
+	// Secrets mounted as files (DISCORD_TOKEN_FILE, RIOT_API_KEY_FILE...)
+	err := config.LoadSecretFiles()
+	if err != nil {
+		log.Fatal(err)
 	}
-	fmt.Println(requiredEnvs)
 
-	for key, value := range requiredEnvs {
-		if value == "" {
-			log.Fatalf("%s environment variable is required", key)
+	// Optional Vault or AWS Secrets Manager secret, its fields win over the environment
+	secretManager, err := loadSecretManager()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *selfTest {
+		os.Exit(selftest.Main(selftest.ConfigFromEnv()))
+	}
+
+	// Validate required environment variables, only their names are logged (they hold secrets)
+	var missingEnvs []string
+	for _, key := range []string{"DISCORD_TOKEN", "RIOT_API_KEY"} {
+		if os.Getenv(key) == "" {
+			missingEnvs = append(missingEnvs, key)
 		}
 	}
+	if len(missingEnvs) > 0 {
+		log.Fatalf("Missing required environment variables: %s", strings.Join(missingEnvs, ", "))
+	}
+
+	// MongoDB connection, from MONGO_URI, MONGO_LOCAL_URI or the MONGO_HOST parts
+	mongoConfig, err := config.MongoConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Using MongoDB %s (database %s)", mongoConfig.Redacted(), mongoConfig.Database)
 
 	ctx := context.Background()
 
+	// Tracing, exported when OTEL_EXPORTER_OTLP_ENDPOINT is set
+	shutdownTracing, err := telemetry.Setup(ctx, "lp_tracker-poller")
+	if err != nil {
+		log.Fatal("Failed to initialize tracing:", err)
+	}
+
+	// Optional admin server for operators (runtime metrics, pprof)
+	var adminServer *admin.Server
+	if addr := os.Getenv("ADMIN_ADDR"); addr != "" {
+		enablePprof, err := config.EnvBool("ADMIN_PPROF", false)
+		if err != nil {
+			log.Fatal(err)
+		}
+		adminServer = admin.NewServer(addr, enablePprof)
+		adminServer.Start()
+	}
+
 	// MongoDB connection
 	dbConfig := database.Config{
-		URI:          os.Getenv("MONGO_URI"),
-		DatabaseName: os.Getenv("MONGO_DATABASE"),
+		URI:          mongoConfig.URI,
+		DatabaseName: mongoConfig.Database,
 		Timeout:      30 * time.Second,
+
+		AnalyticsReadPreference: mongoConfig.AnalyticsReadPreference,
+		SlowQueryThreshold:      mongoConfig.SlowQueryThreshold,
+		IndexStatsInterval:      mongoConfig.IndexStatsInterval,
 	}
 
 	dbManager, err := database.NewManager(dbConfig)
@@ -52,4 +141,467 @@ func main() {
 	} else {
 		log.Println("Database ping successful!")
 	}
+
+	// Initialize service container
+	riotClient, err := services.RiotClientConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	serviceContainer := container.NewContainer(dbManager, os.Getenv("RIOT_API_KEY"), riotClient)
+
+	// Default dataset, the tenant containers share its Riot service when they have no Riot API key
+	defaultContainer := serviceContainer
+
+	// A poller serves a single dataset: the default one, or the tenant set in TENANT (one poller per tenant)
+	var tenant *config.Tenant
+	if tenantID := os.Getenv("TENANT"); tenantID != "" {
+		tenants, err := config.TenantsFromEnv()
+		if err != nil {
+			log.Fatal(err)
+		}
+		found, ok := tenants.Find(tenantID)
+		if !ok {
+			log.Fatalf("Tenant %s is not in TENANTS_FILE", tenantID)
+		}
+		tenant = &found
+
+		serviceContainer, err = container.NewTenantContainer(ctx, defaultContainer, found, riotClient)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("🏢 Polling tenant %s (database %s)", found.ID, found.DatabaseName(mongoConfig.Database))
+	}
+
+	riotDebug, err := config.EnvBool("RIOT_DEBUG", false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if riotDebug {
+		serviceContainer.GetRiotService().SetDebug(true)
+	}
+
+	// Discord session is only used for REST calls (no gateway connection needed to send messages)
+	dg, err := discordgo.New("Bot " + os.Getenv("DISCORD_TOKEN"))
+	if err != nil {
+		log.Fatal("Error creating Discord session:", err)
+	}
+
+	digestWindow, err := config.EnvDuration("NOTIFICATION_DIGEST_WINDOW", discord.DefaultDigestWindow)
+	if err != nil {
+		log.Fatal(err)
+	}
+	digestThreshold, err := config.EnvInt("NOTIFICATION_DIGEST_THRESHOLD", discord.DefaultDigestThreshold)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ingestion, err := matchIngestionOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+	serviceContainer.GetPlayerService().SetMatchIngestionOptions(ingestion)
+	writeBuffer, err := writeBufferOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+	serviceContainer.GetPlayerService().SetWriteBufferOptions(writeBuffer)
+
+	dispatcher := discord.NewDispatcher(dg, digestWindow, digestThreshold)
+	notifier := discord.NewNotifier(dispatcher, serviceContainer.GetGuildConfigService(), serviceContainer.GetDataDragonService(),
+		serviceContainer.GetNotificationDedupeStore(), serviceContainer.GetEnemyRankService(), serviceContainer.GetFeatureFlagService())
+	patchWatcher := discord.NewPatchWatcher(dispatcher, serviceContainer.GetGuildConfigService(), serviceContainer.GetDataDragonService())
+	sessionSummaries := discord.NewSessionSummaries(dg, serviceContainer.GetGuildConfigService())
+	liveLeaderboard := discord.NewLiveLeaderboard(dg, serviceContainer.GetGuildConfigService(), serviceContainer.GetStandingsService())
+	recapScheduler := discord.NewRecapScheduler(dg, serviceContainer.GetGuildConfigService(), serviceContainer.GetRecapService())
+	competitionScheduler := discord.NewCompetitionScheduler(dg, serviceContainer.GetGuildConfigService(), serviceContainer.GetCompetitionService())
+	clashEvents := discord.NewClashEvents(dg, serviceContainer.GetGuildConfigService(), serviceContainer.GetFeatureFlagService(), serviceContainer.GetClashService())
+	pendingActionQueue := discord.NewPendingActionQueue(dg, serviceContainer.GetPendingActionService(), serviceContainer.GetVerificationService())
+
+	// Poll until a shutdown signal is received
+	pollCtx, stopPolling := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stopPolling()
+
+	runs := []func(context.Context){dispatcher.Run, recapScheduler.Run, competitionScheduler.Run, pendingActionQueue.Run}
+
+	// Optional JSON snapshots written to an S3-compatible bucket
+	exportConfig, err := export.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if exportConfig.Enabled() {
+		if tenant != nil {
+			exportConfig.Prefix += tenant.ID + "/"
+		}
+		exportInterval, err := config.EnvDuration("EXPORT_INTERVAL", export.DefaultInterval)
+		if err != nil {
+			log.Fatal(err)
+		}
+		exporter, err := export.NewExporter(exportConfig, exportInterval, serviceContainer.GetPlayerService(), serviceContainer.GetStandingsService())
+		if err != nil {
+			log.Fatal("Failed to initialize the snapshot export:", err)
+		}
+		runs = append(runs, exporter.Run)
+	}
+
+	// Clash registrations of the tracked players, served as a calendar by the web dashboard
+	clashSyncInterval, err := config.EnvDuration("CLASH_SYNC_INTERVAL", DEFAULT_CLASH_SYNC_INTERVAL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if clashSyncInterval > 0 {
+		runs = append(runs, func(ctx context.Context) {
+			syncClash(ctx, serviceContainer, clashEvents, clashSyncInterval)
+		})
+	}
+
+	// Challenge progress of the tracked players, the notable levels reached are listed in the weekly recaps
+	challengesSyncInterval, err := config.EnvDuration("CHALLENGES_SYNC_INTERVAL", DEFAULT_CHALLENGES_SYNC_INTERVAL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if challengesSyncInterval > 0 {
+		runs = append(runs, func(ctx context.Context) {
+			syncChallenges(ctx, serviceContainer, challengesSyncInterval)
+		})
+	}
+
+	// Optional Twitch integration announcing the tracked players going live
+	if clientID, clientSecret := os.Getenv("TWITCH_CLIENT_ID"), os.Getenv("TWITCH_CLIENT_SECRET"); clientID != "" && clientSecret != "" {
+		twitchInterval, err := config.EnvDuration("TWITCH_POLL_INTERVAL", DEFAULT_TWITCH_POLL_INTERVAL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if twitchInterval > 0 {
+			twitchWatcher := discord.NewTwitchWatcher(dispatcher, serviceContainer.GetGuildConfigService(), serviceContainer.GetPlayerService(),
+				services.NewTwitchService(clientID, clientSecret), twitchInterval)
+			runs = append(runs, twitchWatcher.Run)
+		}
+	}
+
+	// Good luck messages on the first ranked game of the day, only enabled guilds cost Riot API calls
+	goodLuckInterval, err := config.EnvDuration("GOOD_LUCK_POLL_INTERVAL", DEFAULT_GOOD_LUCK_POLL_INTERVAL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if goodLuckInterval > 0 {
+		goodLuckWatcher := discord.NewGoodLuckWatcher(dispatcher, serviceContainer.GetGuildConfigService(), serviceContainer.GetPlayerService(),
+			serviceContainer.GetDataDragonService(), goodLuckInterval)
+		runs = append(runs, goodLuckWatcher.Run)
+	}
+
+	// Rotated secrets are applied without a restart
+	if secretManager != nil {
+		runs = append(runs, func(ctx context.Context) {
+			secretManager.Watch(ctx, func(name, value string) {
+				applySecret([]*container.Container{defaultContainer, serviceContainer}, dg, name, value)
+			})
+		})
+	}
+
+	// SIGUSR1 toggles the Riot API debug logs without a restart (ex: docker kill -s USR1 poller)
+	runs = append(runs, func(ctx context.Context) {
+		toggleRiotDebug(ctx, serviceContainer.GetRiotService())
+	})
+
+	// Background goroutines of the process, joined on shutdown
+	background := startBackground(pollCtx, runs)
+
+	// Catch up on the games played while the poller was down
+	backfillMax, err := config.EnvInt("BACKFILL_MAX_MATCHES", DEFAULT_BACKFILL_MAX_MATCHES)
+	if err != nil {
+		log.Fatal(err)
+	}
+	backfill(pollCtx, serviceContainer, notifier, backfillMax)
+
+	log.Printf("🔄 Poller started, polling every %v", POLL_INTERVAL)
+	ticker := time.NewTicker(POLL_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		poll(pollCtx, serviceContainer, notifier, sessionSummaries, liveLeaderboard)
+
+		err = patchWatcher.Check(pollCtx)
+		if err != nil {
+			log.Printf("Error checking for a new patch: %v", err)
+		}
+
+		select {
+		case <-pollCtx.Done():
+			log.Println("🛑 Shutting down poller...")
+			background.Wait()
+			// Messages enqueued by the last cycle after the dispatcher stopped
+			dispatcher.Flush()
+
+			closeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := dbManager.Close(closeCtx); err != nil {
+				log.Printf("Error closing database connection: %v", err)
+			}
+			if err := shutdownTracing(closeCtx); err != nil {
+				log.Printf("Error flushing traces: %v", err)
+			}
+			if adminServer != nil {
+				adminServer.Shutdown(closeCtx)
+			}
+			log.Println("✅ Shutdown complete")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// startBackground starts every run in its own goroutine until ctx is cancelled, the returned group
+// is done once they all returned
+func startBackground(ctx context.Context, runs []func(context.Context)) *sync.WaitGroup {
+	var background sync.WaitGroup
+	for _, run := range runs {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			run(ctx)
+		}()
+	}
+	return &background
+}
+
+// toggleRiotDebug switches the Riot API debug logs on every SIGUSR1 until the context is cancelled
+func toggleRiotDebug(ctx context.Context, riot *services.RiotService) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			riot.SetDebug(!riot.Debug())
+		}
+	}
+}
+
+// backfill ingests the games missed since the players' checkpoints and announces them as a digest
+func backfill(ctx context.Context, c *container.Container, notifier *discord.Notifier, maxMatches int) {
+	if maxMatches == 0 {
+		return
+	}
+	start := time.Now()
+
+	catchUps, err := c.GetPlayerService().Backfill(ctx, maxMatches)
+	if err != nil {
+		log.Printf("Error backfilling missed games: %v", err)
+		return
+	}
+
+	err = notifier.NotifyCatchUp(ctx, catchUps)
+	if err != nil {
+		log.Printf("Error sending catch-up notifications: %v", err)
+	}
+
+	log.Printf("⏪ Backfill done in %v - %d players caught up", time.Since(start), len(catchUps))
+}
+
+// syncClash refreshes the Clash registrations of the tracked players and the guild events until the context is cancelled
+func syncClash(ctx context.Context, c *container.Container, clashEvents *discord.ClashEvents, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		result, err := c.GetClashService().SyncRegistrations(ctx)
+		if err != nil {
+			log.Printf("Error syncing clash registrations: %v", err)
+		}
+		if result != nil {
+			log.Printf("🏆 Clash registrations synced in %v - %d new, %d cancelled", time.Since(start), len(result.Added), len(result.Removed))
+		}
+
+		err = clashEvents.Sync(ctx)
+		if err != nil {
+			log.Printf("Error syncing clash events: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncChallenges refreshes the challenge progress of the tracked players until the context is cancelled
+func syncChallenges(ctx context.Context, c *container.Container, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		completions, err := c.GetChallengeService().SyncAll(ctx)
+		if err != nil {
+			log.Printf("Error syncing challenges: %v", err)
+		}
+		log.Printf("🏅 Challenges synced in %v - %d notable levels reached", time.Since(start), len(completions))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll updates every tracked player and notifies guilds of the rank changes
+func poll(ctx context.Context, c *container.Container, notifier *discord.Notifier, sessionSummaries *discord.SessionSummaries, liveLeaderboard *discord.LiveLeaderboard) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "poll")
+	defer span.End()
+
+	changes, err := c.GetPlayerService().UpdateAllPlayers(ctx)
+	if err != nil {
+		log.Printf("Error updating players: %v", err)
+	}
+
+	// The leaderboards switch to the ranks of this cycle, before the live leaderboards are refreshed
+	err = completeCycle(ctx, c)
+	if err != nil {
+		log.Printf("Error recording the leaderboard of the cycle: %v", err)
+	}
+
+	err = notifier.NotifyRankChanges(ctx, changes)
+	if err != nil {
+		log.Printf("Error sending notifications: %v", err)
+	}
+
+	unlocks, err := c.GetAchievementService().EvaluateRankChanges(ctx, changes)
+	if err != nil {
+		log.Printf("Error evaluating achievements: %v", err)
+	}
+
+	err = notifier.NotifyAchievements(ctx, unlocks)
+	if err != nil {
+		log.Printf("Error sending achievements: %v", err)
+	}
+
+	alerts, err := c.GetTiltService().EvaluateRankChanges(ctx, changes)
+	if err != nil {
+		log.Printf("Error detecting tilt sessions: %v", err)
+	}
+
+	err = notifier.NotifyTilt(ctx, alerts)
+	if err != nil {
+		log.Printf("Error sending tilt alerts: %v", err)
+	}
+
+	// After the player updates, the session markers are saved on the players
+	summaries, err := c.GetSessionService().SummarizeEndedSessions(ctx, time.Now())
+	if err != nil {
+		log.Printf("Error summarizing sessions: %v", err)
+	}
+
+	err = sessionSummaries.Post(ctx, summaries)
+	if err != nil {
+		log.Printf("Error posting session summaries: %v", err)
+	}
+
+	err = liveLeaderboard.Update(ctx)
+	if err != nil {
+		log.Printf("Error updating live leaderboards: %v", err)
+	}
+
+	if violations := c.GetRiotService().SchemaViolations(); len(violations) > 0 {
+		log.Printf("⚠️ Riot API responses rejected by the schema guard since startup: %v", violations)
+	}
+
+	connections := c.GetRiotService().ConnectionStats()
+	log.Printf("📊 Poll cycle done in %v - %d rank changes, %.0f%% of Riot API connections reused (%d new since startup)",
+		time.Since(start), len(changes), connections.ReuseRatio(), connections.New)
+}
+
+// completeCycle records the leaderboard at the end of a poll cycle. A cancelled cycle is not recorded,
+// the leaderboards keep the ranks of the last complete one.
+func completeCycle(ctx context.Context, c *container.Container) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	players, err := c.GetPlayerService().GetAllPlayers(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.GetStandingsService().CompleteCycle(ctx, players)
+	return err
+}
+
+// matchIngestionOptions reads the optional match ingestion settings from the environment
+func matchIngestionOptions() (services.MatchIngestionOptions, error) {
+	opts := services.DefaultMatchIngestionOptions()
+
+	maxMatches, err := config.EnvInt("MATCH_INGESTION_MAX", opts.MaxMatches)
+	if err != nil {
+		return opts, err
+	}
+	if maxMatches == 0 {
+		return opts, fmt.Errorf("invalid MATCH_INGESTION_MAX: at least 1 game must be ingested")
+	}
+	opts.MaxMatches = maxMatches
+
+	if spec := os.Getenv("MATCH_INGESTION_QUEUES"); spec != "" {
+		opts.Queues, err = services.ParseQueues(spec)
+		if err != nil {
+			return opts, fmt.Errorf("invalid MATCH_INGESTION_QUEUES: %w", err)
+		}
+	}
+
+	opts.StoreParticipants, err = config.EnvBool("MATCH_STORE_PARTICIPANTS", opts.StoreParticipants)
+	return opts, err
+}
+
+// writeBufferOptions reads the optional batching of the poll cycle writes from the environment
+func writeBufferOptions() (services.WriteBufferOptions, error) {
+	opts := services.DefaultWriteBufferOptions()
+
+	maxWrites, err := config.EnvInt("WRITE_BUFFER_SIZE", opts.MaxWrites)
+	if err != nil {
+		return opts, err
+	}
+	opts.MaxWrites = maxWrites
+
+	opts.FlushInterval, err = config.EnvDuration("WRITE_BUFFER_FLUSH_INTERVAL", opts.FlushInterval)
+	return opts, err
+}
+
+// loadSecretManager reads the credentials from Vault or AWS Secrets Manager when one is configured, nil otherwise
+func loadSecretManager() (*config.SecretManager, error) {
+	secretManager, err := config.SecretManagerFromEnv()
+	if err != nil || secretManager == nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	loaded, err := secretManager.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🔑 Loaded %s from %s", strings.Join(loaded, ", "), secretManager.Name())
+	return secretManager, nil
+}
+
+// applySecret applies a secret refreshed from the secret manager to the default dataset and the tenants,
+// the ones without hot reload need a restart
+func applySecret(containers []*container.Container, dg *discordgo.Session, name, value string) {
+	switch name {
+	case "RIOT_API_KEY":
+		for _, c := range containers {
+			c.RotateRiotAPIKey(value)
+		}
+	case "DISCORD_TOKEN":
+		err := discord.RotateToken(dg, value, false)
+		if err != nil {
+			log.Printf("Error applying the new Discord token: %v", err)
+		}
+	default:
+		log.Printf("⚠️ %s changed, restart to apply it", name)
+	}
 }
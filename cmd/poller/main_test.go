@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"lp_tracker/discord"
+	"lp_tracker/services"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/goleak"
+)
+
+// countingTransport accepts every Discord REST call and counts them
+type countingTransport struct {
+	requests atomic.Int64
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"id": "1"}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestBackgroundStopsOnShutdown(t *testing.T) {
+	// The signal watcher of the runtime is started by signal.Notify and never stops
+	defer goleak.VerifyNone(t, goleak.IgnoreTopFunction("os/signal.signal_recv"))
+
+	transport := &countingTransport{}
+	dg, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("failed to create the session: %v", err)
+	}
+	dg.Client = &http.Client{Transport: transport}
+
+	dispatcher := discord.NewDispatcher(dg, time.Hour, discord.DefaultDigestThreshold)
+	riot := services.NewRiotService("test", services.DefaultRiotClientConfig())
+
+	pollCtx, stopPolling := context.WithCancel(context.Background())
+	background := startBackground(pollCtx, []func(context.Context){
+		dispatcher.Run,
+		func(ctx context.Context) {
+			toggleRiotDebug(ctx, riot)
+		},
+	})
+
+	dispatcher.Enqueue("guild-1", "channel-1", "🎉 Promoted")
+	stopPolling()
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		background.Wait()
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the background goroutines did not stop")
+	}
+
+	// Same order as main: messages of the last cycle are flushed once the dispatcher stopped
+	dispatcher.Enqueue("guild-1", "channel-1", "📉 Demoted")
+	dispatcher.Flush()
+
+	if got := transport.requests.Load(); got != 2 {
+		t.Errorf("%d messages sent on shutdown, want 2", got)
+	}
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// AWSSecretsConfig locates the AWS Secrets Manager secret holding the credentials
+type AWSSecretsConfig struct {
+	SecretID        string // Name or ARN of the secret
+	Region          string // ex: eu-west-3
+	Endpoint        string // Overrides https://secretsmanager.<region>.amazonaws.com (ex: a VPC endpoint, LocalStack)
+	RefreshInterval time.Duration
+}
+
+// AWSSecretsConfigFromEnv reads AWS_SECRET_ID, AWS_REGION (or AWS_DEFAULT_REGION), AWS_SECRETS_MANAGER_ENDPOINT
+// and AWS_SECRET_REFRESH_INTERVAL
+func AWSSecretsConfigFromEnv() (AWSSecretsConfig, error) {
+	config := AWSSecretsConfig{
+		SecretID: os.Getenv("AWS_SECRET_ID"),
+		Region:   os.Getenv("AWS_REGION"),
+		Endpoint: strings.TrimSuffix(os.Getenv("AWS_SECRETS_MANAGER_ENDPOINT"), "/"),
+	}
+	if config.Region == "" {
+		config.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+
+	interval, err := refreshIntervalFromEnv("AWS_SECRET_REFRESH_INTERVAL")
+	if err != nil {
+		return config, err
+	}
+	config.RefreshInterval = interval
+
+	if config.Enabled() && config.Region == "" {
+		return config, fmt.Errorf("AWS_REGION is required to read %s", config.SecretID)
+	}
+	return config, nil
+}
+
+// Enabled checks if an AWS Secrets Manager secret is configured
+func (c AWSSecretsConfig) Enabled() bool {
+	return c.SecretID != ""
+}
+
+// NewAWSSecrets returns the secret manager reading the keys of a JSON secret of AWS Secrets Manager named
+// after the secrets (ex: {"DISCORD_TOKEN": "...", "RIOT_API_KEY": "..."}). The AWS credentials are read
+// from AWS_ACCESS_KEY_ID with AWS_SECRET_ACCESS_KEY_FILE, AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY, the
+// shared credentials file or the IAM role of the instance, task or pod, in that order.
+func NewAWSSecrets(config AWSSecretsConfig) (*SecretManager, error) {
+	secretAccessKey, err := readSecret("AWS_SECRET_ACCESS_KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", config.Region)
+	}
+
+	client := &awsSecretsClient{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		credentials: credentials.NewChainCredentials([]credentials.Provider{
+			// Skipped by the chain without AWS_SECRET_ACCESS_KEY_FILE
+			&credentials.Static{Value: credentials.Value{
+				AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+				SecretAccessKey: secretAccessKey,
+				SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+				SignerType:      credentials.SignatureV4,
+			}},
+			&credentials.EnvAWS{},
+			&credentials.FileAWSCredentials{},
+			&credentials.IAM{Client: &http.Client{Transport: http.DefaultTransport}},
+		}),
+	}
+	return &SecretManager{
+		name:            "AWS Secrets Manager",
+		fetch:           client.fetch,
+		refreshInterval: config.RefreshInterval,
+	}, nil
+}
+
+type awsSecretsClient struct {
+	config      AWSSecretsConfig
+	httpClient  *http.Client
+	credentials *credentials.Credentials // Cached until they expire (IAM roles)
+}
+
+// fetch reads the known secrets from the keys of the JSON secret (GetSecretValue action)
+func (a *awsSecretsClient) fetch(ctx context.Context) (map[string]string, error) {
+	creds, err := a.credentials.Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the AWS credentials: %w", err)
+	}
+	if creds.AccessKeyID == "" {
+		return nil, errors.New("no AWS credentials found (environment, shared credentials file or IAM role)")
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": a.config.SecretID})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", a.config.Endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signSigV4(req, body, awsCredentials{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+	}, a.config.Region, "secretsmanager", time.Now())
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from AWS Secrets Manager: %w", a.config.SecretID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Expired role credentials are fetched again by the next refresh
+		if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusBadRequest {
+			a.credentials.Expire()
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("failed to read %s from AWS Secrets Manager: status %d: %s", a.config.SecretID, resp.StatusCode, string(body))
+	}
+
+	var secret struct {
+		SecretString string `json:"SecretString"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", a.config.SecretID, err)
+	}
+	if secret.SecretString == "" {
+		return nil, fmt.Errorf("%s has no string value, store the secrets as JSON key/value pairs", a.config.SecretID)
+	}
+
+	var fields map[string]any
+	err = json.Unmarshal([]byte(secret.SecretString), &fields)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a JSON object of key/value pairs: %w", a.config.SecretID, err)
+	}
+	return knownSecrets(a.config.SecretID, fields)
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSecretsManager answers GetSecretValue with the current value of the secret
+type fakeSecretsManager struct {
+	mu     sync.Mutex
+	secret string
+}
+
+func (f *fakeSecretsManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+		!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+		!strings.Contains(r.Header.Get("Authorization"), "/eu-west-3/secretsmanager/aws4_request") {
+		http.Error(w, `{"__type": "AccessDeniedException"}`, http.StatusForbidden)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	var input struct {
+		SecretID string `json:"SecretId"`
+	}
+	if json.Unmarshal(body, &input) != nil || input.SecretID != "lp_tracker/prod" {
+		http.Error(w, `{"__type": "ResourceNotFoundException"}`, http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_ = json.NewEncoder(w).Encode(map[string]string{"Name": input.SecretID, "SecretString": f.secret})
+}
+
+func newTestAWSSecrets(t *testing.T, server *httptest.Server) *SecretManager {
+	t.Helper()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	t.Setenv("AWS_SECRET_ID", "lp_tracker/prod")
+	t.Setenv("AWS_REGION", "eu-west-3")
+	t.Setenv("AWS_SECRETS_MANAGER_ENDPOINT", server.URL)
+	t.Setenv("VAULT_SECRET_PATH", "")
+
+	manager, err := SecretManagerFromEnv()
+	if err != nil {
+		t.Fatalf("SecretManagerFromEnv = %v", err)
+	}
+	if manager == nil || manager.Name() != "AWS Secrets Manager" {
+		t.Fatalf("SecretManagerFromEnv = %v, want AWS Secrets Manager", manager)
+	}
+	return manager
+}
+
+func TestAWSSecretsLoad(t *testing.T) {
+	fake := &fakeSecretsManager{secret: `{"DISCORD_TOKEN": "discord-1", "RIOT_API_KEY": "riot-1", "UNKNOWN": "ignored"}`}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	manager := newTestAWSSecrets(t, server)
+	t.Setenv("DISCORD_TOKEN", "from-env")
+	t.Setenv("RIOT_API_KEY", "from-env")
+
+	loaded, err := manager.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load = %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Errorf("loaded %v, want DISCORD_TOKEN and RIOT_API_KEY", loaded)
+	}
+	if got := os.Getenv("DISCORD_TOKEN"); got != "discord-1" {
+		t.Errorf("DISCORD_TOKEN = %q, want the value of the secret", got)
+	}
+
+	// Rotated secret, only the changed values are reported
+	fake.mu.Lock()
+	fake.secret = `{"DISCORD_TOKEN": "discord-1", "RIOT_API_KEY": "riot-2"}`
+	fake.mu.Unlock()
+
+	manager.refreshInterval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var changed []string
+	manager.Watch(ctx, func(name, value string) {
+		changed = append(changed, name+"="+value)
+		cancel()
+	})
+	if len(changed) != 1 || changed[0] != "RIOT_API_KEY=riot-2" {
+		t.Errorf("changed secrets = %v, want the rotated Riot API key only", changed)
+	}
+}
+
+func TestAWSSecretsKeyFile(t *testing.T) {
+	server := httptest.NewServer(&fakeSecretsManager{secret: `{"RIOT_API_KEY": "riot-1"}`})
+	defer server.Close()
+
+	keyFile := filepath.Join(t.TempDir(), "aws_secret_access_key")
+	err := os.WriteFile(keyFile, []byte("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTestAWSSecrets(t, server)
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY_FILE", keyFile)
+
+	manager, err := SecretManagerFromEnv()
+	if err != nil {
+		t.Fatalf("SecretManagerFromEnv = %v", err)
+	}
+	if _, err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load = %v", err)
+	}
+	// The key of the file signs the requests without being exported to the environment
+	if got := os.Getenv("AWS_SECRET_ACCESS_KEY"); got != "" {
+		t.Errorf("AWS_SECRET_ACCESS_KEY = %q, want it left unset", got)
+	}
+}
+
+func TestAWSSecretsErrors(t *testing.T) {
+	for name, secret := range map[string]string{
+		"not JSON":         "riot-1",
+		"no known secrets": `{"OTHER": "value"}`,
+		"binary secret":    "",
+	} {
+		server := httptest.NewServer(&fakeSecretsManager{secret: secret})
+		manager := newTestAWSSecrets(t, server)
+
+		if _, err := manager.Load(context.Background()); err == nil {
+			t.Errorf("%s: Load = nil, want an error", name)
+		}
+		server.Close()
+	}
+}
+
+func TestSecretManagerFromEnvRejectsBoth(t *testing.T) {
+	t.Setenv("AWS_SECRET_ID", "lp_tracker/prod")
+	t.Setenv("AWS_REGION", "eu-west-3")
+	t.Setenv("VAULT_ADDR", "https://vault:8200")
+	t.Setenv("VAULT_TOKEN", "token")
+	t.Setenv("VAULT_SECRET_PATH", "secret/data/lp_tracker")
+
+	if _, err := SecretManagerFromEnv(); err == nil {
+		t.Error("SecretManagerFromEnv = nil error with both Vault and AWS Secrets Manager configured")
+	}
+}
+
+func TestAWSSecretsConfigRequiresRegion(t *testing.T) {
+	t.Setenv("AWS_SECRET_ID", "lp_tracker/prod")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	if _, err := AWSSecretsConfigFromEnv(); err == nil {
+		t.Error("AWSSecretsConfigFromEnv = nil error without region")
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// EnvDuration reads an optional duration (ex: 30s, 1m) from the environment
+func EnvDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid %s: %q is not a valid duration", key, value)
+	}
+	return duration, nil
+}
+
+// EnvBool reads an optional boolean (true, false, 1, 0) from the environment
+func EnvBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %q is not a valid boolean", key, value)
+	}
+	return enabled, nil
+}
+
+// EnvInt reads an optional non negative integer from the environment
+func EnvInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid %s: %q is not a valid number", key, value)
+	}
+	return number, nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnvHelpers(t *testing.T) {
+	t.Setenv("TEST_DURATION", "")
+	if got, err := EnvDuration("TEST_DURATION", time.Minute); err != nil || got != time.Minute {
+		t.Fatalf("EnvDuration(unset) = %v, %v, want the fallback", got, err)
+	}
+	t.Setenv("TEST_DURATION", "30s")
+	if got, err := EnvDuration("TEST_DURATION", time.Minute); err != nil || got != 30*time.Second {
+		t.Fatalf("EnvDuration(30s) = %v, %v", got, err)
+	}
+	t.Setenv("TEST_DURATION", "-1s")
+	if _, err := EnvDuration("TEST_DURATION", time.Minute); err == nil {
+		t.Fatal("EnvDuration(-1s) should fail")
+	}
+
+	t.Setenv("TEST_BOOL", "1")
+	if got, err := EnvBool("TEST_BOOL", false); err != nil || !got {
+		t.Fatalf("EnvBool(1) = %v, %v", got, err)
+	}
+	t.Setenv("TEST_BOOL", "yes")
+	if _, err := EnvBool("TEST_BOOL", false); err == nil {
+		t.Fatal("EnvBool(yes) should fail")
+	}
+
+	t.Setenv("TEST_INT", "")
+	if got, err := EnvInt("TEST_INT", 5); err != nil || got != 5 {
+		t.Fatalf("EnvInt(unset) = %v, %v, want the fallback", got, err)
+	}
+	t.Setenv("TEST_INT", "-3")
+	if _, err := EnvInt("TEST_INT", 5); err == nil {
+		t.Fatal("EnvInt(-3) should fail")
+	}
+}
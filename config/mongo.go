@@ -0,0 +1,126 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// MongoConfig is the resolved MongoDB connection of a binary
+type MongoConfig struct {
+	URI      string
+	Database string
+
+	// MONGO_ANALYTICS_READ_PREFERENCE, read preference of the heavy read-only queries (ex: secondaryPreferred)
+	AnalyticsReadPreference string
+
+	// MONGO_SLOW_QUERY_THRESHOLD, the commands slower than it are logged (ex: 200ms), 0 to disable
+	SlowQueryThreshold time.Duration
+
+	// MONGO_INDEX_STATS_INTERVAL, period of the index usage reports in the logs (ex: 24h), 0 to disable
+	IndexStatsInterval time.Duration
+}
+
+// MongoConfigFromEnv resolves the MongoDB connection, from the first of:
+//   - MONGO_URI: full connection string (ex: the Docker URI of docker-compose)
+//   - MONGO_LOCAL_URI: full connection string for local runs outside Docker
+//   - MONGO_HOST (host:port, or a comma separated list for replica sets) with the optional
+//     MONGO_USERNAME, MONGO_PASSWORD, MONGO_AUTH_SOURCE (default admin) and MONGO_OPTIONS (ex: replicaSet=rs0)
+//
+// MONGO_DATABASE is required in every case.
+func MongoConfigFromEnv() (MongoConfig, error) {
+	config := MongoConfig{
+		URI:                     os.Getenv("MONGO_URI"),
+		Database:                os.Getenv("MONGO_DATABASE"),
+		AnalyticsReadPreference: os.Getenv("MONGO_ANALYTICS_READ_PREFERENCE"),
+	}
+	if config.URI == "" {
+		config.URI = os.Getenv("MONGO_LOCAL_URI")
+	}
+
+	host := os.Getenv("MONGO_HOST")
+	switch {
+	case config.URI != "" && host != "":
+		return config, errors.New("both a MongoDB URI (MONGO_URI or MONGO_LOCAL_URI) and MONGO_HOST are set, keep only one")
+	case host != "":
+		uri, err := buildMongoURI(host)
+		if err != nil {
+			return config, err
+		}
+		config.URI = uri
+	case config.URI == "":
+		return config, errors.New("MONGO_URI (or MONGO_LOCAL_URI, or MONGO_HOST) environment variable is required")
+	}
+
+	if !strings.HasPrefix(config.URI, "mongodb://") && !strings.HasPrefix(config.URI, "mongodb+srv://") {
+		return config, errors.New("invalid MongoDB URI: expected the mongodb:// or mongodb+srv:// scheme")
+	}
+	if config.Database == "" {
+		return config, errors.New("MONGO_DATABASE environment variable is required")
+	}
+
+	durations := map[string]*time.Duration{
+		"MONGO_SLOW_QUERY_THRESHOLD": &config.SlowQueryThreshold,
+		"MONGO_INDEX_STATS_INTERVAL": &config.IndexStatsInterval,
+	}
+	for key, target := range durations {
+		duration, err := EnvDuration(key, *target)
+		if err != nil {
+			return config, err
+		}
+		*target = duration
+	}
+	return config, nil
+}
+
+// buildMongoURI assembles a connection string from the discrete MONGO_* parts, escaping the credentials
+func buildMongoURI(host string) (string, error) {
+	uri := url.URL{
+		Scheme: "mongodb",
+		Host:   host,
+		Path:   "/",
+	}
+
+	username := os.Getenv("MONGO_USERNAME")
+	password := os.Getenv("MONGO_PASSWORD")
+	switch {
+	case username != "":
+		uri.User = url.UserPassword(username, password)
+	case password != "":
+		return "", errors.New("MONGO_PASSWORD is set without MONGO_USERNAME")
+	}
+
+	query, err := url.ParseQuery(os.Getenv("MONGO_OPTIONS"))
+	if err != nil {
+		return "", fmt.Errorf("invalid MONGO_OPTIONS: %w", err)
+	}
+	if authSource := os.Getenv("MONGO_AUTH_SOURCE"); authSource != "" {
+		query.Set("authSource", authSource)
+	} else if username != "" && !query.Has("authSource") {
+		query.Set("authSource", "admin")
+	}
+	uri.RawQuery = query.Encode()
+
+	return uri.String(), nil
+}
+
+// Redacted returns the URI without its password, for logs. net/url can't parse the host lists of
+// replica sets, so the credentials are located by hand.
+func (c MongoConfig) Redacted() string {
+	scheme, rest, found := strings.Cut(c.URI, "://")
+	if !found {
+		return c.URI
+	}
+	authority, path, _ := strings.Cut(rest, "/")
+	userInfo, hosts, found := strings.Cut(authority, "@")
+	if !found {
+		return c.URI
+	}
+	if username, _, hasPassword := strings.Cut(userInfo, ":"); hasPassword {
+		userInfo = username + ":xxxxx"
+	}
+	return scheme + "://" + userInfo + "@" + hosts + "/" + path
+}
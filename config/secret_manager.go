@@ -0,0 +1,120 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// SecretManager reads the secrets (DISCORD_TOKEN, RIOT_API_KEY...) from an external store, HashiCorp
+// Vault or AWS Secrets Manager, and refreshes them so rotated credentials apply without a restart
+type SecretManager struct {
+	name            string // Store of the secrets in the logs, ex: "Vault"
+	fetch           func(ctx context.Context) (map[string]string, error)
+	refreshInterval time.Duration
+	values          map[string]string // Last values read
+}
+
+// SecretManagerFromEnv returns the secret manager configured by the environment (VAULT_* or
+// AWS_SECRET_ID), nil when none is configured
+func SecretManagerFromEnv() (*SecretManager, error) {
+	vaultConfig, err := VaultConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	awsConfig, err := AWSSecretsConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case vaultConfig.Enabled() && awsConfig.Enabled():
+		return nil, errors.New("both VAULT_SECRET_PATH and AWS_SECRET_ID are set, keep only one secret manager")
+	case vaultConfig.Enabled():
+		return NewVault(vaultConfig), nil
+	case awsConfig.Enabled():
+		return NewAWSSecrets(awsConfig)
+	default:
+		return nil, nil
+	}
+}
+
+// Name returns the store of the secrets, for the logs
+func (m *SecretManager) Name() string {
+	return m.name
+}
+
+// Load reads the secrets once and exports them as environment variables, they win over the
+// values of the environment. It returns the names of the loaded secrets.
+func (m *SecretManager) Load(ctx context.Context) ([]string, error) {
+	values, err := m.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var loaded []string
+	for name, value := range values {
+		err := os.Setenv(name, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", name, err)
+		}
+		loaded = append(loaded, name)
+	}
+	m.values = values
+	return loaded, nil
+}
+
+// Watch reads the secrets again every refresh interval until the context is cancelled, and calls
+// onChange for every secret whose value changed. Read failures keep the previous values.
+func (m *SecretManager) Watch(ctx context.Context, onChange func(name, value string)) {
+	if m.refreshInterval == 0 {
+		return
+	}
+	ticker := time.NewTicker(m.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		values, err := m.fetch(ctx)
+		if err != nil {
+			log.Printf("Error refreshing secrets from %s: %v", m.name, err)
+			continue
+		}
+		for name, value := range values {
+			if m.values[name] == value {
+				continue
+			}
+			log.Printf("🔑 %s changed in %s", name, m.name)
+			onChange(name, value)
+		}
+		m.values = values
+	}
+}
+
+// knownSecrets keeps the fields of a secret named after the known secrets
+func knownSecrets(secret string, fields map[string]any) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, name := range Secrets {
+		if value, ok := fields[name].(string); ok && value != "" {
+			values[name] = value
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s has none of the fields %s", secret, strings.Join(Secrets, ", "))
+	}
+	return values, nil
+}
+
+// refreshIntervalFromEnv reads the refresh interval of a secret manager, 0 disables the refresh
+func refreshIntervalFromEnv(key string) (time.Duration, error) {
+	return EnvDuration(key, DefaultSecretRefreshInterval)
+}
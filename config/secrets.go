@@ -0,0 +1,67 @@
+// Package config resolves the settings shared by the binaries (secrets, connection settings)
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Secrets lists the variables that can be read from a file with <NAME>_FILE, for Docker and
+// Kubernetes secrets mounted as files (ex: DISCORD_TOKEN_FILE=/run/secrets/discord_token)
+var Secrets = []string{
+	"DISCORD_TOKEN",
+	"RIOT_API_KEY",
+	"RIOT_API_KEYS",
+	"MONGO_URI",
+	"MONGO_LOCAL_URI",
+	"MONGO_PASSWORD",
+	"TWITCH_CLIENT_SECRET",
+	"EXPORT_S3_SECRET_KEY",
+	"CLASH_FEED_TOKEN",
+}
+
+// LoadSecretFiles reads the <NAME>_FILE variables of the secrets into <NAME>, so the rest of the
+// process keeps reading the environment. Precedence rules:
+//   - <NAME> alone: used as is
+//   - <NAME>_FILE alone: the file content, without the trailing newline
+//   - both: rejected, one of them would be silently ignored
+func LoadSecretFiles() error {
+	for _, name := range Secrets {
+		value, err := readSecret(name)
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			continue
+		}
+
+		err = os.Setenv(name, value)
+		if err != nil {
+			return fmt.Errorf("failed to set %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// readSecret returns the value of a secret read from its file, empty when no file is configured
+func readSecret(name string) (string, error) {
+	path := os.Getenv(name + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+	if os.Getenv(name) != "" {
+		return "", fmt.Errorf("both %s and %s_FILE are set, keep only one", name, name)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s_FILE: %w", name, err)
+	}
+
+	value := strings.TrimRight(string(content), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("%s_FILE (%s) is empty", name, path)
+	}
+	return value, nil
+}
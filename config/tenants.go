@@ -0,0 +1,95 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// Identifiers of the tenants, used in the names of their databases
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// Tenant is a community served by a shared instance, with its own dataset, Riot API key and quota.
+// Its data lives in its own database (see DatabaseName): no query can reach the players of another
+// tenant, and a tenant is removed by dropping its database.
+type Tenant struct {
+	ID         string   `json:"id"`                   // Lower case letters, digits, - and _ (ex: "team_a")
+	Guilds     []string `json:"guilds"`               // Discord guild IDs of the community
+	RiotAPIKey string   `json:"riotApiKey,omitempty"` // Empty to share RIOT_API_KEY
+	MaxPlayers int      `json:"maxPlayers,omitempty"` // Tracked players allowed, 0 for no limit
+}
+
+// DatabaseName returns the database of the tenant, next to the default one (ex: lp_tracker_team_a)
+func (t Tenant) DatabaseName(base string) string {
+	return base + "_" + t.ID
+}
+
+// Tenants are the communities of a shared instance. The guilds not listed in any tenant use the
+// default dataset (MONGO_DATABASE and RIOT_API_KEY), so single community instances need no tenant.
+type Tenants []Tenant
+
+// TenantsFromEnv reads the tenants from the JSON file set in TENANTS_FILE (ex: [{"id": "team_a", "guilds": ["123"]}]),
+// nil when unset. The file holds Riot API keys: mount it like the other secrets.
+func TenantsFromEnv() (Tenants, error) {
+	path := os.Getenv("TENANTS_FILE")
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TENANTS_FILE: %w", err)
+	}
+
+	var tenants Tenants
+	err = json.Unmarshal(content, &tenants)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TENANTS_FILE: %w", err)
+	}
+
+	err = tenants.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid TENANTS_FILE: %w", err)
+	}
+	return tenants, nil
+}
+
+// validate rejects the duplicate tenants and the guilds shared by two tenants, their data would be split
+func (t Tenants) validate() error {
+	ids := make(map[string]bool, len(t))
+	guilds := make(map[string]string)
+	for _, tenant := range t {
+		if !tenantIDPattern.MatchString(tenant.ID) {
+			return fmt.Errorf("invalid tenant id %q, expected lower case letters, digits, - and _", tenant.ID)
+		}
+		if ids[tenant.ID] {
+			return fmt.Errorf("duplicate tenant %s", tenant.ID)
+		}
+		ids[tenant.ID] = true
+
+		if len(tenant.Guilds) == 0 {
+			return fmt.Errorf("tenant %s has no guild", tenant.ID)
+		}
+		if tenant.MaxPlayers < 0 {
+			return fmt.Errorf("tenant %s: maxPlayers must be positive", tenant.ID)
+		}
+		for _, guildID := range tenant.Guilds {
+			if other, ok := guilds[guildID]; ok {
+				return fmt.Errorf("guild %s is in tenants %s and %s", guildID, other, tenant.ID)
+			}
+			guilds[guildID] = tenant.ID
+		}
+	}
+	return nil
+}
+
+// Find returns a tenant by its ID
+func (t Tenants) Find(id string) (Tenant, bool) {
+	for _, tenant := range t {
+		if tenant.ID == id {
+			return tenant, true
+		}
+	}
+	return Tenant{}, false
+}
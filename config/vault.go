@@ -0,0 +1,110 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultSecretRefreshInterval is the delay between two reads of the secret of a secret manager
+const DefaultSecretRefreshInterval = 10 * time.Minute
+
+// VaultConfig locates the Vault secret holding the credentials
+type VaultConfig struct {
+	Addr            string // ex: https://vault.example.com:8200
+	Token           string
+	SecretPath      string // API path of the secret, ex: secret/data/lp_tracker (KV v2) or kv/lp_tracker (KV v1)
+	RefreshInterval time.Duration
+}
+
+// VaultConfigFromEnv reads the VAULT_* environment variables, VAULT_TOKEN can be read from VAULT_TOKEN_FILE
+func VaultConfigFromEnv() (VaultConfig, error) {
+	config := VaultConfig{
+		Addr:       strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/"),
+		SecretPath: strings.Trim(os.Getenv("VAULT_SECRET_PATH"), "/"),
+	}
+
+	token, err := readSecret("VAULT_TOKEN")
+	if err != nil {
+		return config, err
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	config.Token = token
+
+	config.RefreshInterval, err = refreshIntervalFromEnv("VAULT_REFRESH_INTERVAL")
+	if err != nil {
+		return config, err
+	}
+
+	if config.Enabled() && config.Token == "" {
+		return config, fmt.Errorf("VAULT_TOKEN is required to read %s", config.SecretPath)
+	}
+	return config, nil
+}
+
+// Enabled checks if a Vault secret is configured
+func (c VaultConfig) Enabled() bool {
+	return c.Addr != "" && c.SecretPath != ""
+}
+
+// NewVault returns the secret manager reading the fields of a Vault secret named after the secrets
+func NewVault(config VaultConfig) *SecretManager {
+	client := &vaultClient{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+	return &SecretManager{
+		name:            "Vault",
+		fetch:           client.fetch,
+		refreshInterval: config.RefreshInterval,
+	}
+}
+
+type vaultClient struct {
+	config     VaultConfig
+	httpClient *http.Client
+}
+
+// fetch reads the known secrets from the fields of the Vault secret
+func (v *vaultClient) fetch(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/v1/%s", v.config.Addr, v.config.SecretPath), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.config.Token)
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from Vault: %w", v.config.SecretPath, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("failed to read %s from Vault: status %d: %s", v.config.SecretPath, resp.StatusCode, string(body))
+	}
+
+	// KV v2 nests the fields in data.data, KV v1 returns them in data
+	var secret struct {
+		Data map[string]any `json:"data"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", v.config.SecretPath, err)
+	}
+	fields := secret.Data
+	if nested, ok := fields["data"].(map[string]any); ok {
+		fields = nested
+	}
+
+	return knownSecrets(v.config.SecretPath, fields)
+}
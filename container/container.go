@@ -1,6 +1,10 @@
 package container
 
 import (
+	"context"
+	"fmt"
+
+	"lp_tracker/config"
 	"lp_tracker/database"
 	"lp_tracker/repositories"
 	"lp_tracker/services"
@@ -12,27 +16,177 @@ type Container struct {
 	DB *database.Manager
 
 	// Repositories
-	PlayerRepo *repositories.PlayerRepository
+	PlayerRepo      *repositories.PlayerRepository
+	GuildConfigRepo *repositories.GuildConfigRepository
+	LPEventRepo     *repositories.LPEventRepository
+	SnapshotRepo    *repositories.SnapshotRepository
+	CompetitionRepo *repositories.CompetitionRepository
+	AchievementRepo *repositories.AchievementRepository
+	MatchRepo       *repositories.MatchRepository
+	SharedMatchRepo *repositories.SharedMatchRepository
+	JobRepo         *repositories.JobRepository
+	FeatureFlagRepo *repositories.FeatureFlagRepository
+	PersonRepo      *repositories.PersonRepository
+	ClashRepo       *repositories.ClashRegistrationRepository
+	ClashEventRepo  *repositories.ClashEventRepository
+
+	ChallengeProgressRepo   *repositories.ChallengeProgressRepository
+	ChallengeCompletionRepo *repositories.ChallengeCompletionRepository
+	VerificationRepo        *repositories.AccountVerificationRepository
+	PendingActionRepo       *repositories.PendingActionRepository
+	APIKeyRepo              *repositories.APIKeyRepository
 
 	// Services
-	PlayerService *services.PlayerService
-	RiotService   *services.RiotService
+	PlayerService  *services.PlayerService
+	RiotService    *services.RiotService
+	RecapService   *services.RecapService
+	DataDragon     *services.DataDragonService
+	Standings      *services.StandingsService
+	Competitions   *services.CompetitionService
+	Achievements   *services.AchievementService
+	GuildConfigs   *services.GuildConfigService
+	Jobs           *services.JobService
+	EnemyRanks     *services.EnemyRankService
+	FeatureFlags   *services.FeatureFlagService
+	Persons        *services.PersonService
+	Profiles       *services.ProfileService
+	Clash          *services.ClashService
+	Challenges     *services.ChallengeService
+	Verifications  *services.VerificationService
+	PendingActions *services.PendingActionService
+	Tilt           *services.TiltService
+	Sessions       *services.SessionService
+	APIKeys        *services.APIKeyService
+
+	// Notification dedupe store, consulted before announcing rank changes
+	NotificationDedupe services.NotificationDedupeStore
+
+	// The tenant has its own Riot API key, it is not replaced by RotateRiotAPIKey
+	tenantRiotKey bool
 }
 
 // NewContainer creates and initializes all dependencies
-func NewContainer(dbManager *database.Manager, riotAPIKey string) *Container {
+func NewContainer(dbManager *database.Manager, riotAPIKey string, riotClient services.RiotClientConfig) *Container {
+	return newContainer(dbManager, services.NewRiotService(riotAPIKey, riotClient))
+}
+
+// NewTenantContainer creates the dependencies of a tenant on its own database, next to the default one of base.
+// A tenant without Riot API key shares the Riot service of base, so the rate limits of the shared key are respected.
+func NewTenantContainer(ctx context.Context, base *Container, tenant config.Tenant, riotClient services.RiotClientConfig) (*Container, error) {
+	dbManager, err := base.DB.ForDatabase(ctx, tenant.DatabaseName(base.DB.GetDatabase().Name()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the database of tenant %s: %w", tenant.ID, err)
+	}
+
+	riotService := base.RiotService
+	if tenant.RiotAPIKey != "" {
+		// The regional keys belong to the operator, the tenant key serves every region
+		riotClient.RegionalAPIKeys = nil
+		riotService = services.NewRiotService(tenant.RiotAPIKey, riotClient)
+	}
+
+	c := newContainer(dbManager, riotService)
+	c.tenantRiotKey = tenant.RiotAPIKey != ""
+	c.GetPlayerService().SetMaxPlayers(tenant.MaxPlayers)
+	return c, nil
+}
+
+func newContainer(dbManager *database.Manager, riotService *services.RiotService) *Container {
 	// Initialize repositories
 	playerRepo := repositories.NewPlayerRepository(dbManager.GetDatabase())
+	guildConfigRepo := repositories.NewGuildConfigRepository(dbManager.GetDatabase())
+	lpEventRepo := repositories.NewLPEventRepository(dbManager.GetDatabase())
+	snapshotRepo := repositories.NewSnapshotRepository(dbManager.GetDatabase())
+	competitionRepo := repositories.NewCompetitionRepository(dbManager.GetDatabase())
+	achievementRepo := repositories.NewAchievementRepository(dbManager.GetDatabase())
+	matchRepo := repositories.NewMatchRepository(dbManager.GetDatabase())
+	sharedMatchRepo := repositories.NewSharedMatchRepository(dbManager.GetDatabase())
+	jobRepo := repositories.NewJobRepository(dbManager.GetDatabase())
+	featureFlagRepo := repositories.NewFeatureFlagRepository(dbManager.GetDatabase())
+	personRepo := repositories.NewPersonRepository(dbManager.GetDatabase())
+	clashRepo := repositories.NewClashRegistrationRepository(dbManager.GetDatabase())
+	clashEventRepo := repositories.NewClashEventRepository(dbManager.GetDatabase())
+	challengeProgressRepo := repositories.NewChallengeProgressRepository(dbManager.GetDatabase())
+	challengeCompletionRepo := repositories.NewChallengeCompletionRepository(dbManager.GetDatabase())
+	verificationRepo := repositories.NewAccountVerificationRepository(dbManager.GetDatabase())
+	pendingActionRepo := repositories.NewPendingActionRepository(dbManager.GetDatabase())
+	apiKeyRepo := repositories.NewAPIKeyRepository(dbManager.GetDatabase())
+	cycleSnapshotRepo := repositories.NewCycleSnapshotRepository(dbManager.GetDatabase())
+
+	// Read-only repositories of the heavy queries (recaps, leaderboards, public profiles), on the analytics read preference
+	analyticsDB := dbManager.GetAnalyticsDatabase()
+	analyticsPlayerRepo := repositories.NewPlayerRepository(analyticsDB)
+	analyticsLPEventRepo := repositories.NewLPEventRepository(analyticsDB)
+	analyticsMatchRepo := repositories.NewMatchRepository(analyticsDB)
+	analyticsPersonRepo := repositories.NewPersonRepository(analyticsDB)
+	analyticsCompletionRepo := repositories.NewChallengeCompletionRepository(analyticsDB)
 
 	// Initialize services
-	riotService := services.NewRiotService(riotAPIKey)
-	playerService := services.NewPlayerService(playerRepo, riotAPIKey)
+	dataDragon := services.NewDataDragonService()
+	playerService := services.NewPlayerService(playerRepo, lpEventRepo, matchRepo, sharedMatchRepo, dataDragon, riotService)
+	recapService := services.NewRecapService(analyticsLPEventRepo, analyticsMatchRepo, analyticsPersonRepo, analyticsCompletionRepo)
+	standingsService := services.NewStandingsService(analyticsPlayerRepo, analyticsLPEventRepo, snapshotRepo, analyticsPersonRepo, cycleSnapshotRepo)
+	competitionService := services.NewCompetitionService(competitionRepo, lpEventRepo)
+	achievementService := services.NewAchievementService(achievementRepo, lpEventRepo)
+	guildConfigService := services.NewGuildConfigService(guildConfigRepo)
+	jobService := services.NewJobService(jobRepo)
+	enemyRankService := services.NewEnemyRankService(riotService)
+	featureFlagService := services.NewFeatureFlagService(featureFlagRepo)
+	personService := services.NewPersonService(personRepo, playerRepo)
+	profileService := services.NewProfileService(playerRepo, analyticsMatchRepo, analyticsLPEventRepo, analyticsPersonRepo)
+	clashService := services.NewClashService(clashRepo, clashEventRepo, playerRepo, riotService)
+	challengeService := services.NewChallengeService(challengeProgressRepo, challengeCompletionRepo, playerRepo, riotService)
+	pendingActionService := services.NewPendingActionService(pendingActionRepo)
+	tiltService := services.NewTiltService(playerRepo, matchRepo)
+	sessionService := services.NewSessionService(playerRepo, matchRepo, lpEventRepo)
+	verificationService := services.NewVerificationService(verificationRepo, playerRepo, riotService, pendingActionService)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo)
+	notificationDedupe := services.NewLedgerDedupeStore(matchRepo, lpEventRepo)
 
 	return &Container{
-		DB:            dbManager,
-		PlayerRepo:    playerRepo,
-		PlayerService: playerService,
-		RiotService:   riotService,
+		DB:              dbManager,
+		PlayerRepo:      playerRepo,
+		GuildConfigRepo: guildConfigRepo,
+		LPEventRepo:     lpEventRepo,
+		SnapshotRepo:    snapshotRepo,
+		CompetitionRepo: competitionRepo,
+		AchievementRepo: achievementRepo,
+		MatchRepo:       matchRepo,
+		SharedMatchRepo: sharedMatchRepo,
+		JobRepo:         jobRepo,
+		FeatureFlagRepo: featureFlagRepo,
+		PersonRepo:      personRepo,
+		ClashRepo:       clashRepo,
+		ClashEventRepo:  clashEventRepo,
+
+		ChallengeProgressRepo:   challengeProgressRepo,
+		ChallengeCompletionRepo: challengeCompletionRepo,
+		VerificationRepo:        verificationRepo,
+		PendingActionRepo:       pendingActionRepo,
+		APIKeyRepo:              apiKeyRepo,
+
+		PlayerService:  playerService,
+		RiotService:    riotService,
+		RecapService:   recapService,
+		DataDragon:     dataDragon,
+		Standings:      standingsService,
+		Competitions:   competitionService,
+		Achievements:   achievementService,
+		GuildConfigs:   guildConfigService,
+		Jobs:           jobService,
+		EnemyRanks:     enemyRankService,
+		FeatureFlags:   featureFlagService,
+		Persons:        personService,
+		Profiles:       profileService,
+		Clash:          clashService,
+		Challenges:     challengeService,
+		Verifications:  verificationService,
+		PendingActions: pendingActionService,
+		Tilt:           tiltService,
+		Sessions:       sessionService,
+		APIKeys:        apiKeyService,
+
+		NotificationDedupe: notificationDedupe,
 	}
 }
 
@@ -46,7 +200,121 @@ func (c *Container) GetRiotService() *services.RiotService {
 	return c.RiotService
 }
 
+// RotateRiotAPIKey replaces the shared Riot API key (RIOT_API_KEY) after a rotation, the tenants
+// with their own key keep it
+func (c *Container) RotateRiotAPIKey(apiKey string) {
+	if c.tenantRiotKey {
+		return
+	}
+	c.RiotService.SetAPIKey(apiKey)
+}
+
+// GetRecapService returns the recap service
+func (c *Container) GetRecapService() *services.RecapService {
+	return c.RecapService
+}
+
+// GetDataDragonService returns the Data Dragon service
+func (c *Container) GetDataDragonService() *services.DataDragonService {
+	return c.DataDragon
+}
+
+// GetStandingsService returns the standings service
+func (c *Container) GetStandingsService() *services.StandingsService {
+	return c.Standings
+}
+
+// GetCompetitionService returns the competition service
+func (c *Container) GetCompetitionService() *services.CompetitionService {
+	return c.Competitions
+}
+
+// GetAchievementService returns the achievement service
+func (c *Container) GetAchievementService() *services.AchievementService {
+	return c.Achievements
+}
+
+// GetGuildConfigService returns the guild config service
+func (c *Container) GetGuildConfigService() *services.GuildConfigService {
+	return c.GuildConfigs
+}
+
+// GetJobService returns the background job service
+func (c *Container) GetJobService() *services.JobService {
+	return c.Jobs
+}
+
+// GetEnemyRankService returns the enemy team rank service
+func (c *Container) GetEnemyRankService() *services.EnemyRankService {
+	return c.EnemyRanks
+}
+
+// GetFeatureFlagService returns the feature flag service
+func (c *Container) GetFeatureFlagService() *services.FeatureFlagService {
+	return c.FeatureFlags
+}
+
+// GetPersonService returns the person (accounts grouping) service
+func (c *Container) GetPersonService() *services.PersonService {
+	return c.Persons
+}
+
+// GetProfileService returns the public profile pages service
+func (c *Container) GetProfileService() *services.ProfileService {
+	return c.Profiles
+}
+
+// GetClashService returns the Clash registrations service
+func (c *Container) GetClashService() *services.ClashService {
+	return c.Clash
+}
+
+// GetChallengeService returns the challenges service
+func (c *Container) GetChallengeService() *services.ChallengeService {
+	return c.Challenges
+}
+
+// GetVerificationService returns the account verification service
+func (c *Container) GetVerificationService() *services.VerificationService {
+	return c.Verifications
+}
+
+// GetPendingActionService returns the queue of the actions waiting for a user confirmation
+func (c *Container) GetPendingActionService() *services.PendingActionService {
+	return c.PendingActions
+}
+
+// GetTiltService returns the tilt detection service
+func (c *Container) GetTiltService() *services.TiltService {
+	return c.Tilt
+}
+
+// GetSessionService returns the play sessions service
+func (c *Container) GetSessionService() *services.SessionService {
+	return c.Sessions
+}
+
+// GetAPIKeyService returns the REST API key service
+func (c *Container) GetAPIKeyService() *services.APIKeyService {
+	return c.APIKeys
+}
+
+// GetNotificationDedupeStore returns the store remembering the announced rank changes
+func (c *Container) GetNotificationDedupeStore() services.NotificationDedupeStore {
+	return c.NotificationDedupe
+}
+
 // GetPlayerRepository returns the player repository
 func (c *Container) GetPlayerRepository() *repositories.PlayerRepository {
 	return c.PlayerRepo
 }
+
+// GetGuildConfigRepository returns the guild config repository
+func (c *Container) GetGuildConfigRepository() *repositories.GuildConfigRepository {
+	return c.GuildConfigRepo
+}
+
+// GetLPEventRepository returns the LP event repository
+func (c *Container) GetLPEventRepository() *repositories.LPEventRepository {
+	return c.LPEventRepo
+}
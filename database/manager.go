@@ -4,22 +4,42 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type Manager struct {
-	client   *mongo.Client
-	database *mongo.Database
+	client    *mongo.Client
+	database  *mongo.Database
+	analytics *mongo.Database // Same database, read with the analytics read preference
+
+	analyticsReadPref *readpref.ReadPref // Nil to read everything from the primary
+
+	// Periodic index usage reports, stopped by Close
+	stopReports context.CancelFunc
+	reports     sync.WaitGroup
 }
 
 type Config struct {
 	URI          string
 	DatabaseName string
 	Timeout      time.Duration
+
+	// Read preference of the analytics queries (recaps, champion stats, leaderboards), ex: "secondaryPreferred"
+	// to keep the heavy aggregations off the primary of a replica set. Writes always go to the primary.
+	// Empty to read everything from the primary.
+	AnalyticsReadPreference string
+
+	// Commands slower than this are logged with their filter or pipeline, 0 to disable
+	SlowQueryThreshold time.Duration
+
+	// Period of the index usage reports in the logs, 0 to disable
+	IndexStatsInterval time.Duration
 }
 
 func NewManager(config Config) (*Manager, error) {
@@ -27,6 +47,18 @@ func NewManager(config Config) (*Manager, error) {
 		config.Timeout = 10 * time.Second
 	}
 
+	var analyticsReadPref *readpref.ReadPref
+	if config.AnalyticsReadPreference != "" {
+		mode, err := readpref.ModeFromString(config.AnalyticsReadPreference)
+		if err != nil {
+			return nil, fmt.Errorf("invalid analytics read preference: %w", err)
+		}
+		analyticsReadPref, err = readpref.New(mode)
+		if err != nil {
+			return nil, fmt.Errorf("invalid analytics read preference: %w", err)
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
 	defer cancel()
 
@@ -35,7 +67,8 @@ func NewManager(config Config) (*Manager, error) {
 		ApplyURI(config.URI).
 		SetMaxPoolSize(100).
 		SetMaxConnIdleTime(30 * time.Second).
-		SetConnectTimeout(config.Timeout)
+		SetConnectTimeout(config.Timeout).
+		SetMonitor(newCommandMonitor(config.SlowQueryThreshold)) // Traces every command when tracing is enabled
 
 	// Connect to MongoDB
 	client, err := mongo.Connect(ctx, clientOptions)
@@ -49,12 +82,7 @@ func NewManager(config Config) (*Manager, error) {
 		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
-	database := client.Database(config.DatabaseName)
-
-	manager := &Manager{
-		client:   client,
-		database: database,
-	}
+	manager := newManager(client, config.DatabaseName, analyticsReadPref)
 
 	// Create indexes
 	err = manager.createIndexes(ctx)
@@ -62,19 +90,77 @@ func NewManager(config Config) (*Manager, error) {
 		log.Printf("Warning: Failed to create indexes: %v", err)
 	}
 
+	// Apply pending data migrations
+	err = manager.runMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.IndexStatsInterval > 0 {
+		reportCtx, stop := context.WithCancel(context.Background())
+		manager.stopReports = stop
+		manager.reports.Add(1)
+		go func() {
+			defer manager.reports.Done()
+			manager.reportIndexUsage(reportCtx, config.IndexStatsInterval)
+		}()
+	}
+
 	log.Printf("Successfully connected to MongoDB database: %s", config.DatabaseName)
 	return manager, nil
 }
 
+// ForDatabase returns a manager of another database of the same server (ex: a tenant database), sharing the connection.
+// Its indexes and migrations are applied like on startup. Only the manager created by NewManager is closed.
+func (m *Manager) ForDatabase(ctx context.Context, name string) (*Manager, error) {
+	manager := newManager(m.client, name, m.analyticsReadPref)
+
+	err := manager.createIndexes(ctx)
+	if err != nil {
+		log.Printf("Warning: Failed to create indexes of %s: %v", name, err)
+	}
+
+	err = manager.runMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager, nil
+}
+
+func newManager(client *mongo.Client, name string, analyticsReadPref *readpref.ReadPref) *Manager {
+	manager := &Manager{
+		client:            client,
+		database:          client.Database(name),
+		analyticsReadPref: analyticsReadPref,
+	}
+
+	manager.analytics = manager.database
+	if analyticsReadPref != nil {
+		manager.analytics = client.Database(name, options.Database().SetReadPreference(analyticsReadPref))
+	}
+	return manager
+}
+
 func (m *Manager) GetDatabase() *mongo.Database {
 	return m.database
 }
 
+// GetAnalyticsDatabase returns the database for the heavy read-only queries, read with the analytics read preference.
+// Reads may lag behind the writes on a secondary: never read back a document just written through it.
+func (m *Manager) GetAnalyticsDatabase() *mongo.Database {
+	return m.analytics
+}
+
 func (m *Manager) GetClient() *mongo.Client {
 	return m.client
 }
 
 func (m *Manager) Close(ctx context.Context) error {
+	if m.stopReports != nil {
+		m.stopReports()
+		m.reports.Wait()
+	}
 	return m.client.Disconnect(ctx)
 }
 
@@ -106,6 +192,11 @@ func (m *Manager) createIndexes(ctx context.Context) error {
 				{Key: "server", Value: 1},
 			},
 		},
+		{
+			Keys: bson.D{
+				{Key: "tags", Value: 1},
+			},
+		},
 	}
 
 	_, err := playersCollection.Indexes().CreateMany(ctx, playerIndexes)
@@ -113,6 +204,380 @@ func (m *Manager) createIndexes(ctx context.Context) error {
 		return fmt.Errorf("failed to create player indexes: %w", err)
 	}
 
+	// Create indexes for guild_configs collection
+	guildConfigsCollection := m.database.Collection("guild_configs")
+
+	guildConfigIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "guildId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err = guildConfigsCollection.Indexes().CreateMany(ctx, guildConfigIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create guild config indexes: %w", err)
+	}
+
+	// Create indexes for lp_events collection
+	lpEventsCollection := m.database.Collection("lp_events")
+
+	lpEventIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "createdAt", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "playerPuuid", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+		},
+	}
+
+	_, err = lpEventsCollection.Indexes().CreateMany(ctx, lpEventIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create LP event indexes: %w", err)
+	}
+
+	// Create indexes for leaderboard_snapshots collection
+	snapshotsCollection := m.database.Collection("leaderboard_snapshots")
+
+	snapshotIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "guildId", Value: 1},
+				{Key: "name", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err = snapshotsCollection.Indexes().CreateMany(ctx, snapshotIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot indexes: %w", err)
+	}
+
+	// Create indexes for cycle_snapshots collection
+	cycleSnapshotsCollection := m.database.Collection("cycle_snapshots")
+
+	cycleSnapshotIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "cycle", Value: -1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err = cycleSnapshotsCollection.Indexes().CreateMany(ctx, cycleSnapshotIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create cycle snapshot indexes: %w", err)
+	}
+
+	// Create indexes for competitions collection
+	competitionsCollection := m.database.Collection("competitions")
+
+	competitionIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "guildId", Value: 1},
+				{Key: "startAt", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "endAt", Value: 1},
+			},
+		},
+	}
+
+	_, err = competitionsCollection.Indexes().CreateMany(ctx, competitionIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create competition indexes: %w", err)
+	}
+
+	// Create indexes for player_achievements collection
+	achievementsCollection := m.database.Collection("player_achievements")
+
+	achievementIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "playerPuuid", Value: 1},
+				{Key: "achievementId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err = achievementsCollection.Indexes().CreateMany(ctx, achievementIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create achievement indexes: %w", err)
+	}
+
+	// Create indexes for player_matches collection
+	matchesCollection := m.database.Collection("player_matches")
+
+	matchIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "player_puuid", Value: 1},
+				{Key: "match_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "player_puuid", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+	}
+
+	_, err = matchesCollection.Indexes().CreateMany(ctx, matchIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create match indexes: %w", err)
+	}
+
+	// Create indexes for matches collection (data shared by the participants of a game)
+	sharedMatchesCollection := m.database.Collection("matches")
+
+	sharedMatchIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "matchId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "patch", Value: 1},
+			},
+		},
+	}
+
+	_, err = sharedMatchesCollection.Indexes().CreateMany(ctx, sharedMatchIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create shared match indexes: %w", err)
+	}
+
+	// Create indexes for jobs collection
+	jobsCollection := m.database.Collection("jobs")
+
+	jobIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "guildId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+			},
+		},
+	}
+
+	_, err = jobsCollection.Indexes().CreateMany(ctx, jobIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create job indexes: %w", err)
+	}
+
+	// Create indexes for feature_flags collection
+	featureFlagsCollection := m.database.Collection("feature_flags")
+
+	featureFlagIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "name", Value: 1},
+				{Key: "guildId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err = featureFlagsCollection.Indexes().CreateMany(ctx, featureFlagIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create feature flag indexes: %w", err)
+	}
+
+	// Create indexes for persons collection
+	personsCollection := m.database.Collection("persons")
+
+	personIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "name", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "playerPuuids", Value: 1},
+			},
+		},
+	}
+
+	_, err = personsCollection.Indexes().CreateMany(ctx, personIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create person indexes: %w", err)
+	}
+
+	// Create indexes for clash_registrations collection
+	clashCollection := m.database.Collection("clash_registrations")
+
+	clashIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "playerPuuid", Value: 1},
+				{Key: "tournamentId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "startTime", Value: 1},
+			},
+		},
+	}
+
+	_, err = clashCollection.Indexes().CreateMany(ctx, clashIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create clash registration indexes: %w", err)
+	}
+
+	// Create indexes for clash_events collection
+	clashEventsCollection := m.database.Collection("clash_events")
+
+	clashEventIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "guildId", Value: 1},
+				{Key: "server", Value: 1},
+				{Key: "tournamentId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err = clashEventsCollection.Indexes().CreateMany(ctx, clashEventIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create clash event indexes: %w", err)
+	}
+
+	// Create indexes for challenge_progress collection
+	challengeProgressCollection := m.database.Collection("challenge_progress")
+
+	challengeProgressIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "playerPuuid", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err = challengeProgressCollection.Indexes().CreateMany(ctx, challengeProgressIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create challenge progress indexes: %w", err)
+	}
+
+	// Create indexes for challenge_completions collection
+	challengeCompletionsCollection := m.database.Collection("challenge_completions")
+
+	challengeCompletionIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "playerPuuid", Value: 1},
+				{Key: "challengeId", Value: 1},
+				{Key: "level", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "achievedAt", Value: 1}},
+		},
+	}
+
+	_, err = challengeCompletionsCollection.Indexes().CreateMany(ctx, challengeCompletionIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create challenge completion indexes: %w", err)
+	}
+
+	// Create indexes for account_verifications collection
+	accountVerificationsCollection := m.database.Collection("account_verifications")
+
+	accountVerificationIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "discordUserId", Value: 1},
+				{Key: "status", Value: 1},
+			},
+		},
+		{
+			// An account is owned by a single Discord user
+			Keys: bson.D{{Key: "playerPuuid", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": "verified"}),
+		},
+	}
+
+	_, err = accountVerificationsCollection.Indexes().CreateMany(ctx, accountVerificationIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create account verification indexes: %w", err)
+	}
+
+	// Create indexes for pending_actions collection
+	pendingActionsCollection := m.database.Collection("pending_actions")
+
+	pendingActionIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "nextCheckAt", Value: 1}},
+		},
+		{
+			Keys: bson.D{
+				{Key: "discordUserId", Value: 1},
+				{Key: "kind", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "kind", Value: 1},
+				{Key: "refId", Value: 1},
+			},
+		},
+		{
+			// Safety net when the poller is down, the queue closes the expired actions itself
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32((24 * time.Hour).Seconds())),
+		},
+	}
+
+	_, err = pendingActionsCollection.Indexes().CreateMany(ctx, pendingActionIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create pending action indexes: %w", err)
+	}
+
+	// Create indexes for api_keys collection
+	apiKeysCollection := m.database.Collection("api_keys")
+
+	apiKeyIndexes := []mongo.IndexModel{
+		{
+			// Every request looks the key up by its hash
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Keys are revoked by name
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err = apiKeysCollection.Indexes().CreateMany(ctx, apiKeyIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create API key indexes: %w", err)
+	}
+
 	log.Println("Successfully created database indexes")
 	return nil
 }
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// migration is a one-shot data change applied once per database
+type migration struct {
+	ID  string
+	Run func(ctx context.Context, db *mongo.Database) error
+}
+
+// migrations are applied in order, the applied IDs are recorded in the migrations collection.
+// Migrations must be idempotent: the poller and the commands listener can run them concurrently.
+var migrations = []migration{
+	{ID: "0001_int64_counters", Run: migrateInt64Counters},
+}
+
+func (m *Manager) runMigrations(ctx context.Context) error {
+	collection := m.database.Collection("migrations")
+
+	for _, migration := range migrations {
+		count, err := collection.CountDocuments(ctx, bson.M{"_id": migration.ID})
+		if err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", migration.ID, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		err = migration.Run(ctx, m.database)
+		if err != nil {
+			return fmt.Errorf("failed to run migration %s: %w", migration.ID, err)
+		}
+
+		_, err = collection.InsertOne(ctx, bson.M{"_id": migration.ID, "appliedAt": time.Now()})
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to record migration %s: %w", migration.ID, err)
+		}
+		log.Printf("Applied database migration %s", migration.ID)
+	}
+
+	return nil
+}
+
+// migrateInt64Counters converts the 64-bit Riot values stored as 32-bit integers before the models
+// used int64 (the driver encodes Go int as int32 when the value fits)
+func migrateInt64Counters(ctx context.Context, db *mongo.Database) error {
+	fields := []struct {
+		collection string
+		field      string
+	}{
+		{"players", "summonerLevel"},
+		{"player_matches", "game_duration"},
+		{"matches", "gameDuration"},
+	}
+
+	for _, f := range fields {
+		filter := bson.M{f.field: bson.M{"$type": "int"}}
+		update := mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{f.field: bson.M{"$toLong": "$" + f.field}}}},
+		}
+
+		_, err := db.Collection(f.collection).UpdateMany(ctx, filter, update)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s.%s: %w", f.collection, f.field, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+)
+
+// Longest command logged with a slow query, the rest is cut
+const maxSlowQueryLength = 512
+
+// Fields of the commands added by the driver, they say nothing about the query
+var commandNoiseFields = map[string]bool{
+	"lsid":            true,
+	"txnNumber":       true,
+	"$clusterTime":    true,
+	"$db":             true,
+	"$readPreference": true,
+	"readConcern":     true,
+	"writeConcern":    true,
+	"apiVersion":      true,
+}
+
+// Commands of the driver itself (handshakes, pings, sessions), never slow queries
+var monitoringCommands = map[string]bool{
+	"hello":            true,
+	"isMaster":         true,
+	"ping":             true,
+	"endSessions":      true,
+	"killCursors":      true,
+	"saslStart":        true,
+	"saslContinue":     true,
+	"buildInfo":        true,
+	"getLastError":     true,
+	"abortTransaction": true,
+}
+
+// slowQueryMonitor logs the commands slower than a threshold, with the shape of their filter or
+// pipeline, to spot the queries missing an index as the collections grow
+type slowQueryMonitor struct {
+	threshold time.Duration
+	commands  sync.Map // Request ID -> startedCommand, until the command finishes
+}
+
+type startedCommand struct {
+	collection string
+	command    string
+}
+
+// newCommandMonitor traces every command when tracing is enabled, and logs the slow ones when threshold is positive
+func newCommandMonitor(threshold time.Duration) *event.CommandMonitor {
+	tracing := otelmongo.NewMonitor()
+	if threshold <= 0 {
+		return tracing
+	}
+
+	slow := &slowQueryMonitor{threshold: threshold}
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			tracing.Started(ctx, evt)
+			slow.started(evt)
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			tracing.Succeeded(ctx, evt)
+			slow.finished(evt.CommandFinishedEvent, "")
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			tracing.Failed(ctx, evt)
+			slow.finished(evt.CommandFinishedEvent, evt.Failure)
+		},
+	}
+}
+
+func (m *slowQueryMonitor) started(evt *event.CommandStartedEvent) {
+	if monitoringCommands[evt.CommandName] {
+		return
+	}
+
+	// The first field of a command is its name, with the collection as value (ex: {find: "players", filter: ...})
+	collection := ""
+	if first, err := evt.Command.IndexErr(0); err == nil {
+		collection, _ = first.Value().StringValueOK()
+	}
+	m.commands.Store(evt.RequestID, startedCommand{collection: collection, command: summarizeCommand(evt.Command)})
+}
+
+func (m *slowQueryMonitor) finished(evt event.CommandFinishedEvent, failure string) {
+	value, ok := m.commands.LoadAndDelete(evt.RequestID)
+	if !ok || evt.Duration < m.threshold {
+		return
+	}
+	command := value.(startedCommand)
+
+	status := ""
+	if failure != "" {
+		status = fmt.Sprintf(" (failed: %s)", failure)
+	}
+	log.Printf("🐢 Slow MongoDB %s on %s.%s: %v%s %s",
+		evt.CommandName, evt.DatabaseName, command.collection, evt.Duration.Round(time.Millisecond), status, command.command)
+}
+
+// summarizeCommand renders a command as extended JSON without the fields added by the driver, cut to
+// maxSlowQueryLength. The values are redacted (see redactValue), only the collection is kept.
+func summarizeCommand(command bson.Raw) string {
+	elements, err := command.Elements()
+	if err != nil {
+		return ""
+	}
+
+	summary := bson.D{}
+	for idx, element := range elements {
+		if commandNoiseFields[element.Key()] {
+			continue
+		}
+		if idx == 0 {
+			summary = append(summary, bson.E{Key: element.Key(), Value: element.Value()})
+			continue
+		}
+		summary = append(summary, bson.E{Key: element.Key(), Value: redactValue(element.Value())})
+	}
+
+	content, err := bson.MarshalExtJSON(summary, false, false)
+	if err != nil {
+		return ""
+	}
+	if len(content) > maxSlowQueryLength {
+		return string(content[:maxSlowQueryLength]) + "…"
+	}
+	return string(content)
+}
+
+// redactValue keeps the shape of a query (fields, operators and field paths such as "$role") and
+// replaces the values with "?", the filters and inserted or updated documents hold player data
+func redactValue(value bson.RawValue) interface{} {
+	switch value.Type {
+	case bson.TypeEmbeddedDocument:
+		elements, _ := value.Document().Elements()
+		document := bson.D{}
+		for _, element := range elements {
+			document = append(document, bson.E{Key: element.Key(), Value: redactValue(element.Value())})
+		}
+		return document
+	case bson.TypeArray:
+		values, _ := value.Array().Values()
+		array := bson.A{}
+		for _, item := range values {
+			array = append(array, redactValue(item))
+		}
+		return array
+	case bson.TypeString:
+		if path := value.StringValue(); strings.HasPrefix(path, "$") {
+			return path
+		}
+	}
+	return "?"
+}
+
+// IndexUsage is the number of queries using an index since the MongoDB server started (or the index was created)
+type IndexUsage struct {
+	Collection string
+	Index      string
+	Ops        int64
+	Since      time.Time
+}
+
+// IndexUsage reads the usage of the indexes of every collection ($indexStats), most used first per collection.
+// On a replica set, the counters are the ones of the member serving the read.
+func (m *Manager) IndexUsage(ctx context.Context) ([]IndexUsage, error) {
+	collections, err := m.database.ListCollectionNames(ctx, bson.M{"type": "collection"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	sort.Strings(collections)
+
+	var usage []IndexUsage
+	for _, collection := range collections {
+		cursor, err := m.database.Collection(collection).Aggregate(ctx, mongo.Pipeline{{{Key: "$indexStats", Value: bson.M{}}}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read index stats of %s: %w", collection, err)
+		}
+
+		var stats []struct {
+			Name     string `bson:"name"`
+			Accesses struct {
+				Ops   int64     `bson:"ops"`
+				Since time.Time `bson:"since"`
+			} `bson:"accesses"`
+		}
+		err = cursor.All(ctx, &stats)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode index stats of %s: %w", collection, err)
+		}
+
+		sort.Slice(stats, func(a, b int) bool { return stats[a].Accesses.Ops > stats[b].Accesses.Ops })
+		for _, stat := range stats {
+			usage = append(usage, IndexUsage{Collection: collection, Index: stat.Name, Ops: stat.Accesses.Ops, Since: stat.Accesses.Since})
+		}
+	}
+	return usage, nil
+}
+
+// reportIndexUsage logs the index usage every interval until ctx is cancelled, the unused indexes are flagged:
+// they slow down the writes for nothing, or a query expected to use them does not
+func (m *Manager) reportIndexUsage(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reportCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		usage, err := m.IndexUsage(reportCtx)
+		cancel()
+		if err != nil {
+			log.Printf("Warning: Failed to read the index usage: %v", err)
+			continue
+		}
+
+		byCollection := make(map[string][]string)
+		var collections []string
+		for _, index := range usage {
+			if _, ok := byCollection[index.Collection]; !ok {
+				collections = append(collections, index.Collection)
+			}
+			entry := fmt.Sprintf("%s %d ops", index.Index, index.Ops)
+			if index.Ops == 0 && index.Index != "_id_" {
+				entry += fmt.Sprintf(" (unused since %s)", index.Since.Format(time.DateOnly))
+			}
+			byCollection[index.Collection] = append(byCollection[index.Collection], entry)
+		}
+
+		log.Printf("📇 Index usage of %s:", m.database.Name())
+		for _, collection := range collections {
+			log.Printf("📇   %s: %s", collection, strings.Join(byCollection[collection], ", "))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSummarizeCommandRedactsValues(t *testing.T) {
+	for name, command := range map[string]bson.D{
+		"find": {
+			{Key: "find", Value: "players"},
+			{Key: "filter", Value: bson.D{{Key: "puuid", Value: "secret-puuid"}, {Key: "tier", Value: bson.D{{Key: "$in", Value: bson.A{"GOLD", "PLATINUM"}}}}}},
+			{Key: "lsid", Value: bson.D{{Key: "id", Value: "session"}}},
+		},
+		"insert": {
+			{Key: "insert", Value: "tenants"},
+			{Key: "documents", Value: bson.A{bson.D{{Key: "riotApiKey", Value: "RGAPI-secret"}, {Key: "maxPlayers", Value: 50}}}},
+		},
+	} {
+		raw, err := bson.Marshal(command)
+		if err != nil {
+			t.Fatal(err)
+		}
+		summary := summarizeCommand(raw)
+
+		for _, secret := range []string{"secret", "GOLD", "50", "lsid"} {
+			if strings.Contains(summary, secret) {
+				t.Errorf("%s: summary %s contains %q", name, summary, secret)
+			}
+		}
+		// The collection, the fields and the field paths stay to spot the missing indexes
+		for _, kept := range []string{`"` + command[0].Key + `":"` + command[0].Value.(string) + `"`, `"?"`} {
+			if !strings.Contains(summary, kept) {
+				t.Errorf("%s: summary %s without %s", name, summary, kept)
+			}
+		}
+	}
+
+	// Field paths are not data
+	raw, _ := bson.Marshal(bson.D{{Key: "aggregate", Value: "matches"}, {Key: "pipeline", Value: bson.A{bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$role"}}}}}}})
+	if summary := summarizeCommand(raw); !strings.Contains(summary, `"$role"`) {
+		t.Errorf("summary %s without the field path of the group", summary)
+	}
+}
@@ -0,0 +1,60 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleAchievementsAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	pseudo, tagline, server := playerIdentity(optionsByName(i.ApplicationCommandData().Options))
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	earned, err := h.achievementService.GetPlayerAchievements(ctx, player.PUUID)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch achievements: %v", err))
+		log.Printf("Error fetching achievements of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	h.sendFollowUp(s, i, formatAchievements(player, earned))
+}
+
+func formatAchievements(player *models.Player, earned []*models.PlayerAchievement) string {
+	earnedAt := make(map[string]time.Time, len(earned))
+	for _, achievement := range earned {
+		earnedAt[achievement.AchievementID] = achievement.EarnedAt
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("🏅 **Achievements of %s#%s** (%d/%d)\n\n",
+		player.GameName, player.TagLine, len(earned), len(models.Achievements)))
+
+	for _, achievement := range models.Achievements {
+		if at, ok := earnedAt[achievement.ID]; ok {
+			response.WriteString(fmt.Sprintf("%s **%s** • %s (<t:%d:d>)\n", achievement.Emoji, achievement.Name, achievement.Description, at.Unix()))
+		} else {
+			response.WriteString(fmt.Sprintf("🔒 ~~%s~~ • %s\n", achievement.Name, achievement.Description))
+		}
+	}
+
+	return response.String()
+}
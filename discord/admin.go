@@ -0,0 +1,321 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SetOwnerID sets the Discord user allowed to use /admin and /purge_inactive, both commands are
+// disabled without owner
+func (h *CommandHandler) SetOwnerID(ownerID string) {
+	h.ownerID = ownerID
+}
+
+// isBotOwner checks if the user of an interaction is the bot owner
+func (h *CommandHandler) isBotOwner(i *discordgo.InteractionCreate) bool {
+	return h.ownerID != "" && interactionUserID(i) == h.ownerID
+}
+
+// ownerOnlyCommands are hidden from /help for everyone but the bot owner
+var ownerOnlyCommands = map[string]bool{
+	"admin":          true,
+	"purge_inactive": true, // Players are shared by every server
+}
+
+// SyncCommands replaces the registered slash commands with the current ones, dropping the removed commands
+func (h *CommandHandler) SyncCommands(s *discordgo.Session) (int, error) {
+	registered, err := s.ApplicationCommandBulkOverwrite(s.State.User.ID, "", commands)
+	if err != nil {
+		return 0, fmt.Errorf("failed to overwrite commands: %w", err)
+	}
+	return len(registered), nil
+}
+
+func (h *CommandHandler) handleAdminAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if h.ownerID == "" {
+		h.sendFollowUp(s, i, "❌ `/admin` is disabled, set `BOT_OWNER_ID` to enable it")
+		return
+	}
+	if !h.isBotOwner(i) {
+		h.sendFollowUp(s, i, "❌ This command is restricted to the bot owner")
+		log.Printf("Rejected /admin from user %s", interactionUserID(i))
+		return
+	}
+
+	subcommand := i.ApplicationCommandData().Options[0]
+	options := optionsByName(subcommand.Options)
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	switch subcommand.Name {
+	case "status":
+		h.sendFollowUp(s, i, h.adminStatus(ctx))
+	case "cache_stats":
+		h.sendFollowUp(s, i, h.adminCacheStats())
+	case "rate_limits":
+		h.sendFollowUp(s, i, h.adminRateLimits())
+	case "payloads":
+		h.sendFollowUp(s, i, h.adminPayloads())
+	case "poll_now":
+		h.sendFollowUp(s, i, h.adminPollNow(ctx, options))
+	case "resync_commands":
+		count, err := h.SyncCommands(s)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to resync commands: %v", err))
+			log.Printf("Error resyncing commands: %v", err)
+			return
+		}
+		h.sendFollowUp(s, i, fmt.Sprintf("✅ %d slash commands registered", count))
+	case "reload_config":
+		h.guildConfigService.InvalidateAll()
+		h.dataDragon.ClearCache()
+		err := h.featureFlags.Reload(ctx)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("⚠️ Caches dropped but the feature flags could not be reloaded: %v", err))
+			return
+		}
+		h.sendFollowUp(s, i, "✅ Guild configs, feature flags and champion data will be read again")
+	case "riot_debug":
+		enabled := options["enabled"].BoolValue()
+		h.container.GetRiotService().SetDebug(enabled)
+		if enabled {
+			h.sendFollowUp(s, i, "🐞 Riot API calls of the commands listener are logged, disable it once done (bodies can contain player data)")
+			return
+		}
+		h.sendFollowUp(s, i, "✅ Riot API debug logging disabled")
+	case "api_key_create":
+		h.sendFollowUp(s, i, h.adminCreateAPIKey(ctx, i, options))
+	case "api_key_list":
+		h.sendFollowUp(s, i, h.adminListAPIKeys(ctx))
+	case "api_key_revoke":
+		name := options["name"].StringValue()
+		revoked, err := h.container.GetAPIKeyService().RevokeKey(ctx, name)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to revoke the key: %v", err))
+			log.Printf("Error revoking API key %s: %v", name, err)
+			return
+		}
+		if !revoked {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ No API key named **%s**", name))
+			return
+		}
+		h.sendFollowUp(s, i, fmt.Sprintf("✅ API key **%s** revoked", name))
+	default:
+		h.sendFollowUp(s, i, "❌ Unknown subcommand")
+	}
+}
+
+func (h *CommandHandler) adminStatus(ctx context.Context) string {
+	total, active, avgTime, failedFollowUps := h.GetStats()
+
+	lastPoll := "never"
+	lastPollAt, err := h.playerService.GetLastPollAt(ctx)
+	if err != nil {
+		lastPoll = fmt.Sprintf("unknown (%v)", err)
+	} else if lastPollAt != nil {
+		lastPoll = fmt.Sprintf("<t:%d:R>", lastPollAt.Unix())
+	}
+
+	return fmt.Sprintf("🩺 **Commands listener**\n⏱️ Up since <t:%d:R> • %d goroutines\n📊 %d commands, %d active, %v average, %d failed followups\n🔄 **Poller** last updated a player %s",
+		h.startedAt.Unix(), runtime.NumGoroutine(), total, active, avgTime.Round(time.Millisecond), failedFollowUps, lastPoll)
+}
+
+func (h *CommandHandler) adminCreateAPIKey(ctx context.Context, i *discordgo.InteractionCreate, options map[string]*discordgo.ApplicationCommandInteractionDataOption) string {
+	rateLimit := 0
+	if opt, ok := options["rate_limit"]; ok {
+		rateLimit = int(opt.IntValue())
+	}
+
+	scope := models.APIKeyScope(options["scope"].StringValue())
+	key, secret, err := h.container.GetAPIKeyService().CreateKey(ctx, options["name"].StringValue(), scope, rateLimit, interactionUserID(i))
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to create the key: %v", err)
+	}
+
+	log.Printf("🔑 API key %s (%s) created", key.Name, key.Scope)
+	return fmt.Sprintf("🔑 API key **%s** created (%s scope, %d requests per minute)\n```\n%s\n```\n⚠️ Copy it now, it is not stored and can't be shown again. Send it as `Authorization: Bearer <key>`.",
+		key.Name, key.Scope, key.RateLimit, secret)
+}
+
+func (h *CommandHandler) adminListAPIKeys(ctx context.Context) string {
+	keys, err := h.container.GetAPIKeyService().ListKeys(ctx)
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to fetch the API keys: %v", err)
+	}
+	if len(keys) == 0 {
+		return "🔑 No API key, create one with `/admin api_key_create`"
+	}
+
+	var message strings.Builder
+	message.WriteString("🔑 **REST API keys**\n")
+	for _, key := range keys {
+		lastUse := "never used"
+		if key.LastUsedAt != nil {
+			lastUse = fmt.Sprintf("used <t:%d:R>", key.LastUsedAt.Unix())
+		}
+		message.WriteString(fmt.Sprintf("**%s** `%s…` • %s • %d/min • created <t:%d:R> • %s\n",
+			key.Name, key.Prefix, key.Scope, key.RateLimit, key.CreatedAt.Unix(), lastUse))
+	}
+	return message.String()
+}
+
+func (h *CommandHandler) adminCacheStats() string {
+	languages := h.dataDragon.CachedLanguages()
+	cachedLanguages := "none"
+	if len(languages) > 0 {
+		cachedLanguages = strings.Join(languages, ", ")
+	}
+
+	return fmt.Sprintf("🗄️ **Caches of the commands listener**\n⚙️ Guild configs: %d\n🚩 Feature flag values: %d\n🧙 Champion data: %s\n🔁 Interaction IDs (dedupe): %d",
+		h.guildConfigService.CacheSize(), h.featureFlags.CacheSize(), cachedLanguages, h.dedupe.size())
+}
+
+func (h *CommandHandler) adminRateLimits() string {
+	riot := h.container.GetRiotService()
+	states := riot.RateLimitStates()
+
+	var message strings.Builder
+	message.WriteString("🚦 **Riot API usage of the commands listener**")
+	requests := int64(0)
+	for _, group := range append([]string{"default"}, riot.KeyGroups()...) {
+		state := states[group]
+		requests += state.Requests
+		if state.Requests == 0 {
+			continue
+		}
+
+		// The key name is only shown when several keys are configured
+		if len(states) > 1 {
+			message.WriteString(fmt.Sprintf("\n🔑 **%s key**", group))
+		}
+		message.WriteString(fmt.Sprintf("\n📨 %d requests, %d rate limited\n📱 App: %s (limits %s)\n🔧 Last method: %s (limits %s)\n🕒 Updated <t:%d:R>",
+			state.Requests, state.RateLimited, valueOrNone(state.AppCount), valueOrNone(state.AppLimit),
+			valueOrNone(state.MethodCount), valueOrNone(state.MethodLimit), state.UpdatedAt.Unix()))
+		if !state.LastRateLimitedAt.IsZero() {
+			message.WriteString(fmt.Sprintf("\n⛔ Last rate limited <t:%d:R> (retry after %ss)", state.LastRateLimitedAt.Unix(), valueOrNone(state.RetryAfter)))
+		}
+	}
+	if requests == 0 {
+		return "🚦 No Riot API request sent by the commands listener yet"
+	}
+
+	connections := riot.ConnectionStats()
+	message.WriteString(fmt.Sprintf("\n🔌 Connections: %.0f%% reused (%d new, %d TLS handshakes, %d HTTP/2)",
+		connections.ReuseRatio(), connections.New, connections.TLSHandshakes, connections.HTTP2))
+	return message.String()
+}
+
+func (h *CommandHandler) adminPayloads() string {
+	stats := h.container.GetRiotService().PayloadStats()
+	if len(stats) == 0 {
+		return "📦 No Riot API response received by the commands listener yet"
+	}
+
+	var message strings.Builder
+	message.WriteString("📦 **Riot API payloads of the commands listener**\n")
+	for _, endpoint := range stats {
+		message.WriteString(fmt.Sprintf("`%s`: %d responses (%d compressed), %s decoded from %s (x%.1f)\n",
+			endpoint.Endpoint, endpoint.Responses, endpoint.Compressed, formatBytes(endpoint.DecodedBytes), formatBytes(endpoint.WireBytes), endpoint.CompressionRatio()))
+	}
+	return message.String()
+}
+
+// formatBytes formats a size with a binary unit (ex: "1.5 MiB")
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func (h *CommandHandler) adminPollNow(ctx context.Context, options map[string]*discordgo.ApplicationCommandInteractionDataOption) string {
+	pseudo, tagline, server := playerIdentity(options)
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to fetch player: %v", err)
+	}
+	if player == nil {
+		return fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server))
+	}
+
+	previousCheckpoint := player.LastMatchID
+	poll, err := h.playerService.PollPlayer(ctx, player)
+	if err != nil {
+		log.Printf("Error force polling %s#%s: %v", pseudo, tagline, err)
+		return fmt.Sprintf("❌ Failed to poll **%s#%s**: %v", pseudo, tagline, err)
+	}
+	h.notifyRankChange(ctx, poll.Change)
+
+	return formatPollNow(poll, previousCheckpoint)
+}
+
+// Games listed by /admin poll_now
+const pollNowMaxGames = 10
+
+func formatPollNow(poll *models.CatchUp, previousCheckpoint string) string {
+	summary := models.NewPlayerSummary(poll.Player)
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("🔄 **%s** polled\n", summary.RiotID()))
+
+	if poll.Change == nil {
+		builder.WriteString(fmt.Sprintf("🏆 No rank change (%s • %d LP)\n", summary.RankLabel(), summary.LeaguePoints))
+	} else {
+		builder.WriteString(fmt.Sprintf("🏆 %s • %d LP ➜ %s • %d LP (%+d LP)\n",
+			models.FormatRank(poll.Change.PreviousTier, poll.Change.PreviousRank), poll.Change.PreviousLeaguePoints,
+			summary.RankLabel(), summary.LeaguePoints, poll.Change.LPDelta()))
+	}
+
+	if len(poll.Matches) == 0 {
+		builder.WriteString("🎮 No new games\n")
+	} else {
+		wins, losses := poll.Record()
+		builder.WriteString(fmt.Sprintf("🎮 %d new games ingested (%dW %dL)\n", len(poll.Matches), wins, losses))
+		for idx, match := range poll.Matches {
+			if idx >= pollNowMaxGames {
+				builder.WriteString(fmt.Sprintf("... and %d more games\n", len(poll.Matches)-pollNowMaxGames))
+				break
+			}
+
+			result := "❌"
+			if match.Victory {
+				result = "✅"
+			}
+			builder.WriteString(fmt.Sprintf("%s `%s` %s • %s • %s\n", result, match.MatchID, match.Champion, match.KDAString(), match.QueueType))
+		}
+	}
+
+	checkpoint := valueOrNone(poll.Player.LastMatchID)
+	if poll.Player.LastMatchID != previousCheckpoint {
+		checkpoint = fmt.Sprintf("%s ➜ %s", valueOrNone(previousCheckpoint), checkpoint)
+	}
+	builder.WriteString(fmt.Sprintf("📍 Checkpoint: %s\n", checkpoint))
+
+	if poll.Change != nil {
+		builder.WriteString("⚠️ Notifications are sent by the poller, this rank change will not be announced")
+	}
+	return builder.String()
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "n/a"
+	}
+	return value
+}
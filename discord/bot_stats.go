@@ -0,0 +1,59 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleBotStatsAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	h.sendFollowUpEmbed(s, i, h.botStatsEmbed(ctx, interactionFormatter(i)))
+}
+
+// botStatsEmbed reports the activity of the commands listener: commands, Riot API calls and database health
+func (h *CommandHandler) botStatsEmbed(ctx context.Context, f models.Formatter) *discordgo.MessageEmbed {
+	total, active, avgTime, failedFollowUps := h.GetStats()
+
+	riot := h.container.GetRiotService()
+	requests, rateLimited := int64(0), int64(0)
+	for _, state := range riot.RateLimitStates() {
+		requests += state.Requests
+		rateLimited += state.RateLimited
+	}
+	riotUsage := fmt.Sprintf("%s requests\n%s rate limited", f.Int(int(requests)), f.Int(int(rateLimited)))
+	if requests > 0 {
+		riotUsage += fmt.Sprintf("\n%s connections reused", f.Percent(riot.ConnectionStats().ReuseRatio(), 0))
+	}
+
+	database := "✅ Reachable"
+	pingStart := time.Now()
+	err := h.container.DB.Ping(ctx)
+	if err != nil {
+		database = "❌ Unreachable"
+		log.Printf("Error pinging the database for /bot_stats: %v", err)
+	} else {
+		database += fmt.Sprintf("\n%v ping", time.Since(pingStart).Round(time.Millisecond))
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: "📊 Bot statistics",
+		Color: colorDefault,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Commands", Value: fmt.Sprintf("%s handled\n%d running", f.Int(int(total)), active), Inline: true},
+			{Name: "Latency", Value: fmt.Sprintf("%v average\n%s failed replies", avgTime.Round(time.Millisecond), f.Int(int(failedFollowUps))), Inline: true},
+			{Name: "Riot API", Value: riotUsage, Inline: true},
+			{Name: "Database", Value: database, Inline: true},
+			{Name: "Up since", Value: fmt.Sprintf("<t:%d:R>", h.startedAt.Unix()), Inline: true},
+		},
+		Footer:    &discordgo.MessageEmbedFooter{Text: "Since the start of the commands listener"},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
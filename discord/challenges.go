@@ -0,0 +1,66 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleChallengesAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	pseudo, tagline, server := playerIdentity(optionsByName(i.ApplicationCommandData().Options))
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	// Synced on demand, the poller only refreshes the progress a few times a day
+	progress, _, err := h.challengeService.SyncPlayer(ctx, player)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch challenges: %v", err))
+		log.Printf("Error syncing challenges of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	f := interactionFormatter(i)
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("🏅 **Challenges of %s#%s**\n\n", player.GameName, player.TagLine))
+	response.WriteString(fmt.Sprintf("**%s** • %s / %s points", models.FormatChallengeLevel(progress.Level),
+		f.Int(progress.Points), f.Int(progress.MaxPoints)))
+	if progress.Percentile > 0 {
+		response.WriteString(fmt.Sprintf(" • top %s", f.Percent(progress.Percentile*100, 1)))
+	}
+	response.WriteString("\n")
+
+	top := progress.TopChallenges(10)
+	if len(top) == 0 {
+		response.WriteString("\n_No challenge level reached yet_")
+		h.sendFollowUp(s, i, response.String())
+		return
+	}
+
+	response.WriteString("\n**Best challenges**\n")
+	for _, challenge := range top {
+		response.WriteString(fmt.Sprintf("• **%s** %s", models.FormatChallengeLevel(challenge.Level),
+			h.challengeService.ChallengeName(ctx, player.Server, challenge.ChallengeID)))
+		if challenge.Percentile > 0 {
+			response.WriteString(fmt.Sprintf(" (top %s)", f.Percent(challenge.Percentile*100, 1)))
+		}
+		response.WriteString("\n")
+	}
+
+	h.sendFollowUp(s, i, response.String())
+}
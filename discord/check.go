@@ -0,0 +1,51 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleCheckAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	pseudo, tagline, server := playerIdentity(optionsByName(i.ApplicationCommandData().Options))
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, tracked, err := h.playerService.LookupPlayer(ctx, pseudo, tagline, server)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** not found on server **%s**\n\n💡 Check the spelling of the name and tagline and make sure the server is correct",
+				pseudo, tagline, strings.ToUpper(server)))
+			return
+		}
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to look up player **%s#%s**\n\n**Error:** %v", pseudo, tagline, err))
+		log.Printf("Error looking up player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	h.sendFollowUp(s, i, formatCheckedPlayer(player, tracked, interactionFormatter(i)))
+}
+
+func formatCheckedPlayer(player *models.Player, tracked bool, f models.Formatter) string {
+	detail := models.NewPlayerDetail(player)
+
+	rankInfo := "🆕 **Unranked**"
+	if detail.IsRanked() {
+		rankInfo = fmt.Sprintf("🏆 **%s** • %d LP\n📊 %s W / %s L (%s win rate)",
+			detail.RankLabel(), detail.LeaguePoints, f.Int(detail.Wins), f.Int(detail.Losses), f.Percent(detail.WinRate, 1))
+	}
+
+	response := fmt.Sprintf("🔎 **%s** (%s)\n📊 **Level:** %d\n%s",
+		detail.RiotID(), detail.Server, detail.Level, rankInfo)
+	if tracked {
+		response += "\n\n✅ Already tracked"
+	} else {
+		response += "\n\n💡 Not tracked, use `/add_player` to follow their climb"
+	}
+	return response
+}
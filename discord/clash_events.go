@@ -0,0 +1,231 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// Discord limits of the scheduled events
+	maxEventNameLength        = 100
+	maxEventDescriptionLength = 1000
+)
+
+// ClashEvents mirrors the Clash registrations of the tracked players as Discord scheduled events,
+// one per tournament and server, in the guilds that enabled the clash_events feature flag
+type ClashEvents struct {
+	session            *discordgo.Session
+	guildConfigService *services.GuildConfigService
+	featureFlags       *services.FeatureFlagService
+	clashService       *services.ClashService
+}
+
+func NewClashEvents(s *discordgo.Session, guildConfigService *services.GuildConfigService, featureFlags *services.FeatureFlagService, clashService *services.ClashService) *ClashEvents {
+	return &ClashEvents{
+		session:            s,
+		guildConfigService: guildConfigService,
+		featureFlags:       featureFlags,
+		clashService:       clashService,
+	}
+}
+
+// clashTournament is a tournament of a server with the tracked players registered in it
+type clashTournament struct {
+	server        string
+	id            int
+	name          string
+	start         time.Time
+	registrations []*models.ClashRegistration
+}
+
+func (t *clashTournament) key() string {
+	return clashEventKey(t.server, t.id)
+}
+
+func clashEventKey(server string, tournamentID int) string {
+	return fmt.Sprintf("%s/%d", server, tournamentID)
+}
+
+// Sync creates the events of the new registrations, updates the player lists and deletes the events
+// of the tournaments nobody is registered in anymore
+func (ce *ClashEvents) Sync(ctx context.Context) error {
+	configs, err := ce.guildConfigService.GetNotificationConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch guild configs: %w", err)
+	}
+
+	registrations, err := ce.clashService.GetUpcomingRegistrations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch clash registrations: %w", err)
+	}
+
+	for _, config := range configs {
+		if !ce.featureFlags.IsEnabled(ctx, models.FlagClashEvents, config.GuildID) {
+			continue
+		}
+
+		err := ce.syncGuild(ctx, config, registrations)
+		if err != nil {
+			log.Printf("Error syncing clash events of guild %s: %v", config.GuildID, err)
+		}
+	}
+
+	return nil
+}
+
+func (ce *ClashEvents) syncGuild(ctx context.Context, config *models.GuildConfig, registrations []*models.ClashRegistration) error {
+	tournaments := make(map[string]*clashTournament)
+	for _, registration := range registrations {
+		if config.IsMuted(registration.PlayerPUUID) {
+			continue
+		}
+
+		key := clashEventKey(registration.Server, registration.TournamentID)
+		tournament, ok := tournaments[key]
+		if !ok {
+			tournament = &clashTournament{
+				server: registration.Server,
+				id:     registration.TournamentID,
+				name:   registration.TournamentName,
+				start:  registration.StartTime,
+			}
+			tournaments[key] = tournament
+		}
+		tournament.registrations = append(tournament.registrations, registration)
+	}
+
+	events, err := ce.clashService.GetGuildEvents(ctx, config.GuildID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, event := range events {
+		tournament, ok := tournaments[clashEventKey(event.Server, event.TournamentID)]
+		switch {
+		case ok:
+			delete(tournaments, tournament.key())
+			err = ce.updateEvent(ctx, config.GuildID, event, tournament)
+		case event.StartTime.Before(now):
+			// Finished (or in progress) tournament, the event is left to Discord
+			err = ce.clashService.DeleteGuildEvent(ctx, event)
+		default:
+			// Every player cancelled their registration, or the tournament was cancelled
+			err = ce.deleteEvent(ctx, config.GuildID, event)
+		}
+		if err != nil {
+			log.Printf("Error syncing clash event %s of guild %s: %v", event.EventID, config.GuildID, err)
+		}
+	}
+
+	for _, tournament := range tournaments {
+		err := ce.createEvent(ctx, config.GuildID, tournament)
+		if err != nil {
+			log.Printf("Error creating clash event of guild %s (missing Manage Events permission?): %v", config.GuildID, err)
+		}
+	}
+
+	return nil
+}
+
+func (ce *ClashEvents) createEvent(ctx context.Context, guildID string, tournament *clashTournament) error {
+	scheduled, err := ce.session.GuildScheduledEventCreate(guildID, clashEventParams(tournament))
+	if err != nil {
+		return err
+	}
+
+	return ce.clashService.SaveGuildEvent(ctx, &models.ClashEvent{
+		GuildID:      guildID,
+		Server:       tournament.server,
+		TournamentID: tournament.id,
+		EventID:      scheduled.ID,
+		PlayerPUUIDs: clashPlayerPUUIDs(tournament),
+		StartTime:    tournament.start,
+	})
+}
+
+func (ce *ClashEvents) updateEvent(ctx context.Context, guildID string, event *models.ClashEvent, tournament *clashTournament) error {
+	puuids := clashPlayerPUUIDs(tournament)
+	if slices.Equal(puuids, event.PlayerPUUIDs) && event.StartTime.Equal(tournament.start) {
+		return nil
+	}
+
+	_, err := ce.session.GuildScheduledEventEdit(guildID, event.EventID, clashEventParams(tournament))
+	if isNotFound(err) {
+		// The event was deleted from Discord, create it again
+		return ce.createEvent(ctx, guildID, tournament)
+	}
+	if err != nil {
+		return err
+	}
+
+	event.PlayerPUUIDs = puuids
+	event.StartTime = tournament.start
+	return ce.clashService.SaveGuildEvent(ctx, event)
+}
+
+func (ce *ClashEvents) deleteEvent(ctx context.Context, guildID string, event *models.ClashEvent) error {
+	err := ce.session.GuildScheduledEventDelete(guildID, event.EventID)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	return ce.clashService.DeleteGuildEvent(ctx, event)
+}
+
+func clashEventParams(tournament *clashTournament) *discordgo.GuildScheduledEventParams {
+	start := tournament.start
+	end := start.Add(models.ClashTournamentDuration)
+
+	return &discordgo.GuildScheduledEventParams{
+		Name:               truncate(fmt.Sprintf("🏆 %s (%s)", tournament.name, strings.ToUpper(tournament.server)), maxEventNameLength),
+		Description:        truncate(formatClashEventDescription(tournament), maxEventDescriptionLength),
+		ScheduledStartTime: &start,
+		ScheduledEndTime:   &end,
+		PrivacyLevel:       discordgo.GuildScheduledEventPrivacyLevelGuildOnly,
+		EntityType:         discordgo.GuildScheduledEventEntityTypeExternal,
+		EntityMetadata:     &discordgo.GuildScheduledEventEntityMetadata{Location: "League of Legends Clash"},
+	}
+}
+
+func formatClashEventDescription(tournament *clashTournament) string {
+	var description strings.Builder
+	description.WriteString("Tracked players registered:\n")
+	for _, registration := range tournament.registrations {
+		description.WriteString(fmt.Sprintf("• %s#%s", registration.GameName, registration.TagLine))
+		if registration.TeamName != "" {
+			description.WriteString(" with " + registration.TeamName)
+		}
+		if registration.Position != "" && registration.Position != "UNSELECTED" {
+			description.WriteString(fmt.Sprintf(" (%s)", registration.Position))
+		}
+		description.WriteString("\n")
+	}
+	return description.String()
+}
+
+func clashPlayerPUUIDs(tournament *clashTournament) []string {
+	puuids := make([]string, 0, len(tournament.registrations))
+	for _, registration := range tournament.registrations {
+		puuids = append(puuids, registration.PlayerPUUID)
+	}
+	slices.Sort(puuids)
+	return puuids
+}
+
+// isNotFound checks if a Discord REST call failed because the resource doesn't exist anymore
+func isNotFound(err error) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,118 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// isRestrictableCommand checks if a command exists and can be restricted by the permissions matrix
+func isRestrictableCommand(name string) bool {
+	if unrestrictedCommands[name] {
+		return false
+	}
+	for _, command := range commands {
+		if command.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *CommandHandler) handleCommandRolesAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+
+	options := optionsByName(i.ApplicationCommandData().Options)
+	command := ""
+	if opt, ok := options["command"]; ok {
+		command = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(opt.StringValue())), "/")
+	}
+	roleID := ""
+	if opt, ok := options["role"]; ok {
+		roleID = opt.RoleValue(nil, "").ID
+	}
+	remove := false
+	if opt, ok := options["remove"]; ok {
+		remove = opt.BoolValue()
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	if roleID == "" {
+		config, err := h.guildConfigService.GetConfig(ctx, i.GuildID)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch the permissions matrix: %v", err))
+			log.Printf("Error fetching guild config %s: %v", i.GuildID, err)
+			return
+		}
+		h.sendFollowUp(s, i, formatCommandRoles(config, command))
+		return
+	}
+
+	if !isRestrictableCommand(command) {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ `%s` is not a command that can be restricted", command))
+		return
+	}
+
+	config, err := h.guildConfigService.UpdateConfig(ctx, i.GuildID, func(config *models.GuildConfig) error {
+		config.SetCommandRole(command, roleID, !remove)
+		return nil
+	})
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to save the permissions matrix: %v", err))
+		log.Printf("Error saving guild config %s: %v", i.GuildID, err)
+		return
+	}
+
+	action := "can now use"
+	if remove {
+		action = "can no longer use"
+	}
+	h.sendFollowUp(s, i, fmt.Sprintf("✅ <@&%s> %s `/%s`\n%s", roleID, action, command, formatCommandRoles(config, command)))
+}
+
+// formatCommandRoles lists the roles allowed per command, or for a single command
+func formatCommandRoles(config *models.GuildConfig, command string) string {
+	if command != "" {
+		roles := config.CommandRoles[command]
+		if len(roles) == 0 {
+			return fmt.Sprintf("🔓 `/%s` is open to every member", command)
+		}
+		return fmt.Sprintf("🔒 `/%s`: %s", command, mentionRoles(roles))
+	}
+
+	if len(config.CommandRoles) == 0 {
+		return "🔓 No command is restricted to roles on this server\n💡 Server admins can always use every command"
+	}
+
+	names := make([]string, 0, len(config.CommandRoles))
+	for name := range config.CommandRoles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	builder.WriteString("🔒 **Permissions matrix**\n")
+	for _, name := range names {
+		builder.WriteString(fmt.Sprintf("• `/%s`: %s\n", name, mentionRoles(config.CommandRoles[name])))
+	}
+	builder.WriteString("💡 Server admins can always use every command")
+	return builder.String()
+}
+
+func mentionRoles(roleIDs []string) string {
+	mentions := make([]string, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		mentions = append(mentions, fmt.Sprintf("<@&%s>", roleID))
+	}
+	return strings.Join(mentions, ", ")
+}
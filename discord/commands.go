@@ -17,119 +17,1020 @@ import (
 )
 
 type CommandHandler struct {
-	container     *container.Container
-	playerService *services.PlayerService
-	workerPool    chan struct{}
-	stats         *CommandStats
+	container           *container.Container
+	playerService       *services.PlayerService
+	standingsService    *services.StandingsService
+	competitionService  *services.CompetitionService
+	achievementService  *services.AchievementService
+	guildConfigService  *services.GuildConfigService
+	jobService          *services.JobService
+	featureFlags        *services.FeatureFlagService
+	personService       *services.PersonService
+	profileService      *services.ProfileService
+	dataDragon          *services.DataDragonService
+	challengeService    *services.ChallengeService
+	verificationService *services.VerificationService
+	tiltService         *services.TiltService
+	workerPool          chan struct{} // Slots of the commands calling the Riot API, see riotCommands
+	stats               *CommandStats
+	timeouts            map[string]time.Duration // Keyed by command name, see commandContext
+	dedupe              *interactionDedupe
+	jobResults          *JobResultDispatcher // Posts the results of long jobs, see startLongJob
+	notifier            *Notifier            // Announces the rank changes of the manual updates, see SetNotifier
+	ownerID             string               // Discord user allowed to use /admin and /purge_inactive, see SetOwnerID
+	publicURL           string               // Base URL of the web dashboard, see SetPublicURL
+	startedAt           time.Time
+
+	// Every goroutine spawned for an interaction is tracked, Shutdown cancels and joins them
+	ctx     context.Context
+	stop    context.CancelFunc
+	running sync.WaitGroup
+	closed  bool // Guarded by closeMu, no interaction is handled after Shutdown
+	closeMu sync.RWMutex
 }
 
 type CommandStats struct {
-	mu             sync.Mutex
-	totalCommands  int64
-	activeCommands int64
-	averageTime    time.Duration
+	mu              sync.Mutex
+	totalCommands   int64
+	activeCommands  int64
+	averageTime     time.Duration
+	failedFollowUps int64
 }
 
 func NewCommandHandler(c *container.Container) *CommandHandler {
+	timeouts := make(map[string]time.Duration, len(defaultCommandTimeouts))
+	for name, timeout := range defaultCommandTimeouts {
+		timeouts[name] = timeout
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+
 	return &CommandHandler{
-		container:     c,
-		playerService: c.GetPlayerService(),
+		ctx:                 ctx,
+		stop:                stop,
+		container:           c,
+		playerService:       c.GetPlayerService(),
+		standingsService:    c.GetStandingsService(),
+		competitionService:  c.GetCompetitionService(),
+		achievementService:  c.GetAchievementService(),
+		guildConfigService:  c.GetGuildConfigService(),
+		jobService:          c.GetJobService(),
+		featureFlags:        c.GetFeatureFlagService(),
+		personService:       c.GetPersonService(),
+		profileService:      c.GetProfileService(),
+		dataDragon:          c.GetDataDragonService(),
+		challengeService:    c.GetChallengeService(),
+		verificationService: c.GetVerificationService(),
+		tiltService:         c.GetTiltService(),
 		// worker pool limit to 2 to avoid overwhelming riot api (since poller which also poll Riot API runs in parallel)
 		workerPool: make(chan struct{}, 2),
 		stats:      &CommandStats{},
+		startedAt:  time.Now(),
+		timeouts:   timeouts,
+		dedupe:     newInteractionDedupe(),
 	}
 }
 
-var commands = []*discordgo.ApplicationCommand{
+// Slash commands, their Discord permissions and contexts are set by withPermissions (see permissions.go)
+var commands = withPermissions([]*discordgo.ApplicationCommand{
 	{
 		Name:        "add_player",
 		Description: "Add a player to the tracking database",
+		Options:     playerOptions(),
+	},
+	{
+		Name:        "check",
+		Description: "Look up the current rank of a player without tracking them",
+		Options:     playerOptions(),
+	},
+	{
+		Name:        "refresh",
+		Description: "Update a tracked player now instead of waiting for the next poll",
+		Options:     playerOptions(),
+	},
+	{
+		Name:        "list_players",
+		Description: "List all tracked players",
 		Options: []*discordgo.ApplicationCommandOption{
 			{
 				Type:        discordgo.ApplicationCommandOptionString,
-				Name:        "pseudo",
-				Description: "Player's game name",
+				Name:        "tag",
+				Description: "Only list players with this tag",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "leaderboard",
+		Description: "Rank the tracked players by tier, division and LP",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "tag",
+				Description: "Only rank players with this tag",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "by_person",
+				Description: "Group the accounts of a person, ranked by their best account",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "tag_player",
+		Description: "Add or remove a tag (ex: team-a, friends) on a tracked player",
+		Options: append(playerOptions(),
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "tag",
+				Description: "Tag name (letters, digits, - and _)",
 				Required:    true,
+				MaxLength:   models.MaxTagLength,
+			},
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "remove",
+				Description: "Remove the tag instead of adding it",
+				Required:    false,
+			},
+		),
+	},
+	{
+		Name:        "player_note",
+		Description: "Show or set the alias and the note of a tracked player (ex: Bob's smurf)",
+		Options: append(playerOptions(),
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "alias",
+				Description: "Name displayed before the Riot ID in lists and notifications",
+				Required:    false,
+				MaxLength:   models.MaxAliasLength,
+			},
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "note",
+				Description: "Free text note (ex: this is Bob's smurf)",
+				Required:    false,
+				MaxLength:   models.MaxNoteLength,
+			},
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "clear",
+				Description: "Clear the alias and the note",
+				Required:    false,
+			},
+		),
+	},
+	{
+		Name:        "person",
+		Description: "Group the accounts (main and smurfs) of a person",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "link",
+				Description: "Link a tracked account to a person (created on first link)",
+				Options: append([]*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "person",
+						Description: "Person name (ex: bob)",
+						Required:    true,
+						MaxLength:   models.MaxPersonNameLength,
+					},
+				}, playerOptions()...),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "unlink",
+				Description: "Unlink a tracked account from its person",
+				Options:     playerOptions(),
 			},
 			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "List the persons and their accounts",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "compare",
+				Description: "Compare the accounts of a person side by side, ranks normalized across servers",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "person",
+						Description: "Person name (ex: bob)",
+						Required:    true,
+						MaxLength:   models.MaxPersonNameLength,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "group",
+				Description: "Group the accounts of a person in the live leaderboard and the recaps of this server",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "enabled",
+						Description: "Rank persons by their best account and sum their games in recaps",
+						Required:    true,
+					},
+				},
+			},
+		},
+	},
+	{
+		Name:        "link",
+		Description: "Link your Discord account to the tracked accounts you own, verified with a profile icon change",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "start",
+				Description: "Start the verification of a tracked account (switch to the requested profile icon)",
+				Options:     playerOptions(),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "verify",
+				Description: "Check the profile icon and complete the verification",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "List the accounts linked to your Discord account",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "unlink",
+				Description: "Unlink one of your accounts",
+				Options:     playerOptions(),
+			},
+		},
+	},
+	{
+		Name:        "public_profile",
+		Description: "Publish (opt-in) or hide the public profile page of a player on the web dashboard",
+		Options: append(playerOptions(),
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enabled",
+				Description: "Publish the profile (charts, champion stats and games)",
+				Required:    true,
+			},
+		),
+	},
+	{
+		Name:        "twitch",
+		Description: "Show or set the Twitch channel of a player, announced when the stream goes live",
+		Options: append(playerOptions(),
+			&discordgo.ApplicationCommandOption{
 				Type:        discordgo.ApplicationCommandOptionString,
-				Name:        "tagline",
-				Description: "Player's tagline (without #)",
+				Name:        "channel",
+				Description: "Twitch channel name or URL (ex: https://twitch.tv/name)",
+				Required:    false,
+			},
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "clear",
+				Description: "Remove the Twitch channel",
+				Required:    false,
+			},
+		),
+	},
+	{
+		Name:        "team_standings",
+		Description: "Compare player tags by average rank, weekly LP and win rate",
+	},
+	{
+		Name:        "snapshot",
+		Description: "Freeze the current leaderboard under a name (lists snapshots without a name)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "name",
+				Description: "Snapshot name (ex: october-cutoff)",
+				Required:    false,
+				MaxLength:   models.MaxSnapshotNameLength,
+			},
+		},
+	},
+	{
+		Name:        "snapshot_compare",
+		Description: "Compare the current leaderboard with a snapshot",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "name",
+				Description: "Snapshot name",
 				Required:    true,
+				MaxLength:   models.MaxSnapshotNameLength,
 			},
+		},
+	},
+	{
+		Name:        "competition_start",
+		Description: "Start a competition scoring LP gains and promotions until an end date",
+		Options: []*discordgo.ApplicationCommandOption{
 			{
 				Type:        discordgo.ApplicationCommandOptionString,
-				Name:        "server",
-				Description: "Server region",
+				Name:        "name",
+				Description: "Competition name",
 				Required:    true,
+				MaxLength:   50,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "end_date",
+				Description: "Last day of the competition (YYYY-MM-DD, server time zone)",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "start_date",
+				Description: "First day of the competition (YYYY-MM-DD, defaults to now)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "points_per_lp",
+				Description: fmt.Sprintf("Points per LP gained (default %d)", models.DefaultPointsPerLP),
+				Required:    false,
+				MinValue:    &minPointsValue,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "points_per_promotion",
+				Description: fmt.Sprintf("Points per division or tier promotion (default %d)", models.DefaultPointsPerPromotion),
+				Required:    false,
+				MinValue:    &minPointsValue,
+			},
+		},
+	},
+	{
+		Name:        "competition_standings",
+		Description: "Show the standings of the current competition",
+	},
+	{
+		Name:        "achievements",
+		Description: "List the badges earned by a tracked player",
+		Options:     playerOptions(),
+	},
+	{
+		Name:        "roles",
+		Description: "Show the games and win rate per role of a tracked player",
+		Options:     playerOptions(),
+	},
+	{
+		Name:        "rank",
+		Description: "Show the rank of a tracked player and when they should reach a target rank",
+		Options: append(playerOptions(),
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "target_tier",
+				Description: "Target tier (default: next division)",
+				Required:    false,
+				Choices:     targetTierChoices,
+			},
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "target_division",
+				Description: "Target division (default: IV)",
+				Required:    false,
+				Choices:     divisionChoices,
+			},
+		),
+	},
+	{
+		Name:        "profile",
+		Description: "Show the profile of a tracked player: icon, level, rank, win rate and tracking date",
+		Options:     playerOptions(),
+	},
+	{
+		Name:        "lp_stats",
+		Description: "Show the average LP gained per win and lost per loss of a tracked player",
+		Options:     playerOptions(),
+	},
+	{
+		Name:        "patch_stats",
+		Description: "Compare the win rate of a tracked player on the current patch and the previous one",
+		Options:     playerOptions(),
+	},
+	{
+		Name:        "tilt_alerts",
+		Description: "Turn on or off the break suggestions sent to a player after several losses in a row",
+		Options: append(playerOptions(),
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enabled",
+				Description: "Receive the tilt alerts",
+				Required:    true,
+			},
+		),
+	},
+	{
+		Name:        "heatmap",
+		Description: "Show when a tracked player plays ranked games (day of the week and hour) over the last 60 days",
+		Options:     playerOptions(),
+	},
+	{
+		Name:        "lp_graph",
+		Description: "Draw the LP of a tracked player over time",
+		Options: append(playerOptions(),
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "days",
+				Description: fmt.Sprintf("Period in days (default %d)", defaultLPGraphDays),
+				Required:    false,
+				MinValue:    &minRecentGamesValue,
+				MaxValue:    365,
+			},
+		),
+	},
+	{
+		Name:        "challenges",
+		Description: "Show the challenge level, points and best challenges of a tracked player",
+		Options:     playerOptions(),
+	},
+	{
+		Name:        "recent",
+		Description: "Show the latest tracked games of a player with advanced metrics",
+		Options: append(playerOptions(),
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "count",
+				Description: fmt.Sprintf("Number of games (default %d)", defaultRecentGames),
+				Required:    false,
+				MinValue:    &minRecentGamesValue,
+				MaxValue:    maxRecentGames,
+			},
+		),
+	},
+	{
+		Name:        "match_history",
+		Description: "Show the latest games of a player with the LP won or lost in each",
+		Options: append(playerOptions(),
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "count",
+				Description: fmt.Sprintf("Number of games (default %d)", defaultMatchHistoryGames),
+				Required:    false,
+				MinValue:    &minRecentGamesValue,
+				MaxValue:    maxMatchHistoryGames,
+			},
+		),
+	},
+	{
+		Name:        "notifications",
+		Description: "Show or configure rank change notifications for this server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "channel",
+				Description:  "Channel where notifications are posted",
+				Required:     false,
+				ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "min_lp_delta",
+				Description: "Minimum LP gained or lost to notify (promotions are always notified)",
+				Required:    false,
+				MinValue:    &minLPDeltaValue,
+				MaxValue:    100,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "template",
+				Description: "Custom message using {player}, {lp_delta}, {rank}, {champion}, {kda} (\"default\" to reset)",
+				Required:    false,
+				MaxLength:   models.MaxNotificationTemplateLength,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "style",
+				Description: "Tone of the default messages",
+				Required:    false,
 				Choices: []*discordgo.ApplicationCommandOptionChoice{
-					{Name: "EUW (Europe West)", Value: "euw1"},
-					{Name: "EUNE (Europe Nordic & East)", Value: "eun1"},
-					{Name: "NA (North America)", Value: "na1"},
-					{Name: "KR (Korea)", Value: "kr"},
-					{Name: "JP (Japan)", Value: "jp1"},
-					{Name: "BR (Brazil)", Value: "br1"},
-					{Name: "LAN (Latin America North)", Value: "la1"},
-					{Name: "LAS (Latin America South)", Value: "la2"},
-					{Name: "OCE (Oceania)", Value: "oc1"},
-					{Name: "TR (Turkey)", Value: "tr1"},
-					{Name: "RU (Russia)", Value: "ru"},
+					{Name: "😐 Neutral", Value: models.NotificationStyleNeutral},
+					{Name: "🔥 Hype", Value: models.NotificationStyleHype},
+					{Name: "😈 Savage", Value: models.NotificationStyleSavage},
 				},
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "afk_callout",
+				Description: "Mention games suspected of having an AFK or leaver",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enemy_ranks",
+				Description: "Show the average rank of the enemy team (uses many Riot API calls)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "good_luck",
+				Description: "Wish good luck when a player starts their first ranked game of the day",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "good_luck_message",
+				Description: "Custom good luck message using {player}, {champion}, {rank}, {queue} (\"default\" to reset)",
+				Required:    false,
+				MaxLength:   models.MaxNotificationTemplateLength,
+			},
 		},
 	},
 	{
-		Name:        "list_players",
-		Description: "List all tracked players",
+		Name:        "live_leaderboard",
+		Description: "Maintain a pinned leaderboard message updated after each poll cycle",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enabled",
+				Description: "Enable or disable the live leaderboard",
+				Required:    true,
+			},
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "channel",
+				Description:  "Channel of the leaderboard (default: this channel)",
+				Required:     false,
+				ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+			},
+		},
+	},
+	{
+		Name:        "settings",
+		Description: "Open the server settings panel (channel, verbosity, language, time zone, threshold)",
 	},
+	{
+		Name:        "set_timezone",
+		Description: "Set the server time zone used to schedule daily and weekly recaps",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "timezone",
+				Description: "IANA time zone name (ex: Europe/Paris, America/New_York)",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "set_language",
+		Description: "Set the language of champion names in notifications",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "language",
+				Description: "Data Dragon language (off to keep the raw match data names)",
+				Required:    true,
+				Choices:     languageChoices(),
+			},
+		},
+	},
+	{
+		Name:        "job_status",
+		Description: "Show the background jobs of the server or cancel a running one",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "job_id",
+				Description: "Job to show (latest jobs when omitted)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "cancel",
+				Description: "Cancel the job",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "feature",
+		Description: "Show the feature flags of the server or switch one",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "name",
+				Description: "Feature to switch (every feature when omitted)",
+				Required:    false,
+				Choices:     featureFlagChoices(),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enabled",
+				Description: "Enable or disable the feature on this server",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "reset",
+				Description: "Remove the server value, the global value applies again",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "inactive",
+		Description: "List the players without ranked games for a while",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "days",
+				Description: fmt.Sprintf("Days without ranked games (default %d)", defaultPurgeDays),
+				Required:    false,
+				MinValue:    &minPurgeDaysValue,
+				MaxValue:    maxPurgeDays,
+			},
+		},
+	},
+	{
+		Name:        "purge_inactive",
+		Description: "Remove or pause the players without ranked games for a while (bot owner only, preview first)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "days",
+				Description: fmt.Sprintf("Days without ranked games (default %d)", defaultPurgeDays),
+				Required:    false,
+				MinValue:    &minPurgeDaysValue,
+				MaxValue:    maxPurgeDays,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "action",
+				Description: "Remove the players (default) or only stop polling them",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Remove", Value: purgeActionRemove},
+					{Name: "Pause", Value: purgeActionPause},
+				},
+			},
+		},
+	},
+	{
+		Name:        "command_roles",
+		Description: "Restrict a command to some roles (shows the permissions matrix without a role)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "command",
+				Description: "Command to restrict (ex: add_player)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionRole,
+				Name:        "role",
+				Description: "Role allowed to use the command",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "remove",
+				Description: "Stop allowing the role instead",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "version",
+		Description: "Show the version, commit and build date of the bot",
+	},
+	{
+		Name:        "bot_stats",
+		Description: "Show the commands handled, Riot API calls and database health of the bot",
+	},
+	{
+		Name:        "help",
+		Description: "List the commands you can use or show the options of one",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "command",
+				Description: "Command to explain (ex: leaderboard)",
+				Required:    false,
+			},
+		},
+	},
+	{
+		// Restricted to the bot owner by handleAdminAsync, not by Discord permissions:
+		// the owner is not necessarily an admin of the server
+		Name:        "admin",
+		Description: "Bot owner diagnostics",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "status",
+				Description: "Uptime, command statistics and last poll of the poller",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "cache_stats",
+				Description: "Size of the in-memory caches of the commands listener",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "rate_limits",
+				Description: "Riot API usage and rate limit state of the commands listener",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "payloads",
+				Description: "Riot API response sizes per endpoint of the commands listener",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "poll_now",
+				Description: "Poll a tracked player immediately and show what changed",
+				Options:     playerOptions(),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "resync_commands",
+				Description: "Register the slash commands again and drop the removed ones",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reload_config",
+				Description: "Drop the cached guild configs, feature flags and champion data",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "riot_debug",
+				Description: "Log every Riot API call of the commands listener (URL, status, rate limits, body)",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "enabled",
+						Description: "Enable the debug logs",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "api_key_create",
+				Description: "Create a key for the REST API, shown once",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Name of the client using the key",
+						Required:    true,
+						MaxLength:   50,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "scope",
+						Description: "What the key can do",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Read only", Value: string(models.APIKeyScopeRead)},
+							{Name: "Refresh (read and trigger player updates)", Value: string(models.APIKeyScopeRefresh)},
+							{Name: "Admin (add and remove players)", Value: string(models.APIKeyScopeAdmin)},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "rate_limit",
+						Description: fmt.Sprintf("Requests per minute (%d by default)", models.DefaultAPIKeyRateLimit),
+						Required:    false,
+						MinValue:    &minAPIKeyRateLimit,
+						MaxValue:    6000,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "api_key_list",
+				Description: "List the REST API keys and their last use",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "api_key_revoke",
+				Description: "Revoke a REST API key",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Name of the key",
+						Required:    true,
+					},
+				},
+			},
+		},
+	},
+})
+
+var minLPDeltaValue = 0.0
+
+var minPointsValue = 0.0
+
+var minRecentGamesValue = 1.0
+
+var minAPIKeyRateLimit = 1.0
+
+var serverChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "EUW (Europe West)", Value: "euw1"},
+	{Name: "EUNE (Europe Nordic & East)", Value: "eun1"},
+	{Name: "NA (North America)", Value: "na1"},
+	{Name: "KR (Korea)", Value: "kr"},
+	{Name: "JP (Japan)", Value: "jp1"},
+	{Name: "BR (Brazil)", Value: "br1"},
+	{Name: "LAN (Latin America North)", Value: "la1"},
+	{Name: "LAS (Latin America South)", Value: "la2"},
+	{Name: "OCE (Oceania)", Value: "oc1"},
+	{Name: "TR (Turkey)", Value: "tr1"},
+	{Name: "RU (Russia)", Value: "ru"},
 }
 
-func (h *CommandHandler) RegisterCommands(s *discordgo.Session) error {
-	log.Println("Registering slash commands...")
+// Tiers that can be targeted by a climb projection, apex tiers above Master have moving cutoffs
+var targetTierChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "Iron", Value: "IRON"},
+	{Name: "Bronze", Value: "BRONZE"},
+	{Name: "Silver", Value: "SILVER"},
+	{Name: "Gold", Value: "GOLD"},
+	{Name: "Platinum", Value: "PLATINUM"},
+	{Name: "Emerald", Value: "EMERALD"},
+	{Name: "Diamond", Value: "DIAMOND"},
+	{Name: "Master", Value: "MASTER"},
+}
 
-	for _, cmd := range commands {
-		_, err := s.ApplicationCommandCreate(s.State.User.ID, "", cmd)
-		if err != nil {
-			return fmt.Errorf("failed to create command %s: %v", cmd.Name, err)
-		}
-		log.Printf("Registered command: %s", cmd.Name)
+var divisionChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "IV", Value: "IV"},
+	{Name: "III", Value: "III"},
+	{Name: "II", Value: "II"},
+	{Name: "I", Value: "I"},
+}
+
+// playerOptions returns the options identifying a player (pseudo, tagline, server)
+func playerOptions() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "pseudo",
+			Description: "Player's game name",
+			Required:    true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "tagline",
+			Description: "Player's tagline (without #)",
+			Required:    true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "server",
+			Description: "Server region",
+			Required:    true,
+			Choices:     serverChoices,
+		},
 	}
+}
+
+// playerIdentity extracts the player identification options of an interaction
+func playerIdentity(options map[string]*discordgo.ApplicationCommandInteractionDataOption) (pseudo, tagline, server string) {
+	return options["pseudo"].StringValue(), options["tagline"].StringValue(), strings.ToLower(options["server"].StringValue())
+}
 
-	return nil
+// interactionFormatter formats numbers and dates in the Discord language of the user
+func interactionFormatter(i *discordgo.InteractionCreate) models.Formatter {
+	return models.NewFormatter(string(i.Locale))
+}
+
+// languageOff disables champion names localization
+const languageOff = "off"
+
+func languageChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := []*discordgo.ApplicationCommandOptionChoice{
+		{Name: "Off", Value: languageOff},
+	}
+	for _, language := range services.SupportedLanguages {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: language, Value: language})
+	}
+	return choices
 }
 
 func (h *CommandHandler) HandleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Duplicates would insert players twice and waste Riot API calls
+	if !h.dedupe.firstSeen(i.ID, time.Now()) {
+		log.Printf("Ignoring duplicate interaction %s", i.ID)
+		return
+	}
+
+	// Buttons and select menus are routed by the prefix of their custom ID
+	if i.Type == discordgo.InteractionMessageComponent {
+		customID := i.MessageComponentData().CustomID
+		switch {
+		case strings.HasPrefix(customID, settingsComponentPrefix):
+			h.async(h.handleSettingsComponentAsync, s, i)
+		case strings.HasPrefix(customID, playerActionPrefix):
+			h.async(h.handlePlayerActionAsync, s, i)
+		case strings.HasPrefix(customID, purgeComponentPrefix):
+			h.async(h.handlePurgeComponentAsync, s, i)
+		}
+		return
+	}
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+
 	// i.ApplicationCommandData().Name is an implicit routine (Discordgo)
 	switch i.ApplicationCommandData().Name {
 	case "add_player":
-		go h.handleAddPlayerAsync(s, i)
+		h.async(h.handleAddPlayerAsync, s, i)
+	case "check":
+		h.async(h.handleCheckAsync, s, i)
+	case "refresh":
+		h.async(h.handleRefreshAsync, s, i)
 	case "list_players":
-		go h.handleListPlayersAsync(s, i)
+		h.async(h.handleListPlayersAsync, s, i)
+	case "leaderboard":
+		h.async(h.handleLeaderboardAsync, s, i)
+	case "tag_player":
+		h.async(h.handleTagPlayerAsync, s, i)
+	case "player_note":
+		h.async(h.handlePlayerNoteAsync, s, i)
+	case "person":
+		h.async(h.handlePersonAsync, s, i)
+	case "link":
+		h.async(h.handleLinkAsync, s, i)
+	case "public_profile":
+		h.async(h.handlePublicProfileAsync, s, i)
+	case "twitch":
+		h.async(h.handleTwitchAsync, s, i)
+	case "team_standings":
+		h.async(h.handleTeamStandingsAsync, s, i)
+	case "snapshot":
+		h.async(h.handleSnapshotAsync, s, i)
+	case "snapshot_compare":
+		h.async(h.handleSnapshotCompareAsync, s, i)
+	case "competition_start":
+		h.async(h.handleCompetitionStartAsync, s, i)
+	case "competition_standings":
+		h.async(h.handleCompetitionStandingsAsync, s, i)
+	case "achievements":
+		h.async(h.handleAchievementsAsync, s, i)
+	case "roles":
+		h.async(h.handleRolesAsync, s, i)
+	case "rank":
+		h.async(h.handleRankAsync, s, i)
+	case "profile":
+		h.async(h.handleProfileAsync, s, i)
+	case "lp_stats":
+		h.async(h.handleLPStatsAsync, s, i)
+	case "patch_stats":
+		h.async(h.handlePatchStatsAsync, s, i)
+	case "tilt_alerts":
+		h.async(h.handleTiltAlertsAsync, s, i)
+	case "lp_graph":
+		h.async(h.handleLPGraphAsync, s, i)
+	case "heatmap":
+		h.async(h.handleHeatmapAsync, s, i)
+	case "challenges":
+		h.async(h.handleChallengesAsync, s, i)
+	case "recent":
+		h.async(h.handleRecentAsync, s, i)
+	case "match_history":
+		h.async(h.handleMatchHistoryAsync, s, i)
+	case "notifications":
+		h.async(h.handleNotificationsAsync, s, i)
+	case "live_leaderboard":
+		h.async(h.handleLiveLeaderboardAsync, s, i)
+	case "settings":
+		h.async(h.handleSettingsAsync, s, i)
+	case "set_timezone":
+		h.async(h.handleSetTimezoneAsync, s, i)
+	case "set_language":
+		h.async(h.handleSetLanguageAsync, s, i)
+	case "job_status":
+		h.async(h.handleJobStatusAsync, s, i)
+	case "feature":
+		h.async(h.handleFeatureAsync, s, i)
+	case "inactive":
+		h.async(h.handleInactiveAsync, s, i)
+	case "purge_inactive":
+		h.async(h.handlePurgeInactiveAsync, s, i)
+	case "command_roles":
+		h.async(h.handleCommandRolesAsync, s, i)
+	case "version":
+		h.async(h.handleVersionAsync, s, i)
+	case "bot_stats":
+		h.async(h.handleBotStatsAsync, s, i)
+	case "help":
+		h.async(h.handleHelpAsync, s, i)
+	case "admin":
+		h.async(h.handleAdminAsync, s, i)
 	}
 }
 
 func (h *CommandHandler) handleAddPlayerAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	//Add a worker to the pool (similar as a ticket in a queue) - We use struct{}{} because we don't need to store any data (optimization)
-	h.workerPool <- struct{}{}
-	//Remove from the pool when the function returns
-	defer func() { <-h.workerPool }()
-
-	// Statistics
-	start := time.Now()
-	h.updateStats(1, 0)
-	defer func() {
-		h.updateStats(-1, time.Since(start))
-	}()
-
-	// Defer response to avoid timeout
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
-	if err != nil {
-		log.Printf("Error deferring response: %v", err)
-		return
-	}
-
 	log.Printf("🔄 Starting processAddPlayer for user interaction")
 	h.processAddPlayer(s, i)
 }
@@ -141,7 +1042,7 @@ func (h *CommandHandler) processAddPlayer(s *discordgo.Session, i *discordgo.Int
 	server := strings.ToLower(options[2].StringValue())
 
 	// context with Timeout to avoid hanging API requests
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := h.commandContext(i)
 	defer cancel()
 
 	type result struct {
@@ -151,10 +1052,10 @@ func (h *CommandHandler) processAddPlayer(s *discordgo.Session, i *discordgo.Int
 	resultChan := make(chan result, 1)
 
 	// Add Player in a goroutine
-	go func() {
+	h.spawn(func() {
 		player, err := h.playerService.AddPlayer(ctx, pseudo, tagline, server)
 		resultChan <- result{player: player, err: err}
-	}()
+	})
 
 	//Wait for Timeout or result
 	select {
@@ -164,7 +1065,7 @@ func (h *CommandHandler) processAddPlayer(s *discordgo.Session, i *discordgo.Int
 			return
 		}
 		log.Printf("✅ AddPlayer success, sending success message")
-		h.sendAppPlayerSuccess(s, i, res.player)
+		h.sendAppPlayerSuccess(ctx, s, i, res.player)
 	case <-ctx.Done():
 		h.sendFollowUp(s, i, "❌ Request timed out. Please try again later.")
 		log.Printf("Add player timed out: %s#%s on server %s", pseudo, tagline, server)
@@ -172,43 +1073,36 @@ func (h *CommandHandler) processAddPlayer(s *discordgo.Session, i *discordgo.Int
 }
 
 func (h *CommandHandler) handleListPlayersAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	h.workerPool <- struct{}{}
-	defer func() { <-h.workerPool }()
-
-	// Statistics
-	start := time.Now()
-	h.updateStats(1, 0)
-	defer func() {
-		h.updateStats(-1, time.Since(start))
-	}()
-
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
-	if err != nil {
-		log.Printf("Error deferring response: %v", err)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := h.commandContext(i)
 	defer cancel()
 
 	// Parallelize the request to the database
 	playersChan := make(chan []*models.Player, 1)
 	errorChan := make(chan error, 1)
 
-	go func() {
-		players, err := h.playerService.GetAllPlayers(ctx)
+	tag := ""
+	if opt, ok := optionsByName(i.ApplicationCommandData().Options)["tag"]; ok {
+		tag = opt.StringValue()
+	}
+
+	h.spawn(func() {
+		var players []*models.Player
+		var err error
+		if tag != "" {
+			players, err = h.playerService.GetPlayersByTag(ctx, tag)
+		} else {
+			players, err = h.playerService.GetAllPlayers(ctx)
+		}
 		if err != nil {
 			errorChan <- err
 			return
 		}
 		playersChan <- players
-	}()
+	})
 
 	select {
 	case players := <-playersChan:
-		h.sendPlayersList(s, i, players)
+		h.sendPlayersList(s, i, players, strings.ToLower(strings.TrimSpace(tag)))
 	case err := <-errorChan:
 		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch players from database: %v", err))
 		log.Printf("Error fetching players from database: %v", err)
@@ -217,6 +1111,150 @@ func (h *CommandHandler) handleListPlayersAsync(s *discordgo.Session, i *discord
 	}
 }
 
+func (h *CommandHandler) handleNotificationsAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	options := optionsByName(i.ApplicationCommandData().Options)
+	if len(options) == 0 {
+		config, err := h.guildConfigService.GetConfig(ctx, i.GuildID)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch notification settings: %v", err))
+			log.Printf("Error fetching guild config %s: %v", i.GuildID, err)
+			return
+		}
+		h.sendNotificationSettings(s, i, config, "🔔 **Notification settings**")
+		return
+	}
+
+	// Templates and style are checked before the update, the other fields are always valid
+	template := ""
+	if opt, ok := options["template"]; ok && !strings.EqualFold(opt.StringValue(), "default") {
+		template = opt.StringValue()
+		if err := models.ValidateNotificationTemplate(template); err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Invalid template: %v", err))
+			return
+		}
+	}
+	if opt, ok := options["style"]; ok && !models.IsNotificationStyle(opt.StringValue()) {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Unknown style **%s**, available: %s", opt.StringValue(), strings.Join(models.NotificationStyles(), ", ")))
+		return
+	}
+	goodLuckTemplate := ""
+	if opt, ok := options["good_luck_message"]; ok && !strings.EqualFold(opt.StringValue(), "default") {
+		goodLuckTemplate = opt.StringValue()
+		if err := models.ValidateGoodLuckTemplate(goodLuckTemplate); err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Invalid good luck message: %v", err))
+			return
+		}
+	}
+
+	config, err := h.guildConfigService.UpdateConfig(ctx, i.GuildID, func(config *models.GuildConfig) error {
+		if opt, ok := options["channel"]; ok {
+			config.NotificationChannelID = opt.ChannelValue(nil).ID
+		}
+		if opt, ok := options["min_lp_delta"]; ok {
+			config.MinLPDelta = int(opt.IntValue())
+		}
+		if _, ok := options["template"]; ok {
+			config.NotificationTemplate = template
+		}
+		if opt, ok := options["style"]; ok {
+			config.NotificationStyle = opt.StringValue()
+		}
+		if opt, ok := options["afk_callout"]; ok {
+			config.AFKCallouts = opt.BoolValue()
+		}
+		if opt, ok := options["enemy_ranks"]; ok {
+			config.EnemyRanks = opt.BoolValue()
+		}
+		if opt, ok := options["good_luck"]; ok {
+			config.GoodLuckMessages = opt.BoolValue()
+		}
+		if _, ok := options["good_luck_message"]; ok {
+			config.GoodLuckTemplate = goodLuckTemplate
+		}
+		return nil
+	})
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to save notification settings: %v", err))
+		log.Printf("Error saving guild config %s: %v", i.GuildID, err)
+		return
+	}
+
+	h.sendNotificationSettings(s, i, config, "✅ **Notification settings updated**")
+}
+
+func (h *CommandHandler) handleSetTimezoneAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+
+	timezone := strings.TrimSpace(i.ApplicationCommandData().Options[0].StringValue())
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" || timezone == "Local" {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Unknown time zone **%s**\n\n💡 Use an IANA name such as `Europe/Paris`, `America/New_York` or `UTC`", timezone))
+		return
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	config, err := h.guildConfigService.UpdateConfig(ctx, i.GuildID, func(config *models.GuildConfig) error {
+		config.Timezone = loc.String()
+		return nil
+	})
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to save time zone: %v", err))
+		log.Printf("Error saving guild config %s: %v", i.GuildID, err)
+		return
+	}
+
+	h.sendFollowUp(s, i, fmt.Sprintf("✅ Time zone set to **%s** (local time: %s)\n📅 Daily recaps are posted at %d:00, weekly recaps on %ss",
+		config.Timezone, time.Now().In(loc).Format("15:04"), models.RecapDispatchHour, models.RecapWeeklyDay))
+}
+
+func (h *CommandHandler) handleSetLanguageAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+
+	language := i.ApplicationCommandData().Options[0].StringValue()
+	if language != languageOff && !services.IsSupportedLanguage(language) {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Unsupported language **%s**", language))
+		return
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	config, err := h.guildConfigService.UpdateConfig(ctx, i.GuildID, func(config *models.GuildConfig) error {
+		config.Language = language
+		if language == languageOff {
+			config.Language = ""
+		}
+		return nil
+	})
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to save language: %v", err))
+		log.Printf("Error saving guild config %s: %v", i.GuildID, err)
+		return
+	}
+
+	if config.Language == "" {
+		h.sendFollowUp(s, i, "✅ Champion names localization disabled")
+		return
+	}
+	h.sendFollowUp(s, i, fmt.Sprintf("✅ Champion names will be displayed in **%s**", config.Language))
+}
+
 func (h *CommandHandler) handleAddPlayerErrors(s *discordgo.Session, i *discordgo.InteractionCreate, err error, pseudo string, tagline string, server string) {
 	var response string
 	if strings.Contains(err.Error(), "already being tracked") {
@@ -230,52 +1268,109 @@ func (h *CommandHandler) handleAddPlayerErrors(s *discordgo.Session, i *discordg
 	h.sendFollowUp(s, i, response)
 }
 
-func (h *CommandHandler) sendAppPlayerSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, player *models.Player) {
-	var rankInfo string
-	if player.Tier == "UNRANKED" {
-		rankInfo = "🆕 **Unranked**"
-	} else {
-		rankInfo = fmt.Sprintf("🏆 **%s %s** • %d LP", player.Tier, player.Rank, player.LeaguePoints)
+func (h *CommandHandler) sendAppPlayerSuccess(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, player *models.Player) {
+	summary := models.NewPlayerSummary(player)
+	f := interactionFormatter(i)
+
+	rankInfo := "🆕 Unranked"
+	if summary.IsRanked() {
+		rankInfo = fmt.Sprintf("🏆 %s • %d LP", summary.RankLabel(), summary.LeaguePoints)
 	}
 
-	response := fmt.Sprintf("✅ Successfully added **%s#%s** (%s)\n📊 **Level:** %d\n%s",
-		player.GameName,
-		player.TagLine,
-		strings.ToUpper(player.Server),
-		player.SummonerLevel,
-		rankInfo,
-	)
-	h.sendFollowUp(s, i, response)
+	embed := newEmbed("✅ Player added").
+		description(fmt.Sprintf("**%s** is now tracked, their ranked games will be announced", summary.RiotID())).
+		tierColor(summary.Tier).
+		thumbnail(h.profileIconURL(ctx, player.ProfileIconID)).
+		inlineField("Solo/Duo", rankInfo).
+		inlineField("Level", f.Int(int(summary.Level))).
+		inlineField("Server", summary.Server).
+		build()
+	h.sendFollowUpEmbed(s, i, embed)
 }
 
-func (h *CommandHandler) sendPlayersList(s *discordgo.Session, i *discordgo.InteractionCreate, players []*models.Player) {
+func (h *CommandHandler) sendPlayersList(s *discordgo.Session, i *discordgo.InteractionCreate, players []*models.Player, tag string) {
 	if len(players) == 0 {
+		if tag != "" {
+			h.sendFollowUp(s, i, fmt.Sprintf("📭 No players tagged **%s**!\nUse `/tag_player` to tag a player.", tag))
+			return
+		}
 		h.sendFollowUp(s, i, "📭 No players tracked yet!\nUse `/add_player` to start tracking.")
 		return
 	}
 
-	var response strings.Builder
-	response.WriteString(fmt.Sprintf("📋 **Tracked Players (%d)**\n\n", len(players)))
+	title := fmt.Sprintf("📋 Tracked Players (%d)", len(players))
+	if tag != "" {
+		title = fmt.Sprintf("📋 Tracked Players tagged %s (%d)", tag, len(players))
+	}
+	embed := newEmbed(title)
 
-	for idx, player := range players {
+	for idx, summary := range models.NewPlayerSummaries(players) {
 		if idx >= 20 {
-			response.WriteString(fmt.Sprintf("... and %d more players\n", len(players)-20))
+			embed.footer(fmt.Sprintf("... and %d more players", len(players)-20))
 			break
 		}
 
-		var rankInfo string
-		if player.Tier == "UNRANKED" {
-			rankInfo = "🆕 Unranked"
-		} else {
-			rankInfo = fmt.Sprintf("🏆 %s %s %d LP", player.Tier, player.Rank, player.LeaguePoints)
+		rankInfo := "🆕 Unranked"
+		if summary.IsRanked() {
+			rankInfo = fmt.Sprintf("🏆 %s %d LP", summary.RankLabel(), summary.LeaguePoints)
 		}
 
-		response.WriteString(fmt.Sprintf("👤 **%s#%s** (%s)\n   📊 Level %d • %s\n\n",
-			player.GameName, player.TagLine, strings.ToUpper(player.Server),
-			player.SummonerLevel, rankInfo))
+		var value strings.Builder
+		value.WriteString(fmt.Sprintf("%s\n📊 Level %d", rankInfo, summary.Level))
+		if len(summary.Tags) > 0 {
+			value.WriteString(fmt.Sprintf("\n🏷️ %s", strings.Join(summary.Tags, ", ")))
+		}
+		if summary.Note != "" {
+			value.WriteString(fmt.Sprintf("\n📝 %s", summary.Note))
+		}
+		embed.inlineField(fmt.Sprintf("👤 %s (%s)", summary.DisplayName(), summary.Server), value.String())
 	}
 
-	h.sendFollowUp(s, i, response.String())
+	h.sendFollowUpEmbed(s, i, embed.build())
+}
+
+func (h *CommandHandler) sendNotificationSettings(s *discordgo.Session, i *discordgo.InteractionCreate, config *models.GuildConfig, title string) {
+	channel := "not set"
+	if config.NotificationChannelID != "" {
+		channel = fmt.Sprintf("<#%s>", config.NotificationChannelID)
+	}
+
+	threshold := "every LP change"
+	if config.MinLPDelta > 0 {
+		threshold = fmt.Sprintf("swings of %d LP or more", config.MinLPDelta)
+	}
+
+	template := "default"
+	if config.NotificationTemplate != "" {
+		template = fmt.Sprintf("`%s`", config.NotificationTemplate)
+	}
+
+	style := config.NotificationStyle
+	if style == "" {
+		style = models.NotificationStyleNeutral
+	}
+
+	afkCallout := "off"
+	if config.AFKCallouts {
+		afkCallout = "on"
+	}
+
+	enemyRanks := "off"
+	if config.EnemyRanks {
+		enemyRanks = "on"
+	}
+
+	goodLuck := "off"
+	if config.GoodLuckMessages {
+		goodLuck = "on (default message)"
+		if config.GoodLuckTemplate != "" {
+			goodLuck = fmt.Sprintf("on, `%s`", config.GoodLuckTemplate)
+		}
+	}
+
+	response := fmt.Sprintf("%s\n📢 **Channel:** %s\n📏 **Threshold:** %s (promotions and demotions are always notified)\n🎭 **Style:** %s\n📝 **Template:** %s\n⚠️ **AFK callouts:** %s\n⚔️ **Enemy ranks:** %s\n🍀 **Good luck messages:** %s",
+		title, channel, threshold, style, template, afkCallout, enemyRanks, goodLuck)
+	h.sendFollowUp(s, i, response)
 }
 
 func (h *CommandHandler) updateStats(delta int64, duration time.Duration) {
@@ -294,17 +1389,42 @@ func (h *CommandHandler) updateStats(delta int64, duration time.Duration) {
 	}
 }
 
-func (h *CommandHandler) GetStats() (total int64, active int64, avgTime time.Duration) {
+func (h *CommandHandler) GetStats() (total int64, active int64, avgTime time.Duration, failedFollowUps int64) {
 	h.stats.mu.Lock()
 	defer h.stats.mu.Unlock()
-	return h.stats.totalCommands, h.stats.activeCommands, h.stats.averageTime
+	return h.stats.totalCommands, h.stats.activeCommands, h.stats.averageTime, h.stats.failedFollowUps
 }
 
+// sendFollowUp answers an interaction with the text laid out as embeds (see textEmbeds), long responses
+// are split over several messages or attached as a file
 func (h *CommandHandler) sendFollowUp(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
-	_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-		Content: content,
-	})
-	if err != nil {
-		log.Printf("Error sending followup message: %v", err)
+	embeds := textEmbeds(content)
+	if len(embeds) > maxMessageChunks {
+		h.followUp(s, i, &discordgo.WebhookParams{
+			Content: longResponseNotice,
+			Files:   []*discordgo.File{responseFile(content)},
+		})
+		return
+	}
+
+	for _, embed := range embeds {
+		if !h.followUp(s, i, &discordgo.WebhookParams{Embeds: []*discordgo.MessageEmbed{embed}}) {
+			return
+		}
 	}
 }
+
+// optionsByName indexes interaction options by name, useful when some options are optional
+func optionsByName(options []*discordgo.ApplicationCommandInteractionDataOption) map[string]*discordgo.ApplicationCommandInteractionDataOption {
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+	return optionMap
+}
+
+func (h *CommandHandler) sendFollowUpEmbed(s *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed) {
+	h.followUp(s, i, &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{embed},
+	})
+}
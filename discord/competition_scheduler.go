@@ -0,0 +1,82 @@
+package discord
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"lp_tracker/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	COMPETITION_CHECK_INTERVAL = 1 * time.Minute
+)
+
+// CompetitionScheduler announces the final results of ended competitions
+type CompetitionScheduler struct {
+	session            *discordgo.Session
+	guildConfigService *services.GuildConfigService
+	competitionService *services.CompetitionService
+}
+
+func NewCompetitionScheduler(s *discordgo.Session, guildConfigService *services.GuildConfigService, competitionService *services.CompetitionService) *CompetitionScheduler {
+	return &CompetitionScheduler{
+		session:            s,
+		guildConfigService: guildConfigService,
+		competitionService: competitionService,
+	}
+}
+
+// Run checks for ended competitions until the context is cancelled
+func (cs *CompetitionScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(COMPETITION_CHECK_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		cs.announceEndedCompetitions(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (cs *CompetitionScheduler) announceEndedCompetitions(ctx context.Context, now time.Time) {
+	competitions, err := cs.competitionService.GetEndedCompetitions(ctx, now)
+	if err != nil {
+		log.Printf("Error fetching ended competitions: %v", err)
+		return
+	}
+
+	for _, competition := range competitions {
+		channelID, err := cs.guildConfigService.GetNotificationChannelID(ctx, competition.GuildID)
+		if err != nil {
+			log.Printf("Error fetching guild config %s: %v", competition.GuildID, err)
+			continue
+		}
+
+		if channelID != "" {
+			scores, err := cs.competitionService.GetStandings(ctx, competition)
+			if err != nil {
+				log.Printf("Error computing final standings of competition %s: %v", competition.Name, err)
+				continue
+			}
+
+			err = sendChannelMessage(cs.session, channelID, formatCompetitionStandings(competition, scores, true))
+			if err != nil {
+				log.Printf("Error announcing competition %s results: %v", competition.Name, err)
+				continue
+			}
+		}
+
+		// Also mark competitions of guilds without channel, so they don't block new competitions
+		err = cs.competitionService.MarkAnnounced(ctx, competition, now)
+		if err != nil {
+			log.Printf("Error marking competition %s announced: %v", competition.Name, err)
+		}
+	}
+}
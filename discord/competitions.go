@@ -0,0 +1,144 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const competitionDateLayout = "2006-01-02"
+
+func (h *CommandHandler) handleCompetitionStartAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	config, err := h.guildConfigService.GetConfig(ctx, i.GuildID)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch server settings: %v", err))
+		log.Printf("Error fetching guild config %s: %v", i.GuildID, err)
+		return
+	}
+	loc := config.Location()
+
+	options := optionsByName(i.ApplicationCommandData().Options)
+	competition := &models.Competition{
+		GuildID:            i.GuildID,
+		Name:               strings.TrimSpace(options["name"].StringValue()),
+		StartAt:            time.Now(),
+		PointsPerLP:        models.DefaultPointsPerLP,
+		PointsPerPromotion: models.DefaultPointsPerPromotion,
+		CreatedBy:          interactionUserID(i),
+	}
+
+	// The end date is inclusive: the competition ends at midnight after the last day
+	endDate, err := time.ParseInLocation(competitionDateLayout, options["end_date"].StringValue(), loc)
+	if err != nil {
+		h.sendFollowUp(s, i, "❌ Invalid end date, expected format: YYYY-MM-DD")
+		return
+	}
+	competition.EndAt = endDate.AddDate(0, 0, 1)
+
+	if opt, ok := options["start_date"]; ok {
+		competition.StartAt, err = time.ParseInLocation(competitionDateLayout, opt.StringValue(), loc)
+		if err != nil {
+			h.sendFollowUp(s, i, "❌ Invalid start date, expected format: YYYY-MM-DD")
+			return
+		}
+	}
+	if opt, ok := options["points_per_lp"]; ok {
+		competition.PointsPerLP = int(opt.IntValue())
+	}
+	if opt, ok := options["points_per_promotion"]; ok {
+		competition.PointsPerPromotion = int(opt.IntValue())
+	}
+
+	err = h.competitionService.StartCompetition(ctx, competition)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to start competition: %v", err))
+		log.Printf("Error starting competition: %v", err)
+		return
+	}
+
+	response := fmt.Sprintf("🏁 Competition **%s** started!\n📅 <t:%d:f> → <t:%d:f>\n🎯 %d point(s) per LP • %d point(s) per promotion\nUse `/competition_standings` to follow the race.",
+		competition.Name, competition.StartAt.Unix(), competition.EndAt.Unix(), competition.PointsPerLP, competition.PointsPerPromotion)
+	if config.NotificationChannelID == "" {
+		response += "\n\n⚠️ No notification channel set, final results will not be announced. Use `/notifications` to set one."
+	}
+	h.sendFollowUp(s, i, response)
+}
+
+func (h *CommandHandler) handleCompetitionStandingsAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	competition, err := h.competitionService.GetCurrentCompetition(ctx, i.GuildID)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch competition: %v", err))
+		log.Printf("Error fetching competition: %v", err)
+		return
+	}
+	if competition == nil {
+		h.sendFollowUp(s, i, "📭 No competition in progress!\nUse `/competition_start` to start one.")
+		return
+	}
+
+	scores, err := h.competitionService.GetStandings(ctx, competition)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to compute standings: %v", err))
+		log.Printf("Error computing competition standings: %v", err)
+		return
+	}
+
+	h.sendFollowUp(s, i, formatCompetitionStandings(competition, scores, false))
+}
+
+func formatCompetitionStandings(competition *models.Competition, scores []*models.CompetitionScore, final bool) string {
+	var response strings.Builder
+	if final {
+		response.WriteString(fmt.Sprintf("🏆 **Final results of %s**\n\n", competition.Name))
+	} else if competition.IsRunning(time.Now()) {
+		response.WriteString(fmt.Sprintf("🏁 **%s** (ends <t:%d:R>)\n\n", competition.Name, competition.EndAt.Unix()))
+	} else if competition.HasEnded(time.Now()) {
+		response.WriteString(fmt.Sprintf("🏁 **%s** (ended, results coming soon)\n\n", competition.Name))
+	} else {
+		response.WriteString(fmt.Sprintf("🏁 **%s** (starts <t:%d:R>)\n\n", competition.Name, competition.StartAt.Unix()))
+	}
+
+	if len(scores) == 0 {
+		response.WriteString("No ranked games played yet.")
+		return response.String()
+	}
+
+	medals := []string{"🥇", "🥈", "🥉"}
+	for idx, score := range scores {
+		if idx >= 20 {
+			response.WriteString(fmt.Sprintf("... and %d more players\n", len(scores)-20))
+			break
+		}
+
+		position := fmt.Sprintf("**%d.**", idx+1)
+		if idx < len(medals) {
+			position = medals[idx]
+		}
+
+		response.WriteString(fmt.Sprintf("%s **%s#%s** • **%d pts** (%+d LP, %d promotion(s), %d games)\n",
+			position, score.GameName, score.TagLine, score.Points, score.LPGained, score.Promotions, score.Games))
+	}
+
+	return response.String()
+}
@@ -0,0 +1,222 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Default digest settings
+const (
+	DefaultDigestWindow    = 30 * time.Second
+	DefaultDigestThreshold = 3
+)
+
+// queuedMessage is a notification waiting to be sent, components are dropped when merged into a digest
+type queuedMessage struct {
+	content    string
+	components []discordgo.MessageComponent
+	sent       func(err error) // Receives the result of the send, nil when nobody waits for it
+}
+
+func (m *queuedMessage) delivered(err error) {
+	if m.sent != nil {
+		m.sent(err)
+	}
+}
+
+// channelBatch holds the messages waiting to be sent to a channel
+type channelBatch struct {
+	guildID   string
+	channelID string
+	messages  []*queuedMessage
+	firstAt   time.Time // Enqueue time of the first message, the batch is sent once the window elapsed
+}
+
+// Dispatcher queues notification messages and sends them per channel. Messages reaching a channel
+// within the digest window are held together: when there are more than the digest threshold, they
+// are merged into a digest embed instead of being sent one by one. Messages are sent by a single
+// worker so a burst of rank changes doesn't flood the channel, the Discord rate limits are left
+// to the session.
+type Dispatcher struct {
+	session         *discordgo.Session
+	window          time.Duration
+	digestThreshold int
+
+	mu      sync.Mutex
+	pending map[string]*channelBatch // Keyed by channel ID
+	order   []string                 // Channels in enqueue order
+}
+
+func NewDispatcher(s *discordgo.Session, window time.Duration, digestThreshold int) *Dispatcher {
+	return &Dispatcher{
+		session:         s,
+		window:          window,
+		digestThreshold: digestThreshold,
+		pending:         make(map[string]*channelBatch),
+	}
+}
+
+// Enqueue adds a message to the pending batch of a channel, it is sent once the digest window elapsed
+func (d *Dispatcher) Enqueue(guildID, channelID, message string, components ...discordgo.MessageComponent) {
+	d.enqueue(guildID, channelID, &queuedMessage{content: message, components: components})
+}
+
+// EnqueueTracked adds a message like Enqueue, sent receives the result of the send (alone or in a digest)
+func (d *Dispatcher) EnqueueTracked(guildID, channelID, message string, sent func(err error), components ...discordgo.MessageComponent) {
+	d.enqueue(guildID, channelID, &queuedMessage{content: message, components: components, sent: sent})
+}
+
+func (d *Dispatcher) enqueue(guildID, channelID string, message *queuedMessage) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	batch, ok := d.pending[channelID]
+	if !ok {
+		batch = &channelBatch{guildID: guildID, channelID: channelID, firstAt: time.Now()}
+		d.pending[channelID] = batch
+		d.order = append(d.order, channelID)
+	}
+	batch.messages = append(batch.messages, message)
+}
+
+// Run sends the batches whose window elapsed until the context is cancelled, the pending batches
+// are then sent right away so a shutdown does not lose them
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.Flush()
+			return
+		case now := <-ticker.C:
+			for _, batch := range d.dueBatches(now) {
+				d.send(batch)
+			}
+		}
+	}
+}
+
+// Flush sends every pending batch without waiting for its window (ex: on shutdown)
+func (d *Dispatcher) Flush() {
+	d.mu.Lock()
+	batches := make([]*channelBatch, 0, len(d.order))
+	for _, channelID := range d.order {
+		batches = append(batches, d.pending[channelID])
+	}
+	d.pending = make(map[string]*channelBatch)
+	d.order = nil
+	d.mu.Unlock()
+
+	for _, batch := range batches {
+		d.send(batch)
+	}
+}
+
+// dueBatches removes and returns the batches whose window elapsed
+func (d *Dispatcher) dueBatches(now time.Time) []*channelBatch {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var due []*channelBatch
+	var order []string
+	for _, channelID := range d.order {
+		batch := d.pending[channelID]
+		if now.Sub(batch.firstAt) < d.window {
+			order = append(order, channelID)
+			continue
+		}
+		due = append(due, batch)
+		delete(d.pending, channelID)
+	}
+	d.order = order
+
+	return due
+}
+
+func (d *Dispatcher) send(batch *channelBatch) {
+	if len(batch.messages) <= d.digestThreshold {
+		for _, message := range batch.messages {
+			err := sendChannelMessage(d.session, batch.channelID, message.content, message.components...)
+			if err != nil {
+				log.Printf("Error sending notification to guild %s: %v", batch.guildID, err)
+			}
+			message.delivered(err)
+		}
+		return
+	}
+
+	for _, digest := range batchEmbeds(batch.messages) {
+		_, err := d.session.ChannelMessageSendEmbed(batch.channelID, digest.embed)
+		if err != nil {
+			log.Printf("Error sending notification digest to guild %s: %v", batch.guildID, err)
+		}
+		for _, message := range digest.messages {
+			message.delivered(err)
+		}
+	}
+}
+
+// digestEmbed is an embed of a digest with the messages merged into its fields
+type digestEmbed struct {
+	embed    *discordgo.MessageEmbed
+	messages []*queuedMessage
+}
+
+// batchEmbeds turns messages into digest embed fields (first line as name, the rest as value),
+// splitting them over several embeds to stay within Discord limits
+func batchEmbeds(messages []*queuedMessage) []*digestEmbed {
+	var embeds []*digestEmbed
+	var current *digestEmbed
+	size := 0
+
+	for _, message := range messages {
+		name, value, _ := strings.Cut(message.content, "\n")
+		name = truncate(name, maxEmbedFieldNameLength)
+		value = truncate(value, maxEmbedFieldValueLength)
+		if strings.TrimSpace(value) == "" {
+			value = "\u200b" // Field values cannot be empty
+		}
+
+		fieldSize := len(name) + len(value)
+		if current == nil || len(current.embed.Fields) >= maxEmbedFields || size+fieldSize > maxEmbedTotalLength-100 {
+			current = &digestEmbed{embed: &discordgo.MessageEmbed{Color: colorDefault}}
+			embeds = append(embeds, current)
+			size = 0
+		}
+
+		current.embed.Fields = append(current.embed.Fields, &discordgo.MessageEmbedField{Name: name, Value: value})
+		current.messages = append(current.messages, message)
+		size += fieldSize
+	}
+
+	for idx, digest := range embeds {
+		digest.embed.Title = fmt.Sprintf("📣 Digest • %d updates", len(messages))
+		if len(embeds) > 1 {
+			digest.embed.Title += fmt.Sprintf(" (%d/%d)", idx+1, len(embeds))
+		}
+	}
+
+	return embeds
+}
+
+// truncate shortens a string to max bytes without cutting a character in half
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	cut := max - len("…")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
@@ -0,0 +1,163 @@
+package discord
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Embed colors of the responses
+const (
+	colorDefault = 0x5865F2 // Discord blurple
+	colorSuccess = 0x57F287
+	colorWarning = 0xFEE75C
+	colorError   = 0xED4245
+)
+
+// Discord embed limits
+const (
+	maxEmbedTitleLength       = 256
+	maxEmbedDescriptionLength = 4096
+	maxEmbedFields            = 25
+	maxEmbedFieldNameLength   = 256
+	maxEmbedFieldValueLength  = 1024
+	maxEmbedTotalLength       = 6000
+)
+
+// Embed colors by tier, unranked players keep the default color
+var tierColors = map[string]int{
+	"IRON":        0x6B5B57,
+	"BRONZE":      0x8C5A3C,
+	"SILVER":      0x99A9B3,
+	"GOLD":        0xD6A84B,
+	"PLATINUM":    0x4E9996,
+	"EMERALD":     0x2EA66A,
+	"DIAMOND":     0x576BCE,
+	"MASTER":      0x9D48E0,
+	"GRANDMASTER": 0xCD4545,
+	"CHALLENGER":  0xF4C874,
+}
+
+// colorOfTier returns the embed color of a tier, the default color for unranked players
+func colorOfTier(tier string) int {
+	if color, ok := tierColors[tier]; ok {
+		return color
+	}
+	return colorDefault
+}
+
+// embedBuilder builds the embeds of the command responses with a consistent layout: default color,
+// short values in inline fields, and the time of the response
+type embedBuilder struct {
+	embed *discordgo.MessageEmbed
+}
+
+func newEmbed(title string) *embedBuilder {
+	return &embedBuilder{embed: &discordgo.MessageEmbed{
+		Title:     truncate(title, maxEmbedTitleLength),
+		Color:     colorDefault,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}}
+}
+
+func (b *embedBuilder) description(text string) *embedBuilder {
+	b.embed.Description = truncate(text, maxEmbedDescriptionLength)
+	return b
+}
+
+func (b *embedBuilder) color(color int) *embedBuilder {
+	b.embed.Color = color
+	return b
+}
+
+// tierColor colors the embed with the tier of a player
+func (b *embedBuilder) tierColor(tier string) *embedBuilder {
+	return b.color(colorOfTier(tier))
+}
+
+// thumbnail sets the image of the top right corner (ex: a profile icon), ignored when empty
+func (b *embedBuilder) thumbnail(url string) *embedBuilder {
+	if url != "" {
+		b.embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: url}
+	}
+	return b
+}
+
+// field adds a field on its own line, the fields over the Discord limit are dropped
+func (b *embedBuilder) field(name, value string) *embedBuilder {
+	return b.addField(name, value, false)
+}
+
+// inlineField adds a field sharing its line with the next inline fields (up to 3 per line)
+func (b *embedBuilder) inlineField(name, value string) *embedBuilder {
+	return b.addField(name, value, true)
+}
+
+func (b *embedBuilder) addField(name, value string, inline bool) *embedBuilder {
+	if len(b.embed.Fields) >= maxEmbedFields {
+		return b
+	}
+	// Discord rejects the empty values
+	if value == "" {
+		value = "-"
+	}
+	b.embed.Fields = append(b.embed.Fields, &discordgo.MessageEmbedField{Name: truncate(name, maxEmbedFieldNameLength), Value: truncate(value, maxEmbedFieldValueLength), Inline: inline})
+	return b
+}
+
+func (b *embedBuilder) footer(text string) *embedBuilder {
+	b.embed.Footer = &discordgo.MessageEmbedFooter{Text: text}
+	return b
+}
+
+func (b *embedBuilder) build() *discordgo.MessageEmbed {
+	return b.embed
+}
+
+// textEmbeds lays out a text response as embeds, split when it exceeds the description limit.
+// A bold first line (ex: "📋 **Tracked Players (3)**") becomes the title, and the color follows
+// the status emoji the response starts with (❌ error, ⚠️ warning, ✅ success).
+func textEmbeds(content string) []*discordgo.MessageEmbed {
+	content = strings.TrimSpace(content)
+
+	title := ""
+	firstLine, rest, _ := strings.Cut(content, "\n")
+	if isHeading(firstLine) {
+		title = strings.TrimSpace(strings.ReplaceAll(firstLine, "**", ""))
+		content = strings.TrimLeft(rest, "\n")
+	}
+
+	color := colorDefault
+	switch {
+	case strings.HasPrefix(firstLine, "❌"):
+		color = colorError
+	case strings.HasPrefix(firstLine, "⚠️"):
+		color = colorWarning
+	case strings.HasPrefix(firstLine, "✅"):
+		color = colorSuccess
+	}
+
+	chunks := splitMessage(content, maxEmbedDescriptionLength)
+	embeds := make([]*discordgo.MessageEmbed, 0, len(chunks))
+	for idx, chunk := range chunks {
+		embed := &discordgo.MessageEmbed{Description: chunk, Color: color}
+		if idx == 0 {
+			embed.Title = title
+		}
+		embeds = append(embeds, embed)
+	}
+	embeds[len(embeds)-1].Timestamp = time.Now().Format(time.RFC3339)
+	return embeds
+}
+
+// isHeading checks if a line is a bold title, with an optional emoji before it
+func isHeading(line string) bool {
+	line = strings.TrimSpace(line)
+	if !strings.HasSuffix(line, "**") || strings.Count(line, "**") != 2 || utf8.RuneCountInString(line) > maxEmbedTitleLength {
+		return false
+	}
+	prefix, _, _ := strings.Cut(line, "**")
+	return utf8.RuneCountInString(strings.TrimSpace(prefix)) <= 2
+}
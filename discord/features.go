@@ -0,0 +1,80 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func featureFlagChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(models.FeatureFlags))
+	for _, flag := range models.FeatureFlags {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: flag.Name, Value: flag.Name})
+	}
+	return choices
+}
+
+func (h *CommandHandler) handleFeatureAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+
+	options := optionsByName(i.ApplicationCommandData().Options)
+	name := ""
+	if opt, ok := options["name"]; ok {
+		name = opt.StringValue()
+	}
+	enabledOpt, setValue := options["enabled"]
+	reset := false
+	if opt, ok := options["reset"]; ok {
+		reset = opt.BoolValue()
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	if name != "" && (setValue || reset) {
+		var err error
+		if reset {
+			err = h.featureFlags.ClearFlag(ctx, name, i.GuildID)
+		} else {
+			err = h.featureFlags.SetFlag(ctx, name, i.GuildID, enabledOpt.BoolValue(), interactionUserID(i))
+		}
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to update feature **%s**: %v", name, err))
+			log.Printf("Error updating feature flag %s of guild %s: %v", name, i.GuildID, err)
+			return
+		}
+	} else if setValue || reset {
+		h.sendFollowUp(s, i, "❌ Give the name of the feature to switch")
+		return
+	}
+
+	states, err := h.featureFlags.GetFlags(ctx, i.GuildID)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch feature flags: %v", err))
+		log.Printf("Error fetching feature flags of guild %s: %v", i.GuildID, err)
+		return
+	}
+
+	var builder strings.Builder
+	builder.WriteString("🚩 **Feature flags**\n")
+	for _, state := range states {
+		if name != "" && state.Name != name {
+			continue
+		}
+
+		status := "❌ off"
+		if state.Enabled {
+			status = "✅ on"
+		}
+		builder.WriteString(fmt.Sprintf("%s `%s` • %s (%s)\n", status, state.Name, state.Description, state.Source))
+	}
+
+	h.sendFollowUp(s, i, builder.String())
+}
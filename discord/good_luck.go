@@ -0,0 +1,141 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+)
+
+// GoodLuckWatcher wishes good luck in the notification channels when a tracked player starts their first ranked game of the day.
+// The last live game is stored on the players so a restart doesn't announce the ongoing games again.
+type GoodLuckWatcher struct {
+	dispatcher         *Dispatcher
+	guildConfigService *services.GuildConfigService
+	playerService      *services.PlayerService
+	dataDragon         *services.DataDragonService
+	interval           time.Duration
+}
+
+func NewGoodLuckWatcher(dispatcher *Dispatcher, guildConfigService *services.GuildConfigService, playerService *services.PlayerService, dataDragon *services.DataDragonService, interval time.Duration) *GoodLuckWatcher {
+	return &GoodLuckWatcher{
+		dispatcher:         dispatcher,
+		guildConfigService: guildConfigService,
+		playerService:      playerService,
+		dataDragon:         dataDragon,
+		interval:           interval,
+	}
+}
+
+// Run checks the games in progress every interval until the context is cancelled
+func (g *GoodLuckWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		err := g.Check(ctx)
+		if err != nil {
+			log.Printf("Error checking games in progress: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check posts the good luck messages of the ranked games started since the last check.
+// The Riot API is only called when at least one guild enabled the messages.
+func (g *GoodLuckWatcher) Check(ctx context.Context) error {
+	configs, err := g.guildConfigService.GetNotificationConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch guild configs: %w", err)
+	}
+
+	var enabled []*models.GuildConfig
+	for _, config := range configs {
+		if config.GoodLuckMessages {
+			enabled = append(enabled, config)
+		}
+	}
+	if len(enabled) == 0 {
+		return nil
+	}
+
+	players, err := g.playerService.GetAllPlayers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch players: %w", err)
+	}
+
+	for _, player := range players {
+		if player.Paused {
+			continue
+		}
+
+		game, err := g.playerService.GetActiveGame(ctx, player)
+		if err != nil {
+			log.Printf("Error fetching game in progress of %s#%s: %v", player.GameName, player.TagLine, err)
+			continue
+		}
+		if game == nil || game.GameID == player.LiveGameID || !models.IsRankedQueue(game.GameQueueConfigID) {
+			continue
+		}
+
+		err = g.playerService.SetLiveGameID(ctx, player, game.GameID)
+		if err != nil {
+			log.Printf("Error recording game in progress of %s#%s: %v", player.GameName, player.TagLine, err)
+			continue
+		}
+
+		g.announce(ctx, player, game, enabled)
+	}
+
+	return nil
+}
+
+// announce posts the good luck message in the guilds where it is the first ranked game of the player today
+func (g *GoodLuckWatcher) announce(ctx context.Context, player *models.Player, game *services.CurrentGameInfoDTO, configs []*models.GuildConfig) {
+	champion := ""
+	for _, participant := range game.Participants {
+		if participant.PUUID == player.PUUID {
+			champion = g.dataDragon.ChampionIDByKey(ctx, participant.ChampionID)
+			break
+		}
+	}
+
+	now := time.Now()
+	for _, config := range configs {
+		if config.IsMuted(player.PUUID) {
+			continue
+		}
+
+		// "Today" follows the time zone of each guild
+		local := now.In(config.Location())
+		midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+		played, err := g.playerService.HasPlayedSince(ctx, player, midnight)
+		if err != nil {
+			log.Printf("Error checking games of %s#%s: %v", player.GameName, player.TagLine, err)
+			continue
+		}
+		if played {
+			continue
+		}
+
+		name := champion
+		if name != "" && config.Language != "" {
+			name = g.dataDragon.LocalizedChampionName(ctx, champion, config.Language)
+		}
+		if name == "" {
+			name = "an unknown champion"
+		}
+
+		log.Printf("🍀 First ranked game of the day for %s#%s in guild %s", player.GameName, player.TagLine, config.GuildID)
+		message := models.RenderGoodLuckMessage(config.GoodLuckTemplate, player, name, game.GameQueueConfigID)
+		g.dispatcher.Enqueue(config.GuildID, config.NotificationChannelID, message)
+	}
+}
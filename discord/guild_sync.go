@@ -0,0 +1,73 @@
+package discord
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"lp_tracker/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// GuildSync keeps the stored guild configurations in line with the guilds the bot is member of
+type GuildSync struct {
+	guildConfigService *services.GuildConfigService
+}
+
+func NewGuildSync(guildConfigService *services.GuildConfigService) *GuildSync {
+	return &GuildSync{
+		guildConfigService: guildConfigService,
+	}
+}
+
+// HandleReady reconciles every stored configuration with the guilds listed on connection
+func (gs *GuildSync) HandleReady(s *discordgo.Session, r *discordgo.Ready) {
+	guildIDs := make([]string, 0, len(r.Guilds))
+	for _, guild := range r.Guilds {
+		guildIDs = append(guildIDs, guild.ID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	created, left, err := gs.guildConfigService.ReconcileGuilds(ctx, guildIDs)
+	if err != nil {
+		log.Printf("Error reconciling guild configs: %v", err)
+		return
+	}
+	log.Printf("Guild configs reconciled: %d created, %d marked inactive", created, left)
+}
+
+// HandleGuildCreate creates the default configuration of a joined guild
+func (gs *GuildSync) HandleGuildCreate(s *discordgo.Session, g *discordgo.GuildCreate) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	created, err := gs.guildConfigService.GuildJoined(ctx, g.ID)
+	if err != nil {
+		log.Printf("Error registering guild %s: %v", g.ID, err)
+		return
+	}
+	if created {
+		log.Printf("Joined guild %s (%s), default config created", g.Name, g.ID)
+	}
+}
+
+// HandleGuildDelete marks the configuration of a departed guild inactive
+func (gs *GuildSync) HandleGuildDelete(s *discordgo.Session, g *discordgo.GuildDelete) {
+	// Outages make guilds unavailable without the bot leaving them
+	if g.Unavailable {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := gs.guildConfigService.GuildLeft(ctx, g.ID, time.Now())
+	if err != nil {
+		log.Printf("Error marking guild %s inactive: %v", g.ID, err)
+		return
+	}
+	log.Printf("Left guild %s, notifications stopped", g.ID)
+}
@@ -0,0 +1,152 @@
+package discord
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Heatmap layout, in pixels
+const (
+	heatmapCellSize   = 22
+	heatmapCellGap    = 2
+	heatmapLabelWidth = 36 // Day names on the left
+	heatmapHeaderSize = 20 // Hours on top
+	heatmapPadding    = 10
+)
+
+var (
+	heatmapBackground = color.RGBA{0x2B, 0x2D, 0x31, 0xFF} // Discord dark theme
+	heatmapEmpty      = color.RGBA{0x38, 0x3A, 0x40, 0xFF}
+	heatmapFull       = color.RGBA{0x57, 0xF2, 0x87, 0xFF} // Discord green
+	heatmapText       = color.RGBA{0xB5, 0xBA, 0xC1, 0xFF}
+)
+
+func (h *CommandHandler) handleHeatmapAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	pseudo, tagline, server := playerIdentity(optionsByName(i.ApplicationCommandData().Options))
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	// Hours follow the server time zone, UTC in DMs
+	loc := time.UTC
+	if i.GuildID != "" {
+		config, err := h.guildConfigService.GetConfig(ctx, i.GuildID)
+		if err != nil {
+			log.Printf("Error fetching guild config %s: %v", i.GuildID, err)
+		} else {
+			loc = config.Location()
+		}
+	}
+
+	heatmap, err := h.playerService.GetActivityHeatmap(ctx, player, loc)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch games: %v", err))
+		log.Printf("Error fetching heatmap of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if heatmap.Total == 0 {
+		h.sendFollowUp(s, i, fmt.Sprintf("📭 No tracked games for **%s#%s** in the last %d days!", player.GameName, player.TagLine, models.HeatmapDays))
+		return
+	}
+
+	chart, err := renderActivityHeatmap(heatmap)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to draw the heatmap: %v", err))
+		log.Printf("Error drawing heatmap of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	f := interactionFormatter(i)
+	description := fmt.Sprintf("%s ranked games between %s and %s (%s)", f.Int(heatmap.Total), f.Date(heatmap.Start.In(loc)), f.Date(heatmap.End.In(loc)), loc)
+	if weekday, hour, ok := heatmap.Peak(); ok {
+		description += fmt.Sprintf("\nMost active on **%s** around **%02d:00**", weekday, hour)
+	}
+
+	h.followUp(s, i, &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       fmt.Sprintf("🗓️ Activity of %s#%s", player.GameName, player.TagLine),
+			Description: description,
+			Color:       colorDefault,
+			Image:       &discordgo.MessageEmbedImage{URL: "attachment://heatmap.png"},
+		}},
+		Files: []*discordgo.File{{
+			Name:        "heatmap.png",
+			ContentType: "image/png",
+			Reader:      bytes.NewReader(chart),
+		}},
+	})
+}
+
+// renderActivityHeatmap draws the games per day of the week (rows) and hour (columns) as a PNG,
+// the color of a cell goes from gray to green with its share of the busiest cell
+func renderActivityHeatmap(heatmap *models.ActivityHeatmap) ([]byte, error) {
+	step := heatmapCellSize + heatmapCellGap
+	width := heatmapPadding*2 + heatmapLabelWidth + 24*step
+	height := heatmapPadding*2 + heatmapHeaderSize + len(models.HeatmapWeekdays)*step
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{heatmapBackground}, image.Point{}, draw.Src)
+
+	drawer := &font.Drawer{Dst: img, Src: &image.Uniform{heatmapText}, Face: basicfont.Face7x13}
+	left, top := heatmapPadding+heatmapLabelWidth, heatmapPadding+heatmapHeaderSize
+
+	for hour := 0; hour < 24; hour += 3 {
+		drawer.Dot = fixed.P(left+hour*step+4, top-6)
+		drawer.DrawString(fmt.Sprintf("%02d", hour))
+	}
+
+	busiest := heatmap.Max()
+	for row, weekday := range models.HeatmapWeekdays {
+		y := top + row*step
+		drawer.Dot = fixed.P(heatmapPadding, y+heatmapCellSize/2+4)
+		drawer.DrawString(weekday.String()[:3])
+
+		for hour, games := range heatmap.Games[row] {
+			cell := heatmapEmpty
+			if games > 0 && busiest > 0 {
+				cell = blendColor(heatmapEmpty, heatmapFull, 0.25+0.75*float64(games)/float64(busiest))
+			}
+			x := left + hour*step
+			draw.Draw(img, image.Rect(x, y, x+heatmapCellSize, y+heatmapCellSize), &image.Uniform{cell}, image.Point{}, draw.Src)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// blendColor mixes two colors, ratio 0 returns from and 1 returns to
+func blendColor(from, to color.RGBA, ratio float64) color.RGBA {
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*ratio)
+	}
+	return color.RGBA{mix(from.R, to.R), mix(from.G, to.G), mix(from.B, to.B), 0xFF}
+}
@@ -0,0 +1,170 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleHelpAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if opt, ok := optionsByName(i.ApplicationCommandData().Options)["command"]; ok {
+		name := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(opt.StringValue())), "/")
+		for _, command := range h.helpCommands(i) {
+			if command.Name == name {
+				h.sendFollowUpEmbed(s, i, buildCommandHelpEmbed(command))
+				return
+			}
+		}
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Unknown command **/%s**, use `/help` to list the commands", name))
+		return
+	}
+
+	h.followUp(s, i, &discordgo.WebhookParams{
+		Embeds: buildHelpEmbeds(h.helpCommands(i)),
+	})
+}
+
+// helpCommands returns the commands the user can see where they called /help: the DM commands in DMs,
+// the admin commands for the members allowed by Discord and /admin for the bot owner only
+func (h *CommandHandler) helpCommands(i *discordgo.InteractionCreate) []*discordgo.ApplicationCommand {
+	visible := make([]*discordgo.ApplicationCommand, 0, len(commands))
+	for _, command := range commands {
+		if i.GuildID == "" && !dmCommands[command.Name] {
+			continue
+		}
+		if ownerOnlyCommands[command.Name] && !h.isBotOwner(i) {
+			continue
+		}
+		if command.DefaultMemberPermissions != nil && i.Member != nil && i.Member.Permissions&*command.DefaultMemberPermissions == 0 {
+			continue
+		}
+		visible = append(visible, command)
+	}
+	return visible
+}
+
+// buildHelpEmbeds lists the commands with their description, the admin commands in their own embed.
+// The lists are split in several embeds when they would not fit in one.
+func buildHelpEmbeds(visible []*discordgo.ApplicationCommand) []*discordgo.MessageEmbed {
+	var memberLines, adminLines []string
+	for _, command := range visible {
+		line := fmt.Sprintf("`/%s` %s", command.Name, command.Description)
+		if command.DefaultMemberPermissions != nil {
+			adminLines = append(adminLines, line)
+			continue
+		}
+		memberLines = append(memberLines, line)
+	}
+
+	embeds := helpSectionEmbeds("📖 Commands", memberLines)
+	embeds = append(embeds, helpSectionEmbeds("🛠️ Server admin commands", adminLines)...)
+	if len(embeds) > 0 {
+		embeds[len(embeds)-1].Footer = &discordgo.MessageEmbedFooter{Text: "/help <command> shows the options of a command"}
+		embeds[len(embeds)-1].Timestamp = time.Now().Format(time.RFC3339)
+	}
+	return embeds
+}
+
+func helpSectionEmbeds(title string, lines []string) []*discordgo.MessageEmbed {
+	var embeds []*discordgo.MessageEmbed
+	var description strings.Builder
+	flush := func() {
+		if description.Len() == 0 {
+			return
+		}
+		embedTitle := title
+		if len(embeds) > 0 {
+			embedTitle += " (continued)"
+		}
+		embeds = append(embeds, &discordgo.MessageEmbed{
+			Title:       embedTitle,
+			Description: description.String(),
+			Color:       colorDefault,
+		})
+		description.Reset()
+	}
+
+	for _, line := range lines {
+		if description.Len()+len(line)+1 > maxEmbedDescriptionLength {
+			flush()
+		}
+		description.WriteString(line + "\n")
+	}
+	flush()
+	return embeds
+}
+
+// buildCommandHelpEmbed shows the usage of a command (one line per subcommand) and describes its options
+func buildCommandHelpEmbed(command *discordgo.ApplicationCommand) *discordgo.MessageEmbed {
+	var usage, options strings.Builder
+	writeCommandHelp(&usage, &options, make(map[string]bool), "/"+command.Name, command.Options)
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📖 /" + command.Name,
+		Description: command.Description,
+		Color:       colorDefault,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Usage", Value: usage.String()},
+		},
+		Footer:    &discordgo.MessageEmbedFooter{Text: "<option> is required, [option] is optional"},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if options.Len() > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Options", Value: truncate(options.String(), maxEmbedFieldValueLength)})
+	}
+	if command.DefaultMemberPermissions != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Permissions", Value: "Manage Server (or a role allowed in Server Settings > Integrations)"})
+	}
+	return embed
+}
+
+// writeCommandHelp writes the usage lines of a command path and the descriptions of its options,
+// walking down the subcommand groups and subcommands. The options shared by several subcommands are described once.
+func writeCommandHelp(usage, descriptions *strings.Builder, described map[string]bool, path string, options []*discordgo.ApplicationCommandOption) {
+	var arguments []string
+	for _, option := range options {
+		switch option.Type {
+		case discordgo.ApplicationCommandOptionSubCommandGroup, discordgo.ApplicationCommandOptionSubCommand:
+			writeCommandHelp(usage, descriptions, described, path+" "+option.Name, option.Options)
+			continue
+		}
+
+		if option.Required {
+			arguments = append(arguments, "<"+option.Name+">")
+		} else {
+			arguments = append(arguments, "["+option.Name+"]")
+		}
+		if !described[option.Name] {
+			described[option.Name] = true
+			descriptions.WriteString(fmt.Sprintf("`%s` %s%s\n", option.Name, option.Description, helpChoices(option)))
+		}
+	}
+
+	// A command with subcommands has no options of its own
+	if len(arguments) > 0 || !hasSubcommands(options) {
+		usage.WriteString(fmt.Sprintf("`%s`\n", strings.TrimSpace(path+" "+strings.Join(arguments, " "))))
+	}
+}
+
+func hasSubcommands(options []*discordgo.ApplicationCommandOption) bool {
+	for _, option := range options {
+		if option.Type == discordgo.ApplicationCommandOptionSubCommandGroup || option.Type == discordgo.ApplicationCommandOptionSubCommand {
+			return true
+		}
+	}
+	return false
+}
+
+// helpChoices lists the choices of an option (ex: " (Remove, Pause)"), the long lists are left to the Discord picker
+func helpChoices(option *discordgo.ApplicationCommandOption) string {
+	if len(option.Choices) == 0 || len(option.Choices) > 5 {
+		return ""
+	}
+	names := make([]string, 0, len(option.Choices))
+	for _, choice := range option.Choices {
+		names = append(names, choice.Name)
+	}
+	return " (" + strings.Join(names, ", ") + ")"
+}
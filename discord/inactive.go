@@ -0,0 +1,66 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Players listed by /inactive
+const inactiveMaxPlayers = 40
+
+func (h *CommandHandler) handleInactiveAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	days := defaultPurgeDays
+	if opt, ok := optionsByName(i.ApplicationCommandData().Options)["days"]; ok {
+		days = int(opt.IntValue())
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	inactive, err := h.playerService.GetInactivePlayers(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch inactive players: %v", err))
+		log.Printf("Error fetching inactive players: %v", err)
+		return
+	}
+
+	h.sendFollowUp(s, i, formatInactiveReport(inactive, days))
+}
+
+func formatInactiveReport(inactive []*models.InactivePlayer, days int) string {
+	if len(inactive) == 0 {
+		return fmt.Sprintf("✅ Every tracked player played a ranked game in the last %d days", days)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("💤 **%d players without ranked games in the last %d days**\n", len(inactive), days))
+	for idx, entry := range inactive {
+		if idx >= inactiveMaxPlayers {
+			builder.WriteString(fmt.Sprintf("... and %d more players\n", len(inactive)-inactiveMaxPlayers))
+			break
+		}
+		builder.WriteString(formatInactivePlayer(entry))
+	}
+	builder.WriteString("💡 The bot owner can remove or pause them with /purge_inactive")
+	return builder.String()
+}
+
+// formatInactivePlayer formats a line of the inactive players lists, with the last stored game
+func formatInactivePlayer(entry *models.InactivePlayer) string {
+	summary := models.NewPlayerSummary(entry.Player)
+	lastGame := fmt.Sprintf("no stored game, tracked since <t:%d:R>", entry.Player.CreatedAt.Unix())
+	if entry.LastGameAt != nil {
+		lastGame = fmt.Sprintf("last game <t:%d:R>", entry.LastGameAt.Unix())
+	}
+	paused := ""
+	if entry.Player.Paused {
+		paused = " • paused"
+	}
+	return fmt.Sprintf("• **%s** (%s) • %s%s\n", summary.DisplayName(), summary.RankLabel(), lastGame, paused)
+}
@@ -0,0 +1,109 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Number of jobs listed by /job_status without a job ID
+const recentJobsLimit = 10
+
+var jobStatusEmojis = map[string]string{
+	models.JobPending:   "🕒",
+	models.JobRunning:   "⏳",
+	models.JobSucceeded: "✅",
+	models.JobFailed:    "❌",
+	models.JobCancelled: "🚫",
+}
+
+func (h *CommandHandler) handleJobStatusAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+
+	options := optionsByName(i.ApplicationCommandData().Options)
+	jobID := ""
+	if opt, ok := options["job_id"]; ok {
+		jobID = strings.TrimSpace(opt.StringValue())
+	}
+	cancelJob := false
+	if opt, ok := options["cancel"]; ok {
+		cancelJob = opt.BoolValue()
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	if cancelJob {
+		if jobID == "" {
+			h.sendFollowUp(s, i, "❌ Give the ID of the job to cancel")
+			return
+		}
+
+		job, err := h.jobService.CancelJob(ctx, i.GuildID, jobID)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to cancel job: %v", err))
+			return
+		}
+		h.sendFollowUp(s, i, fmt.Sprintf("🚫 Cancelling **%s** (job `%s`), the result will be posted once it stopped", job.Name, job.ShortID()))
+		return
+	}
+
+	if jobID != "" {
+		job, err := h.jobService.GetJob(ctx, i.GuildID, jobID)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch job: %v", err))
+			log.Printf("Error fetching job %s: %v", jobID, err)
+			return
+		}
+		if job == nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Job `%s` not found", jobID))
+			return
+		}
+		h.sendFollowUp(s, i, formatJob(job))
+		return
+	}
+
+	jobs, err := h.jobService.GetRecentJobs(ctx, i.GuildID, recentJobsLimit)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch jobs: %v", err))
+		log.Printf("Error fetching jobs of guild %s: %v", i.GuildID, err)
+		return
+	}
+	if len(jobs) == 0 {
+		h.sendFollowUp(s, i, "📭 No background jobs for this server")
+		return
+	}
+
+	var builder strings.Builder
+	builder.WriteString("🧰 **Latest background jobs**\n")
+	for _, job := range jobs {
+		builder.WriteString(fmt.Sprintf("%s `%s` **%s** • %s • %d%% • <t:%d:R>\n",
+			jobStatusEmojis[job.Status], job.ShortID(), job.Name, job.Status, job.Progress, job.CreatedAt.Unix()))
+	}
+
+	h.sendFollowUp(s, i, builder.String())
+}
+
+func formatJob(job *models.Job) string {
+	message := fmt.Sprintf("%s **%s** (job `%s`)\n📌 **Status:** %s • %d%%\n👤 Requested by <@%s> <t:%d:R>",
+		jobStatusEmojis[job.Status], job.Name, job.ShortID(), job.Status, job.Progress, job.RequestedBy, job.CreatedAt.Unix())
+
+	if job.FinishedAt != nil {
+		message += fmt.Sprintf("\n🏁 Finished <t:%d:R>", job.FinishedAt.Unix())
+	}
+	if job.Error != "" {
+		message += fmt.Sprintf("\n⚠️ %s", job.Error)
+	}
+	if job.Result != "" {
+		message += "\n" + job.Result
+	}
+
+	return message
+}
@@ -0,0 +1,151 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Results waiting to be posted, Deliver blocks when the queue is full
+const jobResultQueueSize = 32
+
+// JobResult is the outcome of a long job, posted in the channel it was requested from
+type JobResult struct {
+	JobID       string
+	Name        string
+	GuildID     string
+	ChannelID   string
+	RequesterID string
+	Content     string
+	Err         error
+	StartedAt   time.Time
+	FinishedAt  time.Time
+}
+
+// JobResultDispatcher posts the results of long jobs as regular channel messages mentioning the
+// requester, since followups cannot be sent once the interaction token expired (15 minutes)
+type JobResultDispatcher struct {
+	session *discordgo.Session
+	results chan *JobResult
+	stopped chan struct{} // Closed when Run returns
+}
+
+func NewJobResultDispatcher(s *discordgo.Session) *JobResultDispatcher {
+	return &JobResultDispatcher{
+		session: s,
+		results: make(chan *JobResult, jobResultQueueSize),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Deliver queues a result to be posted by Run
+func (d *JobResultDispatcher) Deliver(result *JobResult) {
+	select {
+	case d.results <- result:
+	case <-d.stopped:
+		log.Printf("Dropping result of job %s for user %s: dispatcher stopped", result.Name, result.RequesterID)
+	}
+}
+
+// Run posts the queued results until the context is cancelled
+func (d *JobResultDispatcher) Run(ctx context.Context) {
+	defer close(d.stopped)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case result := <-d.results:
+			d.send(result)
+		}
+	}
+}
+
+func (d *JobResultDispatcher) send(result *JobResult) {
+	if result.ChannelID == "" {
+		log.Printf("Dropping result of job %s for user %s: no channel", result.Name, result.RequesterID)
+		return
+	}
+
+	message := formatJobResult(result)
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		err = sendChannelEmbeds(d.session, result.ChannelID, result.RequesterID, message)
+		if err == nil {
+			return
+		}
+	}
+
+	log.Printf("Error posting result of job %s (guild %s, user %s): %v", result.Name, result.GuildID, result.RequesterID, err)
+}
+
+func formatJobResult(result *JobResult) string {
+	duration := result.FinishedAt.Sub(result.StartedAt).Round(time.Second)
+
+	if result.Err != nil {
+		return fmt.Sprintf("❌ **%s** (job `%s`) stopped after %v: %v", result.Name, result.JobID, duration, result.Err)
+	}
+
+	message := fmt.Sprintf("✅ **%s** (job `%s`) finished in %v", result.Name, result.JobID, duration)
+	if result.Content != "" {
+		message += "\n" + result.Content
+	}
+	return message
+}
+
+// SetJobResultDispatcher sets the dispatcher posting the results of long jobs
+func (h *CommandHandler) SetJobResultDispatcher(d *JobResultDispatcher) {
+	h.jobResults = d
+}
+
+// startLongJob acknowledges the interaction right away and runs the job in the background, outside
+// of the worker pool. The job is persisted (see /job_status) and its result is delivered as a channel
+// message by the job result dispatcher.
+func (h *CommandHandler) startLongJob(s *discordgo.Session, i *discordgo.InteractionCreate, jobType, name string, run services.JobFunc) {
+	if h.jobResults == nil || i.ChannelID == "" {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ %s cannot be started here", name))
+		return
+	}
+
+	job := &models.Job{
+		Type:        jobType,
+		Name:        name,
+		GuildID:     i.GuildID,
+		ChannelID:   i.ChannelID,
+		RequestedBy: interactionUserID(i),
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	err := h.jobService.Start(ctx, job, run, func(job *models.Job) {
+		result := &JobResult{
+			JobID:       job.ShortID(),
+			Name:        job.Name,
+			GuildID:     job.GuildID,
+			ChannelID:   job.ChannelID,
+			RequesterID: job.RequestedBy,
+			Content:     job.Result,
+			StartedAt:   job.CreatedAt,
+			FinishedAt:  time.Now(),
+		}
+		if job.Status != models.JobSucceeded {
+			result.Err = errors.New(job.Error)
+		}
+		h.jobResults.Deliver(result)
+	})
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to start %s: %v", name, err))
+		log.Printf("Error starting job %s: %v", name, err)
+		return
+	}
+
+	h.sendFollowUp(s, i, fmt.Sprintf("⏳ **%s** started (job `%s`), the result will be posted in this channel once done. Follow it with `/job_status`",
+		name, job.ShortID()))
+}
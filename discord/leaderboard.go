@@ -0,0 +1,126 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Rows of the leaderboards, the other players are summed up so the message stays under the Discord limit
+const leaderboardMaxRows = 20
+
+var leaderboardMedals = []string{"🥇", "🥈", "🥉"}
+
+func (h *CommandHandler) handleLeaderboardAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := optionsByName(i.ApplicationCommandData().Options)
+	tag := ""
+	if opt, ok := options["tag"]; ok {
+		tag = strings.ToLower(strings.TrimSpace(opt.StringValue()))
+	}
+	byPerson := false
+	if opt, ok := options["by_person"]; ok {
+		byPerson = opt.BoolValue()
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	title := "🏆 **Leaderboard**"
+	if tag != "" {
+		title += fmt.Sprintf(" • `%s`", tag)
+	}
+
+	var response strings.Builder
+	var err error
+	if byPerson {
+		var standings []*models.PersonStanding
+		if tag != "" {
+			standings, err = h.standingsService.GetTagPersonLeaderboard(ctx, tag)
+		} else {
+			standings, err = h.standingsService.GetPersonLeaderboard(ctx)
+		}
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch leaderboard: %v", err))
+			log.Printf("Error fetching leaderboard by person: %v", err)
+			return
+		}
+		response.WriteString(title + " (by person, best account)\n\n")
+		writePersonLeaderboardRows(&response, standings)
+	} else {
+		var players []*models.Player
+		if tag != "" {
+			players, err = h.standingsService.GetTagLeaderboard(ctx, tag)
+		} else {
+			players, err = h.standingsService.GetLeaderboard(ctx)
+		}
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch leaderboard: %v", err))
+			log.Printf("Error fetching leaderboard: %v", err)
+			return
+		}
+		response.WriteString(title + "\n\n")
+		writeLeaderboardRows(&response, players)
+	}
+
+	h.sendFollowUp(s, i, response.String())
+}
+
+// leaderboardPosition returns the medal of the podium, or the position
+func leaderboardPosition(idx int) string {
+	if idx < len(leaderboardMedals) {
+		return leaderboardMedals[idx]
+	}
+	return fmt.Sprintf("**%d.**", idx+1)
+}
+
+// leaderboardRank formats the rank of a player (ex: "GOLD II • 54 LP")
+func leaderboardRank(player *models.Player) string {
+	if player.Tier == "" || player.Tier == "UNRANKED" {
+		return "Unranked"
+	}
+	return fmt.Sprintf("%s • %d LP", models.FormatRank(player.Tier, player.Rank), player.LeaguePoints)
+}
+
+// writeLeaderboardRows writes the players already sorted by rank, one per line
+func writeLeaderboardRows(response *strings.Builder, players []*models.Player) {
+	if len(players) == 0 {
+		response.WriteString("No players tracked yet.\n")
+	}
+
+	for idx, player := range players {
+		if idx >= leaderboardMaxRows {
+			response.WriteString(fmt.Sprintf("... and %d more players\n", len(players)-leaderboardMaxRows))
+			break
+		}
+		response.WriteString(fmt.Sprintf("%s **%s#%s** • %s\n", leaderboardPosition(idx), player.GameName, player.TagLine, leaderboardRank(player)))
+	}
+}
+
+// writePersonLeaderboardRows writes the persons ranked by their best account, one per line
+func writePersonLeaderboardRows(response *strings.Builder, standings []*models.PersonStanding) {
+	if len(standings) == 0 {
+		response.WriteString("No players tracked yet.\n")
+	}
+
+	for idx, standing := range standings {
+		if idx >= leaderboardMaxRows {
+			response.WriteString(fmt.Sprintf("... and %d more persons\n", len(standings)-leaderboardMaxRows))
+			break
+		}
+
+		best := standing.Best
+		if standing.Person == nil {
+			response.WriteString(fmt.Sprintf("%s **%s** • %s\n", leaderboardPosition(idx), best.DisplayName(), leaderboardRank(best)))
+			continue
+		}
+		response.WriteString(fmt.Sprintf("%s **%s** • %s (%s#%s", leaderboardPosition(idx), standing.Name(), leaderboardRank(best), best.GameName, best.TagLine))
+		if len(standing.Accounts) > 1 {
+			response.WriteString(fmt.Sprintf(", %d accounts", len(standing.Accounts)))
+		}
+		response.WriteString(")\n")
+	}
+}
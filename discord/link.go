@@ -0,0 +1,104 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleLinkAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	subcommand := i.ApplicationCommandData().Options[0]
+	options := optionsByName(subcommand.Options)
+	userID := interactionUserID(i)
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	switch subcommand.Name {
+	case "start":
+		pseudo, tagline, server := playerIdentity(options)
+		verification, err := h.verificationService.StartVerification(ctx, userID, pseudo, tagline, server)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to start the verification of **%s#%s**\n\n**Error:** %v", pseudo, tagline, err))
+			log.Printf("Error starting verification of %s#%s: %v", pseudo, tagline, err)
+			return
+		}
+		h.sendFollowUpEmbed(s, i, h.verificationEmbed(ctx, verification))
+	case "verify":
+		verification, err := h.verificationService.CheckVerification(ctx, userID)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to verify your account: %v", err))
+			return
+		}
+
+		switch verification.Status {
+		case models.VerificationVerified:
+			h.sendFollowUp(s, i, fmt.Sprintf("✅ **%s#%s** (%s) is now linked to your Discord account, you can switch back to your previous icon",
+				verification.GameName, verification.TagLine, strings.ToUpper(verification.Server)))
+		case models.VerificationExpired:
+			h.sendFollowUp(s, i, "⌛ The verification expired, start a new one with `/link start`")
+		default:
+			h.sendFollowUp(s, i, fmt.Sprintf("⏳ The profile icon of **%s#%s** is not icon **%d** yet (it can take a minute to update after the switch). Try again before <t:%d:t>",
+				verification.GameName, verification.TagLine, verification.IconID, verification.ExpiresAt.Unix()))
+		}
+	case "list":
+		accounts, err := h.verificationService.GetLinkedAccounts(ctx, userID)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch your accounts: %v", err))
+			log.Printf("Error fetching linked accounts of %s: %v", userID, err)
+			return
+		}
+		h.sendFollowUp(s, i, formatLinkedAccounts(accounts))
+	case "unlink":
+		pseudo, tagline, server := playerIdentity(options)
+		player, err := h.verificationService.Unlink(ctx, userID, pseudo, tagline, server)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to unlink **%s#%s**\n\n**Error:** %v", pseudo, tagline, err))
+			return
+		}
+		h.sendFollowUp(s, i, fmt.Sprintf("✂️ **%s#%s** (%s) is no longer linked to your Discord account",
+			player.GameName, player.TagLine, strings.ToUpper(player.Server)))
+	default:
+		h.sendFollowUp(s, i, "❌ Unknown subcommand")
+	}
+}
+
+// verificationEmbed shows the profile icon to switch to, with its image when Data Dragon is available
+func (h *CommandHandler) verificationEmbed(ctx context.Context, verification *models.AccountVerification) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("🔐 Verify %s#%s", verification.GameName, verification.TagLine),
+		Description: fmt.Sprintf("To prove you own this account, switch its profile icon to icon **%d** (shown here) in the League client. The bot checks it every minute and sends you a DM once the account is linked, or use `/link verify` to check right away.\n\nThe verification expires <t:%d:R>.",
+			verification.IconID, verification.ExpiresAt.Unix()),
+		Color: colorDefault,
+	}
+
+	iconURL, err := h.dataDragon.ProfileIconURL(ctx, verification.IconID)
+	if err != nil {
+		log.Printf("Error fetching profile icon %d: %v", verification.IconID, err)
+		return embed
+	}
+	embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: iconURL}
+	return embed
+}
+
+func formatLinkedAccounts(accounts []*models.AccountVerification) string {
+	if len(accounts) == 0 {
+		return "📭 No account linked yet!\nUse `/link start` to link one of the tracked players."
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("🔗 **Your accounts (%d)**\n\n", len(accounts)))
+	for _, account := range accounts {
+		response.WriteString(fmt.Sprintf("• **%s#%s** (%s)", account.GameName, account.TagLine, strings.ToUpper(account.Server)))
+		if account.VerifiedAt != nil {
+			response.WriteString(fmt.Sprintf(" • verified <t:%d:D>", account.VerifiedAt.Unix()))
+		}
+		response.WriteString("\n")
+	}
+	return response.String()
+}
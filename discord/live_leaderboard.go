@@ -0,0 +1,157 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// LiveLeaderboard maintains a single pinned leaderboard message per guild, edited after each poll cycle
+type LiveLeaderboard struct {
+	session            *discordgo.Session
+	guildConfigService *services.GuildConfigService
+	standingsService   *services.StandingsService
+}
+
+func NewLiveLeaderboard(s *discordgo.Session, guildConfigService *services.GuildConfigService, standingsService *services.StandingsService) *LiveLeaderboard {
+	return &LiveLeaderboard{
+		session:            s,
+		guildConfigService: guildConfigService,
+		standingsService:   standingsService,
+	}
+}
+
+// Update edits the live leaderboard message of every guild that enabled it
+func (l *LiveLeaderboard) Update(ctx context.Context) error {
+	configs, err := l.guildConfigService.GetLiveLeaderboardConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch guild configs: %w", err)
+	}
+	if len(configs) == 0 {
+		return nil
+	}
+
+	players, err := l.standingsService.GetLeaderboard(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch leaderboard: %w", err)
+	}
+	content := formatLiveLeaderboard(players, time.Now())
+
+	// The leaderboard by person is only built for the guilds grouping accounts
+	personContent := ""
+	for _, config := range configs {
+		if !config.GroupByPerson || personContent != "" {
+			continue
+		}
+		standings, err := l.standingsService.GetPersonLeaderboard(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch leaderboard by person: %w", err)
+		}
+		personContent = formatPersonLeaderboard(standings, time.Now())
+	}
+
+	for _, config := range configs {
+		content := content
+		if config.GroupByPerson {
+			content = personContent
+		}
+		err := l.updateGuild(ctx, config, content)
+		if err != nil {
+			log.Printf("Error updating live leaderboard of guild %s: %v", config.GuildID, err)
+		}
+	}
+
+	return nil
+}
+
+func (l *LiveLeaderboard) updateGuild(ctx context.Context, config *models.GuildConfig, content string) error {
+	if config.LiveLeaderboardMessageID != "" {
+		_, err := l.session.ChannelMessageEdit(config.LiveLeaderboardChannelID, config.LiveLeaderboardMessageID, content)
+		if err == nil {
+			return nil
+		}
+
+		// The message was deleted, post a new one
+		if !isNotFound(err) {
+			return err
+		}
+	}
+
+	message, err := l.session.ChannelMessageSend(config.LiveLeaderboardChannelID, content)
+	if err != nil {
+		return err
+	}
+
+	err = l.session.ChannelMessagePin(config.LiveLeaderboardChannelID, message.ID)
+	if err != nil {
+		log.Printf("Error pinning live leaderboard of guild %s (missing Manage Messages permission?): %v", config.GuildID, err)
+	}
+
+	return l.guildConfigService.SetLiveLeaderboardMessage(ctx, config.GuildID, message.ID)
+}
+
+func formatLiveLeaderboard(players []*models.Player, updatedAt time.Time) string {
+	var response strings.Builder
+	response.WriteString("📊 **Live leaderboard**\n\n")
+	writeLeaderboardRows(&response, players)
+	response.WriteString(fmt.Sprintf("\n🔄 Updated <t:%d:R>", updatedAt.Unix()))
+	return response.String()
+}
+
+func formatPersonLeaderboard(standings []*models.PersonStanding, updatedAt time.Time) string {
+	var response strings.Builder
+	response.WriteString("📊 **Live leaderboard** (by person, best account)\n\n")
+	writePersonLeaderboardRows(&response, standings)
+	response.WriteString(fmt.Sprintf("\n🔄 Updated <t:%d:R>", updatedAt.Unix()))
+	return response.String()
+}
+
+func (h *CommandHandler) handleLiveLeaderboardAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+
+	options := optionsByName(i.ApplicationCommandData().Options)
+	enabled := options["enabled"].BoolValue()
+	channelID := i.ChannelID
+	if opt, ok := options["channel"]; ok {
+		channelID = opt.ChannelValue(nil).ID
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	_, err := h.guildConfigService.UpdateConfig(ctx, i.GuildID, func(config *models.GuildConfig) error {
+		if !enabled {
+			config.LiveLeaderboardChannelID = ""
+			config.LiveLeaderboardMessageID = ""
+			return nil
+		}
+
+		// Moving the leaderboard to another channel posts a new message
+		if config.LiveLeaderboardChannelID != channelID {
+			config.LiveLeaderboardMessageID = ""
+		}
+		config.LiveLeaderboardChannelID = channelID
+		return nil
+	})
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to save live leaderboard settings: %v", err))
+		log.Printf("Error saving guild config %s: %v", i.GuildID, err)
+		return
+	}
+
+	if !enabled {
+		h.sendFollowUp(s, i, "✅ Live leaderboard disabled, the pinned message will no longer be updated")
+		return
+	}
+	h.sendFollowUp(s, i, fmt.Sprintf("✅ Live leaderboard enabled in <#%s>, it will be posted, pinned and updated after each poll cycle", channelID))
+}
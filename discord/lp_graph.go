@@ -0,0 +1,200 @@
+package discord
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// LP graph layout, in pixels
+const (
+	lpGraphWidth      = 720
+	lpGraphHeight     = 320
+	lpGraphPadding    = 12
+	lpGraphLabelWidth = 96 // Ranks on the left
+	lpGraphAxisHeight = 20 // Dates at the bottom
+	lpGraphMaxLines   = 8  // Rank grid lines
+	lpGraphDateLabels = 5
+)
+
+const defaultLPGraphDays = 30
+
+var (
+	lpGraphGrid = color.RGBA{0x38, 0x3A, 0x40, 0xFF}
+	lpGraphLine = color.RGBA{0x58, 0x65, 0xF2, 0xFF} // Discord blurple
+)
+
+func (h *CommandHandler) handleLPGraphAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := optionsByName(i.ApplicationCommandData().Options)
+	pseudo, tagline, server := playerIdentity(options)
+	days := defaultLPGraphDays
+	if opt, ok := options["days"]; ok {
+		days = int(opt.IntValue())
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	timeline, err := h.playerService.GetLPTimeline(ctx, player, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch the LP history: %v", err))
+		log.Printf("Error fetching LP history of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	timeline = rankedPoints(timeline)
+	if len(timeline) < 2 {
+		h.sendFollowUp(s, i, fmt.Sprintf("📭 No ranked LP change for **%s#%s** in the last %d days!", player.GameName, player.TagLine, days))
+		return
+	}
+
+	f := interactionFormatter(i)
+	chart, err := renderLPGraph(timeline, f)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to draw the graph: %v", err))
+		log.Printf("Error drawing LP graph of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	first, last := timeline[0], timeline[len(timeline)-1]
+	peak := first
+	for _, point := range timeline {
+		if point.Value > peak.Value {
+			peak = point
+		}
+	}
+	description := fmt.Sprintf("Last %d days • %s → %s (**%+d LP**)\nPeak: %s on %s",
+		days, lpGraphRank(first.Value), lpGraphRank(last.Value), last.Value-first.Value, lpGraphRank(peak.Value), f.DayMonth(peak.At))
+
+	h.followUp(s, i, &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       fmt.Sprintf("📈 LP of %s#%s", player.GameName, player.TagLine),
+			Description: description,
+			Color:       colorDefault,
+			Image:       &discordgo.MessageEmbedImage{URL: "attachment://lp_graph.png"},
+		}},
+		Files: []*discordgo.File{{
+			Name:        "lp_graph.png",
+			ContentType: "image/png",
+			Reader:      bytes.NewReader(chart),
+		}},
+	})
+}
+
+// rankedPoints drops the points where the player was unranked (placements, reset), they have no LP to draw
+func rankedPoints(timeline []models.LPPoint) []models.LPPoint {
+	ranked := make([]models.LPPoint, 0, len(timeline))
+	for _, point := range timeline {
+		if point.Value > 0 {
+			ranked = append(ranked, point)
+		}
+	}
+	return ranked
+}
+
+// lpGraphRank labels a rank value (ex: "Gold II 45 LP")
+func lpGraphRank(value int) string {
+	tier, rank, leaguePoints := models.RankFromValue(value)
+	return fmt.Sprintf("%s %d LP", models.FormatRank(tier, rank), leaguePoints)
+}
+
+// renderLPGraph draws the rank of a player over time as a PNG, with a grid line on the division boundaries
+func renderLPGraph(timeline []models.LPPoint, f models.Formatter) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, lpGraphWidth, lpGraphHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{heatmapBackground}, image.Point{}, draw.Src)
+	drawer := &font.Drawer{Dst: img, Src: &image.Uniform{heatmapText}, Face: basicfont.Face7x13}
+
+	left, top := lpGraphPadding+lpGraphLabelWidth, lpGraphPadding
+	right, bottom := lpGraphWidth-lpGraphPadding, lpGraphHeight-lpGraphPadding-lpGraphAxisHeight
+
+	// Rank values start at 1 (Iron IV 0 LP) and divisions are 100 LP wide, the range is widened to whole divisions
+	low, high := timeline[0].Value, timeline[0].Value
+	for _, point := range timeline {
+		low = min(low, point.Value)
+		high = max(high, point.Value)
+	}
+	low = (low-1)/100*100 + 1
+	high = ((high-1)/100+1)*100 + 1
+	gridStep := 100 * ((high - low + 100*lpGraphMaxLines - 1) / (100 * lpGraphMaxLines))
+
+	y := func(value int) int {
+		return bottom - (value-low)*(bottom-top)/(high-low)
+	}
+	for value := low; value <= high; value += gridStep {
+		line := y(value)
+		draw.Draw(img, image.Rect(left, line, right, line+1), &image.Uniform{lpGraphGrid}, image.Point{}, draw.Src)
+		drawer.Dot = fixed.P(lpGraphPadding, line+4)
+		drawer.DrawString(lpGraphGridLabel(value))
+	}
+
+	start, end := timeline[0].At, timeline[len(timeline)-1].At
+	period := max(end.Sub(start), time.Minute)
+	x := func(at time.Time) int {
+		return left + int(float64(right-left)*float64(at.Sub(start))/float64(period))
+	}
+	for idx := 0; idx < lpGraphDateLabels; idx++ {
+		at := start.Add(period * time.Duration(idx) / (lpGraphDateLabels - 1))
+		label := f.DayMonth(at)
+		drawer.Dot = fixed.P(min(x(at)-len(label)*7/2, right-len(label)*7), lpGraphHeight-lpGraphPadding)
+		drawer.DrawString(label)
+	}
+
+	for idx := 1; idx < len(timeline); idx++ {
+		drawLine(img, x(timeline[idx-1].At), y(timeline[idx-1].Value), x(timeline[idx].At), y(timeline[idx].Value), lpGraphLine)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// lpGraphGridLabel names a division boundary (ex: "Gold II"), the apex tiers share one ladder labeled in LP
+func lpGraphGridLabel(value int) string {
+	tier, rank, leaguePoints := models.RankFromValue(value)
+	if leaguePoints > 0 {
+		return fmt.Sprintf("%s %d", models.FormatRank(tier, rank), leaguePoints)
+	}
+	return models.FormatRank(tier, rank)
+}
+
+// drawLine draws a 2 pixels wide segment
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	steps := max(abs(x1-x0), abs(y1-y0), 1)
+	for step := 0; step <= steps; step++ {
+		px := x0 + (x1-x0)*step/steps
+		py := y0 + (y1-y0)*step/steps
+		draw.Draw(img, image.Rect(px, py, px+2, py+2), &image.Uniform{c}, image.Point{}, draw.Src)
+	}
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,75 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleLPStatsAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	pseudo, tagline, server := playerIdentity(optionsByName(i.ApplicationCommandData().Options))
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	stats, err := h.playerService.GetLPStats(ctx, player)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch LP statistics: %v", err))
+		log.Printf("Error fetching LP stats of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	h.sendFollowUp(s, i, formatLPStats(player, stats, interactionFormatter(i)))
+}
+
+func formatLPStats(player *models.Player, stats *models.LPStats, f models.Formatter) string {
+	if stats.Games() == 0 {
+		return fmt.Sprintf("📭 No tracked games for **%s#%s** yet!", player.GameName, player.TagLine)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("📈 **LP stats of %s#%s** (last %d tracked games)\n\n", player.GameName, player.TagLine, stats.Games()))
+	response.WriteString(fmt.Sprintf("🏆 %s %s • %d LP\n", player.Tier, player.Rank, player.LeaguePoints))
+	response.WriteString(fmt.Sprintf("🟢 **+%s LP** per win (%d wins)\n", f.Number(stats.AverageGain(), 1), stats.Wins))
+	response.WriteString(fmt.Sprintf("🔴 **-%s LP** per loss (%d losses)\n", f.Number(stats.AverageLoss(), 1), stats.Losses))
+	response.WriteString(fmt.Sprintf("⚖️ %s win rate • %s LP per game\n", f.Percent(stats.WinRate(), 1), f.Signed(stats.ExpectedLPPerGame(), 1)))
+
+	if games, ok := stats.GamesToNextDivision(player); ok {
+		response.WriteString(fmt.Sprintf("🎯 ~%d games to reach the next division at this pace\n", games))
+	} else if _, hasNext := models.LPToNextDivision(player.Tier, player.Rank, player.LeaguePoints); hasNext {
+		response.WriteString("🎯 Not climbing at this pace, the next division is out of reach for now\n")
+	}
+
+	drift, explanation := stats.MMRDrift()
+	response.WriteString(fmt.Sprintf("\n%s %s\n", mmrDriftEmoji(drift), explanation))
+
+	return response.String()
+}
+
+func mmrDriftEmoji(drift int) string {
+	switch drift {
+	case models.MMRAboveRank:
+		return "⬆️"
+	case models.MMRBelowRank:
+		return "⬇️"
+	case models.MMRAtRank:
+		return "↔️"
+	default:
+		return "❔"
+	}
+}
@@ -0,0 +1,12 @@
+package discord
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+// Every test of the package must stop the goroutines it started (dispatchers, interactions...)
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
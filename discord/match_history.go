@@ -0,0 +1,83 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	defaultMatchHistoryGames = 10
+	maxMatchHistoryGames     = 20
+)
+
+func (h *CommandHandler) handleMatchHistoryAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := optionsByName(i.ApplicationCommandData().Options)
+	pseudo, tagline, server := playerIdentity(options)
+	count := defaultMatchHistoryGames
+	if opt, ok := options["count"]; ok {
+		count = int(opt.IntValue())
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	history, err := h.playerService.GetMatchHistory(ctx, player, count)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch the match history: %v", err))
+		log.Printf("Error fetching match history of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	h.sendFollowUp(s, i, formatMatchHistory(player, history))
+}
+
+// formatMatchHistory lists the games of a player on one line each, with the LP change when it was recorded
+func formatMatchHistory(player *models.Player, history []*models.MatchHistoryEntry) string {
+	if len(history) == 0 {
+		return fmt.Sprintf("📭 No tracked games for **%s#%s** yet!", player.GameName, player.TagLine)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("📜 **Match history of %s#%s**\n\n", player.GameName, player.TagLine))
+
+	wins := 0
+	for _, entry := range history {
+		match := entry.Match
+		result := "🔴"
+		if match.Victory {
+			result = "🟢"
+			wins++
+		}
+
+		lp := ""
+		if entry.LPDelta != nil {
+			lp = fmt.Sprintf(" • **%+d LP**", *entry.LPDelta)
+		}
+		queue := ""
+		if match.QueueType == "RANKED_FLEX_SR" {
+			queue = " • Flex"
+		}
+
+		response.WriteString(fmt.Sprintf("%s **%s** %s • %s%s%s • <t:%d:R>\n",
+			result, match.Champion, models.RoleEmoji(match.Role), match.KDAString(), lp, queue, match.CreatedAt.Unix()))
+	}
+
+	response.WriteString(fmt.Sprintf("\n📊 %d W / %d L", wins, len(history)-wins))
+	return response.String()
+}
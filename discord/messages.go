@@ -0,0 +1,213 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// Discord rejects messages longer than 2000 characters
+	maxMessageLength = 2000
+
+	// Longer responses are sent as a text file instead of flooding the channel
+	maxMessageChunks = 4
+
+	longResponseNotice = "📄 The response is too long for Discord, see the attached file"
+
+	// Interaction tokens (needed for followups) expire after 15 minutes
+	interactionTokenLifetime = 15 * time.Minute
+)
+
+// splitMessage splits a message into chunks of at most limit characters, cutting between lines
+// when possible
+func splitMessage(content string, limit int) []string {
+	if utf8.RuneCountInString(content) <= limit {
+		return []string{content}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentLength := 0
+
+	flush := func() {
+		if currentLength > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentLength = 0
+		}
+	}
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		lineLength := utf8.RuneCountInString(line)
+		if currentLength+lineLength > limit {
+			flush()
+		}
+
+		// A single line longer than the limit is cut in pieces
+		for lineLength > limit {
+			runes := []rune(line)
+			chunks = append(chunks, string(runes[:limit]))
+			line = string(runes[limit:])
+			lineLength -= limit
+		}
+
+		current.WriteString(line)
+		currentLength += lineLength
+	}
+	flush()
+
+	return chunks
+}
+
+// responseFile wraps a long response into a text attachment
+func responseFile(content string) *discordgo.File {
+	return &discordgo.File{
+		Name:        "response.txt",
+		ContentType: "text/plain",
+		Reader:      strings.NewReader(content),
+	}
+}
+
+// sendChannelMessage sends a message to a channel, split over several messages or attached as
+// a file when it exceeds Discord's length limit. Components are attached to the last message.
+func sendChannelMessage(s *discordgo.Session, channelID, content string, components ...discordgo.MessageComponent) error {
+	chunks := splitMessage(content, maxMessageLength)
+	if len(chunks) > maxMessageChunks {
+		_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Content:    longResponseNotice,
+			Files:      []*discordgo.File{responseFile(content)},
+			Components: components,
+		})
+		return err
+	}
+
+	for idx, chunk := range chunks {
+		message := &discordgo.MessageSend{Content: chunk}
+		if idx == len(chunks)-1 {
+			message.Components = components
+		}
+
+		_, err := s.ChannelMessageSendComplex(channelID, message)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendChannelEmbeds sends a text laid out as embeds (see textEmbeds) to a channel, mentioning a user
+// in the first message when userID is set. Responses too long for a few messages are attached as a file.
+func sendChannelEmbeds(s *discordgo.Session, channelID, userID, content string) error {
+	mention := ""
+	allowedMentions := &discordgo.MessageAllowedMentions{}
+	if userID != "" {
+		mention = fmt.Sprintf("<@%s>", userID)
+		allowedMentions.Users = []string{userID}
+	}
+
+	embeds := textEmbeds(content)
+	if len(embeds) > maxMessageChunks {
+		_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Content:         strings.TrimSpace(mention + " " + longResponseNotice),
+			Files:           []*discordgo.File{responseFile(content)},
+			AllowedMentions: allowedMentions,
+		})
+		return err
+	}
+
+	for idx, embed := range embeds {
+		message := &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}, AllowedMentions: allowedMentions}
+		if idx == 0 {
+			message.Content = mention
+		}
+
+		_, err := s.ChannelMessageSendComplex(channelID, message)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// followUp sends a followup message, retrying once on failure. When the interaction token has
+// expired, the message is posted in the channel of the interaction instead, mentioning the requester.
+// It returns false if the message could not be delivered.
+func (h *CommandHandler) followUp(s *discordgo.Session, i *discordgo.InteractionCreate, params *discordgo.WebhookParams) bool {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if isInteractionExpired(i, err) {
+			err = sendChannelFallback(s, i, params)
+			break
+		}
+
+		_, err = s.FollowupMessageCreate(i.Interaction, true, params)
+		if err == nil {
+			return true
+		}
+		rewindFiles(params.Files)
+	}
+	if err == nil {
+		return true
+	}
+
+	h.stats.mu.Lock()
+	h.stats.failedFollowUps++
+	h.stats.mu.Unlock()
+
+	log.Printf("Error sending followup (interaction %s, command %s, guild %s, user %s): %v",
+		i.ID, interactionName(i), i.GuildID, interactionUserID(i), err)
+	return false
+}
+
+// rewindFiles rewinds the attachments consumed by a failed attempt so they can be sent again
+func rewindFiles(files []*discordgo.File) {
+	for _, file := range files {
+		if seeker, ok := file.Reader.(io.Seeker); ok {
+			seeker.Seek(0, io.SeekStart)
+		}
+	}
+}
+
+// isInteractionExpired checks if the interaction token can no longer be used, from its age
+// or from the error of the last followup attempt
+func isInteractionExpired(i *discordgo.InteractionCreate, lastErr error) bool {
+	createdAt, err := discordgo.SnowflakeTimestamp(i.ID)
+	if err == nil && time.Since(createdAt) >= interactionTokenLifetime {
+		return true
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(lastErr, &restErr) && restErr.Response != nil {
+		return restErr.Response.StatusCode == http.StatusUnauthorized ||
+			(restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeInvalidWebhookTokenProvided)
+	}
+	return false
+}
+
+// sendChannelFallback delivers a followup as a regular channel message
+func sendChannelFallback(s *discordgo.Session, i *discordgo.InteractionCreate, params *discordgo.WebhookParams) error {
+	if i.ChannelID == "" {
+		return fmt.Errorf("interaction token expired and no channel to fall back to")
+	}
+
+	message := &discordgo.MessageSend{
+		Content: params.Content,
+		Embeds:  params.Embeds,
+		Files:   params.Files,
+	}
+	if userID := interactionUserID(i); userID != "" {
+		message.Content = fmt.Sprintf("<@%s> %s", userID, message.Content)
+		message.AllowedMentions = &discordgo.MessageAllowedMentions{Users: []string{userID}}
+	}
+
+	_, err := s.ChannelMessageSendComplex(i.ChannelID, message)
+	return err
+}
@@ -0,0 +1,316 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"lp_tracker/telemetry"
+
+	"github.com/bwmarrin/discordgo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = telemetry.Tracer("lp_tracker/discord")
+
+// DefaultCommandTimeout applies to the commands without a specific timeout
+const DefaultCommandTimeout = 10 * time.Second
+
+// Commands calling the Riot API need more time than database lookups
+var defaultCommandTimeouts = map[string]time.Duration{
+	"add_player": 30 * time.Second,
+	"check":      30 * time.Second,
+	"refresh":    30 * time.Second,
+	"challenges": 30 * time.Second,
+	"link":       30 * time.Second,
+	"admin":      60 * time.Second,
+}
+
+// ParseCommandTimeouts parses per-command timeouts such as "add_player=45s,list_players=20s".
+// The "default" key overrides the timeout of every other command.
+func ParseCommandTimeouts(spec string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid command timeout %q, expected <command>=<duration>", entry)
+		}
+
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout for command %s: %q", name, value)
+		}
+		timeouts[strings.TrimSpace(name)] = timeout
+	}
+	return timeouts, nil
+}
+
+// SetCommandTimeouts overrides the execution timeout of commands, see ParseCommandTimeouts
+func (h *CommandHandler) SetCommandTimeouts(timeouts map[string]time.Duration) {
+	for name, timeout := range timeouts {
+		h.timeouts[name] = timeout
+	}
+}
+
+// commandContext returns the execution context of an interaction, bounded by the command timeout.
+// It carries the root span of the interaction, ended by the cancel function.
+func (h *CommandHandler) commandContext(i *discordgo.InteractionCreate) (context.Context, context.CancelFunc) {
+	name := interactionName(i)
+	ctx, span := tracer.Start(h.ctx, "discord "+name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+		attribute.String("discord.command", name),
+		attribute.String("discord.guild_id", i.GuildID),
+		attribute.String("discord.user_id", interactionUserID(i)),
+	))
+
+	ctx, cancel := context.WithTimeout(ctx, h.commandTimeout(name))
+	return ctx, func() {
+		if ctx.Err() == context.DeadlineExceeded {
+			span.SetStatus(codes.Error, "command timed out")
+		}
+		cancel()
+		span.End()
+	}
+}
+
+// Commands answered with messages only visible to the user
+var ephemeralCommands = map[string]bool{
+	"admin":          true, // Diagnostics are only visible to the owner
+	"link":           true, // Linked accounts are personal
+	"purge_inactive": true, // The preview and its buttons are only visible to the admin who asked
+	"settings":       true, // The panel is only visible to the member who opened it
+	"tilt_alerts":    true,
+}
+
+// Components answered with a new message only visible to the member who clicked, the other
+// components edit the message they belong to
+var ephemeralComponents = map[string]bool{
+	"player": true, // Profile, recent games and mute buttons of the notifications
+}
+
+// Commands calling the Riot API, they take a slot of the worker pool so a burst of lookups
+// doesn't exceed the rate limits. The other commands only read the database.
+var riotCommands = map[string]bool{
+	"add_player": true,
+	"check":      true,
+	"refresh":    true,
+	"link":       true,
+	"admin":      true, // poll_now
+}
+
+// async handles an interaction in a tracked goroutine once authorized, interactions received
+// after Shutdown are dropped. The interaction is acknowledged before the handler runs, and before
+// waiting for the worker pool, so it never misses the 3 seconds deadline of Discord: the handlers
+// answer with followups (or edit the message of a component).
+func (h *CommandHandler) async(handler func(*discordgo.Session, *discordgo.InteractionCreate), s *discordgo.Session, i *discordgo.InteractionCreate) {
+	run := func() {
+		if !h.authorize(s, i) {
+			return
+		}
+
+		// Statistics
+		start := time.Now()
+		h.updateStats(1, 0)
+		defer func() {
+			h.updateStats(-1, time.Since(start))
+		}()
+
+		err := h.deferInteraction(s, i)
+		if err != nil {
+			log.Printf("Error deferring response: %v", err)
+			return
+		}
+
+		if riotCommands[interactionName(i)] {
+			select {
+			case h.workerPool <- struct{}{}:
+				defer func() { <-h.workerPool }()
+			case <-h.ctx.Done():
+				return
+			}
+		}
+		handler(s, i)
+	}
+	if !h.spawn(run) {
+		log.Printf("Ignoring interaction %s received during shutdown", i.ID)
+	}
+}
+
+// deferInteraction acknowledges an interaction, Discord shows "thinking..." until the first followup
+func (h *CommandHandler) deferInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	name := interactionName(i)
+	response := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}
+	switch {
+	case i.Type == discordgo.InteractionMessageComponent && !ephemeralComponents[name]:
+		response.Type = discordgo.InteractionResponseDeferredMessageUpdate
+	case i.Type == discordgo.InteractionMessageComponent || ephemeralCommands[name]:
+		response.Data = &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		}
+	}
+	return s.InteractionRespond(i.Interaction, response)
+}
+
+// spawn runs fn in a goroutine joined by Shutdown. It returns false if the handler is shut down.
+func (h *CommandHandler) spawn(fn func()) bool {
+	h.closeMu.RLock()
+	defer h.closeMu.RUnlock()
+	if h.closed {
+		return false
+	}
+
+	h.running.Add(1)
+	go func() {
+		defer h.running.Done()
+		fn()
+	}()
+	return true
+}
+
+// Shutdown cancels the running interactions and waits for their goroutines to return,
+// or for ctx to expire
+func (h *CommandHandler) Shutdown(ctx context.Context) error {
+	h.closeMu.Lock()
+	h.closed = true
+	h.closeMu.Unlock()
+	h.stop()
+
+	done := make(chan struct{})
+	go func() {
+		h.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("interactions still running: %w", ctx.Err())
+	}
+}
+
+// Commands that cannot be restricted by the permissions matrix
+var unrestrictedCommands = map[string]bool{
+	"admin":         true, // Owner only
+	"command_roles": true, // Server admins must not lock themselves out
+}
+
+// authorize enforces the permissions matrix of the guild (see /command_roles) on top of the Discord
+// permissions, and answers the interaction when the member is not allowed. Server admins are always allowed.
+func (h *CommandHandler) authorize(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	name := interactionName(i)
+	if i.GuildID == "" || i.Member == nil || unrestrictedCommands[name] {
+		return true
+	}
+	if i.Member.Permissions&(discordgo.PermissionAdministrator|discordgo.PermissionManageGuild) != 0 {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(h.ctx, 5*time.Second)
+	defer cancel()
+
+	config, err := h.guildConfigService.GetConfig(ctx, i.GuildID)
+	if err != nil {
+		log.Printf("Error fetching permissions of guild %s: %v", i.GuildID, err)
+		h.respondEphemeral(s, i, "❌ Failed to check your permissions, please try again later")
+		return false
+	}
+	if config.AllowsCommand(name, i.Member.Roles) {
+		return true
+	}
+
+	h.respondEphemeral(s, i, fmt.Sprintf("❌ `/%s` is restricted to %s on this server", name, mentionRoles(config.CommandRoles[name])))
+	return false
+}
+
+// respondEphemeral answers an interaction with a message only visible to the user
+func (h *CommandHandler) respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:          textEmbeds(content),
+			Flags:           discordgo.MessageFlagsEphemeral,
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to interaction %s: %v", i.ID, err)
+	}
+}
+
+func (h *CommandHandler) commandTimeout(name string) time.Duration {
+	if timeout, ok := h.timeouts[name]; ok {
+		return timeout
+	}
+	if timeout, ok := h.timeouts["default"]; ok {
+		return timeout
+	}
+	return DefaultCommandTimeout
+}
+
+// interactionName returns the command name of an interaction, or the custom ID prefix
+// (ex: "settings", "player") of a component
+func interactionName(i *discordgo.InteractionCreate) string {
+	if i.Type == discordgo.InteractionMessageComponent {
+		name, _, _ := strings.Cut(i.MessageComponentData().CustomID, ":")
+		return name
+	}
+	return i.ApplicationCommandData().Name
+}
+
+// Interaction tokens are valid 15 minutes, a duplicate cannot be delivered after that
+const interactionDedupeTTL = 15 * time.Minute
+
+// interactionDedupe remembers the handled interaction IDs, Discord may deliver an interaction
+// twice when the gateway reconnects
+type interactionDedupe struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time // Interaction ID -> reception time
+	lastPrune time.Time
+}
+
+func newInteractionDedupe() *interactionDedupe {
+	return &interactionDedupe{
+		seen: make(map[string]time.Time),
+	}
+}
+
+// firstSeen records an interaction ID, it returns false if the interaction was already received
+func (d *interactionDedupe) firstSeen(id string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if now.Sub(d.lastPrune) > time.Minute {
+		for seenID, at := range d.seen {
+			if now.Sub(at) > interactionDedupeTTL {
+				delete(d.seen, seenID)
+			}
+		}
+		d.lastPrune = now
+	}
+
+	if _, ok := d.seen[id]; ok {
+		return false
+	}
+	d.seen[id] = now
+	return true
+}
+
+// size returns the number of remembered interaction IDs
+func (d *interactionDedupe) size() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.seen)
+}
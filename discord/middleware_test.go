@@ -0,0 +1,173 @@
+package discord
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/goleak"
+)
+
+// newTestCommandHandler returns a handler with the shutdown state, the worker pool and the statistics
+// only, enough to spawn interactions
+func newTestCommandHandler() *CommandHandler {
+	ctx, stop := context.WithCancel(context.Background())
+	return &CommandHandler{ctx: ctx, stop: stop, workerPool: make(chan struct{}, 2), stats: &CommandStats{}}
+}
+
+// testInteraction is a command received in DMs, authorized without reading the guild configuration
+func testInteraction(name string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:   "interaction-" + name,
+		Type: discordgo.InteractionApplicationCommand,
+		Data: discordgo.ApplicationCommandInteractionData{Name: name},
+	}}
+}
+
+func TestCommandHandlerShutdownJoinsInteractions(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	h := newTestCommandHandler()
+	session := newTestSession(t, &fakeDiscord{})
+
+	// Interactions and their helper goroutines wait on the context of the handler, like the Riot API calls
+	started := make(chan struct{}, 4)
+	for range 3 {
+		h.spawn(func() {
+			started <- struct{}{}
+			<-h.ctx.Done()
+		})
+	}
+	h.async(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		started <- struct{}{}
+		<-h.ctx.Done()
+	}, session, testInteraction("check"))
+	for range 4 {
+		<-started
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown = %v, want the interactions joined", err)
+	}
+
+	if h.spawn(func() { t.Error("goroutine spawned after the shutdown") }) {
+		t.Error("spawn accepted a goroutine after the shutdown")
+	}
+	h.async(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		t.Error("interaction handled after the shutdown")
+	}, session, testInteraction("check"))
+}
+
+func TestAsyncAcknowledgesBeforeWorkerPool(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	h := newTestCommandHandler()
+	discord := &fakeDiscord{}
+	session := newTestSession(t, discord)
+
+	// Two slow Riot API lookups hold the worker pool
+	for range cap(h.workerPool) {
+		h.workerPool <- struct{}{}
+	}
+
+	handled := make(chan string, 2)
+	handler := func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		handled <- i.ApplicationCommandData().Name
+	}
+	h.async(handler, session, testInteraction("check"))
+	h.async(handler, session, testInteraction("version"))
+
+	// Commands without Riot API calls don't wait for the pool
+	select {
+	case name := <-handled:
+		if name != "version" {
+			t.Fatalf("%s handled while the worker pool is full", name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("/version waited for the worker pool")
+	}
+
+	// Both interactions are acknowledged, the Riot API lookup before its turn in the pool
+	deadline := time.Now().Add(5 * time.Second)
+	for discord.sent() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := discord.sent(); got != 2 {
+		t.Errorf("%d interactions acknowledged, want 2", got)
+	}
+	select {
+	case <-handled:
+		t.Error("/check handled while the worker pool is full")
+	default:
+	}
+
+	<-h.workerPool
+	if name := <-handled; name != "check" {
+		t.Errorf("handled %s once a slot is free, want check", name)
+	}
+	<-h.workerPool
+
+	if err := h.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown = %v", err)
+	}
+	if total, active, _, _ := h.GetStats(); total != 2 || active != 0 {
+		t.Errorf("stats = %d commands, %d active, want 2 and 0", total, active)
+	}
+}
+
+func TestCommandHandlerShutdownTimeout(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	h := newTestCommandHandler()
+
+	// An interaction ignoring the cancellation (ex: a call without context) holds the shutdown
+	release := make(chan struct{})
+	h.spawn(func() {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := h.Shutdown(ctx); err == nil {
+		t.Error("Shutdown = nil while an interaction is still running")
+	}
+
+	// Joined once it returns
+	close(release)
+	if err := h.Shutdown(context.Background()); err != nil {
+		t.Errorf("second Shutdown = %v, want nil", err)
+	}
+}
+
+func TestJobResultDispatcherStops(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	dispatcher := NewJobResultDispatcher(newTestSession(t, &fakeDiscord{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		dispatcher.Run(ctx)
+	}()
+	cancel()
+	<-stopped
+
+	// The jobs finishing after the shutdown don't block on the stopped dispatcher
+	delivered := make(chan struct{})
+	go func() {
+		defer close(delivered)
+		for range jobResultQueueSize + 1 {
+			dispatcher.Deliver(&JobResult{Name: "refresh", RequesterID: "user-1"})
+		}
+	}()
+
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Deliver blocked after the dispatcher stopped")
+	}
+}
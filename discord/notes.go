@@ -0,0 +1,69 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handlePlayerNoteAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := optionsByName(i.ApplicationCommandData().Options)
+	pseudo, tagline, server := playerIdentity(options)
+
+	var alias, note *string
+	if opt, ok := options["alias"]; ok {
+		value := opt.StringValue()
+		alias = &value
+	}
+	if opt, ok := options["note"]; ok {
+		value := opt.StringValue()
+		note = &value
+	}
+	if opt, ok := options["clear"]; ok && opt.BoolValue() {
+		empty := ""
+		alias, note = &empty, &empty
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	if alias == nil && note == nil {
+		player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+			return
+		}
+		if player == nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+			return
+		}
+		h.sendFollowUp(s, i, formatPlayerNote(player, "📝"))
+		return
+	}
+
+	player, err := h.playerService.SetPlayerNote(ctx, pseudo, tagline, server, alias, note)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to update **%s#%s**\n\n**Error:** %v", pseudo, tagline, err))
+		log.Printf("Error updating note of player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	h.sendFollowUp(s, i, formatPlayerNote(player, "✅"))
+}
+
+func formatPlayerNote(player *models.Player, emoji string) string {
+	summary := models.NewPlayerSummary(player)
+	alias := "none"
+	if summary.Alias != "" {
+		alias = summary.Alias
+	}
+	note := "none"
+	if summary.Note != "" {
+		note = summary.Note
+	}
+	return fmt.Sprintf("%s **%s** (%s)\n🪪 **Alias:** %s\n📝 **Note:** %s", emoji, summary.RiotID(), summary.Server, alias, note)
+}
@@ -0,0 +1,394 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+)
+
+// Notifier posts rank change notifications to the guilds' notification channels
+type Notifier struct {
+	dispatcher         *Dispatcher
+	guildConfigService *services.GuildConfigService
+	dataDragon         *services.DataDragonService
+	dedupe             services.NotificationDedupeStore
+	enemyRanks         *services.EnemyRankService
+	featureFlags       *services.FeatureFlagService
+}
+
+func NewNotifier(dispatcher *Dispatcher, guildConfigService *services.GuildConfigService, dataDragon *services.DataDragonService,
+	dedupe services.NotificationDedupeStore, enemyRanks *services.EnemyRankService, featureFlags *services.FeatureFlagService) *Notifier {
+	return &Notifier{
+		dispatcher:         dispatcher,
+		guildConfigService: guildConfigService,
+		dataDragon:         dataDragon,
+		dedupe:             dedupe,
+		enemyRanks:         enemyRanks,
+		featureFlags:       featureFlags,
+	}
+}
+
+// NotifyRankChanges sends the rank changes to every guild whose threshold they pass.
+// Changes already announced (ex: before a restart) are skipped, a change is marked as announced
+// once one of its messages was sent.
+func (n *Notifier) NotifyRankChanges(ctx context.Context, changes []*models.RankChange) error {
+	changes = n.unnotified(ctx, changes)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	configs, err := n.guildConfigService.GetNotificationConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch guild configs: %w", err)
+	}
+	deliveries := n.newDeliveryTracker(ctx, changes)
+	defer deliveries.enqueued()
+
+	// Resolved once per player and game, only when a guild asked for it
+	enemyRanks := make(map[string]string)
+
+	for _, config := range configs {
+		for _, change := range changes {
+			if config.IsMuted(change.Player.PUUID) {
+				continue
+			}
+
+			// Multikills are highlighted whatever the LP threshold
+			if change.Match != nil && change.Match.MultiKillHighlight() != "" {
+				n.dispatcher.EnqueueTracked(config.GuildID, config.NotificationChannelID, formatMultiKill(change.Player, change.Match),
+					deliveries.track(change))
+			}
+
+			if !config.ShouldNotify(change) {
+				continue
+			}
+
+			localized := change
+			if config.Language != "" {
+				localized = n.localizeChange(ctx, change, config.Language)
+			}
+
+			message := formatRankChange(localized, config.NotificationStyle, config.IsCompact())
+			if config.NotificationTemplate != "" {
+				message = models.RenderNotificationTemplate(config.NotificationTemplate, localized)
+			}
+			if config.AFKCallouts && change.Match != nil && change.Match.HasAFK() {
+				message += "\n⚠️ _AFK or leaver suspected in this game_"
+			}
+			if config.EnemyRanks && change.Match != nil && !config.IsCompact() {
+				key := change.Player.PUUID + "/" + change.Match.MatchID
+				enemyRank, ok := enemyRanks[key]
+				if !ok {
+					enemyRank = n.enemyAverageRank(ctx, change)
+					enemyRanks[key] = enemyRank
+				}
+				if enemyRank != "" {
+					message += fmt.Sprintf("\n👥 Avg enemy rank: %s", enemyRank)
+				}
+			}
+
+			n.dispatcher.EnqueueTracked(config.GuildID, config.NotificationChannelID, message, deliveries.track(change), playerActions(change.Player))
+		}
+	}
+
+	return nil
+}
+
+// NotifyCatchUp announces the games missed during a downtime as a single digest per guild
+func (n *Notifier) NotifyCatchUp(ctx context.Context, catchUps []*models.CatchUp) error {
+	var pending []*models.CatchUp
+	var changes []*models.RankChange
+	for _, catchUp := range catchUps {
+		if catchUp.Change == nil {
+			pending = append(pending, catchUp)
+			continue
+		}
+		if len(n.unnotified(ctx, []*models.RankChange{catchUp.Change})) == 0 {
+			continue
+		}
+		pending = append(pending, catchUp)
+		changes = append(changes, catchUp.Change)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	configs, err := n.guildConfigService.GetNotificationConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch guild configs: %w", err)
+	}
+	deliveries := n.newDeliveryTracker(ctx, changes)
+	defer deliveries.enqueued()
+
+	for _, config := range configs {
+		var lines []string
+		var announced []*models.RankChange
+		for _, catchUp := range pending {
+			if config.IsMuted(catchUp.Player.PUUID) {
+				continue
+			}
+			lines = append(lines, formatCatchUp(catchUp))
+			if catchUp.Change != nil {
+				announced = append(announced, catchUp.Change)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		message := "⏪ **Catch-up: games played while the tracker was offline**\n" + strings.Join(lines, "\n")
+		n.dispatcher.EnqueueTracked(config.GuildID, config.NotificationChannelID, message, deliveries.track(announced...))
+	}
+
+	return nil
+}
+
+func formatCatchUp(catchUp *models.CatchUp) string {
+	player := catchUp.Player
+	wins, losses := catchUp.Record()
+
+	line := fmt.Sprintf("• **%s** • %d games (%dW %dL)", player.DisplayName(), len(catchUp.Matches), wins, losses)
+	if catchUp.Change != nil {
+		line += fmt.Sprintf(" • %+d LP", catchUp.Change.LPDelta())
+	}
+	line += fmt.Sprintf(" • now %s %d LP", models.FormatRank(player.Tier, player.Rank), player.LeaguePoints)
+
+	return line
+}
+
+// enemyAverageRank resolves the average rank of the enemy team, empty when unknown
+func (n *Notifier) enemyAverageRank(ctx context.Context, change *models.RankChange) string {
+	rank, err := n.enemyRanks.AverageRank(ctx, change.Match, change.Player.Server)
+	if err != nil {
+		log.Printf("Error resolving enemy ranks of match %s: %v", change.Match.MatchID, err)
+	}
+	return rank
+}
+
+// unnotified filters out the changes found in the dedupe store. When the store cannot be read,
+// the change is announced: a duplicate is better than a missed notification.
+func (n *Notifier) unnotified(ctx context.Context, changes []*models.RankChange) []*models.RankChange {
+	var pending []*models.RankChange
+	for _, change := range changes {
+		notified, err := n.dedupe.IsNotified(ctx, change)
+		if err != nil {
+			log.Printf("Error checking notification of %s#%s: %v", change.Player.GameName, change.Player.TagLine, err)
+		}
+		if notified {
+			log.Printf("Skipping already notified change of %s#%s", change.Player.GameName, change.Player.TagLine)
+			continue
+		}
+		pending = append(pending, change)
+	}
+	return pending
+}
+
+func (n *Notifier) markNotified(ctx context.Context, change *models.RankChange) {
+	err := n.dedupe.MarkNotified(ctx, change, time.Now())
+	if err != nil {
+		log.Printf("Error marking change of %s#%s as notified: %v", change.Player.GameName, change.Player.TagLine, err)
+	}
+}
+
+// deliveryTracker marks the changes as notified once their messages were sent by the dispatcher, so
+// the changes still queued when the poller stops are announced again after the restart. A change is
+// marked as soon as one of its messages was sent (the guilds whose message failed are not worth a
+// duplicate in the others), the changes without message (muted, under the thresholds) are marked
+// once every message was enqueued.
+type deliveryTracker struct {
+	notifier *Notifier
+	ctx      context.Context // Outlives the poll cycle, the messages are sent after it (or on shutdown)
+
+	mu     sync.Mutex
+	states map[*models.RankChange]*deliveryState
+}
+
+type deliveryState struct {
+	waiting  int // Messages not sent yet, plus one until every message is enqueued
+	messages int
+	sent     bool
+}
+
+func (n *Notifier) newDeliveryTracker(ctx context.Context, changes []*models.RankChange) *deliveryTracker {
+	tracker := &deliveryTracker{
+		notifier: n,
+		ctx:      context.WithoutCancel(ctx),
+		states:   make(map[*models.RankChange]*deliveryState, len(changes)),
+	}
+	for _, change := range changes {
+		tracker.states[change] = &deliveryState{waiting: 1}
+	}
+	return tracker
+}
+
+// track returns the callback of a message announcing some changes
+func (t *deliveryTracker) track(changes ...*models.RankChange) func(err error) {
+	t.mu.Lock()
+	for _, change := range changes {
+		state := t.states[change]
+		state.waiting++
+		state.messages++
+	}
+	t.mu.Unlock()
+
+	return func(err error) {
+		for _, change := range changes {
+			t.done(change, err == nil)
+		}
+	}
+}
+
+// enqueued is called once every message is enqueued
+func (t *deliveryTracker) enqueued() {
+	for change := range t.states {
+		t.done(change, false)
+	}
+}
+
+func (t *deliveryTracker) done(change *models.RankChange, sent bool) {
+	t.mu.Lock()
+	state := t.states[change]
+	state.waiting--
+	state.sent = state.sent || sent
+	finished := state.waiting == 0
+	delivered := state.sent || state.messages == 0
+	t.mu.Unlock()
+
+	if !finished {
+		return
+	}
+	if !delivered {
+		log.Printf("Notification of %s#%s could not be sent, it is left unnotified", change.Player.GameName, change.Player.TagLine)
+		return
+	}
+	t.notifier.markNotified(t.ctx, change)
+}
+
+// NotifyAchievements announces newly earned badges to every guild with a notification channel
+func (n *Notifier) NotifyAchievements(ctx context.Context, unlocks []*models.AchievementUnlock) error {
+	if len(unlocks) == 0 {
+		return nil
+	}
+
+	configs, err := n.guildConfigService.GetNotificationConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch guild configs: %w", err)
+	}
+
+	for _, config := range configs {
+		for _, unlock := range unlocks {
+			if config.IsMuted(unlock.Player.PUUID) {
+				continue
+			}
+
+			message := fmt.Sprintf("🏅 **%s#%s** unlocked the achievement %s **%s**\n_%s_",
+				unlock.Player.GameName, unlock.Player.TagLine,
+				unlock.Achievement.Emoji, unlock.Achievement.Name, unlock.Achievement.Description)
+
+			n.dispatcher.Enqueue(config.GuildID, config.NotificationChannelID, message)
+		}
+	}
+
+	return nil
+}
+
+// NotifyTilt suggests a break to the players on a tilt session, in the guilds with the tilt_alerts flag
+func (n *Notifier) NotifyTilt(ctx context.Context, alerts []*models.TiltAlert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	configs, err := n.guildConfigService.GetNotificationConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch guild configs: %w", err)
+	}
+
+	for _, config := range configs {
+		if !n.featureFlags.IsEnabled(ctx, models.FlagTiltAlerts, config.GuildID) {
+			continue
+		}
+		for _, alert := range alerts {
+			if config.IsMuted(alert.Player.PUUID) {
+				continue
+			}
+			n.dispatcher.Enqueue(config.GuildID, config.NotificationChannelID, formatTiltAlert(alert))
+		}
+	}
+
+	return nil
+}
+
+// formatTiltAlert keeps the tone kind, the message is public
+func formatTiltAlert(alert *models.TiltAlert) string {
+	return fmt.Sprintf("🍵 **%s#%s** lost %d games in the last 2 hours. Maybe a good moment for a short break, the ladder will still be there later!\n_Players can turn these messages off with `/tilt_alerts`_",
+		alert.Player.GameName, alert.Player.TagLine, alert.Losses)
+}
+
+// localizeChange returns a copy of the change with the champion name in the given language
+func (n *Notifier) localizeChange(ctx context.Context, change *models.RankChange, language string) *models.RankChange {
+	if change.Match == nil {
+		return change
+	}
+
+	match := *change.Match
+	match.Champion = n.dataDragon.LocalizedChampionName(ctx, match.Champion, language)
+	if match.BannedChampion != "" {
+		match.BannedChampion = n.dataDragon.LocalizedChampionName(ctx, match.BannedChampion, language)
+	}
+	if match.EnemyChampion != "" {
+		match.EnemyChampion = n.dataDragon.LocalizedChampionName(ctx, match.EnemyChampion, language)
+	}
+
+	localized := *change
+	localized.Match = &match
+	return &localized
+}
+
+func formatRankChange(change *models.RankChange, style string, compact bool) string {
+	player := change.Player
+	delta := change.LPDelta()
+
+	header := models.RenderNotificationTemplate(models.NotificationPackFor(style).TemplateFor(change), change)
+
+	message := fmt.Sprintf("%s\n🏆 %s %s • %d LP (%+d LP) • %s",
+		header,
+		player.Tier,
+		player.Rank,
+		player.LeaguePoints,
+		delta,
+		strings.ToUpper(player.Server),
+	)
+
+	if change.Match != nil && !compact {
+		message += fmt.Sprintf("\n🎮 %s • %s • %s", change.Match.Champion, change.Match.KDAString(), change.Match.MetricsString())
+		if draft := change.Match.DraftString(); draft != "" {
+			message += "\n" + draft
+		}
+	}
+
+	return message
+}
+
+func formatMultiKill(player *models.Player, match *models.MatchPlayerInfo) string {
+	emoji := "💥"
+	if match.PentaKills > 0 {
+		emoji = "🌟💥"
+	}
+
+	message := fmt.Sprintf("%s **%s!** **%s** on **%s** (%s)",
+		emoji, match.MultiKillHighlight(), player.DisplayName(), match.Champion, match.KDAString())
+	if match.PentaKills > 1 {
+		message += fmt.Sprintf(" • %d pentakills in one game!", match.PentaKills)
+	}
+	if match.LargestKillingSpree >= 8 {
+		message += fmt.Sprintf("\n🔥 Killing spree of %d", match.LargestKillingSpree)
+	}
+
+	return message
+}
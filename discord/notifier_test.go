@@ -0,0 +1,290 @@
+package discord
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// fakeDiscord answers the REST calls of a session, every message is accepted unless fail is set
+// or it is posted to failChannel
+type fakeDiscord struct {
+	mu          sync.Mutex
+	fail        bool
+	failChannel string
+	requests    []string // Paths of the messages posted
+}
+
+func (f *fakeDiscord) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, req.URL.Path)
+	status, body := http.StatusOK, `{"id": "1"}`
+	if f.fail || (f.failChannel != "" && strings.Contains(req.URL.Path, "/channels/"+f.failChannel+"/")) {
+		status, body = http.StatusForbidden, `{"code": 50013, "message": "Missing Permissions"}`
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func (f *fakeDiscord) sent() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+func newTestSession(t *testing.T, discord *fakeDiscord) *discordgo.Session {
+	t.Helper()
+
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("failed to create the session: %v", err)
+	}
+	session.Client = &http.Client{Transport: discord}
+	return session
+}
+
+// memoryDedupeStore is the dedupe store of the tests, it survives the restarts of the notifier
+type memoryDedupeStore struct {
+	mu       sync.Mutex
+	notified map[string]time.Time // Keyed by PUUID and match ID
+}
+
+func newMemoryDedupeStore() *memoryDedupeStore {
+	return &memoryDedupeStore{notified: make(map[string]time.Time)}
+}
+
+func dedupeKey(change *models.RankChange) string {
+	return change.Player.PUUID + "/" + change.Match.MatchID
+}
+
+func (s *memoryDedupeStore) IsNotified(ctx context.Context, change *models.RankChange) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.notified[dedupeKey(change)]
+	return ok, nil
+}
+
+func (s *memoryDedupeStore) MarkNotified(ctx context.Context, change *models.RankChange, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notified[dedupeKey(change)] = at
+	return nil
+}
+
+func testRankChange(gameName, matchID string) *models.RankChange {
+	return &models.RankChange{
+		Player:               &models.Player{PUUID: "puuid-" + gameName, GameName: gameName, TagLine: "EUW", Tier: "GOLD", Rank: "II", LeaguePoints: 70},
+		PreviousTier:         "GOLD",
+		PreviousRank:         "II",
+		PreviousLeaguePoints: 50,
+		Match:                &models.MatchPlayerInfo{MatchID: matchID},
+	}
+}
+
+// newTestNotifier returns a notifier whose dispatcher holds the messages for an hour, until it is flushed
+func newTestNotifier(t *testing.T, discord *fakeDiscord, dedupe *memoryDedupeStore) *Notifier {
+	t.Helper()
+
+	return &Notifier{
+		dispatcher: NewDispatcher(newTestSession(t, discord), time.Hour, DefaultDigestThreshold),
+		dedupe:     dedupe,
+	}
+}
+
+// announce enqueues a message per channel for the changes, like NotifyRankChanges for several guilds
+func announce(ctx context.Context, n *Notifier, channelIDs []string, changes ...*models.RankChange) {
+	deliveries := n.newDeliveryTracker(ctx, changes)
+	defer deliveries.enqueued()
+
+	for _, channelID := range channelIDs {
+		for _, change := range changes {
+			n.dispatcher.EnqueueTracked("guild-"+channelID, channelID, formatRankChange(change, "", false), deliveries.track(change))
+		}
+	}
+}
+
+func isNotified(t *testing.T, dedupe *memoryDedupeStore, change *models.RankChange) bool {
+	t.Helper()
+
+	notified, err := dedupe.IsNotified(context.Background(), change)
+	if err != nil {
+		t.Fatalf("failed to read the dedupe store: %v", err)
+	}
+	return notified
+}
+
+func TestNotifierMarksChangesOnceSent(t *testing.T) {
+	discord := &fakeDiscord{}
+	dedupe := newMemoryDedupeStore()
+	notifier := newTestNotifier(t, discord, dedupe)
+	change := testRankChange("Faker", "EUW1_1")
+
+	announce(context.Background(), notifier, []string{"channel-1"}, change)
+	if isNotified(t, dedupe, change) {
+		t.Fatal("the change was marked as notified while its message is still queued")
+	}
+
+	notifier.dispatcher.Flush()
+	if discord.sent() != 1 {
+		t.Fatalf("%d messages sent, want 1", discord.sent())
+	}
+	if !isNotified(t, dedupe, change) {
+		t.Error("the change was not marked as notified once its message was sent")
+	}
+}
+
+func TestNotifierRestartBeforeSendAnnouncesAgain(t *testing.T) {
+	discord := &fakeDiscord{}
+	dedupe := newMemoryDedupeStore()
+	change := testRankChange("Faker", "EUW1_1")
+
+	// The poller crashes before the digest window elapsed, the queued message is lost
+	crashed := newTestNotifier(t, discord, dedupe)
+	announce(context.Background(), crashed, []string{"channel-1"}, change)
+
+	// The restarted poller ingests the game again, the change is still to announce
+	restarted := newTestNotifier(t, discord, dedupe)
+	changes := restarted.unnotified(context.Background(), []*models.RankChange{testRankChange("Faker", "EUW1_1")})
+	if len(changes) != 1 {
+		t.Fatalf("the restarted notifier skips %d changes, want the change announced again", 1-len(changes))
+	}
+
+	announce(context.Background(), restarted, []string{"channel-1"}, changes...)
+	restarted.dispatcher.Flush()
+	if !isNotified(t, dedupe, change) {
+		t.Fatal("the change was not marked as notified after the restart")
+	}
+
+	// Announced once, the change is skipped by the next restart
+	again := newTestNotifier(t, discord, dedupe)
+	if changes := again.unnotified(context.Background(), []*models.RankChange{testRankChange("Faker", "EUW1_1")}); len(changes) != 0 {
+		t.Errorf("%d changes announced twice", len(changes))
+	}
+}
+
+func TestNotifierShutdownFlushesPendingMessages(t *testing.T) {
+	discord := &fakeDiscord{}
+	dedupe := newMemoryDedupeStore()
+	notifier := newTestNotifier(t, discord, dedupe)
+	first := testRankChange("Faker", "EUW1_1")
+	second := testRankChange("Caps", "EUW1_2")
+
+	ctx, shutdown := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		notifier.dispatcher.Run(ctx)
+	}()
+
+	// Enqueued with the poll context, the messages are sent after the end of the cycle
+	announce(ctx, notifier, []string{"channel-1", "channel-2"}, first, second)
+	shutdown()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the dispatcher did not stop")
+	}
+
+	if discord.sent() != 4 {
+		t.Errorf("%d messages sent on shutdown, want 4", discord.sent())
+	}
+	for _, change := range []*models.RankChange{first, second} {
+		if !isNotified(t, dedupe, change) {
+			t.Errorf("the change of %s was not marked as notified on shutdown", change.Player.GameName)
+		}
+	}
+}
+
+func TestNotifierKeepsUnsentChangesUnnotified(t *testing.T) {
+	discord := &fakeDiscord{fail: true}
+	dedupe := newMemoryDedupeStore()
+	notifier := newTestNotifier(t, discord, dedupe)
+	change := testRankChange("Faker", "EUW1_1")
+
+	announce(context.Background(), notifier, []string{"channel-1", "channel-2"}, change)
+	notifier.dispatcher.Flush()
+
+	if isNotified(t, dedupe, change) {
+		t.Error("the change was marked as notified while every message failed")
+	}
+}
+
+func TestNotifierMarksChangesSentToOneGuild(t *testing.T) {
+	// The first guild removed the permissions of the bot, the second one gets the message
+	discord := &fakeDiscord{failChannel: "channel-1"}
+	dedupe := newMemoryDedupeStore()
+	notifier := newTestNotifier(t, discord, dedupe)
+	change := testRankChange("Faker", "EUW1_1")
+
+	announce(context.Background(), notifier, []string{"channel-1", "channel-2"}, change)
+	notifier.dispatcher.Flush()
+
+	if discord.sent() != 2 {
+		t.Errorf("%d messages sent, want 2", discord.sent())
+	}
+	if !isNotified(t, dedupe, change) {
+		t.Error("the change was not marked as notified while a guild got its message")
+	}
+}
+
+func TestNotifierMarksChangesWithoutMessage(t *testing.T) {
+	discord := &fakeDiscord{}
+	dedupe := newMemoryDedupeStore()
+	notifier := newTestNotifier(t, discord, dedupe)
+	change := testRankChange("Faker", "EUW1_1")
+
+	// Muted in every guild
+	announce(context.Background(), notifier, nil, change)
+
+	if discord.sent() != 0 {
+		t.Errorf("%d messages sent, want none", discord.sent())
+	}
+	if !isNotified(t, dedupe, change) {
+		t.Error("the change without message was not marked as notified")
+	}
+}
+
+func TestDispatcherReportsDigestResults(t *testing.T) {
+	for _, fail := range []bool{false, true} {
+		discord := &fakeDiscord{fail: fail}
+		dispatcher := NewDispatcher(newTestSession(t, discord), time.Hour, 1)
+
+		var mu sync.Mutex
+		var results []error
+		for _, content := range []string{"first\nline", "second\nline", "third\nline"} {
+			dispatcher.EnqueueTracked("guild-1", "channel-1", content, func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				results = append(results, err)
+			})
+		}
+		dispatcher.Flush()
+
+		if discord.sent() != 1 {
+			t.Errorf("fail = %t: %d requests, want a single digest", fail, discord.sent())
+		}
+		if len(results) != 3 {
+			t.Fatalf("fail = %t: %d results, want one per merged message", fail, len(results))
+		}
+		for _, err := range results {
+			if (err != nil) != fail {
+				t.Errorf("fail = %t: result %v", fail, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handlePatchStatsAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	pseudo, tagline, server := playerIdentity(optionsByName(i.ApplicationCommandData().Options))
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	stats, err := h.playerService.GetPatchStats(ctx, player)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch patch statistics: %v", err))
+		log.Printf("Error fetching patch stats of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	// The newest patch played is used when Data Dragon is unavailable
+	currentPatch, err := h.dataDragon.LatestPatch(ctx)
+	if err != nil {
+		log.Printf("Error fetching current patch: %v", err)
+		if len(stats) > 0 {
+			currentPatch = stats[0].Patch
+		}
+	}
+
+	h.sendFollowUp(s, i, formatPatchStats(player, stats, currentPatch, interactionFormatter(i)))
+}
+
+func formatPatchStats(player *models.Player, stats []*models.PatchStats, currentPatch string, f models.Formatter) string {
+	if len(stats) == 0 {
+		return fmt.Sprintf("📭 No tracked games with a known patch for **%s#%s** yet!", player.GameName, player.TagLine)
+	}
+
+	current := &models.PatchStats{Patch: currentPatch}
+	var previous *models.PatchStats
+	for _, patch := range stats {
+		switch comparison := models.ComparePatches(patch.Patch, currentPatch); {
+		case comparison == 0:
+			current = patch
+		case comparison < 0 && previous == nil:
+			previous = patch
+		}
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("🛠️ **Patch stats of %s#%s**\n\n", player.GameName, player.TagLine))
+	response.WriteString(formatPatchLine("Current patch", current, f))
+
+	if previous == nil {
+		response.WriteString("\n_No tracked games on a previous patch to compare with_")
+		return response.String()
+	}
+	response.WriteString(formatPatchLine("Previous patch", previous, f))
+
+	if current.Games > 0 {
+		diff := current.WinRate() - previous.WinRate()
+		emoji := "📈"
+		if diff < 0 {
+			emoji = "📉"
+		}
+		response.WriteString(fmt.Sprintf("\n%s %s points of win rate since patch %s", emoji, f.Signed(diff, 1), previous.Patch))
+	}
+
+	return response.String()
+}
+
+func formatPatchLine(label string, stats *models.PatchStats, f models.Formatter) string {
+	if stats.Games == 0 {
+		return fmt.Sprintf("**%s %s**: no games yet\n", label, stats.Patch)
+	}
+	return fmt.Sprintf("**%s %s**: %d games • %dW %dL • %s win rate\n",
+		label, stats.Patch, stats.Games, stats.Wins, stats.Games-stats.Wins, f.Percent(stats.WinRate(), 1))
+}
@@ -0,0 +1,58 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"lp_tracker/services"
+)
+
+// PatchWatcher announces new patches, detected from the latest Data Dragon version, in the
+// notification channels. The last announced patch is stored per guild so restarts don't
+// announce it again.
+type PatchWatcher struct {
+	dispatcher         *Dispatcher
+	guildConfigService *services.GuildConfigService
+	dataDragon         *services.DataDragonService
+}
+
+func NewPatchWatcher(dispatcher *Dispatcher, guildConfigService *services.GuildConfigService, dataDragon *services.DataDragonService) *PatchWatcher {
+	return &PatchWatcher{
+		dispatcher:         dispatcher,
+		guildConfigService: guildConfigService,
+		dataDragon:         dataDragon,
+	}
+}
+
+// Check announces the current patch to the guilds that haven't seen it yet. Guilds without a
+// recorded patch (ex: new guilds) only record it, to avoid announcing a patch released long ago.
+func (p *PatchWatcher) Check(ctx context.Context) error {
+	patch, err := p.dataDragon.LatestPatch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch current patch: %w", err)
+	}
+
+	configs, err := p.guildConfigService.GetNotificationConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch guild configs: %w", err)
+	}
+
+	for _, config := range configs {
+		if config.LastPatch == patch {
+			continue
+		}
+
+		if config.LastPatch != "" {
+			message := fmt.Sprintf("🛠️ **Patch %s is live!** Use `/patch_stats` to compare your win rate with patch %s", patch, config.LastPatch)
+			p.dispatcher.Enqueue(config.GuildID, config.NotificationChannelID, message)
+		}
+
+		err := p.guildConfigService.SetLastPatch(ctx, config.GuildID, patch)
+		if err != nil {
+			log.Printf("Error recording patch %s for guild %s: %v", patch, config.GuildID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// Expired verifications are kept a week to investigate the reports of users, then removed
+	VERIFICATION_RETENTION = 7 * 24 * time.Hour
+
+	PENDING_ACTIONS_CLEANUP_INTERVAL = 1 * time.Hour
+)
+
+// PendingActionQueue checks the actions waiting for a user confirmation (ex: the account verifications),
+// DMs the users the outcome and the reminders, and cleans up the expired flows
+type PendingActionQueue struct {
+	session             *discordgo.Session
+	pendingActions      *services.PendingActionService
+	verificationService *services.VerificationService
+}
+
+func NewPendingActionQueue(s *discordgo.Session, pendingActions *services.PendingActionService, verificationService *services.VerificationService) *PendingActionQueue {
+	return &PendingActionQueue{
+		session:             s,
+		pendingActions:      pendingActions,
+		verificationService: verificationService,
+	}
+}
+
+// Run processes the due actions until the context is cancelled
+func (q *PendingActionQueue) Run(ctx context.Context) {
+	ticker := time.NewTicker(models.PendingActionCheckInterval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		now := time.Now()
+		q.process(ctx, now)
+
+		if now.Sub(lastCleanup) >= PENDING_ACTIONS_CLEANUP_INTERVAL {
+			q.cleanup(ctx)
+			lastCleanup = now
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *PendingActionQueue) process(ctx context.Context, now time.Time) {
+	messages, err := q.pendingActions.Process(ctx, now)
+	if err != nil {
+		log.Printf("Error processing pending actions: %v", err)
+	}
+
+	for _, message := range messages {
+		err := sendDirectMessage(q.session, message.DiscordUserID, message.Content)
+		if err != nil {
+			// DMs can be closed by the user, the outcome is still visible with the commands
+			log.Printf("Error sending DM to user %s: %v", message.DiscordUserID, err)
+		}
+	}
+}
+
+func (q *PendingActionQueue) cleanup(ctx context.Context) {
+	deleted, err := q.verificationService.CleanupExpired(ctx, VERIFICATION_RETENTION)
+	if err != nil {
+		log.Printf("Error cleaning up expired verifications: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("🧹 Removed %d expired verifications", deleted)
+	}
+}
+
+// sendDirectMessage sends a message in the DM channel of a user
+func sendDirectMessage(s *discordgo.Session, userID, content string) error {
+	channel, err := s.UserChannelCreate(userID)
+	if err != nil {
+		return err
+	}
+	return sendChannelMessage(s, channel.ID, content)
+}
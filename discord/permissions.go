@@ -0,0 +1,71 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// Commands restricted to server admins (members with the Manage Server permission)
+var adminPermissions int64 = discordgo.PermissionManageGuild
+
+// isServerAdmin checks if the member of an interaction has the permission of the admin commands.
+// Components (buttons, select menus) are not covered by the default member permissions of their command.
+func isServerAdmin(i *discordgo.InteractionCreate) bool {
+	return i.Member != nil && i.Member.Permissions&(discordgo.PermissionAdministrator|adminPermissions) != 0
+}
+
+// commandPermissions lists the default member permissions (Discord permissions v2) of the commands
+// changing the tracked players or the server configuration. Discord hides them from the members
+// without the permission, server admins can still open them to roles in Server Settings > Integrations.
+// /command_roles restricts the remaining commands further, it cannot widen these ones.
+var commandPermissions = map[string]int64{
+	"add_player":        adminPermissions,
+	"tag_player":        adminPermissions,
+	"player_note":       adminPermissions,
+	"person":            adminPermissions,
+	"public_profile":    adminPermissions,
+	"twitch":            adminPermissions,
+	"snapshot":          adminPermissions,
+	"competition_start": adminPermissions,
+	"notifications":     adminPermissions,
+	"live_leaderboard":  adminPermissions,
+	"settings":          adminPermissions,
+	"set_timezone":      adminPermissions,
+	"set_language":      adminPermissions,
+	"job_status":        adminPermissions,
+	"feature":           adminPermissions,
+	"inactive":          adminPermissions,
+	"purge_inactive":    adminPermissions,
+	"command_roles":     adminPermissions,
+	"bot_stats":         adminPermissions,
+}
+
+// Commands that also work in DMs with the bot, the others read the server configuration
+var dmCommands = map[string]bool{
+	"check":   true,
+	"version": true,
+	"help":    true,
+	"link":    true, // Linked accounts are global, not per server
+	"admin":   true, // Owner only, checked by handleAdminAsync
+}
+
+var (
+	guildContexts = []discordgo.InteractionContextType{discordgo.InteractionContextGuild}
+	dmContexts    = []discordgo.InteractionContextType{discordgo.InteractionContextGuild, discordgo.InteractionContextBotDM}
+)
+
+// withPermissions sets the default member permissions and the contexts of the commands, so both are
+// synced with Discord every time the commands are registered
+func withPermissions(commands []*discordgo.ApplicationCommand) []*discordgo.ApplicationCommand {
+	for _, command := range commands {
+		if permissions, ok := commandPermissions[command.Name]; ok {
+			command.DefaultMemberPermissions = &permissions
+		}
+
+		if dmCommands[command.Name] {
+			command.Contexts = &dmContexts
+		} else {
+			command.Contexts = &guildContexts
+		}
+	}
+	return commands
+}
@@ -0,0 +1,124 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handlePersonAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	subcommand := i.ApplicationCommandData().Options[0]
+	options := optionsByName(subcommand.Options)
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	switch subcommand.Name {
+	case "link":
+		pseudo, tagline, server := playerIdentity(options)
+		person, player, err := h.personService.LinkAccount(ctx, options["person"].StringValue(), pseudo, tagline, server)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to link **%s#%s**\n\n**Error:** %v", pseudo, tagline, err))
+			log.Printf("Error linking player %s#%s: %v", pseudo, tagline, err)
+			return
+		}
+		h.sendFollowUp(s, i, fmt.Sprintf("🔗 **%s#%s** (%s) linked to **%s** (%d accounts)",
+			player.GameName, player.TagLine, strings.ToUpper(player.Server), person.Name, len(person.PlayerPUUIDs)))
+	case "unlink":
+		pseudo, tagline, server := playerIdentity(options)
+		player, err := h.personService.UnlinkAccount(ctx, pseudo, tagline, server)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to unlink **%s#%s**\n\n**Error:** %v", pseudo, tagline, err))
+			log.Printf("Error unlinking player %s#%s: %v", pseudo, tagline, err)
+			return
+		}
+		h.sendFollowUp(s, i, fmt.Sprintf("✂️ **%s#%s** (%s) is no longer linked to a person",
+			player.GameName, player.TagLine, strings.ToUpper(player.Server)))
+	case "list":
+		persons, err := h.personService.GetPersons(ctx)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch persons: %v", err))
+			log.Printf("Error fetching persons: %v", err)
+			return
+		}
+		h.sendFollowUp(s, i, formatPersons(persons))
+	case "compare":
+		person, comparisons, err := h.personService.CompareAccounts(ctx, options["person"].StringValue())
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to compare accounts: %v", err))
+			return
+		}
+		h.sendFollowUp(s, i, formatAccountComparison(person, comparisons))
+	case "group":
+		if i.GuildID == "" {
+			h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+			return
+		}
+
+		enabled := options["enabled"].BoolValue()
+		_, err := h.guildConfigService.UpdateConfig(ctx, i.GuildID, func(config *models.GuildConfig) error {
+			config.GroupByPerson = enabled
+			return nil
+		})
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to save server settings: %v", err))
+			log.Printf("Error saving guild config %s: %v", i.GuildID, err)
+			return
+		}
+
+		if enabled {
+			h.sendFollowUp(s, i, "✅ The live leaderboard ranks persons by their best account and the recaps sum the games of their accounts")
+		} else {
+			h.sendFollowUp(s, i, "✅ Accounts are no longer grouped by person")
+		}
+	default:
+		h.sendFollowUp(s, i, "❌ Unknown subcommand")
+	}
+}
+
+func formatPersons(persons []*models.PersonStanding) string {
+	if len(persons) == 0 {
+		return "📭 No persons yet!\nUse `/person link` to group the accounts of a player."
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("👥 **Persons (%d)**\n\n", len(persons)))
+	for _, person := range persons {
+		response.WriteString(fmt.Sprintf("👤 **%s**\n", person.Name()))
+		for _, account := range person.Accounts {
+			summary := models.NewPlayerSummary(account)
+			response.WriteString(fmt.Sprintf("   • %s (%s) • %s\n", summary.RiotID(), summary.Server, summary.RankLabel()))
+		}
+	}
+	return response.String()
+}
+
+func formatAccountComparison(person *models.Person, comparisons []*models.AccountComparison) string {
+	if len(comparisons) == 0 {
+		return fmt.Sprintf("📭 No tracked account linked to **%s**", person.Name)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("🌍 **Accounts of %s** (ranks normalized to %s)\n\n", person.Name, strings.ToUpper(models.ReferenceServer)))
+	for idx, comparison := range comparisons {
+		summary := models.NewPlayerSummary(comparison.Player)
+		if !summary.IsRanked() {
+			response.WriteString(fmt.Sprintf("**%d.** %s (%s) • Unranked\n", idx+1, summary.RiotID(), summary.Server))
+			continue
+		}
+
+		normalized, normalizedLP := comparison.NormalizedRank()
+		response.WriteString(fmt.Sprintf("**%d.** %s (%s) • %s %d LP ≈ %s %d LP",
+			idx+1, summary.RiotID(), summary.Server, summary.RankLabel(), summary.LeaguePoints, normalized, normalizedLP))
+		if comparison.GapToBest > 0 {
+			response.WriteString(fmt.Sprintf(" • %d LP behind", comparison.GapToBest))
+		}
+		response.WriteString("\n")
+	}
+	response.WriteString("\n💡 Region difficulty is approximated from the ladders distributions")
+	return response.String()
+}
@@ -0,0 +1,112 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Custom IDs of the notification buttons are "player:<action>:<puuid>"
+const (
+	playerActionPrefix = "player:"
+
+	playerActionProfile = "profile"
+	playerActionMute    = "mute"
+	playerActionRecent  = "recent"
+)
+
+// playerActions returns the quick action buttons attached to the notifications of a player
+func playerActions(player *models.Player) discordgo.ActionsRow {
+	return discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{
+			CustomID: playerActionPrefix + playerActionProfile + ":" + player.PUUID,
+			Label:    "View profile",
+			Style:    discordgo.SecondaryButton,
+			Emoji:    &discordgo.ComponentEmoji{Name: "👤"},
+		},
+		discordgo.Button{
+			CustomID: playerActionPrefix + playerActionRecent + ":" + player.PUUID,
+			Label:    "Show recent games",
+			Style:    discordgo.SecondaryButton,
+			Emoji:    &discordgo.ComponentEmoji{Name: "🕹️"},
+		},
+		discordgo.Button{
+			CustomID: playerActionPrefix + playerActionMute + ":" + player.PUUID,
+			Label:    "Mute this player",
+			Style:    discordgo.SecondaryButton,
+			Emoji:    &discordgo.ComponentEmoji{Name: "🔇"},
+		},
+	}}
+}
+
+func (h *CommandHandler) handlePlayerActionAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	action, puuid, ok := strings.Cut(strings.TrimPrefix(i.MessageComponentData().CustomID, playerActionPrefix), ":")
+	if !ok || puuid == "" {
+		h.sendFollowUp(s, i, "❌ Unknown action")
+		return
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByPUUID(ctx, puuid)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s: %v", puuid, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, "❌ This player is no longer tracked")
+		return
+	}
+
+	switch action {
+	case playerActionProfile:
+		response := formatPlayerRank(player, interactionFormatter(i))
+		summary := models.NewPlayerSummary(player)
+		if len(summary.Tags) > 0 {
+			response += fmt.Sprintf("🏷️ %s\n", strings.Join(summary.Tags, ", "))
+		}
+		if summary.Note != "" {
+			response += fmt.Sprintf("📝 %s\n", summary.Note)
+		}
+		h.sendFollowUp(s, i, response)
+	case playerActionRecent:
+		matches, err := h.playerService.GetRecentMatches(ctx, player, defaultRecentGames)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch recent games: %v", err))
+			log.Printf("Error fetching recent games of %s#%s: %v", player.GameName, player.TagLine, err)
+			return
+		}
+		h.sendFollowUp(s, i, formatRecentMatches(player, matches))
+	case playerActionMute:
+		if i.GuildID == "" {
+			h.sendFollowUp(s, i, "❌ Players can only be muted in a server")
+			return
+		}
+		// Same permission as /settings, the button is shown to every member who can see the notification
+		if !isServerAdmin(i) {
+			h.sendFollowUp(s, i, "❌ Only server admins (Manage Server permission) can mute players")
+			return
+		}
+
+		muted, err := h.guildConfigService.TogglePlayerMuted(ctx, i.GuildID, player.PUUID)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to mute player: %v", err))
+			log.Printf("Error muting player %s#%s in guild %s: %v", player.GameName, player.TagLine, i.GuildID, err)
+			return
+		}
+
+		if muted {
+			h.sendFollowUp(s, i, fmt.Sprintf("🔇 Notifications of **%s#%s** are muted on this server, click again to unmute", player.GameName, player.TagLine))
+		} else {
+			h.sendFollowUp(s, i, fmt.Sprintf("🔊 Notifications of **%s#%s** are unmuted", player.GameName, player.TagLine))
+		}
+	default:
+		h.sendFollowUp(s, i, "❌ Unknown action")
+	}
+}
@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleProfileAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	pseudo, tagline, server := playerIdentity(optionsByName(i.ApplicationCommandData().Options))
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	h.sendFollowUpEmbed(s, i, h.profileEmbed(ctx, player, interactionFormatter(i)))
+}
+
+// profileEmbed shows a player with their profile icon when Data Dragon is available.
+// The title links to the public profile when it is published.
+func (h *CommandHandler) profileEmbed(ctx context.Context, player *models.Player, f models.Formatter) *discordgo.MessageEmbed {
+	embed := buildProfileEmbed(models.NewPlayerDetail(player), f)
+	if player.PublicProfile && h.publicURL != "" {
+		embed.URL = h.publicURL + models.PublicProfilePath(player.Server, player.GameName, player.TagLine)
+	}
+
+	if iconURL := h.profileIconURL(ctx, player.ProfileIconID); iconURL != "" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: iconURL}
+	}
+	return embed
+}
+
+// profileIconURL returns the image of a profile icon, empty when unknown or when Data Dragon is unavailable
+func (h *CommandHandler) profileIconURL(ctx context.Context, iconID int) string {
+	if iconID == 0 {
+		return ""
+	}
+	iconURL, err := h.dataDragon.ProfileIconURL(ctx, iconID)
+	if err != nil {
+		log.Printf("Error fetching profile icon %d: %v", iconID, err)
+		return ""
+	}
+	return iconURL
+}
+
+func buildProfileEmbed(detail models.PlayerDetail, f models.Formatter) *discordgo.MessageEmbed {
+	rank := "Unranked"
+	record := "No ranked game this season"
+	if detail.IsRanked() {
+		rank = fmt.Sprintf("%s • %d LP", detail.RankLabel(), detail.LeaguePoints)
+	}
+	if detail.Wins+detail.Losses > 0 {
+		record = fmt.Sprintf("%s (%s W / %s L)", f.Percent(detail.WinRate, 1), f.Int(detail.Wins), f.Int(detail.Losses))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "👤 " + detail.DisplayName(),
+		Color: colorOfTier(detail.Tier),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Solo/Duo", Value: rank, Inline: true},
+			{Name: "Win rate", Value: record, Inline: true},
+			{Name: "Level", Value: f.Int(int(detail.Level)), Inline: true},
+			{Name: "Server", Value: detail.Server, Inline: true},
+			{Name: "Tracked since", Value: fmt.Sprintf("<t:%d:D>", detail.TrackedSince.Unix()), Inline: true},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if detail.Alias != "" {
+		embed.Description = detail.RiotID()
+	}
+	if detail.LastPolledAt != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: "Updated", Value: fmt.Sprintf("<t:%d:R>", detail.LastPolledAt.Unix()), Inline: true,
+		})
+	}
+	return embed
+}
@@ -0,0 +1,44 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SetPublicURL sets the base URL of the web dashboard (ex: https://lp.example.com) used in the shared links
+func (h *CommandHandler) SetPublicURL(publicURL string) {
+	h.publicURL = strings.TrimSuffix(publicURL, "/")
+}
+
+func (h *CommandHandler) handlePublicProfileAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := optionsByName(i.ApplicationCommandData().Options)
+	pseudo, tagline, server := playerIdentity(options)
+	enabled := options["enabled"].BoolValue()
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.profileService.SetPublicProfile(ctx, pseudo, tagline, server, enabled)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to update the public profile of **%s#%s**\n\n**Error:** %v", pseudo, tagline, err))
+		log.Printf("Error updating public profile of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	if !enabled {
+		h.sendFollowUp(s, i, fmt.Sprintf("🔒 The public profile of **%s#%s** is no longer published", player.GameName, player.TagLine))
+		return
+	}
+
+	path := models.PublicProfilePath(player.Server, player.GameName, player.TagLine)
+	if h.publicURL == "" {
+		h.sendFollowUp(s, i, fmt.Sprintf("🌐 The public profile of **%s#%s** is published on `%s`\n⚠️ `PUBLIC_URL` is not set, the full link is unknown", player.GameName, player.TagLine, path))
+		return
+	}
+	h.sendFollowUp(s, i, fmt.Sprintf("🌐 The public profile of **%s#%s** is published: %s%s", player.GameName, player.TagLine, h.publicURL, path))
+}
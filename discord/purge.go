@@ -0,0 +1,230 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Custom IDs of the /purge_inactive buttons are "purge_inactive:confirm:<action>:<days>" and
+// "purge_inactive:cancel", the prefix matches the command so the permissions matrix applies
+const (
+	purgeComponentPrefix = "purge_inactive:"
+
+	purgeConfirm = "confirm"
+	purgeCancel  = "cancel"
+
+	purgeActionRemove = "remove"
+	purgeActionPause  = "pause"
+)
+
+// Inactivity windows accepted by /purge_inactive
+const (
+	defaultPurgeDays = 30
+	maxPurgeDays     = 365
+)
+
+// Players listed in the /purge_inactive preview
+const purgePreviewMaxPlayers = 25
+
+var minPurgeDaysValue = 7.0
+
+func (h *CommandHandler) handlePurgeInactiveAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+	// Players are shared by every server, removing them would untrack them everywhere
+	if !h.isBotOwner(i) {
+		h.sendFollowUp(s, i, "❌ This command is restricted to the bot owner, the players are shared by every server")
+		log.Printf("Rejected /purge_inactive from user %s in guild %s", interactionUserID(i), i.GuildID)
+		return
+	}
+
+	options := optionsByName(i.ApplicationCommandData().Options)
+	days := defaultPurgeDays
+	if opt, ok := options["days"]; ok {
+		days = int(opt.IntValue())
+	}
+	action := purgeActionRemove
+	if opt, ok := options["action"]; ok {
+		action = opt.StringValue()
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	inactive, err := h.inactivePlayers(ctx, days, action)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch inactive players: %v", err))
+		log.Printf("Error fetching inactive players: %v", err)
+		return
+	}
+
+	if len(inactive) == 0 {
+		h.sendFollowUp(s, i, fmt.Sprintf("✅ Every tracked player played a ranked game in the last %d days", days))
+		return
+	}
+
+	embeds := textEmbeds(formatPurgePreview(inactive, days, action))
+	components := purgeComponents(action, days)
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &embeds,
+		Components: &components,
+	})
+	if err != nil {
+		log.Printf("Error sending purge preview: %v", err)
+	}
+}
+
+func (h *CommandHandler) handlePurgeComponentAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// The preview is ephemeral, the owner is checked again in case the buttons were forged
+	if !h.isBotOwner(i) {
+		h.editPurgeMessage(s, i, "❌ This command is restricted to the bot owner, the players are shared by every server")
+		log.Printf("Rejected purge confirmation from user %s in guild %s", interactionUserID(i), i.GuildID)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(i.MessageComponentData().CustomID, purgeComponentPrefix), ":")
+	if parts[0] == purgeCancel {
+		h.editPurgeMessage(s, i, "🚫 Purge cancelled, no player was changed")
+		return
+	}
+
+	if len(parts) != 3 || parts[0] != purgeConfirm {
+		h.editPurgeMessage(s, i, "❌ Unknown action")
+		return
+	}
+	action := parts[1]
+	days, err := strconv.Atoi(parts[2])
+	if err != nil || (action != purgeActionRemove && action != purgeActionPause) {
+		h.editPurgeMessage(s, i, "❌ Unknown action")
+		return
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	// The list is read again, a player may have played since the preview
+	inactive, err := h.inactivePlayers(ctx, days, action)
+	if err != nil {
+		h.editPurgeMessage(s, i, fmt.Sprintf("❌ Failed to fetch inactive players: %v", err))
+		log.Printf("Error fetching inactive players: %v", err)
+		return
+	}
+
+	var done []string
+	var failed []string
+	for _, entry := range inactive {
+		player := entry.Player
+		if action == purgeActionPause {
+			err = h.playerService.PausePlayer(ctx, player)
+		} else {
+			err = h.playerService.RemovePlayer(ctx, player)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s#%s", player.GameName, player.TagLine))
+			log.Printf("Error purging player %s#%s (%s): %v", player.GameName, player.TagLine, action, err)
+			continue
+		}
+		done = append(done, fmt.Sprintf("%s#%s", player.GameName, player.TagLine))
+	}
+
+	verb := "removed"
+	if action == purgeActionPause {
+		verb = "paused"
+	}
+	content := fmt.Sprintf("🧹 %d inactive players %s", len(done), verb)
+	if len(failed) > 0 {
+		content += fmt.Sprintf("\n⚠️ %d players could not be %s: %s", len(failed), verb, strings.Join(failed, ", "))
+	}
+	if action == purgeActionPause && len(done) > 0 {
+		content += "\n💡 Use /add_player to resume a paused player"
+	}
+	log.Printf("Purge of inactive players (%d days) by %s: %d %s, %d failed", days, interactionUserID(i), len(done), verb, len(failed))
+
+	h.editPurgeMessage(s, i, content)
+}
+
+// inactivePlayers lists the players without ranked games in the last days, the already paused
+// players are left out when pausing
+func (h *CommandHandler) inactivePlayers(ctx context.Context, days int, action string) ([]*models.InactivePlayer, error) {
+	since := time.Now().AddDate(0, 0, -days)
+	inactive, err := h.playerService.GetInactivePlayers(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	if action != purgeActionPause {
+		return inactive, nil
+	}
+
+	var active []*models.InactivePlayer
+	for _, entry := range inactive {
+		if !entry.Player.Paused {
+			active = append(active, entry)
+		}
+	}
+	return active, nil
+}
+
+// editPurgeMessage replaces the preview, dropping its buttons
+func (h *CommandHandler) editPurgeMessage(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	embeds := textEmbeds(content)
+	components := []discordgo.MessageComponent{}
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &embeds,
+		Components: &components,
+	})
+	if err != nil {
+		log.Printf("Error editing purge message: %v", err)
+	}
+}
+
+func purgeComponents(action string, days int) []discordgo.MessageComponent {
+	label := "Remove players"
+	if action == purgeActionPause {
+		label = "Pause players"
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				CustomID: fmt.Sprintf("%s%s:%s:%d", purgeComponentPrefix, purgeConfirm, action, days),
+				Label:    label,
+				Style:    discordgo.DangerButton,
+			},
+			discordgo.Button{
+				CustomID: purgeComponentPrefix + purgeCancel,
+				Label:    "Cancel",
+				Style:    discordgo.SecondaryButton,
+			},
+		}},
+	}
+}
+
+func formatPurgePreview(inactive []*models.InactivePlayer, days int, action string) string {
+	verb := "removed"
+	if action == purgeActionPause {
+		verb = "paused (kept but no longer polled)"
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("🧹 **%d players without ranked games in the last %d days** will be %s:\n", len(inactive), days, verb))
+	for idx, entry := range inactive {
+		if idx >= purgePreviewMaxPlayers {
+			builder.WriteString(fmt.Sprintf("... and %d more players\n", len(inactive)-purgePreviewMaxPlayers))
+			break
+		}
+
+		builder.WriteString(formatInactivePlayer(entry))
+	}
+	builder.WriteString("💡 Based on the stored games, players are tracked for every server")
+	return builder.String()
+}
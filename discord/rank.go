@@ -0,0 +1,85 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleRankAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := optionsByName(i.ApplicationCommandData().Options)
+	pseudo, tagline, server := playerIdentity(options)
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	// Target the next division unless a rank is given
+	targetTier, targetRank, hasTarget := models.NextDivision(player.Tier, player.Rank)
+	if opt, ok := options["target_tier"]; ok {
+		targetTier, targetRank, hasTarget = opt.StringValue(), "IV", true
+		if opt, ok := options["target_division"]; ok {
+			targetRank = opt.StringValue()
+		}
+	}
+
+	response := formatPlayerRank(player, interactionFormatter(i))
+	if hasTarget {
+		projection, err := h.playerService.ProjectClimb(ctx, player, targetTier, targetRank)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to compute the climb projection: %v", err))
+			log.Printf("Error projecting climb of %s#%s: %v", pseudo, tagline, err)
+			return
+		}
+		response += "\n" + formatClimbProjection(player, projection, targetTier, targetRank)
+	}
+
+	h.sendFollowUp(s, i, response)
+}
+
+func formatPlayerRank(player *models.Player, f models.Formatter) string {
+	detail := models.NewPlayerDetail(player)
+	response := fmt.Sprintf("🏆 **%s** (%s) • %s • %d LP\n📊 %s W / %s L (%s win rate)\n",
+		detail.DisplayName(), detail.Server, detail.RankLabel(), detail.LeaguePoints,
+		f.Int(detail.Wins), f.Int(detail.Losses), f.Percent(detail.WinRate, 1))
+	if player.LastPolledAt != nil {
+		response += fmt.Sprintf("🔄 Updated %s\n", f.RelativeTime(*player.LastPolledAt, time.Now()))
+	}
+	return response
+}
+
+func formatClimbProjection(player *models.Player, projection *models.ClimbProjection, targetTier, targetRank string) string {
+	target := models.FormatRank(targetTier, targetRank)
+
+	if models.RankValue(targetTier, targetRank, 0) <= models.RankValue(player.Tier, player.Rank, player.LeaguePoints) {
+		return fmt.Sprintf("✅ Already at or above **%s**", target)
+	}
+	if projection == nil {
+		return fmt.Sprintf("🔮 No projection to **%s**: not enough tracked games or not climbing at the moment", target)
+	}
+
+	latest := "never at worst"
+	if !projection.LatestIsOpen {
+		latest = fmt.Sprintf("<t:%d:D> at worst", projection.LatestETA.Unix())
+	}
+
+	return fmt.Sprintf("🔮 **%s** in ~%d games (%d LP), expected <t:%d:D> (<t:%d:R>)\n📐 Between <t:%d:D> at best and %s",
+		target, projection.Games, projection.LPNeeded,
+		projection.ETA.Unix(), projection.ETA.Unix(),
+		projection.EarliestETA.Unix(), latest)
+}
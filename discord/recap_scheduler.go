@@ -0,0 +1,224 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	RECAP_CHECK_INTERVAL = 1 * time.Minute
+)
+
+// RecapScheduler posts daily and weekly recaps at each guild's local dispatch time
+type RecapScheduler struct {
+	session            *discordgo.Session
+	guildConfigService *services.GuildConfigService
+	recapService       *services.RecapService
+}
+
+func NewRecapScheduler(s *discordgo.Session, guildConfigService *services.GuildConfigService, recapService *services.RecapService) *RecapScheduler {
+	return &RecapScheduler{
+		session:            s,
+		guildConfigService: guildConfigService,
+		recapService:       recapService,
+	}
+}
+
+// Run checks for due recaps until the context is cancelled
+func (rs *RecapScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(RECAP_CHECK_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		rs.dispatchDueRecaps(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (rs *RecapScheduler) dispatchDueRecaps(ctx context.Context, now time.Time) {
+	configs, err := rs.guildConfigService.GetNotificationConfigs(ctx)
+	if err != nil {
+		log.Printf("Error fetching guild configs for recaps: %v", err)
+		return
+	}
+
+	for _, config := range configs {
+		rs.dispatchIfDue(ctx, config, models.RecapPeriodDaily, config.LastDailyRecapAt, now)
+		rs.dispatchIfDue(ctx, config, models.RecapPeriodWeekly, config.LastWeeklyRecapAt, now)
+	}
+}
+
+func (rs *RecapScheduler) dispatchIfDue(ctx context.Context, config *models.GuildConfig, period models.RecapPeriod, lastSentAt time.Time, now time.Time) {
+	dueAt := config.LastRecapDispatch(now, period)
+
+	// Already sent, or the guild was configured after the dispatch time
+	if !lastSentAt.Before(dueAt) || config.CreatedAt.After(dueAt) {
+		return
+	}
+
+	start := dueAt.AddDate(0, 0, -1)
+	if period == models.RecapPeriodWeekly {
+		start = dueAt.AddDate(0, 0, -7)
+	}
+
+	recap, err := rs.recapService.BuildRecap(ctx, period, start, dueAt)
+	if err != nil {
+		log.Printf("Error building %s recap for guild %s: %v", period, config.GuildID, err)
+		return
+	}
+	if config.GroupByPerson {
+		recap, err = rs.recapService.GroupByPerson(ctx, recap)
+		if err != nil {
+			log.Printf("Error grouping %s recap by person for guild %s: %v", period, config.GuildID, err)
+			return
+		}
+	}
+
+	if len(recap.Entries) > 0 {
+		err = sendChannelMessage(rs.session, config.NotificationChannelID, formatRecap(recap, config.Location(), models.NewFormatter(config.Language)))
+		if err != nil {
+			log.Printf("Error sending %s recap to guild %s: %v", period, config.GuildID, err)
+			return
+		}
+	}
+
+	err = rs.guildConfigService.MarkRecapSent(ctx, config.GuildID, period, now)
+	if err != nil {
+		log.Printf("Error marking %s recap sent for guild %s: %v", period, config.GuildID, err)
+	}
+}
+
+// formatRecap formats a recap in the time zone of the guild, numbers and dates follow its language
+func formatRecap(recap *models.Recap, loc *time.Location, f models.Formatter) string {
+	var response strings.Builder
+
+	if recap.Period == models.RecapPeriodWeekly {
+		response.WriteString(fmt.Sprintf("📅 **Weekly recap** (%s → %s)\n\n",
+			f.DayMonth(recap.Start.In(loc)), f.DayMonth(recap.End.In(loc))))
+	} else {
+		response.WriteString(fmt.Sprintf("📅 **Daily recap** (%s)\n\n", f.DayMonth(recap.Start.In(loc))))
+	}
+
+	for idx, entry := range recap.Entries {
+		if idx >= 20 {
+			response.WriteString(fmt.Sprintf("... and %d more players\n", len(recap.Entries)-20))
+			break
+		}
+
+		emoji := "⚪"
+		if entry.LPDelta > 0 {
+			emoji = "🟢"
+		} else if entry.LPDelta < 0 {
+			emoji = "🔴"
+		}
+
+		response.WriteString(fmt.Sprintf("%s **%s** %s LP • %dW %dL • %s %s %d LP\n",
+			emoji, entry.DisplayName(), f.Signed(float64(entry.LPDelta), 0), entry.Wins, entry.Losses,
+			entry.Tier, entry.Rank, entry.LeaguePoints))
+	}
+
+	if recap.Period == models.RecapPeriodWeekly {
+		response.WriteString(formatRecapHighlights(recap, f))
+		response.WriteString(formatChampionRecommendations(recap))
+		response.WriteString(formatChallengeCompletions(recap))
+	}
+
+	var drifting []string
+	for _, entry := range recap.Entries {
+		if entry.IsRoleDrifting() {
+			drifting = append(drifting, fmt.Sprintf("• **%s** played %d/%d games off-role (main: %s %s)",
+				entry.DisplayName(), entry.OffRoleGames, entry.RoleGames, models.RoleEmoji(entry.MainRole), models.RoleName(entry.MainRole)))
+		}
+	}
+	if len(drifting) > 0 {
+		response.WriteString("\n⚠️ **Role drift** (autofilled often)\n")
+		response.WriteString(strings.Join(drifting, "\n"))
+		response.WriteString("\n")
+	}
+
+	return response.String()
+}
+
+// formatRecapHighlights returns the non-KDA contributions section of a recap (vision and objectives)
+func formatRecapHighlights(recap *models.Recap, f models.Formatter) string {
+	var visionMVP, objectiveMVP *models.RecapEntry
+	for _, entry := range recap.Entries {
+		if entry.MatchGames == 0 {
+			continue
+		}
+		if visionMVP == nil || entry.AverageVisionScore() > visionMVP.AverageVisionScore() {
+			visionMVP = entry
+		}
+		if objectiveMVP == nil || entry.ObjectiveTakedowns() > objectiveMVP.ObjectiveTakedowns() {
+			objectiveMVP = entry
+		}
+	}
+	if visionMVP == nil {
+		return ""
+	}
+
+	var highlights strings.Builder
+	highlights.WriteString("\n🌟 **Beyond the KDA**\n")
+	highlights.WriteString(fmt.Sprintf("👁️ Vision MVP: **%s** (%s vision score per game)\n",
+		visionMVP.DisplayName(), f.Number(visionMVP.AverageVisionScore(), 1)))
+	if objectiveMVP.ObjectiveTakedowns() > 0 {
+		highlights.WriteString(fmt.Sprintf("🐉 Objective MVP: **%s** (%d dragons, %d barons, %d heralds, %d turrets)\n",
+			objectiveMVP.DisplayName(), objectiveMVP.DragonTakedowns, objectiveMVP.BaronTakedowns,
+			objectiveMVP.HeraldTakedowns, objectiveMVP.TurretTakedowns))
+	}
+
+	return highlights.String()
+}
+
+// formatChallengeCompletions returns the notable challenge levels reached over the period
+func formatChallengeCompletions(recap *models.Recap) string {
+	if len(recap.ChallengeCompletions) == 0 {
+		return ""
+	}
+
+	var completions strings.Builder
+	completions.WriteString("\n🏅 **Challenges**\n")
+	for idx, completion := range recap.ChallengeCompletions {
+		if idx >= 10 {
+			completions.WriteString(fmt.Sprintf("... and %d more\n", len(recap.ChallengeCompletions)-10))
+			break
+		}
+		completions.WriteString(fmt.Sprintf("• **%s#%s** reached **%s** in **%s**\n",
+			completion.GameName, completion.TagLine, models.FormatChallengeLevel(completion.Level), completion.ChallengeName))
+	}
+
+	return completions.String()
+}
+
+// formatChampionRecommendations returns the best and worst champion of each player over the period
+func formatChampionRecommendations(recap *models.Recap) string {
+	var lines []string
+	for _, entry := range recap.Entries {
+		if best := entry.BestChampion(); best != nil {
+			lines = append(lines, fmt.Sprintf("⭐ **%s** best champion this week: **%s** (%dW %dL)",
+				entry.DisplayName(), best.Champion, best.Wins, best.Games-best.Wins))
+		}
+		if worst := entry.WorstChampion(); worst != nil {
+			lines = append(lines, fmt.Sprintf("🚫 **%s** consider banning yourself from **%s** (%dW %dL)",
+				entry.DisplayName(), worst.Champion, worst.Wins, worst.Games-worst.Wins))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return "\n🎯 **Champion pool**\n" + strings.Join(lines, "\n") + "\n"
+}
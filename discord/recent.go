@@ -0,0 +1,75 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	defaultRecentGames = 5
+	maxRecentGames     = 10
+)
+
+func (h *CommandHandler) handleRecentAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := optionsByName(i.ApplicationCommandData().Options)
+	pseudo, tagline, server := playerIdentity(options)
+	count := defaultRecentGames
+	if opt, ok := options["count"]; ok {
+		count = int(opt.IntValue())
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	matches, err := h.playerService.GetRecentMatches(ctx, player, count)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch recent games: %v", err))
+		log.Printf("Error fetching recent games of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	h.sendFollowUp(s, i, formatRecentMatches(player, matches))
+}
+
+func formatRecentMatches(player *models.Player, matches []*models.MatchPlayerInfo) string {
+	if len(matches) == 0 {
+		return fmt.Sprintf("📭 No tracked games for **%s#%s** yet!", player.GameName, player.TagLine)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("🕹️ **Recent games of %s#%s**\n\n", player.GameName, player.TagLine))
+
+	for _, match := range matches {
+		result := "🔴 Defeat"
+		if match.Victory {
+			result = "🟢 Victory"
+		}
+
+		afk := ""
+		if match.HasAFK() {
+			afk = " • ⚠️ AFK suspected"
+		}
+
+		response.WriteString(fmt.Sprintf("%s • **%s** %s • %s (%s) • <t:%d:R>%s\n   📊 %s\n",
+			result, match.Champion, models.RoleEmoji(match.Role), match.KDAString(), match.FormatGameDuration(),
+			match.CreatedAt.Unix(), afk, match.MetricsString()))
+	}
+
+	return response.String()
+}
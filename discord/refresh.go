@@ -0,0 +1,89 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SetNotifier sets the notifier announcing the rank changes detected by /refresh and /admin poll_now.
+// The updates save the rank and the checkpoint of the player, the poller doesn't see these changes again.
+func (h *CommandHandler) SetNotifier(n *Notifier) {
+	h.notifier = n
+}
+
+// notifyRankChange announces a change detected outside of the poller to the guilds
+func (h *CommandHandler) notifyRankChange(ctx context.Context, change *models.RankChange) {
+	if h.notifier == nil || change == nil {
+		return
+	}
+
+	err := h.notifier.NotifyRankChanges(ctx, []*models.RankChange{change})
+	if err != nil {
+		log.Printf("Error notifying rank change of %s#%s: %v", change.Player.GameName, change.Player.TagLine, err)
+	}
+}
+
+func (h *CommandHandler) handleRefreshAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	pseudo, tagline, server := playerIdentity(optionsByName(i.ApplicationCommandData().Options))
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked, use `/add_player` first", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	if player.LastPolledAt != nil && time.Since(*player.LastPolledAt) < services.RefreshCooldown {
+		h.sendFollowUp(s, i, formatRefreshedPlayer(player, nil)+fmt.Sprintf("\n🕒 Already updated <t:%d:R>", player.LastPolledAt.Unix()))
+		return
+	}
+
+	change, err := h.playerService.UpdatePlayer(ctx, player)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to refresh **%s#%s**\n\n**Error:** %v", pseudo, tagline, err))
+		log.Printf("Error refreshing player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	h.notifyRankChange(ctx, change)
+
+	h.sendFollowUp(s, i, formatRefreshedPlayer(player, change))
+}
+
+// formatRefreshedPlayer shows the rank of a player, with the move since the previous update when it changed
+func formatRefreshedPlayer(player *models.Player, change *models.RankChange) string {
+	summary := models.NewPlayerSummary(player)
+
+	rankInfo := "🆕 **Unranked**"
+	if summary.IsRanked() {
+		rankInfo = fmt.Sprintf("🏆 **%s** • %d LP", summary.RankLabel(), summary.LeaguePoints)
+	}
+
+	response := fmt.Sprintf("🔄 **%s** refreshed\n%s", summary.DisplayName(), rankInfo)
+	switch {
+	case change == nil:
+		response += "\n➖ No change since the last update"
+	case change.IsPromotion():
+		response += fmt.Sprintf("\n🎉 Promoted from %s (%d LP)", models.FormatRank(change.PreviousTier, change.PreviousRank), change.PreviousLeaguePoints)
+	case change.IsDemotion():
+		response += fmt.Sprintf("\n📉 Demoted from %s (%d LP)", models.FormatRank(change.PreviousTier, change.PreviousRank), change.PreviousLeaguePoints)
+	case change.LPDelta() < 0:
+		response += fmt.Sprintf("\n📉 %+d LP since the last update", change.LPDelta())
+	default:
+		response += fmt.Sprintf("\n📈 %+d LP since the last update", change.LPDelta())
+	}
+	return response
+}
@@ -0,0 +1,59 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleRolesAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	pseudo, tagline, server := playerIdentity(optionsByName(i.ApplicationCommandData().Options))
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	stats, err := h.playerService.GetRoleStats(ctx, player)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch role statistics: %v", err))
+		log.Printf("Error fetching role stats of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	h.sendFollowUp(s, i, formatRoleStats(player, stats, interactionFormatter(i)))
+}
+
+func formatRoleStats(player *models.Player, stats []*models.RoleStats, f models.Formatter) string {
+	if len(stats) == 0 {
+		return fmt.Sprintf("📭 No tracked games for **%s#%s** yet!", player.GameName, player.TagLine)
+	}
+
+	total := 0
+	for _, stat := range stats {
+		total += stat.Games
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("🗺️ **Roles of %s#%s** (%d tracked games)\n\n", player.GameName, player.TagLine, total))
+	for _, stat := range stats {
+		response.WriteString(fmt.Sprintf("%s **%s** • %d games (%s) • %s win rate\n",
+			models.RoleEmoji(stat.Role), models.RoleName(stat.Role),
+			stat.Games, f.Percent(float64(stat.Games)/float64(total)*100, 0), f.Percent(stat.WinRate(), 1)))
+	}
+
+	return response.String()
+}
@@ -0,0 +1,91 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SessionSummaries posts a summary embed when a tracked player ends a play session
+type SessionSummaries struct {
+	session            *discordgo.Session
+	guildConfigService *services.GuildConfigService
+}
+
+func NewSessionSummaries(s *discordgo.Session, guildConfigService *services.GuildConfigService) *SessionSummaries {
+	return &SessionSummaries{
+		session:            s,
+		guildConfigService: guildConfigService,
+	}
+}
+
+// Post sends the summaries to the notification channel of every guild, except the compact ones
+func (ss *SessionSummaries) Post(ctx context.Context, summaries []*models.SessionSummary) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	configs, err := ss.guildConfigService.GetNotificationConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch guild configs: %w", err)
+	}
+
+	for _, config := range configs {
+		if config.IsCompact() {
+			continue
+		}
+
+		f := models.NewFormatter(config.Language)
+		for _, summary := range summaries {
+			if config.IsMuted(summary.Player.PUUID) {
+				continue
+			}
+
+			_, err := ss.session.ChannelMessageSendEmbed(config.NotificationChannelID, sessionSummaryEmbed(summary, f))
+			if err != nil {
+				log.Printf("Error posting session summary of %s#%s in guild %s: %v",
+					summary.Player.GameName, summary.Player.TagLine, config.GuildID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func sessionSummaryEmbed(summary *models.SessionSummary, f models.Formatter) *discordgo.MessageEmbed {
+	session := summary.Session
+	color := 0x99AAB5
+	if summary.LPDelta > 0 {
+		color = 0x57F287
+	} else if summary.LPDelta < 0 {
+		color = 0xED4245
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🎮 Session of %s", summary.Player.DisplayName()),
+		Description: fmt.Sprintf("**%s LP** over %d games", f.Signed(float64(summary.LPDelta), 0), len(session.Matches)),
+		Color:       color,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Record", Value: fmt.Sprintf("%dW %dL", session.Wins(), session.Losses()), Inline: true},
+			{Name: "Duration", Value: fmt.Sprintf("<t:%d:t> → <t:%d:t>", session.Start().Unix(), session.End().Unix()), Inline: true},
+		},
+	}
+
+	if best := summary.BestGame; best != nil {
+		result := "Loss"
+		if best.Victory {
+			result = "Win"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Best game",
+			Value: fmt.Sprintf("%s • %s %s (%s KDA)", best.Champion, result, best.KDAString(), f.Number(best.KDA(), 2)),
+		})
+	}
+
+	return embed
+}
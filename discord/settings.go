@@ -0,0 +1,219 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Custom IDs of the settings panel components
+const (
+	settingsComponentPrefix = "settings:"
+
+	settingsChannelID   = settingsComponentPrefix + "channel"
+	settingsLanguageID  = settingsComponentPrefix + "language"
+	settingsTimezoneID  = settingsComponentPrefix + "timezone"
+	settingsThresholdID = settingsComponentPrefix + "threshold"
+	settingsFullID      = settingsComponentPrefix + "verbosity_full"
+	settingsCompactID   = settingsComponentPrefix + "verbosity_compact"
+)
+
+// Time zones offered by the settings panel (select menus are limited to 25 options),
+// any other IANA zone can be set with /set_timezone
+var settingsTimezones = []string{
+	"UTC", "Europe/London", "Europe/Paris", "Europe/Berlin", "Europe/Madrid", "Europe/Rome",
+	"Europe/Warsaw", "Europe/Athens", "Europe/Istanbul", "Europe/Moscow", "America/New_York",
+	"America/Chicago", "America/Denver", "America/Los_Angeles", "America/Mexico_City", "America/Sao_Paulo",
+	"America/Argentina/Buenos_Aires", "Asia/Seoul", "Asia/Tokyo", "Asia/Shanghai", "Asia/Singapore",
+	"Asia/Manila", "Asia/Ho_Chi_Minh", "Australia/Sydney", "Pacific/Auckland",
+}
+
+// LP thresholds offered by the settings panel
+var settingsThresholds = []int{0, 5, 10, 15, 20, 25, 30, 40, 50}
+
+func (h *CommandHandler) handleSettingsAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	config, err := h.guildConfigService.GetConfig(ctx, i.GuildID)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch server settings: %v", err))
+		log.Printf("Error fetching guild config %s: %v", i.GuildID, err)
+		return
+	}
+
+	h.editSettingsPanel(s, i, config, "⚙️ **Server settings**")
+}
+
+func (h *CommandHandler) handleSettingsComponentAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.MessageComponentData()
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	config, err := h.guildConfigService.UpdateConfig(ctx, i.GuildID, func(config *models.GuildConfig) error {
+		return applySettingsComponent(config, data)
+	})
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to save server settings: %v", err))
+		log.Printf("Error saving guild config %s: %v", i.GuildID, err)
+		return
+	}
+
+	h.editSettingsPanel(s, i, config, "✅ **Server settings updated**")
+}
+
+// applySettingsComponent updates the setting matching the component used in the panel
+func applySettingsComponent(config *models.GuildConfig, data discordgo.MessageComponentInteractionData) error {
+	value := ""
+	if len(data.Values) > 0 {
+		value = data.Values[0]
+	}
+
+	switch data.CustomID {
+	case settingsChannelID:
+		config.NotificationChannelID = value
+	case settingsLanguageID:
+		if value == languageOff {
+			value = ""
+		} else if !services.IsSupportedLanguage(value) {
+			return fmt.Errorf("unsupported language %s", value)
+		}
+		config.Language = value
+	case settingsTimezoneID:
+		if _, err := time.LoadLocation(value); err != nil {
+			return fmt.Errorf("unknown time zone %s", value)
+		}
+		config.Timezone = value
+	case settingsThresholdID:
+		threshold, err := strconv.Atoi(value)
+		if err != nil || threshold < 0 {
+			return fmt.Errorf("invalid threshold %s", value)
+		}
+		config.MinLPDelta = threshold
+	case settingsFullID:
+		config.Verbosity = models.VerbosityFull
+	case settingsCompactID:
+		config.Verbosity = models.VerbosityCompact
+	default:
+		return fmt.Errorf("unknown setting %s", data.CustomID)
+	}
+
+	return nil
+}
+
+func (h *CommandHandler) editSettingsPanel(s *discordgo.Session, i *discordgo.InteractionCreate, config *models.GuildConfig, title string) {
+	embeds := textEmbeds(formatSettingsPanel(config, title))
+	components := settingsComponents(config)
+
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &embeds,
+		Components: &components,
+	})
+	if err != nil {
+		log.Printf("Error editing settings panel: %v", err)
+	}
+}
+
+func formatSettingsPanel(config *models.GuildConfig, title string) string {
+	channel := "not set"
+	if config.NotificationChannelID != "" {
+		channel = fmt.Sprintf("<#%s>", config.NotificationChannelID)
+	}
+
+	verbosity := models.VerbosityFull
+	if config.IsCompact() {
+		verbosity = models.VerbosityCompact
+	}
+
+	language := "off"
+	if config.Language != "" {
+		language = config.Language
+	}
+
+	threshold := "every LP change"
+	if config.MinLPDelta > 0 {
+		threshold = fmt.Sprintf("swings of %d LP or more", config.MinLPDelta)
+	}
+
+	return fmt.Sprintf("%s\n📢 **Channel:** %s\n📝 **Verbosity:** %s\n🌐 **Language:** %s\n🕒 **Time zone:** %s\n📏 **Threshold:** %s",
+		title, channel, verbosity, language, config.Location().String(), threshold)
+}
+
+func settingsComponents(config *models.GuildConfig) []discordgo.MessageComponent {
+	var channelDefaults []discordgo.SelectMenuDefaultValue
+	if config.NotificationChannelID != "" {
+		channelDefaults = append(channelDefaults, discordgo.SelectMenuDefaultValue{
+			ID:   config.NotificationChannelID,
+			Type: discordgo.SelectMenuDefaultValueChannel,
+		})
+	}
+
+	languageOptions := []discordgo.SelectMenuOption{
+		{Label: "Off", Value: languageOff, Default: config.Language == ""},
+	}
+	for _, language := range services.SupportedLanguages {
+		languageOptions = append(languageOptions, discordgo.SelectMenuOption{
+			Label: language, Value: language, Default: config.Language == language,
+		})
+	}
+
+	timezoneOptions := make([]discordgo.SelectMenuOption, 0, len(settingsTimezones))
+	for _, timezone := range settingsTimezones {
+		timezoneOptions = append(timezoneOptions, discordgo.SelectMenuOption{
+			Label: timezone, Value: timezone, Default: config.Location().String() == timezone,
+		})
+	}
+
+	thresholdOptions := make([]discordgo.SelectMenuOption, 0, len(settingsThresholds))
+	for _, threshold := range settingsThresholds {
+		label := fmt.Sprintf("%d LP or more", threshold)
+		if threshold == 0 {
+			label = "Every LP change"
+		}
+		thresholdOptions = append(thresholdOptions, discordgo.SelectMenuOption{
+			Label: label, Value: strconv.Itoa(threshold), Default: config.MinLPDelta == threshold,
+		})
+	}
+
+	fullStyle, compactStyle := discordgo.PrimaryButton, discordgo.SecondaryButton
+	if config.IsCompact() {
+		fullStyle, compactStyle = discordgo.SecondaryButton, discordgo.PrimaryButton
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				MenuType:      discordgo.ChannelSelectMenu,
+				CustomID:      settingsChannelID,
+				Placeholder:   "Notification channel",
+				ChannelTypes:  []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+				DefaultValues: channelDefaults,
+			},
+		}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{CustomID: settingsLanguageID, Placeholder: "Champion names language", Options: languageOptions},
+		}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{CustomID: settingsTimezoneID, Placeholder: "Recap time zone", Options: timezoneOptions},
+		}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{CustomID: settingsThresholdID, Placeholder: "Minimum LP swing", Options: thresholdOptions},
+		}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{CustomID: settingsFullID, Label: "Full notifications", Style: fullStyle},
+			discordgo.Button{CustomID: settingsCompactID, Label: "Compact notifications", Style: compactStyle},
+		}},
+	}
+}
@@ -0,0 +1,133 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleSnapshotAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	opt, ok := optionsByName(i.ApplicationCommandData().Options)["name"]
+	if !ok {
+		h.sendSnapshotsList(ctx, s, i)
+		return
+	}
+
+	snapshot, err := h.standingsService.TakeSnapshot(ctx, i.GuildID, opt.StringValue(), interactionUserID(i))
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to take snapshot: %v", err))
+		log.Printf("Error taking snapshot: %v", err)
+		return
+	}
+
+	h.sendFollowUp(s, i, fmt.Sprintf("📸 Snapshot **%s** saved with %d players\nUse `/snapshot_compare %s` to compare the standings later.",
+		snapshot.Name, len(snapshot.Entries), snapshot.Name))
+}
+
+func (h *CommandHandler) sendSnapshotsList(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	snapshots, err := h.standingsService.GetSnapshots(ctx, i.GuildID)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch snapshots: %v", err))
+		log.Printf("Error fetching snapshots: %v", err)
+		return
+	}
+
+	if len(snapshots) == 0 {
+		h.sendFollowUp(s, i, "📭 No snapshots yet!\nUse `/snapshot <name>` to freeze the current leaderboard.")
+		return
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("📸 **Snapshots (%d)**\n\n", len(snapshots)))
+	for idx, snapshot := range snapshots {
+		if idx >= 20 {
+			response.WriteString(fmt.Sprintf("... and %d more snapshots\n", len(snapshots)-20))
+			break
+		}
+		response.WriteString(fmt.Sprintf("• **%s** (<t:%d:f>)\n", snapshot.Name, snapshot.CreatedAt.Unix()))
+	}
+
+	h.sendFollowUp(s, i, response.String())
+}
+
+func (h *CommandHandler) handleSnapshotCompareAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		h.sendFollowUp(s, i, "❌ This command can only be used in a server")
+		return
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	name := i.ApplicationCommandData().Options[0].StringValue()
+	snapshot, diffs, err := h.standingsService.CompareSnapshot(ctx, i.GuildID, name)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to compare with snapshot: %v", err))
+		log.Printf("Error comparing snapshot %s: %v", name, err)
+		return
+	}
+
+	h.sendFollowUp(s, i, formatSnapshotComparison(snapshot, diffs))
+}
+
+func formatSnapshotComparison(snapshot *models.LeaderboardSnapshot, diffs []*models.SnapshotDiff) string {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("📸 **Standings since %s** (<t:%d:R>)\n\n", snapshot.Name, snapshot.CreatedAt.Unix()))
+
+	for idx, diff := range diffs {
+		if idx >= 20 {
+			response.WriteString(fmt.Sprintf("... and %d more players\n", len(diffs)-20))
+			break
+		}
+
+		var movement string
+		switch {
+		case diff.IsNew():
+			movement = "🆕"
+		case diff.PositionChange() > 0:
+			movement = fmt.Sprintf("▲%d", diff.PositionChange())
+		case diff.PositionChange() < 0:
+			movement = fmt.Sprintf("▼%d", -diff.PositionChange())
+		default:
+			movement = "＝"
+		}
+
+		player := diff.Player
+		rankInfo := "Unranked"
+		if player.Tier != "UNRANKED" {
+			rankInfo = fmt.Sprintf("%s %s %d LP", player.Tier, player.Rank, player.LeaguePoints)
+		}
+
+		response.WriteString(fmt.Sprintf("**%d.** %s **%s#%s** • %s", diff.Position, movement, player.GameName, player.TagLine, rankInfo))
+		if !diff.IsNew() {
+			response.WriteString(fmt.Sprintf(" (%+d LP, %d games)", diff.LPDelta, diff.GamesPlayed))
+		}
+		response.WriteString("\n")
+	}
+
+	return response.String()
+}
+
+// interactionUserID returns the ID of the user who triggered the interaction (guild or DM)
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleTeamStandingsAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	standings, err := h.standingsService.GetTeamStandings(ctx)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to compute team standings: %v", err))
+		log.Printf("Error computing team standings: %v", err)
+		return
+	}
+
+	if len(standings) == 0 {
+		h.sendFollowUp(s, i, "📭 No tagged players yet!\nUse `/tag_player` to group players into teams.")
+		return
+	}
+
+	h.sendFollowUpEmbed(s, i, buildTeamStandingsEmbed(standings, interactionFormatter(i)))
+}
+
+func buildTeamStandingsEmbed(standings []*models.TeamStanding, f models.Formatter) *discordgo.MessageEmbed {
+	medals := []string{"🥇", "🥈", "🥉"}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🏟️ Team Standings",
+		Description: "Teams are ranked by the average rank of their ranked players",
+		Color:       colorDefault,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	for idx, standing := range standings {
+		if idx >= maxEmbedFields {
+			embed.Footer = &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("... and %d more teams", len(standings)-maxEmbedFields),
+			}
+			break
+		}
+
+		position := fmt.Sprintf("#%d", idx+1)
+		if idx < len(medals) {
+			position = medals[idx]
+		}
+
+		var value strings.Builder
+		value.WriteString(fmt.Sprintf("🏆 **Avg rank:** %s\n", standing.AverageRankString()))
+		value.WriteString(fmt.Sprintf("📈 **LP (7d):** %s\n", f.Signed(float64(standing.WeeklyLPDelta), 0)))
+		value.WriteString(fmt.Sprintf("⚔️ **Win rate:** %s (%dW %dL)\n", f.Percent(standing.WinRate(), 1), standing.Wins, standing.Losses))
+		value.WriteString(fmt.Sprintf("👥 **Players:** %d", standing.Players))
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("%s %s", position, standing.Tag),
+			Value:  value.String(),
+			Inline: true,
+		})
+	}
+
+	return embed
+}
@@ -0,0 +1,42 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleTagPlayerAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := optionsByName(i.ApplicationCommandData().Options)
+	pseudo, tagline, server := playerIdentity(options)
+	tag := options["tag"].StringValue()
+	remove := false
+	if opt, ok := options["remove"]; ok {
+		remove = opt.BoolValue()
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.SetPlayerTag(ctx, pseudo, tagline, server, tag, remove)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to update tags of **%s#%s**\n\n**Error:** %v", pseudo, tagline, err))
+		log.Printf("Error tagging player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	tags := "none"
+	if len(player.Tags) > 0 {
+		tags = strings.Join(player.Tags, ", ")
+	}
+
+	action := "added to"
+	if remove {
+		action = "removed from"
+	}
+
+	h.sendFollowUp(s, i, fmt.Sprintf("✅ Tag **%s** %s **%s#%s** (%s)\n🏷️ **Tags:** %s",
+		strings.ToLower(strings.TrimSpace(tag)), action, player.GameName, player.TagLine, strings.ToUpper(player.Server), tags))
+}
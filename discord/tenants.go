@@ -0,0 +1,72 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// TenantRouter dispatches the Discord events of a shared instance to the handlers of the tenant owning the guild,
+// each tenant having its own dataset. The guilds without tenant and the DMs use the default handlers.
+type TenantRouter struct {
+	fallback *tenantHandlers
+	byGuild  map[string]*tenantHandlers
+	tenants  []*tenantHandlers
+}
+
+type tenantHandlers struct {
+	commands  *CommandHandler
+	guildSync *GuildSync
+}
+
+func NewTenantRouter(commands *CommandHandler, guildSync *GuildSync) *TenantRouter {
+	return &TenantRouter{
+		fallback: &tenantHandlers{commands: commands, guildSync: guildSync},
+		byGuild:  make(map[string]*tenantHandlers),
+	}
+}
+
+// AddTenant routes the events of the guilds of a tenant to its handlers
+func (r *TenantRouter) AddTenant(guildIDs []string, commands *CommandHandler, guildSync *GuildSync) {
+	tenant := &tenantHandlers{commands: commands, guildSync: guildSync}
+	for _, guildID := range guildIDs {
+		r.byGuild[guildID] = tenant
+	}
+	r.tenants = append(r.tenants, tenant)
+}
+
+func (r *TenantRouter) forGuild(guildID string) *tenantHandlers {
+	if tenant, ok := r.byGuild[guildID]; ok {
+		return tenant
+	}
+	return r.fallback
+}
+
+func (r *TenantRouter) HandleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	r.forGuild(i.GuildID).commands.HandleInteraction(s, i)
+}
+
+// HandleReady reconciles the guild configurations of every dataset with the guilds it owns
+func (r *TenantRouter) HandleReady(s *discordgo.Session, ready *discordgo.Ready) {
+	var defaultGuilds []*discordgo.Guild
+	tenantGuilds := make(map[*tenantHandlers][]*discordgo.Guild, len(r.tenants))
+	for _, guild := range ready.Guilds {
+		tenant := r.forGuild(guild.ID)
+		if tenant == r.fallback {
+			defaultGuilds = append(defaultGuilds, guild)
+			continue
+		}
+		tenantGuilds[tenant] = append(tenantGuilds[tenant], guild)
+	}
+
+	r.fallback.guildSync.HandleReady(s, &discordgo.Ready{Guilds: defaultGuilds})
+	for _, tenant := range r.tenants {
+		tenant.guildSync.HandleReady(s, &discordgo.Ready{Guilds: tenantGuilds[tenant]})
+	}
+}
+
+func (r *TenantRouter) HandleGuildCreate(s *discordgo.Session, g *discordgo.GuildCreate) {
+	r.forGuild(g.ID).guildSync.HandleGuildCreate(s, g)
+}
+
+func (r *TenantRouter) HandleGuildDelete(s *discordgo.Session, g *discordgo.GuildDelete) {
+	r.forGuild(g.ID).guildSync.HandleGuildDelete(s, g)
+}
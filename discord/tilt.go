@@ -0,0 +1,56 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleTiltAlertsAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := optionsByName(i.ApplicationCommandData().Options)
+	pseudo, tagline, server := playerIdentity(options)
+	enabled := options["enabled"].BoolValue()
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+		log.Printf("Error fetching player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+	if player == nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+		return
+	}
+
+	// The player decides, through their linked account, server admins can also do it for them
+	if !isServerAdmin(i) {
+		owner, err := h.verificationService.IsOwner(ctx, interactionUserID(i), player.PUUID)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to check the owner of the account: %v", err))
+			log.Printf("Error checking owner of %s#%s: %v", pseudo, tagline, err)
+			return
+		}
+		if !owner {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Only the owner of **%s#%s** (linked with `/link`) or a server admin can change their tilt alerts", player.GameName, player.TagLine))
+			return
+		}
+	}
+
+	player, err = h.tiltService.SetTiltAlerts(ctx, pseudo, tagline, server, enabled)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to update the tilt alerts: %v", err))
+		log.Printf("Error updating tilt alerts of %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	if enabled {
+		h.sendFollowUp(s, i, fmt.Sprintf("🍵 Tilt alerts enabled for **%s#%s** (on the servers with the `tilt_alerts` feature)", player.GameName, player.TagLine))
+	} else {
+		h.sendFollowUp(s, i, fmt.Sprintf("🔕 No more tilt alerts for **%s#%s**", player.GameName, player.TagLine))
+	}
+}
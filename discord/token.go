@@ -0,0 +1,24 @@
+package discord
+
+import (
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// RotateToken replaces the bot token of a session (ex: a token rotated in Vault). REST calls use the
+// new token right away, a connected gateway is reopened to identify with it.
+func RotateToken(s *discordgo.Session, token string, gateway bool) error {
+	s.Token = "Bot " + token
+	s.Identify.Token = s.Token
+	if !gateway {
+		return nil
+	}
+
+	log.Println("🔄 Reconnecting to Discord with the new token...")
+	err := s.Close()
+	if err != nil {
+		return err
+	}
+	return s.Open()
+}
@@ -0,0 +1,64 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"lp_tracker/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *CommandHandler) handleTwitchAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := optionsByName(i.ApplicationCommandData().Options)
+	pseudo, tagline, server := playerIdentity(options)
+
+	var channel *string
+	if opt, ok := options["channel"]; ok {
+		value := opt.StringValue()
+		channel = &value
+	}
+	if opt, ok := options["clear"]; ok && opt.BoolValue() {
+		empty := ""
+		channel = &empty
+	}
+
+	ctx, cancel := h.commandContext(i)
+	defer cancel()
+
+	if channel == nil {
+		player, err := h.playerService.GetPlayerByRiotID(ctx, pseudo, tagline, server)
+		if err != nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to fetch player: %v", err))
+			return
+		}
+		if player == nil {
+			h.sendFollowUp(s, i, fmt.Sprintf("❌ Player **%s#%s** (%s) is not tracked", pseudo, tagline, strings.ToUpper(server)))
+			return
+		}
+		h.sendFollowUp(s, i, formatTwitchChannel(player))
+		return
+	}
+
+	player, err := h.playerService.SetTwitchChannel(ctx, pseudo, tagline, server, *channel)
+	if err != nil {
+		h.sendFollowUp(s, i, fmt.Sprintf("❌ Failed to update **%s#%s**\n\n**Error:** %v", pseudo, tagline, err))
+		log.Printf("Error updating Twitch channel of player %s#%s: %v", pseudo, tagline, err)
+		return
+	}
+
+	h.sendFollowUp(s, i, formatTwitchChannel(player))
+}
+
+func formatTwitchChannel(player *models.Player) string {
+	if player.TwitchChannel == "" {
+		return fmt.Sprintf("📺 **%s#%s** has no Twitch channel", player.GameName, player.TagLine)
+	}
+
+	status := "offline"
+	if player.TwitchLive {
+		status = "🔴 live"
+	}
+	return fmt.Sprintf("📺 **%s#%s** streams on <%s> (%s)", player.GameName, player.TagLine, models.TwitchChannelURL(player.TwitchChannel), status)
+}
@@ -0,0 +1,122 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+)
+
+// TwitchWatcher announces in the notification channels when a tracked player starts streaming.
+// The stream state is stored on the players so a restart doesn't announce the ongoing streams again.
+type TwitchWatcher struct {
+	dispatcher         *Dispatcher
+	guildConfigService *services.GuildConfigService
+	playerService      *services.PlayerService
+	twitch             *services.TwitchService
+	interval           time.Duration
+}
+
+func NewTwitchWatcher(dispatcher *Dispatcher, guildConfigService *services.GuildConfigService, playerService *services.PlayerService, twitch *services.TwitchService, interval time.Duration) *TwitchWatcher {
+	return &TwitchWatcher{
+		dispatcher:         dispatcher,
+		guildConfigService: guildConfigService,
+		playerService:      playerService,
+		twitch:             twitch,
+		interval:           interval,
+	}
+}
+
+// Run checks the streams every interval until the context is cancelled
+func (t *TwitchWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		err := t.Check(ctx)
+		if err != nil {
+			log.Printf("Error checking Twitch streams: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check announces the players who went live since the last check
+func (t *TwitchWatcher) Check(ctx context.Context) error {
+	players, err := t.playerService.GetTwitchPlayers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch players: %w", err)
+	}
+	if len(players) == 0 {
+		return nil
+	}
+
+	logins := make([]string, 0, len(players))
+	for _, player := range players {
+		logins = append(logins, player.TwitchChannel)
+	}
+	streams, err := t.twitch.GetLiveStreams(ctx, logins)
+	if err != nil {
+		return fmt.Errorf("failed to fetch streams: %w", err)
+	}
+
+	var live []*models.Player
+	for _, player := range players {
+		stream, isLive := streams[player.TwitchChannel]
+		if isLive == player.TwitchLive {
+			continue
+		}
+
+		err := t.playerService.SetTwitchLive(ctx, player, isLive)
+		if err != nil {
+			log.Printf("Error recording stream state of %s#%s: %v", player.GameName, player.TagLine, err)
+			continue
+		}
+		if isLive {
+			live = append(live, player)
+			log.Printf("🔴 %s#%s is live on %s (%s)", player.GameName, player.TagLine, player.TwitchChannel, stream.GameName)
+		}
+	}
+	if len(live) == 0 {
+		return nil
+	}
+
+	configs, err := t.guildConfigService.GetNotificationConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch guild configs: %w", err)
+	}
+
+	for _, player := range live {
+		message := formatLiveNotification(player, streams[player.TwitchChannel])
+		for _, config := range configs {
+			if config.IsMuted(player.PUUID) {
+				continue
+			}
+			t.dispatcher.Enqueue(config.GuildID, config.NotificationChannelID, message)
+		}
+	}
+
+	return nil
+}
+
+func formatLiveNotification(player *models.Player, stream *services.TwitchStreamDTO) string {
+	rankInfo := "unranked"
+	if player.Tier != "" && player.Tier != "UNRANKED" {
+		rankInfo = fmt.Sprintf("currently **%s** (%d LP)", models.FormatRank(player.Tier, player.Rank), player.LeaguePoints)
+	}
+
+	message := fmt.Sprintf("🔴 **%s** is live and %s!", player.DisplayName(), rankInfo)
+	if title := strings.TrimSpace(stream.Title); title != "" {
+		message += fmt.Sprintf("\n> %s", title)
+	}
+	return message + "\n" + models.TwitchChannelURL(player.TwitchChannel)
+}
@@ -0,0 +1,39 @@
+package discord
+
+import (
+	"fmt"
+	"time"
+
+	"lp_tracker/version"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Commits link to the repository when the build is a known commit
+const repositoryURL = "https://github.com/Nitale/lp_tracker"
+
+func (h *CommandHandler) handleVersionAsync(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	h.sendFollowUpEmbed(s, i, buildVersionEmbed(version.Get(), h.startedAt))
+}
+
+func buildVersionEmbed(info version.Info, startedAt time.Time) *discordgo.MessageEmbed {
+	commit := fmt.Sprintf("`%s`", info.ShortCommit())
+	if info.Commit != "unknown" {
+		commit = fmt.Sprintf("[`%s`](%s/commit/%s)", info.ShortCommit(), repositoryURL, info.Commit)
+	}
+	if info.Modified {
+		commit += " (uncommitted changes)"
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: "🏷️ lp_tracker " + info.Version,
+		Color: colorDefault,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Commit", Value: commit, Inline: true},
+			{Name: "Built", Value: info.BuildDate, Inline: true},
+			{Name: "Go", Value: info.GoVersion, Inline: true},
+			{Name: "Up since", Value: fmt.Sprintf("<t:%d:R>", startedAt.Unix()), Inline: true},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,186 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// DefaultInterval is the delay between two exports
+const DefaultInterval = 15 * time.Minute
+
+// LP events exported per player history
+const historySize = 200
+
+// Config holds the settings of the S3-compatible bucket (AWS S3, GCS interoperability, MinIO...)
+type Config struct {
+	Endpoint  string // ex: s3.amazonaws.com, storage.googleapis.com
+	Bucket    string
+	Prefix    string // Prepended to the object keys (ex: "lp_tracker/")
+	Region    string
+	AccessKey string
+	SecretKey string
+	Insecure  bool // Plain HTTP, for local MinIO
+}
+
+// ConfigFromEnv reads the bucket settings from the EXPORT_S3_* environment variables
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		Endpoint:  os.Getenv("EXPORT_S3_ENDPOINT"),
+		Bucket:    os.Getenv("EXPORT_S3_BUCKET"),
+		Prefix:    os.Getenv("EXPORT_S3_PREFIX"),
+		Region:    os.Getenv("EXPORT_S3_REGION"),
+		AccessKey: os.Getenv("EXPORT_S3_ACCESS_KEY"),
+		SecretKey: os.Getenv("EXPORT_S3_SECRET_KEY"),
+	}
+
+	if value := os.Getenv("EXPORT_S3_INSECURE"); value != "" {
+		insecure, err := strconv.ParseBool(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid EXPORT_S3_INSECURE: %q is not a valid boolean", value)
+		}
+		config.Insecure = insecure
+	}
+	return config, nil
+}
+
+// Enabled checks if a bucket is configured, the export is disabled otherwise
+func (c Config) Enabled() bool {
+	return c.Endpoint != "" && c.Bucket != ""
+}
+
+// Exporter periodically writes the leaderboard and the LP history of the players as JSON objects,
+// so that static sites can be built from the bucket without exposing the bot
+type Exporter struct {
+	client        *minio.Client
+	config        Config
+	interval      time.Duration
+	playerService *services.PlayerService
+	standings     *services.StandingsService
+}
+
+// LeaderboardExport is the content of leaderboard.json
+type LeaderboardExport struct {
+	GeneratedAt time.Time              `json:"generatedAt"`
+	Players     []LeaderboardExportRow `json:"players"`
+}
+
+// LeaderboardExportRow is a player of leaderboard.json
+type LeaderboardExportRow struct {
+	Position int `json:"position"`
+	models.PlayerDetail
+	HistoryKey string `json:"historyKey"` // Object key of the player history
+}
+
+// HistoryExport is the content of players/<puuid>.json
+type HistoryExport struct {
+	GeneratedAt time.Time            `json:"generatedAt"`
+	Player      models.PlayerSummary `json:"player"`
+	Events      []*models.LPEvent    `json:"events"` // Newest first
+}
+
+func NewExporter(config Config, interval time.Duration, playerService *services.PlayerService, standings *services.StandingsService) (*Exporter, error) {
+	client, err := minio.New(config.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.AccessKey, config.SecretKey, ""),
+		Secure: !config.Insecure,
+		Region: config.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	return &Exporter{
+		client:        client,
+		config:        config,
+		interval:      interval,
+		playerService: playerService,
+		standings:     standings,
+	}, nil
+}
+
+// Run exports the snapshots until the context is cancelled
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		objects, err := e.Export(ctx)
+		if err != nil {
+			log.Printf("Error exporting snapshots to bucket %s: %v", e.config.Bucket, err)
+		} else {
+			log.Printf("📤 Exported %d snapshots to bucket %s in %v", objects, e.config.Bucket, time.Since(start))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Export writes leaderboard.json and the history of every player, it returns the number of objects written
+func (e *Exporter) Export(ctx context.Context) (int, error) {
+	players, err := e.standings.GetLeaderboard(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch leaderboard: %w", err)
+	}
+
+	now := time.Now().UTC()
+	leaderboard := LeaderboardExport{GeneratedAt: now, Players: make([]LeaderboardExportRow, 0, len(players))}
+	written := 0
+	for idx, player := range players {
+		historyKey := path.Join("players", player.PUUID+".json")
+		leaderboard.Players = append(leaderboard.Players, LeaderboardExportRow{
+			Position:     idx + 1,
+			PlayerDetail: models.NewPlayerDetail(player),
+			HistoryKey:   historyKey,
+		})
+
+		events, err := e.playerService.GetLPHistory(ctx, player, historySize)
+		if err != nil {
+			return written, fmt.Errorf("failed to fetch LP history of %s#%s: %w", player.GameName, player.TagLine, err)
+		}
+		err = e.putJSON(ctx, historyKey, HistoryExport{GeneratedAt: now, Player: models.NewPlayerSummary(player), Events: events})
+		if err != nil {
+			return written, err
+		}
+		written++
+	}
+
+	// Written last, so the histories it references already exist
+	err = e.putJSON(ctx, "leaderboard.json", leaderboard)
+	if err != nil {
+		return written, err
+	}
+	return written + 1, nil
+}
+
+func (e *Exporter) putJSON(ctx context.Context, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+
+	_, err = e.client.PutObject(ctx, e.config.Bucket, e.config.Prefix+key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType:  "application/json",
+		CacheControl: "max-age=60",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Achievement IDs
+const (
+	AchievementFirstPentakill = "first_pentakill"
+	AchievementWinStreak10    = "win_streak_10"
+	AchievementReachedDiamond = "reached_diamond"
+	AchievementGamesTracked   = "games_tracked_100"
+)
+
+// Achievement describes a badge players can earn
+type Achievement struct {
+	ID          string
+	Name        string
+	Emoji       string
+	Description string
+}
+
+// Achievements lists every badge that can be earned, in display order
+var Achievements = []Achievement{
+	{ID: AchievementFirstPentakill, Name: "Pentakill!", Emoji: "⚔️", Description: "First Pentakill recorded in a tracked game"},
+	{ID: AchievementWinStreak10, Name: "Unstoppable", Emoji: "🔥", Description: "Won 10 tracked games in a row"},
+	{ID: AchievementReachedDiamond, Name: "Shine bright", Emoji: "💎", Description: "Reached Diamond or higher"},
+	{ID: AchievementGamesTracked, Name: "Dedicated", Emoji: "🎖️", Description: "100 ranked games tracked"},
+}
+
+// FindAchievement returns the achievement with the given ID, nil if unknown
+func FindAchievement(id string) *Achievement {
+	for idx := range Achievements {
+		if Achievements[idx].ID == id {
+			return &Achievements[idx]
+		}
+	}
+	return nil
+}
+
+// PlayerAchievement is a badge earned by a player
+type PlayerAchievement struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PlayerPUUID   string             `bson:"playerPuuid" json:"playerPuuid"`
+	AchievementID string             `bson:"achievementId" json:"achievementId"`
+	MatchID       string             `bson:"matchId,omitempty" json:"matchId,omitempty"` // Game that unlocked the badge, when known
+	EarnedAt      time.Time          `bson:"earnedAt" json:"earnedAt"`
+}
+
+// AchievementUnlock is a badge newly earned by a player, to be announced
+type AchievementUnlock struct {
+	Player      *Player
+	Achievement *Achievement
+}
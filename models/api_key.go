@@ -0,0 +1,98 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyScope is what a key can do on the REST API, each scope includes the previous ones:
+// read < refresh (trigger player updates, ex: a stream overlay) < admin
+type APIKeyScope string
+
+const (
+	APIKeyScopeRead    APIKeyScope = "read"
+	APIKeyScopeRefresh APIKeyScope = "refresh"
+	APIKeyScopeAdmin   APIKeyScope = "admin"
+)
+
+// Rank of the scopes, a key allows the scopes up to its own
+var apiKeyScopeLevels = map[APIKeyScope]int{
+	APIKeyScopeRead:    1,
+	APIKeyScopeRefresh: 2,
+	APIKeyScopeAdmin:   3,
+}
+
+const (
+	// Keys are recognizable in logs and secret scanners by their prefix
+	apiKeyPrefix = "lpt_"
+
+	// Characters of the key kept in clear to tell the keys apart
+	apiKeyVisibleLength = len(apiKeyPrefix) + 8
+
+	// DefaultAPIKeyRateLimit is the requests per minute allowed when the key is created without limit
+	DefaultAPIKeyRateLimit = 60
+)
+
+// APIKey authenticates a client of the REST API. Only the SHA-256 of the key is stored:
+// the key is shown once on creation and can't be recovered, a lost key is revoked and created again.
+// A fast hash is enough since the keys are random and not guessable like passwords.
+type APIKey struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name       string             `bson:"name" json:"name"`
+	Prefix     string             `bson:"prefix" json:"prefix"` // Beginning of the key (ex: "lpt_1a2b3c4d")
+	Hash       string             `bson:"hash" json:"-"`
+	Scope      APIKeyScope        `bson:"scope" json:"scope"`
+	RateLimit  int                `bson:"rateLimit" json:"rateLimit"` // Requests per minute
+	CreatedBy  string             `bson:"createdBy" json:"createdBy"` // Discord user ID
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	LastUsedAt *time.Time         `bson:"lastUsedAt,omitempty" json:"lastUsedAt,omitempty"`
+}
+
+// NewAPIKey generates a key, the clear key is returned next to the stored one
+func NewAPIKey(name string, scope APIKeyScope, rateLimit int, createdBy string, now time.Time) (*APIKey, string, error) {
+	if !IsAPIKeyScope(scope) {
+		return nil, "", fmt.Errorf("unknown scope %s", scope)
+	}
+	if rateLimit <= 0 {
+		rateLimit = DefaultAPIKeyRateLimit
+	}
+
+	random := make([]byte, 24)
+	_, err := rand.Read(random)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	secret := apiKeyPrefix + hex.EncodeToString(random)
+
+	return &APIKey{
+		Name:      name,
+		Prefix:    secret[:apiKeyVisibleLength],
+		Hash:      HashAPIKey(secret),
+		Scope:     scope,
+		RateLimit: rateLimit,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}, secret, nil
+}
+
+// HashAPIKey returns the stored form of a key
+func HashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKeyScope checks if a scope exists
+func IsAPIKeyScope(scope APIKeyScope) bool {
+	_, ok := apiKeyScopeLevels[scope]
+	return ok
+}
+
+// Allows checks if the key can use an endpoint requiring the scope
+func (k *APIKey) Allows(scope APIKeyScope) bool {
+	return apiKeyScopeLevels[k.Scope] >= apiKeyScopeLevels[scope]
+}
@@ -0,0 +1,20 @@
+package models
+
+// CatchUp gathers the games a player played while the poller was down
+type CatchUp struct {
+	Player  *Player
+	Matches []*MatchPlayerInfo // Newest first
+	Change  *RankChange        // Nil when the rank didn't move
+}
+
+// Record returns the wins and losses of the missed games
+func (c *CatchUp) Record() (wins, losses int) {
+	for _, match := range c.Matches {
+		if match.Victory {
+			wins++
+		} else {
+			losses++
+		}
+	}
+	return wins, losses
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Challenge levels ordered from lowest to highest, NONE before the first threshold
+var challengeLevels = []string{
+	"NONE",
+	"IRON",
+	"BRONZE",
+	"SILVER",
+	"GOLD",
+	"PLATINUM",
+	"DIAMOND",
+	"MASTER",
+	"GRANDMASTER",
+	"CHALLENGER",
+}
+
+// NotableChallengeLevel is the lowest level whose completion is announced in the weekly recaps,
+// the lower ones are reached by most players
+const NotableChallengeLevel = "MASTER"
+
+// ChallengeLevelValue converts a challenge level into a comparable number, 0 when unknown or NONE
+func ChallengeLevelValue(level string) int {
+	for idx, l := range challengeLevels {
+		if l == level {
+			return idx
+		}
+	}
+	return 0
+}
+
+// FormatChallengeLevel returns the display name of a challenge level, ex: GRANDMASTER -> Grandmaster
+func FormatChallengeLevel(level string) string {
+	if level == "" {
+		return "None"
+	}
+	return strings.ToUpper(level[:1]) + strings.ToLower(level[1:])
+}
+
+// ChallengeProgress is the last known challenge progress of a tracked player
+type ChallengeProgress struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PlayerPUUID string             `bson:"playerPuuid" json:"playerPuuid"`
+	GameName    string             `bson:"gameName" json:"gameName"`
+	TagLine     string             `bson:"tagLine" json:"tagLine"`
+
+	// Overall level and points
+	Level      string  `bson:"level" json:"level"`
+	Points     int     `bson:"points" json:"points"`
+	MaxPoints  int     `bson:"maxPoints" json:"maxPoints"`
+	Percentile float64 `bson:"percentile" json:"percentile"` // Share of the players above, 0.01 = top 1%
+
+	Title      string            `bson:"title,omitempty" json:"title,omitempty"` // Displayed title ID, empty when none
+	Challenges []*ChallengeLevel `bson:"challenges" json:"challenges"`
+
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ChallengeLevel is the level reached by a player in a single challenge
+type ChallengeLevel struct {
+	ChallengeID int64      `bson:"challengeId" json:"challengeId"`
+	Level       string     `bson:"level" json:"level"`
+	Value       float64    `bson:"value" json:"value"`
+	Percentile  float64    `bson:"percentile" json:"percentile"`
+	AchievedAt  *time.Time `bson:"achievedAt,omitempty" json:"achievedAt,omitempty"` // Time of the last level up
+}
+
+// LevelOf returns the level reached in a challenge, NONE when the challenge is not started
+func (p *ChallengeProgress) LevelOf(challengeID int64) string {
+	for _, challenge := range p.Challenges {
+		if challenge.ChallengeID == challengeID {
+			return challenge.Level
+		}
+	}
+	return "NONE"
+}
+
+// TopChallenges returns the highest challenge levels reached (best percentile first within a level)
+func (p *ChallengeProgress) TopChallenges(limit int) []*ChallengeLevel {
+	challenges := make([]*ChallengeLevel, 0, len(p.Challenges))
+	for _, challenge := range p.Challenges {
+		if ChallengeLevelValue(challenge.Level) > 0 {
+			challenges = append(challenges, challenge)
+		}
+	}
+
+	sort.SliceStable(challenges, func(a, b int) bool {
+		levelA, levelB := ChallengeLevelValue(challenges[a].Level), ChallengeLevelValue(challenges[b].Level)
+		if levelA != levelB {
+			return levelA > levelB
+		}
+		return challenges[a].Percentile < challenges[b].Percentile
+	})
+
+	if len(challenges) > limit {
+		challenges = challenges[:limit]
+	}
+	return challenges
+}
+
+// ChallengeCompletion is a notable challenge level reached by a tracked player, listed in the weekly recaps
+type ChallengeCompletion struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PlayerPUUID   string             `bson:"playerPuuid" json:"playerPuuid"`
+	GameName      string             `bson:"gameName" json:"gameName"`
+	TagLine       string             `bson:"tagLine" json:"tagLine"`
+	ChallengeID   int64              `bson:"challengeId" json:"challengeId"`
+	ChallengeName string             `bson:"challengeName" json:"challengeName"`
+	Level         string             `bson:"level" json:"level"`
+	AchievedAt    time.Time          `bson:"achievedAt" json:"achievedAt"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ClashTournamentDuration is the time a Clash tournament lasts after the start of the first round, used as the end of the calendar events
+const ClashTournamentDuration = 4 * time.Hour
+
+// ClashRegistration is a tracked player registered in an upcoming Clash tournament
+type ClashRegistration struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PlayerPUUID      string             `bson:"playerPuuid" json:"playerPuuid"`
+	GameName         string             `bson:"gameName" json:"gameName"`
+	TagLine          string             `bson:"tagLine" json:"tagLine"`
+	Server           string             `bson:"server" json:"server"`
+	TournamentID     int                `bson:"tournamentId" json:"tournamentId"`
+	TournamentName   string             `bson:"tournamentName" json:"tournamentName"` // Ex: "Shurima Cup - Day 2"
+	TeamID           string             `bson:"teamId" json:"teamId"`
+	TeamName         string             `bson:"teamName,omitempty" json:"teamName,omitempty"`
+	Position         string             `bson:"position" json:"position"` // TOP, JUNGLE, MIDDLE, BOTTOM, UTILITY, FILL or UNSELECTED
+	RegistrationTime time.Time          `bson:"registrationTime" json:"registrationTime"`
+	StartTime        time.Time          `bson:"startTime" json:"startTime"`
+	UpdatedAt        time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ClashEvent is the Discord scheduled event created in a guild for a tournament of a server
+type ClashEvent struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GuildID      string             `bson:"guildId" json:"guildId"`
+	Server       string             `bson:"server" json:"server"`
+	TournamentID int                `bson:"tournamentId" json:"tournamentId"`
+	EventID      string             `bson:"eventId" json:"eventId"`           // Discord scheduled event ID
+	PlayerPUUIDs []string           `bson:"playerPuuids" json:"playerPuuids"` // Registered players listed in the event
+	StartTime    time.Time          `bson:"startTime" json:"startTime"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ClashTournamentName builds a readable name from the Clash name keys (ex: "shurima", "day_2" -> "Shurima Cup - Day 2")
+func ClashTournamentName(nameKey, nameKeySecondary string) string {
+	name := "Clash"
+	if nameKey != "" {
+		name = titleKey(nameKey) + " Cup"
+	}
+	if nameKeySecondary != "" {
+		name += " - " + titleKey(nameKeySecondary)
+	}
+	return name
+}
+
+func titleKey(key string) string {
+	words := strings.Fields(strings.ReplaceAll(key, "_", " "))
+	for idx, word := range words {
+		words[idx] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Default scoring of competitions
+const (
+	DefaultPointsPerLP        = 1
+	DefaultPointsPerPromotion = 50
+)
+
+// Competition is an opt-in guild contest scoring LP gains and promotions between two dates
+type Competition struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GuildID string             `bson:"guildId" json:"guildId"`
+	Name    string             `bson:"name" json:"name"`
+
+	StartAt time.Time `bson:"startAt" json:"startAt"`
+	EndAt   time.Time `bson:"endAt" json:"endAt"`
+
+	// Scoring
+	PointsPerLP        int `bson:"pointsPerLp" json:"pointsPerLp"`
+	PointsPerPromotion int `bson:"pointsPerPromotion" json:"pointsPerPromotion"`
+
+	// Set once the final results have been announced
+	AnnouncedAt *time.Time `bson:"announcedAt,omitempty" json:"announcedAt,omitempty"`
+
+	CreatedBy string    `bson:"createdBy" json:"createdBy"` // Discord user ID
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+}
+
+// IsRunning checks if the competition is in progress at the given time
+func (c *Competition) IsRunning(now time.Time) bool {
+	return !now.Before(c.StartAt) && now.Before(c.EndAt)
+}
+
+// HasEnded checks if the competition end date is passed
+func (c *Competition) HasEnded(now time.Time) bool {
+	return !now.Before(c.EndAt)
+}
+
+// Validate checks the competition dates and scoring
+func (c *Competition) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("competition name cannot be empty")
+	}
+	if !c.EndAt.After(c.StartAt) {
+		return fmt.Errorf("end date must be after start date")
+	}
+	if c.PointsPerLP < 0 || c.PointsPerPromotion < 0 {
+		return fmt.Errorf("points cannot be negative")
+	}
+	return nil
+}
+
+// CompetitionScore is the result of a player in a competition
+type CompetitionScore struct {
+	PlayerPUUID string
+	GameName    string
+	TagLine     string
+	Tier        string
+	Rank        string
+	LPGained    int // Net LP over the competition
+	Promotions  int
+	Games       int
+	Points      int
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Known feature flags, capabilities that can be switched at runtime without a redeploy
+const (
+	FlagLiveGame    = "live_game"    // Track games in progress
+	FlagFlexQueue   = "flex_queue"   // Announce Ranked Flex games
+	FlagImageCards  = "image_cards"  // Render notifications as image cards
+	FlagClashEvents = "clash_events" // Create Discord scheduled events for the Clash registrations
+	FlagTiltAlerts  = "tilt_alerts"  // Suggest a break to the players on a losing streak
+)
+
+// FeatureFlagInfo describes a known flag and its value when it is set nowhere
+type FeatureFlagInfo struct {
+	Name        string
+	Description string
+	Default     bool
+}
+
+// FeatureFlags lists the known flags, unknown names are rejected
+var FeatureFlags = []FeatureFlagInfo{
+	{Name: FlagLiveGame, Description: "Track games in progress"},
+	{Name: FlagFlexQueue, Description: "Announce Ranked Flex games"},
+	{Name: FlagImageCards, Description: "Render notifications as image cards"},
+	{Name: FlagClashEvents, Description: "Create Discord events for the Clash tournaments of tracked players"},
+	{Name: FlagTiltAlerts, Description: "Suggest a break to the players losing several games in a row below their usual level"},
+}
+
+// FindFeatureFlag returns the description of a known flag, nil if the flag doesn't exist
+func FindFeatureFlag(name string) *FeatureFlagInfo {
+	for idx := range FeatureFlags {
+		if FeatureFlags[idx].Name == name {
+			return &FeatureFlags[idx]
+		}
+	}
+	return nil
+}
+
+// FeatureFlag is the value of a flag, for a guild or globally (empty guild ID).
+// A guild value overrides the global one.
+type FeatureFlag struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	GuildID   string             `bson:"guildId" json:"guildId"` // Empty for the global value
+	Enabled   bool               `bson:"enabled" json:"enabled"`
+	UpdatedBy string             `bson:"updatedBy,omitempty" json:"updatedBy,omitempty"` // Discord user ID
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// IsGlobal checks if the flag applies to every guild
+func (f *FeatureFlag) IsGlobal() bool {
+	return f.GuildID == ""
+}
+
+// Where the effective value of a flag comes from
+const (
+	FlagSourceGuild   = "guild"
+	FlagSourceGlobal  = "global"
+	FlagSourceDefault = "default"
+)
+
+// FeatureFlagState is the effective value of a flag for a guild
+type FeatureFlagState struct {
+	FeatureFlagInfo
+	Enabled bool
+	Source  string // See FlagSource*
+}
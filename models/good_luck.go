@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultGoodLuckTemplate is posted when the guild did not set its own good luck message
+const DefaultGoodLuckTemplate = "🍀 **{player}** is starting their first ranked game of the day on **{champion}**, good luck!"
+
+// Placeholders available in good luck messages
+var goodLuckPlaceholders = []string{"player", "champion", "rank", "queue"}
+
+// Ranked queues announced by the good luck messages (spectator gameQueueConfigId)
+var goodLuckQueues = map[int]string{
+	420: "Ranked Solo/Duo",
+	440: "Ranked Flex",
+}
+
+// IsRankedQueue checks if a live game is a ranked game
+func IsRankedQueue(queueID int) bool {
+	_, ok := goodLuckQueues[queueID]
+	return ok
+}
+
+// ValidateGoodLuckTemplate checks that a good luck message only uses known placeholders
+func ValidateGoodLuckTemplate(template string) error {
+	return validateTemplate(template, goodLuckPlaceholders)
+}
+
+// GoodLuckPlaceholdersString returns the placeholders of the good luck messages
+func GoodLuckPlaceholdersString() string {
+	return placeholdersString(goodLuckPlaceholders)
+}
+
+// RenderGoodLuckMessage fills a good luck template, the default one when empty
+func RenderGoodLuckMessage(template string, player *Player, champion string, queueID int) string {
+	if template == "" {
+		template = DefaultGoodLuckTemplate
+	}
+
+	rank := "Unranked"
+	if player.Tier != "" && player.Tier != "UNRANKED" {
+		rank = fmt.Sprintf("%s %d LP", FormatRank(player.Tier, player.Rank), player.LeaguePoints)
+	}
+
+	replacer := strings.NewReplacer(
+		"{player}", player.DisplayName(),
+		"{champion}", champion,
+		"{rank}", rank,
+		"{queue}", goodLuckQueues[queueID],
+	)
+	return replacer.Replace(template)
+}
@@ -0,0 +1,212 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GuildConfig holds the notification settings of a Discord guild
+type GuildConfig struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GuildID string             `bson:"guildId" json:"guildId"`
+
+	// Notifications
+	NotificationChannelID string `bson:"notificationChannelId" json:"notificationChannelId"`
+	MinLPDelta            int    `bson:"minLpDelta" json:"minLpDelta"`                                         // Minimum LP swing to notify, 0 = every change
+	NotificationTemplate  string `bson:"notificationTemplate,omitempty" json:"notificationTemplate,omitempty"` // Custom message, default format when empty
+	NotificationStyle     string `bson:"notificationStyle,omitempty" json:"notificationStyle,omitempty"`       // Template pack (neutral, hype, savage)
+	Language              string `bson:"language,omitempty" json:"language,omitempty"`                         // Data Dragon locale (ex: fr_FR), localization disabled when empty
+	AFKCallouts           bool   `bson:"afkCallouts" json:"afkCallouts"`                                       // Mention games suspected of AFK in notifications
+	EnemyRanks            bool   `bson:"enemyRanks" json:"enemyRanks"`                                         // Show the average enemy rank, costly in Riot API calls
+	Verbosity             string `bson:"verbosity,omitempty" json:"verbosity,omitempty"`                       // Notification detail level (full, compact), full when empty
+
+	// Message posted when a player starts their first ranked game of the day (live game detection)
+	GoodLuckMessages bool   `bson:"goodLuckMessages,omitempty" json:"goodLuckMessages,omitempty"`
+	GoodLuckTemplate string `bson:"goodLuckTemplate,omitempty" json:"goodLuckTemplate,omitempty"` // Default message when empty
+
+	// Recaps
+	Timezone          string    `bson:"timezone,omitempty" json:"timezone,omitempty"` // IANA zone name, UTC when empty
+	LastDailyRecapAt  time.Time `bson:"lastDailyRecapAt,omitempty" json:"lastDailyRecapAt,omitempty"`
+	LastWeeklyRecapAt time.Time `bson:"lastWeeklyRecapAt,omitempty" json:"lastWeeklyRecapAt,omitempty"`
+
+	// Last patch announced in the notification channel
+	LastPatch string `bson:"lastPatch,omitempty" json:"lastPatch,omitempty"`
+
+	// Players (PUUIDs) whose notifications are muted in the guild
+	MutedPlayers []string `bson:"mutedPlayers,omitempty" json:"mutedPlayers,omitempty"`
+
+	// Live leaderboard: a single pinned message edited after each poll cycle, disabled when no channel
+	LiveLeaderboardChannelID string `bson:"liveLeaderboardChannelId,omitempty" json:"liveLeaderboardChannelId,omitempty"`
+	LiveLeaderboardMessageID string `bson:"liveLeaderboardMessageId,omitempty" json:"liveLeaderboardMessageId,omitempty"`
+
+	// Group the accounts of a person in the live leaderboard (best account) and the recaps (summed games)
+	GroupByPerson bool `bson:"groupByPerson,omitempty" json:"groupByPerson,omitempty"`
+
+	// Permissions matrix: command name -> roles allowed to use it, on top of the Discord permissions.
+	// Commands without roles are open to every member allowed by Discord.
+	CommandRoles map[string][]string `bson:"commandRoles,omitempty" json:"commandRoles,omitempty"`
+
+	// Set when the bot is removed from the guild, nothing is sent to departed guilds
+	LeftAt *time.Time `bson:"leftAt,omitempty" json:"leftAt,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Notification verbosity levels
+const (
+	VerbosityFull    = "full"    // Rank, champion, KDA and game metrics
+	VerbosityCompact = "compact" // Headline and rank only
+)
+
+// NewGuildConfig returns the default configuration of a guild
+func NewGuildConfig(guildID string) *GuildConfig {
+	return &GuildConfig{
+		GuildID:           guildID,
+		NotificationStyle: NotificationStyleNeutral,
+		Verbosity:         VerbosityFull,
+	}
+}
+
+// Validate checks the configuration before it is saved
+func (g *GuildConfig) Validate() error {
+	if g.GuildID == "" {
+		return fmt.Errorf("guild ID cannot be empty")
+	}
+	if g.MinLPDelta < 0 {
+		return fmt.Errorf("minimum LP delta cannot be negative")
+	}
+	if g.NotificationTemplate != "" {
+		if err := ValidateNotificationTemplate(g.NotificationTemplate); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	if g.GoodLuckTemplate != "" {
+		if err := ValidateGoodLuckTemplate(g.GoodLuckTemplate); err != nil {
+			return fmt.Errorf("invalid good luck message: %w", err)
+		}
+	}
+	if g.NotificationStyle != "" && !IsNotificationStyle(g.NotificationStyle) {
+		return fmt.Errorf("unknown notification style %s", g.NotificationStyle)
+	}
+	if g.Verbosity != "" && g.Verbosity != VerbosityFull && g.Verbosity != VerbosityCompact {
+		return fmt.Errorf("unknown verbosity %s", g.Verbosity)
+	}
+	if g.Timezone != "" {
+		if _, err := time.LoadLocation(g.Timezone); err != nil || g.Timezone == "Local" {
+			return fmt.Errorf("unknown time zone %s", g.Timezone)
+		}
+	}
+	return nil
+}
+
+// IsActive checks if the bot is still a member of the guild
+func (g *GuildConfig) IsActive() bool {
+	return g.LeftAt == nil
+}
+
+// IsMuted checks if the notifications of a player are muted in the guild
+func (g *GuildConfig) IsMuted(puuid string) bool {
+	for _, muted := range g.MutedPlayers {
+		if muted == puuid {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsCommand checks if a member with the given roles can use a command of the permissions matrix
+func (g *GuildConfig) AllowsCommand(command string, memberRoles []string) bool {
+	allowed, ok := g.CommandRoles[command]
+	if !ok || len(allowed) == 0 {
+		return true
+	}
+
+	for _, role := range memberRoles {
+		for _, allowedRole := range allowed {
+			if role == allowedRole {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetCommandRole allows (or stops allowing) a role to use a command. The matrix is copied since
+// configs are shared with the cache.
+func (g *GuildConfig) SetCommandRole(command, roleID string, allowed bool) {
+	matrix := make(map[string][]string, len(g.CommandRoles)+1)
+	for name, roles := range g.CommandRoles {
+		matrix[name] = roles
+	}
+
+	var roles []string
+	for _, role := range matrix[command] {
+		if role != roleID {
+			roles = append(roles, role)
+		}
+	}
+	if allowed {
+		roles = append(roles, roleID)
+	}
+
+	if len(roles) == 0 {
+		delete(matrix, command)
+	} else {
+		matrix[command] = roles
+	}
+	g.CommandRoles = matrix
+}
+
+// IsCompact checks if notifications should omit the game details
+func (g *GuildConfig) IsCompact() bool {
+	return g.Verbosity == VerbosityCompact
+}
+
+// ShouldNotify checks if a rank change passes the guild notification threshold.
+// Tier and division changes are always notified.
+func (g *GuildConfig) ShouldNotify(change *RankChange) bool {
+	if change.DivisionChanged() {
+		return true
+	}
+
+	delta := change.LPDelta()
+	if delta < 0 {
+		delta = -delta
+	}
+	return delta > 0 && delta >= g.MinLPDelta
+}
+
+// Location returns the guild time zone, UTC if not set or invalid
+func (g *GuildConfig) Location() *time.Location {
+	if g.Timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(g.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LastRecapDispatch returns the most recent time (at or before now) a recap of the given
+// period was due, computed in the guild local time
+func (g *GuildConfig) LastRecapDispatch(now time.Time, period RecapPeriod) time.Time {
+	local := now.In(g.Location())
+	dispatch := time.Date(local.Year(), local.Month(), local.Day(), RecapDispatchHour, 0, 0, 0, local.Location())
+	if dispatch.After(local) {
+		dispatch = dispatch.AddDate(0, 0, -1)
+	}
+
+	if period == RecapPeriodWeekly {
+		for dispatch.Weekday() != RecapWeeklyDay {
+			dispatch = dispatch.AddDate(0, 0, -1)
+		}
+	}
+
+	return dispatch
+}
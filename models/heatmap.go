@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// HeatmapDays is the period covered by the activity heatmaps
+const HeatmapDays = 60
+
+// HeatmapWeekdays lists the rows of the heatmaps, the week starts on Monday
+var HeatmapWeekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// ActivityHeatmap counts the ranked games started per day of the week and hour, in a time zone
+type ActivityHeatmap struct {
+	Games    [7][24]int // Indexed by HeatmapWeekdays position, then hour
+	Total    int
+	Start    time.Time
+	End      time.Time
+	Location *time.Location
+}
+
+// NewActivityHeatmap buckets the games by their local start time
+func NewActivityHeatmap(matches []*MatchPlayerInfo, start, end time.Time, loc *time.Location) *ActivityHeatmap {
+	heatmap := &ActivityHeatmap{Start: start, End: end, Location: loc}
+	for _, match := range matches {
+		local := match.CreatedAt.In(loc)
+		heatmap.Games[heatmapRow(local.Weekday())][local.Hour()]++
+		heatmap.Total++
+	}
+	return heatmap
+}
+
+// heatmapRow returns the row of a weekday, Monday first
+func heatmapRow(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+// Max returns the highest number of games in a single cell
+func (h *ActivityHeatmap) Max() int {
+	busiest := 0
+	for _, hours := range h.Games {
+		for _, games := range hours {
+			if games > busiest {
+				busiest = games
+			}
+		}
+	}
+	return busiest
+}
+
+// Peak returns the busiest day of the week and hour, ok is false when no game was played
+func (h *ActivityHeatmap) Peak() (weekday time.Weekday, hour int, ok bool) {
+	busiest := 0
+	for row, hours := range h.Games {
+		for idx, games := range hours {
+			if games > busiest {
+				busiest, weekday, hour = games, HeatmapWeekdays[row], idx
+			}
+		}
+	}
+	return weekday, hour, busiest > 0
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Job statuses
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// Job types
+const (
+	JobTypeBackfill   = "backfill"
+	JobTypeBulkImport = "bulk_import"
+	JobTypeReport     = "report"
+	JobTypeRefresh    = "refresh"
+)
+
+// Job is a long running background operation requested from Discord, or from the API (without guild)
+type Job struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type        string             `bson:"type" json:"type"`
+	Name        string             `bson:"name" json:"name"` // Human readable description
+	GuildID     string             `bson:"guildId" json:"guildId,omitempty"`
+	ChannelID   string             `bson:"channelId" json:"channelId,omitempty"`
+	RequestedBy string             `bson:"requestedBy" json:"requestedBy"` // Discord user ID, or "api:<key name>"
+	Status      string             `bson:"status" json:"status"`
+	Progress    int                `bson:"progress" json:"progress"` // Percentage
+	Result      string             `bson:"result,omitempty" json:"result,omitempty"`
+	Error       string             `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	StartedAt   *time.Time         `bson:"startedAt,omitempty" json:"startedAt,omitempty"`
+	FinishedAt  *time.Time         `bson:"finishedAt,omitempty" json:"finishedAt,omitempty"`
+}
+
+// IsFinished checks if the job reached a final status
+func (j *Job) IsFinished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed || j.Status == JobCancelled
+}
+
+// ShortID returns the identifier shown to users
+func (j *Job) ShortID() string {
+	return j.ID.Hex()
+}
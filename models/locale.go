@@ -0,0 +1,185 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLocale is used for the interactions without a locale and the unknown locales
+const DefaultLocale = "en-US"
+
+// relativeWords are the words of a language for relative times ("2 hours ago", "in 3 days")
+type relativeWords struct {
+	now    string
+	past   string    // Format of a past duration, ex: "%s ago"
+	future string    // Format of a future duration, ex: "in %s"
+	units  [3]string // Minute, hour and day: "singular|plural"
+}
+
+// localeFormat holds the conventions of a locale, Discord (fr, en-US) and Data Dragon (fr_FR) codes map to it
+type localeFormat struct {
+	decimal  string // Decimal separator
+	group    string // Thousands separator
+	percent  string // Between the number and %, ex: a narrow no-break space in French
+	date     string // Layout of a full date
+	dayMonth string // Layout of a date without the year (recaps)
+	relative relativeWords
+}
+
+var englishWords = relativeWords{
+	now: "just now", past: "%s ago", future: "in %s",
+	units: [3]string{"minute|minutes", "hour|hours", "day|days"},
+}
+
+var relativeWordsByLanguage = map[string]relativeWords{
+	"en": englishWords,
+	"fr": {now: "à l'instant", past: "il y a %s", future: "dans %s", units: [3]string{"minute|minutes", "heure|heures", "jour|jours"}},
+	"de": {now: "gerade eben", past: "vor %s", future: "in %s", units: [3]string{"Minute|Minuten", "Stunde|Stunden", "Tag|Tagen"}},
+	"es": {now: "justo ahora", past: "hace %s", future: "en %s", units: [3]string{"minuto|minutos", "hora|horas", "día|días"}},
+	"it": {now: "proprio ora", past: "%s fa", future: "tra %s", units: [3]string{"minuto|minuti", "ora|ore", "giorno|giorni"}},
+	"pt": {now: "agora mesmo", past: "há %s", future: "em %s", units: [3]string{"minuto|minutos", "hora|horas", "dia|dias"}},
+}
+
+// Conventions by Discord locale, then by language for the locales without a specific entry
+var localeFormats = map[string]localeFormat{
+	"en-us":  {decimal: ".", group: ",", date: "01/02/2006", dayMonth: "Mon 01/02"},
+	"en-gb":  {decimal: ".", group: ",", date: "02/01/2006", dayMonth: "Mon 02/01"},
+	"zh-tw":  {decimal: ".", group: ",", date: "2006/01/02", dayMonth: "01/02"},
+	"es-419": {decimal: ".", group: ",", date: "02/01/2006", dayMonth: "02/01"},
+	"pt-br":  {decimal: ",", group: ".", date: "02/01/2006", dayMonth: "02/01"},
+
+	"en": {decimal: ".", group: ",", date: "01/02/2006", dayMonth: "Mon 01/02"},
+	"fr": {decimal: ",", group: "\u202f", percent: "\u202f", date: "02/01/2006", dayMonth: "02/01"},
+	"de": {decimal: ",", group: ".", percent: "\u00a0", date: "02.01.2006", dayMonth: "02.01."},
+	"es": {decimal: ",", group: ".", percent: "\u00a0", date: "02/01/2006", dayMonth: "02/01"},
+	"it": {decimal: ",", group: ".", date: "02/01/2006", dayMonth: "02/01"},
+	"pt": {decimal: ",", group: "\u00a0", date: "02/01/2006", dayMonth: "02/01"},
+	"nl": {decimal: ",", group: ".", date: "02-01-2006", dayMonth: "02-01"},
+	"pl": {decimal: ",", group: "\u00a0", date: "02.01.2006", dayMonth: "02.01"},
+	"ro": {decimal: ",", group: ".", percent: "\u00a0", date: "02.01.2006", dayMonth: "02.01"},
+	"cs": {decimal: ",", group: "\u00a0", percent: "\u00a0", date: "02.01.2006", dayMonth: "02.01."},
+	"el": {decimal: ",", group: ".", date: "02/01/2006", dayMonth: "02/01"},
+	"hu": {decimal: ",", group: "\u00a0", date: "2006. 01. 02.", dayMonth: "01. 02."},
+	"ru": {decimal: ",", group: "\u00a0", percent: "\u00a0", date: "02.01.2006", dayMonth: "02.01"},
+	"uk": {decimal: ",", group: "\u00a0", date: "02.01.2006", dayMonth: "02.01"},
+	"tr": {decimal: ",", group: ".", date: "02.01.2006", dayMonth: "02.01"},
+	"sv": {decimal: ",", group: "\u00a0", percent: "\u00a0", date: "2006-01-02", dayMonth: "02/01"},
+	"da": {decimal: ",", group: ".", percent: "\u00a0", date: "02.01.2006", dayMonth: "02.01"},
+	"no": {decimal: ",", group: "\u00a0", percent: "\u00a0", date: "02.01.2006", dayMonth: "02.01"},
+	"fi": {decimal: ",", group: "\u00a0", percent: "\u00a0", date: "02.01.2006", dayMonth: "02.01."},
+	"vi": {decimal: ",", group: ".", date: "02/01/2006", dayMonth: "02/01"},
+	"ja": {decimal: ".", group: ",", date: "2006/01/02", dayMonth: "01/02"},
+	"ko": {decimal: ".", group: ",", date: "2006. 01. 02.", dayMonth: "01. 02."},
+	"zh": {decimal: ".", group: ",", date: "2006/01/02", dayMonth: "01/02"},
+}
+
+// Formatter formats numbers, dates and relative times with the conventions of a locale.
+// It is shared by the interaction responses (interaction locale) and the recaps (guild language).
+type Formatter struct {
+	format localeFormat
+}
+
+// NewFormatter returns the formatter of a Discord locale (ex: fr, en-US) or a Data Dragon locale
+// (ex: fr_FR), unknown locales fall back to DefaultLocale
+func NewFormatter(locale string) Formatter {
+	normalized := strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	language, _, _ := strings.Cut(normalized, "-")
+
+	format, ok := localeFormats[normalized]
+	if !ok {
+		format, ok = localeFormats[language]
+	}
+	if !ok {
+		return NewFormatter(DefaultLocale)
+	}
+
+	format.relative = englishWords
+	if words, ok := relativeWordsByLanguage[language]; ok {
+		format.relative = words
+	}
+	return Formatter{format: format}
+}
+
+// Int formats an integer with the thousands separator, ex: 12,345 or 12 345
+func (f Formatter) Int(value int) string {
+	return f.Number(float64(value), 0)
+}
+
+// Number formats a number with the given number of decimals, ex: 1,234.5 or 1.234,5
+func (f Formatter) Number(value float64, decimals int) string {
+	formatted := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(formatted, ".")
+
+	var builder strings.Builder
+	if value < 0 && formatted != strconv.FormatFloat(0, 'f', decimals, 64) {
+		builder.WriteString("-")
+	}
+	for idx, digit := range integer {
+		if idx > 0 && (len(integer)-idx)%3 == 0 {
+			builder.WriteString(f.format.group)
+		}
+		builder.WriteRune(digit)
+	}
+	if fraction != "" {
+		builder.WriteString(f.format.decimal)
+		builder.WriteString(fraction)
+	}
+	return builder.String()
+}
+
+// Signed formats a number with its sign, ex: +12.5 or -3
+func (f Formatter) Signed(value float64, decimals int) string {
+	formatted := f.Number(value, decimals)
+	if strings.HasPrefix(formatted, "-") {
+		return formatted
+	}
+	return "+" + formatted
+}
+
+// Percent formats a percentage (52.3 for 52.3%), ex: 52.3% or 52,3 %
+func (f Formatter) Percent(value float64, decimals int) string {
+	return f.Number(value, decimals) + f.format.percent + "%"
+}
+
+// Date formats the day of t, ex: 03/14/2026 or 14.03.2026
+func (f Formatter) Date(t time.Time) string {
+	return t.Format(f.format.date)
+}
+
+// DayMonth formats the day of t without the year, ex: Sat 03/14 or 14/03
+func (f Formatter) DayMonth(t time.Time) string {
+	return t.Format(f.format.dayMonth)
+}
+
+// RelativeTime formats the distance between t and now, ex: "2 hours ago", "dans 3 jours"
+func (f Formatter) RelativeTime(t time.Time, now time.Time) string {
+	words := f.format.relative
+	distance := now.Sub(t)
+	format := words.past
+	if distance < 0 {
+		distance = -distance
+		format = words.future
+	}
+
+	var count int
+	var unit string
+	switch {
+	case distance < time.Minute:
+		return words.now
+	case distance < time.Hour:
+		count, unit = int(distance/time.Minute), words.units[0]
+	case distance < 24*time.Hour:
+		count, unit = int(distance/time.Hour), words.units[1]
+	default:
+		count, unit = int(distance/(24*time.Hour)), words.units[2]
+	}
+
+	singular, plural, _ := strings.Cut(unit, "|")
+	if count > 1 {
+		singular = plural
+	}
+	return fmt.Sprintf(format, f.Int(count)+" "+singular)
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LPEvent is a ledger entry recorded each time a player's rank or LP changes
+type LPEvent struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+
+	// Player identity at the time of the change
+	PlayerPUUID string `bson:"playerPuuid" json:"playerPuuid"`
+	GameName    string `bson:"gameName" json:"gameName"`
+	TagLine     string `bson:"tagLine" json:"tagLine"`
+	Server      string `bson:"server" json:"server"`
+
+	// Rank before and after the change
+	PreviousTier         string `bson:"previousTier" json:"previousTier"`
+	PreviousRank         string `bson:"previousRank" json:"previousRank"`
+	PreviousLeaguePoints int    `bson:"previousLeaguePoints" json:"previousLeaguePoints"`
+	Tier                 string `bson:"tier" json:"tier"`
+	Rank                 string `bson:"rank" json:"rank"`
+	LeaguePoints         int    `bson:"leaguePoints" json:"leaguePoints"`
+	LPDelta              int    `bson:"lpDelta" json:"lpDelta"`
+
+	// Game that caused the change, empty when unknown
+	MatchID      string `bson:"matchId,omitempty" json:"matchId,omitempty"`
+	Victory      bool   `bson:"victory" json:"victory"`
+	SuspectedAFK bool   `bson:"suspectedAfk,omitempty" json:"suspectedAfk,omitempty"` // Excluded from streaks
+
+	CreatedAt  time.Time  `bson:"createdAt" json:"createdAt"`
+	NotifiedAt *time.Time `bson:"notifiedAt,omitempty" json:"notifiedAt,omitempty"` // When the change was announced on Discord
+}
+
+// NewLPEvent builds the ledger entry of a rank change
+func NewLPEvent(change *RankChange) *LPEvent {
+	player := change.Player
+	delta := change.LPDelta()
+
+	event := &LPEvent{
+		PlayerPUUID:          player.PUUID,
+		GameName:             player.GameName,
+		TagLine:              player.TagLine,
+		Server:               player.Server,
+		PreviousTier:         change.PreviousTier,
+		PreviousRank:         change.PreviousRank,
+		PreviousLeaguePoints: change.PreviousLeaguePoints,
+		Tier:                 player.Tier,
+		Rank:                 player.Rank,
+		LeaguePoints:         player.LeaguePoints,
+		LPDelta:              delta,
+		Victory:              delta > 0,
+	}
+
+	if change.Match != nil {
+		event.MatchID = change.Match.MatchID
+		event.Victory = change.Match.Victory
+		event.SuspectedAFK = change.Match.HasAFK()
+	}
+
+	return event
+}
+
+// IsPromotion checks if the event moved the player to a higher tier or division (placements excluded)
+func (e *LPEvent) IsPromotion() bool {
+	if e.PreviousTier == "UNRANKED" || e.Tier == "UNRANKED" {
+		return false
+	}
+	divisionChanged := e.PreviousTier != e.Tier || e.PreviousRank != e.Rank
+	return divisionChanged && e.LPDelta > 0
+}
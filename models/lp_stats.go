@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Hidden MMR estimations relative to the displayed rank
+const (
+	MMRUnknown = iota
+	MMRAboveRank
+	MMRAtRank
+	MMRBelowRank
+)
+
+const (
+	// Minimum wins and losses before estimating the hidden MMR
+	minMMRSampleGames = 5
+
+	// Gap between the average LP gain and loss considered meaningful
+	mmrDriftThreshold = 3.0
+)
+
+// LPStats aggregates the LP gained and lost by a player over the games attributed to a match
+type LPStats struct {
+	Wins     int
+	Losses   int
+	LPGained int // Sum of the LP won
+	LPLost   int // Sum of the LP lost, positive
+
+	// Time span of the games taken into account
+	FirstGameAt time.Time
+	LastGameAt  time.Time
+}
+
+// NewLPStats computes the LP stats of a player from their ledger entries.
+// Entries without a known match or LP swing (placements, decay) are ignored.
+func NewLPStats(events []*LPEvent) *LPStats {
+	stats := &LPStats{}
+	for _, event := range events {
+		if event.MatchID == "" || event.LPDelta == 0 {
+			continue
+		}
+
+		if event.LPDelta > 0 {
+			stats.Wins++
+			stats.LPGained += event.LPDelta
+		} else {
+			stats.Losses++
+			stats.LPLost -= event.LPDelta
+		}
+
+		if stats.FirstGameAt.IsZero() || event.CreatedAt.Before(stats.FirstGameAt) {
+			stats.FirstGameAt = event.CreatedAt
+		}
+		if event.CreatedAt.After(stats.LastGameAt) {
+			stats.LastGameAt = event.CreatedAt
+		}
+	}
+	return stats
+}
+
+// Games returns the number of games taken into account
+func (s *LPStats) Games() int {
+	return s.Wins + s.Losses
+}
+
+// WinRate returns the win rate in percent
+func (s *LPStats) WinRate() float64 {
+	if s.Games() == 0 {
+		return 0
+	}
+	return float64(s.Wins) / float64(s.Games()) * 100
+}
+
+// AverageGain returns the average LP gained per win
+func (s *LPStats) AverageGain() float64 {
+	if s.Wins == 0 {
+		return 0
+	}
+	return float64(s.LPGained) / float64(s.Wins)
+}
+
+// AverageLoss returns the average LP lost per loss, positive
+func (s *LPStats) AverageLoss() float64 {
+	if s.Losses == 0 {
+		return 0
+	}
+	return float64(s.LPLost) / float64(s.Losses)
+}
+
+// GamesPerDay returns the average number of games played per day over the sampled period,
+// counting at least one day
+func (s *LPStats) GamesPerDay() float64 {
+	days := s.LastGameAt.Sub(s.FirstGameAt).Hours() / 24
+	if days < 1 {
+		days = 1
+	}
+	return float64(s.Games()) / days
+}
+
+// ExpectedLPPerGame returns the average LP swing of a game at the current win rate
+func (s *LPStats) ExpectedLPPerGame() float64 {
+	if s.Games() == 0 {
+		return 0
+	}
+	return float64(s.LPGained-s.LPLost) / float64(s.Games())
+}
+
+// GamesToNextDivision estimates the games needed to reach the next division at the current pace.
+// It returns false if the player is not climbing or has no next division.
+func (s *LPStats) GamesToNextDivision(player *Player) (int, bool) {
+	needed, ok := LPToNextDivision(player.Tier, player.Rank, player.LeaguePoints)
+	if !ok {
+		return 0, false
+	}
+
+	expected := s.ExpectedLPPerGame()
+	if expected <= 0 {
+		return 0, false
+	}
+	return int(math.Ceil(float64(needed) / expected)), true
+}
+
+// MMRDrift compares the average LP gain and loss to estimate if the hidden MMR
+// is above or below the displayed rank, with an explanation for players
+func (s *LPStats) MMRDrift() (int, string) {
+	if s.Wins < minMMRSampleGames || s.Losses < minMMRSampleGames {
+		return MMRUnknown, fmt.Sprintf("Not enough games to estimate the hidden MMR (%d wins and %d losses needed)",
+			minMMRSampleGames, minMMRSampleGames)
+	}
+
+	gap := s.AverageGain() - s.AverageLoss()
+	switch {
+	case gap >= mmrDriftThreshold:
+		return MMRAboveRank, fmt.Sprintf("Your LP gains suggest your hidden MMR is above your rank: you win %.1f LP more than you lose, keep climbing!", gap)
+	case gap <= -mmrDriftThreshold:
+		return MMRBelowRank, fmt.Sprintf("Your LP gains suggest your hidden MMR is below your rank: you lose %.1f LP more than you win, wins are needed to stabilize it", -gap)
+	default:
+		return MMRAtRank, "Your LP gains and losses are balanced, your hidden MMR matches your rank"
+	}
+}
@@ -3,6 +3,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -27,17 +28,55 @@ type MatchPlayerInfo struct {
 	LeaguePoints int    `bson:"league_points" json:"league_points"`
 	QueueType    string `bson:"queue_type" json:"queue_type"` // "RANKED_SOLO_5x5" or "RANKED_FLEX_SR"
 
+	// Game version
+	GameVersion string `bson:"game_version" json:"game_version"` // ex: "14.5.567.1234"
+	Patch       string `bson:"patch" json:"patch"`               // ex: "14.5"
+
 	// Player performance
 	Kills    int    `bson:"kills" json:"kills"`
 	Deaths   int    `bson:"deaths" json:"deaths"`
 	Assists  int    `bson:"assists" json:"assists"`
 	Champion string `bson:"champion" json:"champion"`
+	Role     string `bson:"role" json:"role"` // teamPosition: TOP, JUNGLE, MIDDLE, BOTTOM, UTILITY
+
+	// Draft
+	BannedChampionKey int    `bson:"banned_champion_key,omitempty" json:"banned_champion_key,omitempty"` // Numeric champion key from Match-V5
+	BannedChampion    string `bson:"banned_champion,omitempty" json:"banned_champion,omitempty"`         // Champion ID (ex: "MonkeyKing")
+	EnemyChampion     string `bson:"enemy_champion,omitempty" json:"enemy_champion,omitempty"`           // Champion of the opponent on the same role
+
+	// Opponents of the player, used to resolve the enemy team average rank
+	EnemyPUUIDs []string `bson:"enemy_puuids,omitempty" json:"enemy_puuids,omitempty"`
 
 	// Advanced statistics
-	DamageToChamps int `bson:"damage_to_champs" json:"damage_to_champs"`
-	CreepScore     int `bson:"creep_score" json:"creep_score"` // CS total
-	GoldEarned     int `bson:"gold_earned" json:"gold_earned"`
-	VisionScore    int `bson:"vision_score" json:"vision_score"`
+	DamageToChamps int   `bson:"damage_to_champs" json:"damage_to_champs"`
+	CreepScore     int   `bson:"creep_score" json:"creep_score"` // CS total
+	GoldEarned     int   `bson:"gold_earned" json:"gold_earned"`
+	VisionScore    int   `bson:"vision_score" json:"vision_score"`
+	GameDuration   int64 `bson:"game_duration" json:"game_duration"` // Seconds
+
+	// Derived metrics (computed at ingestion from team-level data)
+	CSPerMinute       float64 `bson:"cs_per_minute" json:"cs_per_minute"`
+	DamageShare       float64 `bson:"damage_share" json:"damage_share"`             // Share of the team damage to champions (0-1)
+	KillParticipation float64 `bson:"kill_participation" json:"kill_participation"` // (kills + assists) / team kills (0-1)
+
+	// Multikills and killing sprees
+	DoubleKills         int `bson:"double_kills" json:"double_kills"`
+	TripleKills         int `bson:"triple_kills" json:"triple_kills"`
+	QuadraKills         int `bson:"quadra_kills" json:"quadra_kills"`
+	PentaKills          int `bson:"penta_kills" json:"penta_kills"`
+	LargestMultiKill    int `bson:"largest_multi_kill" json:"largest_multi_kill"`
+	KillingSprees       int `bson:"killing_sprees" json:"killing_sprees"`
+	LargestKillingSpree int `bson:"largest_killing_spree" json:"largest_killing_spree"`
+
+	// Objectives (from challenges data)
+	DragonTakedowns int `bson:"dragon_takedowns" json:"dragon_takedowns"`
+	BaronTakedowns  int `bson:"baron_takedowns" json:"baron_takedowns"`
+	HeraldTakedowns int `bson:"herald_takedowns" json:"herald_takedowns"`
+	TurretTakedowns int `bson:"turret_takedowns" json:"turret_takedowns"`
+
+	// AFK / disconnect heuristics
+	SuspectedAFK bool `bson:"suspected_afk" json:"suspected_afk"` // The player barely took part in the game
+	AFKTeammate  bool `bson:"afk_teammate" json:"afk_teammate"`   // Riot flagged an AFK teammate
 
 	// Metadata
 	CreatedAt   time.Time  `bson:"created_at" json:"created_at"`
@@ -60,12 +99,64 @@ func (m *MatchPlayerInfo) KDAString() string {
 	return fmt.Sprintf("%d/%d/%d", m.Kills, m.Deaths, m.Assists)
 }
 
+// MultiKillHighlight returns "PENTAKILL" or "QUADRA KILL" when the player got one, empty otherwise
+func (m *MatchPlayerInfo) MultiKillHighlight() string {
+	switch {
+	case m.PentaKills > 0:
+		return "PENTAKILL"
+	case m.QuadraKills > 0:
+		return "QUADRA KILL"
+	default:
+		return ""
+	}
+}
+
+// HasAFK checks if the game is suspected of having an AFK player on the player's team
+func (m *MatchPlayerInfo) HasAFK() bool {
+	return m.SuspectedAFK || m.AFKTeammate
+}
+
 // FormatGameDuration returns the match duration formatted (MM:SS)
-// func (m *MatchPlayerInfo) FormatGameDuration() string {
-// 	minutes := m.GameDuration / 60
-// 	seconds := m.GameDuration % 60
-// 	return fmt.Sprintf("%d:%02d", minutes, seconds)
-// }
+func (m *MatchPlayerInfo) FormatGameDuration() string {
+	minutes := m.GameDuration / 60
+	seconds := m.GameDuration % 60
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
+
+// ComputeDerivedMetrics fills CS per minute, damage share and kill participation
+func (m *MatchPlayerInfo) ComputeDerivedMetrics(teamKills, teamDamage int) {
+	if m.GameDuration > 0 {
+		m.CSPerMinute = float64(m.CreepScore) / (float64(m.GameDuration) / 60)
+	}
+	if teamDamage > 0 {
+		m.DamageShare = float64(m.DamageToChamps) / float64(teamDamage)
+	}
+	if teamKills > 0 {
+		m.KillParticipation = float64(m.Kills+m.Assists) / float64(teamKills)
+	}
+}
+
+// MetricsString returns the derived metrics formatted (ex: "7.2 CS/min • 28% dmg • 61% KP")
+func (m *MatchPlayerInfo) MetricsString() string {
+	return fmt.Sprintf("%.1f CS/min • %.0f%% dmg • %.0f%% KP", m.CSPerMinute, m.DamageShare*100, m.KillParticipation*100)
+}
+
+// DraftString returns the ban and lane opponent of the player (ex: "🚫 Ban: Yasuo • ⚔️ vs Zed"), empty if unknown
+func (m *MatchPlayerInfo) DraftString() string {
+	var parts []string
+	if m.BannedChampion != "" {
+		parts = append(parts, "🚫 Ban: "+m.BannedChampion)
+	}
+	if m.EnemyChampion != "" {
+		parts = append(parts, "⚔️ vs "+m.EnemyChampion)
+	}
+	return strings.Join(parts, " • ")
+}
+
+// IsRankedSolo checks if the match was played in ranked Solo/Duo, the queue whose LP are tracked
+func (m *MatchPlayerInfo) IsRankedSolo() bool {
+	return m.QueueType == "RANKED_SOLO_5x5"
+}
 
 // IsRanked checks if the match is ranked
 func (m *MatchPlayerInfo) IsRanked() bool {
@@ -0,0 +1,27 @@
+package models
+
+// MatchHistoryEntry is a stored game of a player with the LP change it caused
+type MatchHistoryEntry struct {
+	Match   *MatchPlayerInfo
+	LPDelta *int // Nil when no LP change was recorded for the game (ex: flex, remake, game missed by the polls)
+}
+
+// NewMatchHistory pairs the games of a player with the LP events recorded for them, in the order of the games
+func NewMatchHistory(matches []*MatchPlayerInfo, events []*LPEvent) []*MatchHistoryEntry {
+	deltas := make(map[string]int, len(events))
+	for _, event := range events {
+		if event.MatchID != "" {
+			deltas[event.MatchID] = event.LPDelta
+		}
+	}
+
+	history := make([]*MatchHistoryEntry, 0, len(matches))
+	for _, match := range matches {
+		entry := &MatchHistoryEntry{Match: match}
+		if delta, ok := deltas[match.MatchID]; ok {
+			entry.LPDelta = &delta
+		}
+		history = append(history, entry)
+	}
+	return history
+}
@@ -0,0 +1,81 @@
+package models
+
+import "strings"
+
+// Notification styles selectable by guilds
+const (
+	NotificationStyleNeutral = "neutral"
+	NotificationStyleHype    = "hype"
+	NotificationStyleSavage  = "savage"
+)
+
+// NotificationPack is a set of headline templates for each kind of rank change
+type NotificationPack struct {
+	Promotion string
+	Demotion  string
+	Win       string
+	Loss      string
+	Update    string
+}
+
+var notificationPacks = map[string]NotificationPack{
+	NotificationStyleNeutral: {
+		Promotion: "🚀 **{player}** promoted to **{rank}**!",
+		Demotion:  "📉 **{player}** demoted to **{rank}**",
+		Win:       "🟢 **{player}** won a game",
+		Loss:      "🔴 **{player}** lost a game",
+		Update:    "🔄 **{player}** rank updated",
+	},
+	NotificationStyleHype: {
+		Promotion: "🎉🔥 LET'S GO! **{player}** just climbed to **{rank}**! 🔥🎉",
+		Demotion:  "💪 **{player}** dropped to **{rank}**, the comeback starts now!",
+		Win:       "🔥 **{player}** is ON FIRE, another W in the bag! ({lp_delta} LP)",
+		Loss:      "😤 **{player}** took an L, shake it off and queue up!",
+		Update:    "✨ **{player}** rank updated, keep grinding!",
+	},
+	NotificationStyleSavage: {
+		Promotion: "👀 **{player}** got carried to **{rank}**. Enjoy it while it lasts.",
+		Demotion:  "🪦 RIP. **{player}** fell to **{rank}**. Uninstall?",
+		Win:       "🍀 **{player}** won a game. Teammates must have been good.",
+		Loss:      "🤡 **{player}** lost again. {lp_delta} LP, skill issue.",
+		Update:    "🥱 **{player}** rank changed. Nobody asked.",
+	},
+}
+
+// NotificationStyles returns the available notification style names
+func NotificationStyles() []string {
+	return []string{NotificationStyleNeutral, NotificationStyleHype, NotificationStyleSavage}
+}
+
+// IsNotificationStyle checks if a style name is known
+func IsNotificationStyle(style string) bool {
+	_, ok := notificationPacks[strings.ToLower(style)]
+	return ok
+}
+
+// NotificationPackFor returns the pack of a style, the neutral pack if the style is unknown
+func NotificationPackFor(style string) NotificationPack {
+	pack, ok := notificationPacks[strings.ToLower(style)]
+	if !ok {
+		return notificationPacks[NotificationStyleNeutral]
+	}
+	return pack
+}
+
+// TemplateFor returns the headline template matching a rank change
+func (p NotificationPack) TemplateFor(change *RankChange) string {
+	delta := change.LPDelta()
+
+	switch {
+	case change.IsPromotion():
+		return p.Promotion
+	case change.IsDemotion():
+		return p.Demotion
+	case delta > 0:
+		return p.Win
+	case delta < 0:
+		return p.Loss
+	default:
+		return p.Update
+	}
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxNotificationTemplateLength is the maximum length of a custom notification template
+const MaxNotificationTemplateLength = 500
+
+// Placeholders available in notification templates
+var notificationPlaceholders = []string{"player", "lp_delta", "rank", "champion", "kda"}
+
+// ValidateNotificationTemplate checks that a template only uses known placeholders
+func ValidateNotificationTemplate(template string) error {
+	return validateTemplate(template, notificationPlaceholders)
+}
+
+// NotificationPlaceholdersString returns the available placeholders (ex: "{player}, {rank}")
+func NotificationPlaceholdersString() string {
+	return placeholdersString(notificationPlaceholders)
+}
+
+// validateTemplate checks that a message template only uses the given placeholders
+func validateTemplate(template string, placeholders []string) error {
+	if strings.TrimSpace(template) == "" {
+		return fmt.Errorf("template cannot be empty")
+	}
+	if len(template) > MaxNotificationTemplateLength {
+		return fmt.Errorf("template cannot exceed %d characters", MaxNotificationTemplateLength)
+	}
+
+	rest := template
+	for {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			return nil
+		}
+		if rest[open] == '}' {
+			return fmt.Errorf("unexpected '}' without matching '{'")
+		}
+
+		end := strings.IndexAny(rest[open+1:], "{}")
+		if end < 0 || rest[open+1+end] == '{' {
+			return fmt.Errorf("unclosed placeholder starting at '%s'", rest[open:])
+		}
+
+		name := rest[open+1 : open+1+end]
+		if !isPlaceholder(name, placeholders) {
+			return fmt.Errorf("unknown placeholder {%s}, available: %s", name, placeholdersString(placeholders))
+		}
+
+		rest = rest[open+end+2:]
+	}
+}
+
+// placeholdersString formats placeholders for the users (ex: "{player}, {rank}")
+func placeholdersString(placeholders []string) string {
+	names := make([]string, len(placeholders))
+	for idx, name := range placeholders {
+		names[idx] = "{" + name + "}"
+	}
+	return strings.Join(names, ", ")
+}
+
+// RenderNotificationTemplate replaces the placeholders of a template with the rank change values
+func RenderNotificationTemplate(template string, change *RankChange) string {
+	player := change.Player
+
+	rank := "UNRANKED"
+	if player.Tier != "UNRANKED" {
+		rank = fmt.Sprintf("%s %s %d LP", player.Tier, player.Rank, player.LeaguePoints)
+	}
+
+	champion, kda := "Unknown", "?/?/?"
+	if change.Match != nil {
+		champion = change.Match.Champion
+		kda = change.Match.KDAString()
+	}
+
+	replacer := strings.NewReplacer(
+		"{player}", player.DisplayName(),
+		"{lp_delta}", fmt.Sprintf("%+d", change.LPDelta()),
+		"{rank}", rank,
+		"{champion}", champion,
+		"{kda}", kda,
+	)
+	return replacer.Replace(template)
+}
+
+func isPlaceholder(name string, placeholders []string) bool {
+	for _, placeholder := range placeholders {
+		if placeholder == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PatchStats holds the games and wins of a player on a patch
+type PatchStats struct {
+	Patch string `bson:"_id" json:"patch"`
+	Games int    `bson:"games" json:"games"`
+	Wins  int    `bson:"wins" json:"wins"`
+}
+
+// WinRate returns the win rate on the patch in percent
+func (p *PatchStats) WinRate() float64 {
+	if p.Games == 0 {
+		return 0
+	}
+	return float64(p.Wins) / float64(p.Games) * 100
+}
+
+// ComparePatches orders patches numerically ("14.10" is after "14.9"),
+// it returns a negative number when a is older than b, 0 when equal, positive otherwise
+func ComparePatches(a, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for idx := 0; idx < len(partsA) && idx < len(partsB); idx++ {
+		numberA, _ := strconv.Atoi(partsA[idx])
+		numberB, _ := strconv.Atoi(partsB[idx])
+		if numberA != numberB {
+			return numberA - numberB
+		}
+	}
+	return len(partsA) - len(partsB)
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PendingActionKind identifies the "confirm then act" flow of a pending action
+type PendingActionKind string
+
+const (
+	PendingAccountVerification PendingActionKind = "account_verification"
+)
+
+const (
+	// PendingActionCheckInterval is the delay between two checks of a pending action
+	PendingActionCheckInterval = 1 * time.Minute
+
+	// PendingActionPromptInterval is the delay before each reminder sent by DM
+	PendingActionPromptInterval = 5 * time.Minute
+
+	// MaxPendingActionPrompts caps the reminders of an action, users are not spammed until it expires
+	MaxPendingActionPrompts = 2
+)
+
+// PendingAction is a step waiting for a user to confirm something outside of Discord (ex: an icon switch).
+// The poller checks it until it is confirmed, reminds the user by DM and drops it when it expires.
+// The state of the flow lives in its own document, referenced by RefID.
+type PendingAction struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Kind          PendingActionKind  `bson:"kind" json:"kind"`
+	DiscordUserID string             `bson:"discordUserId" json:"discordUserId"`
+	RefID         primitive.ObjectID `bson:"refId" json:"refId"` // Ex: the account verification
+
+	Prompts     int       `bson:"prompts" json:"prompts"` // Reminders sent by DM
+	NextCheckAt time.Time `bson:"nextCheckAt" json:"nextCheckAt"`
+	CreatedAt   time.Time `bson:"createdAt" json:"createdAt"`
+	ExpiresAt   time.Time `bson:"expiresAt" json:"expiresAt"`
+}
+
+// NewPendingAction creates an action checked from the next check interval on
+func NewPendingAction(kind PendingActionKind, discordUserID string, refID primitive.ObjectID, now, expiresAt time.Time) *PendingAction {
+	return &PendingAction{
+		Kind:          kind,
+		DiscordUserID: discordUserID,
+		RefID:         refID,
+		NextCheckAt:   now.Add(PendingActionCheckInterval),
+		CreatedAt:     now,
+		ExpiresAt:     expiresAt,
+	}
+}
+
+// IsExpired checks if the action can no longer be confirmed
+func (a *PendingAction) IsExpired(now time.Time) bool {
+	return !now.Before(a.ExpiresAt)
+}
+
+// ShouldPrompt checks if a reminder is due, one every PendingActionPromptInterval up to MaxPendingActionPrompts
+func (a *PendingAction) ShouldPrompt(now time.Time) bool {
+	if a.Prompts >= MaxPendingActionPrompts {
+		return false
+	}
+	return !now.Before(a.CreatedAt.Add(time.Duration(a.Prompts+1) * PendingActionPromptInterval))
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxPersonNameLength is the maximum length of a person name
+const MaxPersonNameLength = 32
+
+// Person groups the tracked accounts (main and smurfs) of a single human
+type Person struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name         string             `bson:"name" json:"name"` // Lower case (ex: "bob")
+	PlayerPUUIDs []string           `bson:"playerPuuids" json:"playerPuuids"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// PersonStanding is a row of the leaderboard by person, ranked by the best account
+type PersonStanding struct {
+	Person   *Person // Nil for an account not linked to a person
+	Best     *Player // Highest ranked account
+	Accounts []*Player
+}
+
+// Name returns the person name, or the Riot ID of an account not linked to a person
+func (s *PersonStanding) Name() string {
+	if s.Person == nil {
+		return s.Best.DisplayName()
+	}
+	return s.Person.Name
+}
+
+// NormalizePersonName lowercases a person name and checks its length
+func NormalizePersonName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", fmt.Errorf("person name cannot be empty")
+	}
+	if len(name) > MaxPersonNameLength {
+		return "", fmt.Errorf("person name cannot exceed %d characters", MaxPersonNameLength)
+	}
+	return name, nil
+}
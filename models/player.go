@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,7 +15,7 @@ type Player struct {
 	TagLine       string             `bson:"tagLine" json:"tagLine"`
 	Server        string             `bson:"server" json:"server"`
 	SummonerID    string             `bson:"summonerId" json:"summonerId"`
-	SummonerLevel int                `bson:"summonerLevel" json:"summonerLevel"`
+	SummonerLevel int64              `bson:"summonerLevel" json:"summonerLevel"`
 	ProfileIconID int                `bson:"profileIconId" json:"profileIconId"`
 
 	// Ranked information
@@ -23,7 +25,94 @@ type Player struct {
 	Wins         int    `bson:"wins" json:"wins"`
 	Losses       int    `bson:"losses" json:"losses"`
 
+	// Roster grouping (ex: "team-a", "friends")
+	Tags []string `bson:"tags,omitempty" json:"tags,omitempty"`
+
+	// Set by the admins (ex: alias "Bob", note "this is Bob's smurf")
+	Alias string `bson:"alias,omitempty" json:"alias,omitempty"`
+	Note  string `bson:"note,omitempty" json:"note,omitempty"`
+
+	// Poll checkpoint, a restarted poller resumes from the last seen game
+	LastMatchID  string     `bson:"lastMatchId,omitempty" json:"lastMatchId,omitempty"`
+	LastPolledAt *time.Time `bson:"lastPolledAt,omitempty" json:"lastPolledAt,omitempty"` // Last successful poll
+
+	// Opt-in: the player has a public profile page on the web dashboard
+	PublicProfile bool `bson:"publicProfile,omitempty" json:"publicProfile,omitempty"`
+
+	// Paused players are kept (rank, history) but no longer polled
+	Paused bool `bson:"paused,omitempty" json:"paused,omitempty"`
+
+	// Twitch channel (login) of the player, announced in the notification channels when it goes live
+	TwitchChannel string `bson:"twitchChannel,omitempty" json:"twitchChannel,omitempty"`
+	TwitchLive    bool   `bson:"twitchLive,omitempty" json:"twitchLive,omitempty"` // Stream state at the last check
+
+	// Spectator ID of the last live game seen, a game is only handled once
+	LiveGameID int64 `bson:"liveGameId,omitempty" json:"liveGameId,omitempty"`
+
+	// Opt-out of the "take a break" messages, and the last one sent (one per session)
+	TiltAlertsOptOut bool       `bson:"tiltAlertsOptOut,omitempty" json:"tiltAlertsOptOut,omitempty"`
+	LastTiltAlertAt  *time.Time `bson:"lastTiltAlertAt,omitempty" json:"lastTiltAlertAt,omitempty"`
+
+	// End of the last play session summarized, see SessionSummary
+	LastSessionSummaryAt *time.Time `bson:"lastSessionSummaryAt,omitempty" json:"lastSessionSummaryAt,omitempty"`
+
 	// Metadata
 	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
 	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
 }
+
+// WinRate returns the season win rate of the player in percent
+func (p *Player) WinRate() float64 {
+	games := p.Wins + p.Losses
+	if games == 0 {
+		return 0
+	}
+	return float64(p.Wins) / float64(games) * 100
+}
+
+// DisplayName returns the alias followed by the Riot ID (ex: "Bob (Faker#KR1)"), or the Riot ID without alias
+func (p *Player) DisplayName() string {
+	return displayName(p.Alias, p.GameName, p.TagLine)
+}
+
+func displayName(alias, gameName, tagLine string) string {
+	if alias == "" {
+		return gameName + "#" + tagLine
+	}
+	return fmt.Sprintf("%s (%s#%s)", alias, gameName, tagLine)
+}
+
+// Maximum lengths of the alias and the note of a player
+const (
+	MaxAliasLength = 32
+	MaxNoteLength  = 200
+)
+
+// InactivePlayer is a tracked player without ranked games since a date
+type InactivePlayer struct {
+	Player     *Player
+	LastGameAt *time.Time // Last stored ranked game, nil if none was stored
+}
+
+// MaxTagLength is the maximum length of a player tag
+const MaxTagLength = 32
+
+// NormalizeTag lowercases a tag and checks it only contains letters, digits, '-' and '_'
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", fmt.Errorf("tag cannot be empty")
+	}
+	if len(tag) > MaxTagLength {
+		return "", fmt.Errorf("tag cannot exceed %d characters", MaxTagLength)
+	}
+
+	for _, char := range tag {
+		isAllowed := (char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') || char == '-' || char == '_'
+		if !isAllowed {
+			return "", fmt.Errorf("tag can only contain letters, digits, '-' and '_'")
+		}
+	}
+
+	return tag, nil
+}
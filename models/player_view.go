@@ -0,0 +1,92 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Read models of a tracked player. Discord messages (and any future API) are built from these views
+// instead of the stored Player document, so that the Mongo schema can evolve without touching formatting.
+
+// PlayerSummary is the short view of a player used in lists and confirmations
+type PlayerSummary struct {
+	PUUID        string   `json:"puuid"`
+	GameName     string   `json:"gameName"`
+	TagLine      string   `json:"tagLine"`
+	Server       string   `json:"server"` // Upper case (ex: "EUW1")
+	Level        int64    `json:"level"`
+	Tier         string   `json:"tier"`
+	Rank         string   `json:"rank"`
+	LeaguePoints int      `json:"leaguePoints"`
+	Tags         []string `json:"tags,omitempty"`
+	Alias        string   `json:"alias,omitempty"`
+	Note         string   `json:"note,omitempty"`
+}
+
+// PlayerDetail is the full view of a player with the season record and the tracking state
+type PlayerDetail struct {
+	PlayerSummary
+	Wins         int        `json:"wins"`
+	Losses       int        `json:"losses"`
+	WinRate      float64    `json:"winRate"` // Percent
+	LastPolledAt *time.Time `json:"lastPolledAt,omitempty"`
+	TrackedSince time.Time  `json:"trackedSince"`
+}
+
+// NewPlayerSummary maps a stored player to its summary view
+func NewPlayerSummary(player *Player) PlayerSummary {
+	return PlayerSummary{
+		PUUID:        player.PUUID,
+		GameName:     player.GameName,
+		TagLine:      player.TagLine,
+		Server:       strings.ToUpper(player.Server),
+		Level:        player.SummonerLevel,
+		Tier:         player.Tier,
+		Rank:         player.Rank,
+		LeaguePoints: player.LeaguePoints,
+		Tags:         player.Tags,
+		Alias:        player.Alias,
+		Note:         player.Note,
+	}
+}
+
+// NewPlayerSummaries maps a list of stored players to their summary views
+func NewPlayerSummaries(players []*Player) []PlayerSummary {
+	summaries := make([]PlayerSummary, 0, len(players))
+	for _, player := range players {
+		summaries = append(summaries, NewPlayerSummary(player))
+	}
+	return summaries
+}
+
+// NewPlayerDetail maps a stored player to its detailed view
+func NewPlayerDetail(player *Player) PlayerDetail {
+	return PlayerDetail{
+		PlayerSummary: NewPlayerSummary(player),
+		Wins:          player.Wins,
+		Losses:        player.Losses,
+		WinRate:       player.WinRate(),
+		LastPolledAt:  player.LastPolledAt,
+		TrackedSince:  player.CreatedAt,
+	}
+}
+
+// RiotID returns the player's Riot ID (ex: "Faker#KR1")
+func (s PlayerSummary) RiotID() string {
+	return s.GameName + "#" + s.TagLine
+}
+
+// DisplayName returns the alias followed by the Riot ID, or the Riot ID without alias
+func (s PlayerSummary) DisplayName() string {
+	return displayName(s.Alias, s.GameName, s.TagLine)
+}
+
+// IsRanked checks if the player has a solo queue rank
+func (s PlayerSummary) IsRanked() bool {
+	return s.Tier != "" && s.Tier != "UNRANKED"
+}
+
+// RankLabel returns the displayed rank (ex: "GOLD II", "MASTER")
+func (s PlayerSummary) RankLabel() string {
+	return FormatRank(s.Tier, s.Rank)
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Minimum number of games needed for a projection to be meaningful
+	minProjectionGames = 5
+
+	// Projections are capped to keep dates readable (and durations from overflowing)
+	maxProjectionDays = 10 * 365
+)
+
+// ClimbProjection estimates when a player will reach a target rank at their current pace
+type ClimbProjection struct {
+	TargetTier string
+	TargetRank string
+	LPNeeded   int
+
+	// Expected games and date, with a 95% confidence band on the win rate
+	Games        int
+	ETA          time.Time
+	EarliestETA  time.Time
+	LatestETA    time.Time
+	LatestIsOpen bool // The pessimistic bound does not climb, the target may never be reached
+}
+
+// ProjectClimb estimates when a player will reach the target rank from their LP stats.
+// It returns nil if the target is already reached, there is not enough data or the player is not climbing.
+func ProjectClimb(stats *LPStats, player *Player, targetTier, targetRank string, now time.Time) *ClimbProjection {
+	needed := RankValue(targetTier, targetRank, 0) - RankValue(player.Tier, player.Rank, player.LeaguePoints)
+	if needed <= 0 || stats.Games() < minProjectionGames || stats.Wins == 0 || stats.Losses == 0 {
+		return nil
+	}
+
+	winRate := float64(stats.Wins) / float64(stats.Games())
+	expected := lpPerGame(stats, winRate)
+	if expected <= 0 {
+		return nil
+	}
+
+	// Normal approximation of the win rate uncertainty
+	margin := 1.96 * math.Sqrt(winRate*(1-winRate)/float64(stats.Games()))
+	optimistic := lpPerGame(stats, math.Min(winRate+margin, 1))
+	pessimistic := lpPerGame(stats, math.Max(winRate-margin, 0))
+
+	gamesPerDay := stats.GamesPerDay()
+	games := math.Ceil(float64(needed) / expected)
+
+	projection := &ClimbProjection{
+		TargetTier:  targetTier,
+		TargetRank:  targetRank,
+		LPNeeded:    needed,
+		Games:       int(games),
+		ETA:         projectDate(now, games, gamesPerDay),
+		EarliestETA: projectDate(now, math.Ceil(float64(needed)/optimistic), gamesPerDay),
+	}
+
+	if pessimistic > 0 {
+		projection.LatestETA = projectDate(now, math.Ceil(float64(needed)/pessimistic), gamesPerDay)
+	} else {
+		projection.LatestIsOpen = true
+	}
+
+	return projection
+}
+
+// lpPerGame returns the average LP swing of a game at the given win rate (0 to 1)
+func lpPerGame(stats *LPStats, winRate float64) float64 {
+	return winRate*stats.AverageGain() - (1-winRate)*stats.AverageLoss()
+}
+
+func projectDate(now time.Time, games, gamesPerDay float64) time.Time {
+	days := math.Min(games/gamesPerDay, maxProjectionDays)
+	return now.Add(time.Duration(days * 24 * float64(time.Hour)))
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"net/url"
+	"time"
+)
+
+// PublicProfile is the content of the public profile page of a player
+type PublicProfile struct {
+	PlayerDetail
+	LPHistory []LPPoint          // Oldest first
+	Champions []*ChampionStats   // Most played first
+	Matches   []*MatchPlayerInfo // Newest first
+}
+
+// LPPoint is the rank of a player after a tracked game, as a RankValue
+type LPPoint struct {
+	At    time.Time
+	Value int
+}
+
+// PublicPerson is the content of the public page of a person, only the public accounts are listed
+type PublicPerson struct {
+	Name     string
+	Accounts []PlayerSummary // Best first
+}
+
+// PublicProfilePath returns the path of the public profile page of a player (ex: "/players/euw1/Faker/KR1")
+func PublicProfilePath(server, gameName, tagLine string) string {
+	return "/players/" + url.PathEscape(server) + "/" + url.PathEscape(gameName) + "/" + url.PathEscape(tagLine)
+}
+
+// PublicPersonPath returns the path of the public page of a person
+func PublicPersonPath(name string) string {
+	return "/persons/" + url.PathEscape(name)
+}
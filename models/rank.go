@@ -0,0 +1,163 @@
+package models
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tiers ordered from lowest to highest
+var tiers = []string{
+	"IRON",
+	"BRONZE",
+	"SILVER",
+	"GOLD",
+	"PLATINUM",
+	"EMERALD",
+	"DIAMOND",
+	"MASTER",
+	"GRANDMASTER",
+	"CHALLENGER",
+}
+
+// Divisions ordered from lowest to highest
+var divisions = []string{"IV", "III", "II", "I"}
+
+// RankValue converts a tier/division/LP triple into a single comparable number.
+// Each division is worth 100 LP. Master and above share a single LP ladder.
+func RankValue(tier, rank string, leaguePoints int) int {
+	tierIndex := -1
+	for idx, t := range tiers {
+		if t == tier {
+			tierIndex = idx
+			break
+		}
+	}
+	if tierIndex < 0 {
+		return 0 // Unranked
+	}
+
+	masterIndex := 7
+	if tierIndex >= masterIndex {
+		return 1 + masterIndex*400 + leaguePoints
+	}
+
+	divisionIndex := 0
+	for idx, d := range divisions {
+		if d == rank {
+			divisionIndex = idx
+			break
+		}
+	}
+
+	return 1 + tierIndex*400 + divisionIndex*100 + leaguePoints
+}
+
+// CompareRanks orders two players by tier, then division, then LP: negative when a ranks higher than b.
+// Unranked players come last, the ties are ordered by Riot ID so the order doesn't depend on the storage.
+func CompareRanks(a, b *Player) int {
+	valueA := RankValue(a.Tier, a.Rank, a.LeaguePoints)
+	valueB := RankValue(b.Tier, b.Rank, b.LeaguePoints)
+	if valueA != valueB {
+		return valueB - valueA
+	}
+	return strings.Compare(strings.ToLower(a.GameName+"#"+a.TagLine), strings.ToLower(b.GameName+"#"+b.TagLine))
+}
+
+// RankChange represents the difference between two rank states of a player
+type RankChange struct {
+	Player *Player
+
+	// Rank information before the update
+	PreviousTier         string
+	PreviousRank         string
+	PreviousLeaguePoints int
+
+	// Match that caused the change, nil when it could not be resolved
+	Match *MatchPlayerInfo
+
+	// Ledger entry of the change, zero when it could not be recorded
+	EventID primitive.ObjectID
+}
+
+// LPDelta returns the LP gained (positive) or lost (negative), accounting for division changes
+func (c *RankChange) LPDelta() int {
+	if c.PreviousTier == "UNRANKED" || c.Player.Tier == "UNRANKED" {
+		return 0
+	}
+	previous := RankValue(c.PreviousTier, c.PreviousRank, c.PreviousLeaguePoints)
+	current := RankValue(c.Player.Tier, c.Player.Rank, c.Player.LeaguePoints)
+	return current - previous
+}
+
+// DivisionChanged checks if the player moved to another tier or division
+func (c *RankChange) DivisionChanged() bool {
+	return c.PreviousTier != c.Player.Tier || c.PreviousRank != c.Player.Rank
+}
+
+// IsPromotion checks if the player reached a higher tier or division
+func (c *RankChange) IsPromotion() bool {
+	return c.DivisionChanged() && c.LPDelta() > 0
+}
+
+// IsDemotion checks if the player dropped to a lower tier or division
+func (c *RankChange) IsDemotion() bool {
+	return c.DivisionChanged() && c.LPDelta() < 0
+}
+
+// RankFromValue converts a value computed by RankValue back into a tier, division and LP
+func RankFromValue(value int) (tier, rank string, leaguePoints int) {
+	if value <= 0 {
+		return "UNRANKED", "", 0
+	}
+
+	value--
+	masterIndex := 7
+	if value >= masterIndex*400 {
+		return tiers[masterIndex], "I", value - masterIndex*400
+	}
+
+	tierIndex := value / 400
+	divisionIndex := (value % 400) / 100
+	return tiers[tierIndex], divisions[divisionIndex], value % 100
+}
+
+// LPToNextDivision returns the LP missing to reach the next division.
+// It returns false for unranked players and apex tiers which have no divisions.
+func LPToNextDivision(tier, rank string, leaguePoints int) (int, bool) {
+	if RankValue(tier, rank, leaguePoints) == 0 {
+		return 0, false
+	}
+	for _, apex := range tiers[7:] {
+		if tier == apex {
+			return 0, false
+		}
+	}
+
+	needed := 100 - leaguePoints
+	if needed <= 0 {
+		needed = 1 // Promotion is decided by the next win
+	}
+	return needed, true
+}
+
+// NextDivision returns the division above the given one, Master being the last reachable step.
+// It returns false for unranked players and apex tiers.
+func NextDivision(tier, rank string) (string, string, bool) {
+	if _, ok := LPToNextDivision(tier, rank, 0); !ok {
+		return "", "", false
+	}
+
+	nextTier, nextRank, _ := RankFromValue(RankValue(tier, rank, 0) + 100)
+	return nextTier, nextRank, true
+}
+
+// FormatRank returns the display name of a tier and division, apex tiers have no division
+func FormatRank(tier, rank string) string {
+	for _, apex := range tiers[7:] {
+		if tier == apex {
+			return tier
+		}
+	}
+	return tier + " " + rank
+}
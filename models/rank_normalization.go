@@ -0,0 +1,70 @@
+package models
+
+import "sort"
+
+// ReferenceServer is the region the normalized ranks are expressed in
+const ReferenceServer = "euw1"
+
+// Approximate difficulty of the regions in LP compared to EUW, from the rank distributions of the
+// ladders: a Diamond IV on KR is worth about a Diamond III on EUW. Unknown regions are not adjusted.
+var regionRankOffsets = map[string]int{
+	"kr":   100,
+	"euw1": 0,
+	"eun1": -50,
+	"na1":  -50,
+	"jp1":  -50,
+	"br1":  -100,
+	"la1":  -100,
+	"la2":  -100,
+	"oc1":  -100,
+	"tr1":  -100,
+	"ru":   -150,
+}
+
+// NormalizedRankValue converts a rank of a region into the RankValue scale of the reference
+// server, so that accounts of different regions can be compared. Unranked stays 0.
+func NormalizedRankValue(server, tier, rank string, leaguePoints int) int {
+	value := RankValue(tier, rank, leaguePoints)
+	if value == 0 {
+		return 0
+	}
+
+	value += regionRankOffsets[server]
+	if value < 1 {
+		return 1
+	}
+	return value
+}
+
+// AccountComparison is an account of a person with its rank normalized to the reference server
+type AccountComparison struct {
+	Player          *Player
+	NormalizedValue int
+	GapToBest       int // LP behind the best account once normalized
+}
+
+// NormalizedRank returns the equivalent rank on the reference server (ex: "DIAMOND III", 40)
+func (c *AccountComparison) NormalizedRank() (string, int) {
+	tier, rank, leaguePoints := RankFromValue(c.NormalizedValue)
+	return FormatRank(tier, rank), leaguePoints
+}
+
+// CompareAccounts normalizes the ranks of accounts on different servers, best account first
+func CompareAccounts(players []*Player) []*AccountComparison {
+	comparisons := make([]*AccountComparison, 0, len(players))
+	for _, player := range players {
+		comparisons = append(comparisons, &AccountComparison{
+			Player:          player,
+			NormalizedValue: NormalizedRankValue(player.Server, player.Tier, player.Rank, player.LeaguePoints),
+		})
+	}
+
+	sort.SliceStable(comparisons, func(a, b int) bool {
+		return comparisons[a].NormalizedValue > comparisons[b].NormalizedValue
+	})
+
+	for _, comparison := range comparisons {
+		comparison.GapToBest = comparisons[0].NormalizedValue - comparison.NormalizedValue
+	}
+	return comparisons
+}
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecapPeriod is the time span covered by a recap
+type RecapPeriod string
+
+const (
+	RecapPeriodDaily  RecapPeriod = "daily"
+	RecapPeriodWeekly RecapPeriod = "weekly"
+)
+
+// Recaps are dispatched at this hour (guild local time), weekly ones on RecapWeeklyDay
+const (
+	RecapDispatchHour = 21
+	RecapWeeklyDay    = time.Sunday
+)
+
+// Recap summarizes the ranked activity of tracked players over a period
+type Recap struct {
+	Period  RecapPeriod
+	Start   time.Time
+	End     time.Time
+	Entries []*RecapEntry // Sorted by LP delta, best first
+
+	// Notable challenge levels reached during the period, oldest first (weekly recaps only)
+	ChallengeCompletions []*ChallengeCompletion
+}
+
+// RecapEntry is the activity of a single player in a recap
+type RecapEntry struct {
+	PlayerPUUID  string
+	GameName     string
+	TagLine      string
+	Tier         string
+	Rank         string
+	LeaguePoints int
+	LPDelta      int
+	Wins         int
+	Losses       int
+
+	// Set when the entry aggregates the accounts of a person, the rank is the one of the best account
+	PersonName string
+	Accounts   int
+
+	// Role drift: games played away from the main role during the period
+	MainRole     string
+	RoleGames    int // Games with a known role
+	OffRoleGames int
+
+	// Non-KDA contributions over the tracked games of the period
+	MatchGames      int
+	VisionScore     int
+	DragonTakedowns int
+	BaronTakedowns  int
+	HeraldTakedowns int
+	TurretTakedowns int
+
+	// Per-champion results over the tracked games of the period
+	Champions map[string]*ChampionStats
+}
+
+// ChampionStats aggregates the games of a player on a champion
+type ChampionStats struct {
+	Champion string
+	Games    int
+	Wins     int
+}
+
+// WinRate returns the win rate on the champion in percent
+func (c *ChampionStats) WinRate() float64 {
+	if c.Games == 0 {
+		return 0
+	}
+	return float64(c.Wins) / float64(c.Games) * 100
+}
+
+const (
+	// Role drift is reported when at least half of the games (and minRoleDriftGames) are off-role
+	minRoleDriftGames = 3
+
+	// Champion recommendations need enough games on the champion to avoid noise
+	minChampionRecommendationGames = 3
+	minBestChampionWinRate         = 60.0
+	maxWorstChampionWinRate        = 40.0
+)
+
+// DisplayName returns the person name (with the number of accounts) or the Riot ID of the player
+func (e *RecapEntry) DisplayName() string {
+	if e.PersonName == "" {
+		return e.GameName + "#" + e.TagLine
+	}
+	if e.Accounts > 1 {
+		return fmt.Sprintf("%s (%d accounts)", e.PersonName, e.Accounts)
+	}
+	return e.PersonName
+}
+
+// Games returns the number of games played during the recap period
+func (e *RecapEntry) Games() int {
+	return e.Wins + e.Losses
+}
+
+// IsRoleDrifting checks if the player was often autofilled away from the main role
+func (e *RecapEntry) IsRoleDrifting() bool {
+	return e.MainRole != "" && e.RoleGames >= minRoleDriftGames && e.OffRoleGames*2 >= e.RoleGames
+}
+
+// AverageVisionScore returns the vision score per tracked game
+func (e *RecapEntry) AverageVisionScore() float64 {
+	if e.MatchGames == 0 {
+		return 0
+	}
+	return float64(e.VisionScore) / float64(e.MatchGames)
+}
+
+// ObjectiveTakedowns returns the epic monsters (dragons, barons, heralds) taken down
+func (e *RecapEntry) ObjectiveTakedowns() int {
+	return e.DragonTakedowns + e.BaronTakedowns + e.HeraldTakedowns
+}
+
+// BestChampion returns the champion with the highest win rate of the period, nil if none
+// has enough games and a good enough win rate
+func (e *RecapEntry) BestChampion() *ChampionStats {
+	var best *ChampionStats
+	for _, stats := range e.Champions {
+		if stats.Games < minChampionRecommendationGames || stats.WinRate() < minBestChampionWinRate {
+			continue
+		}
+		if best == nil || stats.WinRate() > best.WinRate() ||
+			(stats.WinRate() == best.WinRate() && stats.Games > best.Games) {
+			best = stats
+		}
+	}
+	return best
+}
+
+// WorstChampion returns the champion with the lowest win rate of the period, nil if none
+// has enough games and a bad enough win rate
+func (e *RecapEntry) WorstChampion() *ChampionStats {
+	var worst *ChampionStats
+	for _, stats := range e.Champions {
+		if stats.Games < minChampionRecommendationGames || stats.WinRate() > maxWorstChampionWinRate {
+			continue
+		}
+		if worst == nil || stats.WinRate() < worst.WinRate() ||
+			(stats.WinRate() == worst.WinRate() && stats.Games > worst.Games) {
+			worst = stats
+		}
+	}
+	return worst
+}
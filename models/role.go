@@ -0,0 +1,64 @@
+package models
+
+// Team positions as reported by Match-V5
+const (
+	RoleTop     = "TOP"
+	RoleJungle  = "JUNGLE"
+	RoleMiddle  = "MIDDLE"
+	RoleBottom  = "BOTTOM"
+	RoleUtility = "UTILITY"
+)
+
+// Roles lists the team positions in display order
+var Roles = []string{RoleTop, RoleJungle, RoleMiddle, RoleBottom, RoleUtility}
+
+// RoleName returns a human readable role name (ex: "UTILITY" -> "Support")
+func RoleName(role string) string {
+	switch role {
+	case RoleTop:
+		return "Top"
+	case RoleJungle:
+		return "Jungle"
+	case RoleMiddle:
+		return "Mid"
+	case RoleBottom:
+		return "ADC"
+	case RoleUtility:
+		return "Support"
+	default:
+		return "Unknown"
+	}
+}
+
+// RoleEmoji returns the emoji used to display a role
+func RoleEmoji(role string) string {
+	switch role {
+	case RoleTop:
+		return "🛡️"
+	case RoleJungle:
+		return "🌲"
+	case RoleMiddle:
+		return "🔮"
+	case RoleBottom:
+		return "🏹"
+	case RoleUtility:
+		return "💚"
+	default:
+		return "❔"
+	}
+}
+
+// RoleStats aggregates the games of a player on a role
+type RoleStats struct {
+	Role  string `bson:"_id" json:"role"`
+	Games int    `bson:"games" json:"games"`
+	Wins  int    `bson:"wins" json:"wins"`
+}
+
+// WinRate returns the win rate on the role in percent
+func (r *RoleStats) WinRate() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+	return float64(r.Wins) / float64(r.Games) * 100
+}
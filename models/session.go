@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// SessionGap is the idle time between two games that ends a play session
+const SessionGap = 90 * time.Minute
+
+// MinSessionSummaryGames is the smallest session summarized, a single game is already announced
+const MinSessionSummaryGames = 2
+
+// PlaySession is a run of games played without a break longer than SessionGap
+type PlaySession struct {
+	Matches []*MatchPlayerInfo // Oldest first
+}
+
+// SplitSessions groups the matches (oldest first) into play sessions. The gap is measured from the end
+// of a game to the start of the next one.
+func SplitSessions(matches []*MatchPlayerInfo, gap time.Duration) []*PlaySession {
+	var sessions []*PlaySession
+	var current *PlaySession
+	for _, match := range matches {
+		if current == nil || match.CreatedAt.Sub(current.End()) > gap {
+			current = &PlaySession{}
+			sessions = append(sessions, current)
+		}
+		current.Matches = append(current.Matches, match)
+	}
+	return sessions
+}
+
+// Start returns the start time of the first game
+func (s *PlaySession) Start() time.Time {
+	return s.Matches[0].CreatedAt
+}
+
+// End returns the end time of the last game
+func (s *PlaySession) End() time.Time {
+	last := s.Matches[len(s.Matches)-1]
+	return last.CreatedAt.Add(time.Duration(last.GameDuration) * time.Second)
+}
+
+// Wins returns the number of games won in the session
+func (s *PlaySession) Wins() int {
+	wins := 0
+	for _, match := range s.Matches {
+		if match.Victory {
+			wins++
+		}
+	}
+	return wins
+}
+
+// Losses returns the number of games lost in the session
+func (s *PlaySession) Losses() int {
+	return len(s.Matches) - s.Wins()
+}
+
+// IsOver checks if no game can be added to the session anymore
+func (s *PlaySession) IsOver(now time.Time) bool {
+	return now.Sub(s.End()) >= SessionGap
+}
+
+// SessionSummary is the outcome of an ended play session
+type SessionSummary struct {
+	Player   *Player
+	Session  *PlaySession
+	LPDelta  int              // Sum of the LP changes of the games, from the ledger
+	BestGame *MatchPlayerInfo // Best KDA among the wins, among all the games without a win
+}
+
+// NewSessionSummary summarizes a session, lpDeltas maps the match IDs to their LP change
+func NewSessionSummary(player *Player, session *PlaySession, lpDeltas map[string]int) *SessionSummary {
+	summary := &SessionSummary{Player: player, Session: session}
+	for _, match := range session.Matches {
+		summary.LPDelta += lpDeltas[match.MatchID]
+
+		best := summary.BestGame
+		if best == nil || (match.Victory && !best.Victory) || (match.Victory == best.Victory && match.KDA() > best.KDA()) {
+			summary.BestGame = match
+		}
+	}
+	return summary
+}
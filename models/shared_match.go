@@ -0,0 +1,54 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Match holds the data of a game shared by all its participants, stored once per game
+// whatever the number of tracked players in it (per player statistics are in MatchPlayerInfo)
+type Match struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MatchID      string             `bson:"matchId" json:"matchId"` // Riot Match ID
+	QueueID      int                `bson:"queueId" json:"queueId"`
+	QueueType    string             `bson:"queueType" json:"queueType"`
+	GameVersion  string             `bson:"gameVersion" json:"gameVersion"`   // ex: "14.5.567.1234"
+	Patch        string             `bson:"patch" json:"patch"`               // ex: "14.5"
+	GameDuration int64              `bson:"gameDuration" json:"gameDuration"` // Seconds
+	Teams        []MatchTeam        `bson:"teams" json:"teams"`
+	PlayedAt     time.Time          `bson:"playedAt" json:"playedAt"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// MatchTeam is one of the two teams of a game
+type MatchTeam struct {
+	TeamID  int   `bson:"teamId" json:"teamId"` // 100 (blue side) or 200 (red side)
+	Victory bool  `bson:"victory" json:"victory"`
+	Bans    []int `bson:"bans" json:"bans"` // Champion IDs, in pick order
+
+	// Only stored when enabled in the match ingestion options
+	Participants []MatchParticipant `bson:"participants,omitempty" json:"participants,omitempty"`
+}
+
+// MatchParticipant is the summary of a participant of a game
+type MatchParticipant struct {
+	PUUID    string `bson:"puuid" json:"puuid"`
+	GameName string `bson:"gameName" json:"gameName"`
+	Champion string `bson:"champion" json:"champion"`
+	Role     string `bson:"role" json:"role"`
+	Victory  bool   `bson:"victory" json:"victory"`
+	Kills    int    `bson:"kills" json:"kills"`
+	Deaths   int    `bson:"deaths" json:"deaths"`
+	Assists  int    `bson:"assists" json:"assists"`
+}
+
+// PatchFromVersion extracts the patch (major.minor) of a game version
+func PatchFromVersion(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxSnapshotNameLength is the maximum length of a snapshot name
+const MaxSnapshotNameLength = 50
+
+// LeaderboardSnapshot is a frozen copy of the leaderboard, used as a reference for competitions
+type LeaderboardSnapshot struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GuildID   string             `bson:"guildId" json:"guildId"`
+	Name      string             `bson:"name" json:"name"`
+	Entries   []SnapshotEntry    `bson:"entries" json:"entries"`
+	CreatedBy string             `bson:"createdBy" json:"createdBy"` // Discord user ID
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// SnapshotEntry is the state of a player when the snapshot was taken
+type SnapshotEntry struct {
+	Position     int    `bson:"position" json:"position"`
+	PUUID        string `bson:"puuid" json:"puuid"`
+	GameName     string `bson:"gameName" json:"gameName"`
+	TagLine      string `bson:"tagLine" json:"tagLine"`
+	Server       string `bson:"server" json:"server"`
+	Tier         string `bson:"tier" json:"tier"`
+	Rank         string `bson:"rank" json:"rank"`
+	LeaguePoints int    `bson:"leaguePoints" json:"leaguePoints"`
+	Wins         int    `bson:"wins" json:"wins"`
+	Losses       int    `bson:"losses" json:"losses"`
+}
+
+// SnapshotDiff compares the current state of a player with a snapshot
+type SnapshotDiff struct {
+	Player           *Player
+	Position         int
+	PreviousPosition int // 0 when the player was not in the snapshot
+	LPDelta          int
+	GamesPlayed      int
+}
+
+// IsNew checks if the player was added after the snapshot
+func (d *SnapshotDiff) IsNew() bool {
+	return d.PreviousPosition == 0
+}
+
+// PositionChange returns the number of places gained (positive) or lost (negative)
+func (d *SnapshotDiff) PositionChange() int {
+	if d.IsNew() {
+		return 0
+	}
+	return d.PreviousPosition - d.Position
+}
+
+// NormalizeSnapshotName lowercases a snapshot name and checks its length
+func NormalizeSnapshotName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", fmt.Errorf("snapshot name cannot be empty")
+	}
+	if len(name) > MaxSnapshotNameLength {
+		return "", fmt.Errorf("snapshot name cannot exceed %d characters", MaxSnapshotNameLength)
+	}
+	return name, nil
+}
+
+// CycleSnapshot is the leaderboard at the end of a poll cycle. The leaderboards show the ranks of the
+// last completed cycle, so they never mix players already updated by a running cycle with the others.
+type CycleSnapshot struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Cycle       int64              `bson:"cycle" json:"cycle"` // Increases with every completed cycle
+	Entries     []SnapshotEntry    `bson:"entries" json:"entries"`
+	CompletedAt time.Time          `bson:"completedAt" json:"completedAt"`
+}
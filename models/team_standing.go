@@ -0,0 +1,35 @@
+package models
+
+import "fmt"
+
+// TeamStanding aggregates the players sharing a tag
+type TeamStanding struct {
+	Tag              string
+	Players          int
+	RankedPlayers    int
+	AverageRankValue int // Average of RankValue over ranked players
+	WeeklyLPDelta    int // LP gained by all players over the last 7 days
+	Wins             int
+	Losses           int
+}
+
+// WinRate returns the combined season win rate of the team in percent
+func (t *TeamStanding) WinRate() float64 {
+	games := t.Wins + t.Losses
+	if games == 0 {
+		return 0
+	}
+	return float64(t.Wins) / float64(games) * 100
+}
+
+// AverageRankString returns the average rank formatted (ex: "GOLD II 50 LP")
+func (t *TeamStanding) AverageRankString() string {
+	tier, rank, leaguePoints := RankFromValue(t.AverageRankValue)
+	if tier == "UNRANKED" {
+		return "Unranked"
+	}
+	if tier == "MASTER" {
+		return fmt.Sprintf("MASTER+ %d LP", leaguePoints)
+	}
+	return fmt.Sprintf("%s %s %d LP", tier, rank, leaguePoints)
+}
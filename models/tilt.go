@@ -0,0 +1,97 @@
+package models
+
+import "time"
+
+const (
+	// TiltLosses losses within TiltWindow start a tilt
+	TiltLosses = 3
+	TiltWindow = 2 * time.Hour
+
+	// Share of the usual performance under which a metric counts as dropping
+	tiltDropRatio = 0.8
+)
+
+// PerformanceMetrics are the averages used to compare the games of a session with the usual level of a player
+type PerformanceMetrics struct {
+	KDA               float64
+	CSPerMinute       float64
+	KillParticipation float64
+}
+
+// AveragePerformance returns the average metrics of the games, zero without games
+func AveragePerformance(matches []*MatchPlayerInfo) PerformanceMetrics {
+	var metrics PerformanceMetrics
+	if len(matches) == 0 {
+		return metrics
+	}
+	for _, match := range matches {
+		metrics.KDA += match.KDA()
+		metrics.CSPerMinute += match.CSPerMinute
+		metrics.KillParticipation += match.KillParticipation
+	}
+	games := float64(len(matches))
+	metrics.KDA /= games
+	metrics.CSPerMinute /= games
+	metrics.KillParticipation /= games
+	return metrics
+}
+
+// DroppingMetrics returns the number of metrics below tiltDropRatio of the baseline
+func (m PerformanceMetrics) DroppingMetrics(baseline PerformanceMetrics) int {
+	dropping := 0
+	for _, pair := range [][2]float64{
+		{m.KDA, baseline.KDA},
+		{m.CSPerMinute, baseline.CSPerMinute},
+		{m.KillParticipation, baseline.KillParticipation},
+	} {
+		if pair[1] > 0 && pair[0] < pair[1]*tiltDropRatio {
+			dropping++
+		}
+	}
+	return dropping
+}
+
+// TiltAlert is a tilt session detected for a player: TiltLosses losses or more within TiltWindow,
+// played below their usual level
+type TiltAlert struct {
+	Player  *Player
+	Session *PlaySession
+	Losses  int // Losses within the window
+	Recent  PerformanceMetrics
+	Usual   PerformanceMetrics
+}
+
+// DetectTilt checks the latest games of a session against the usual performance of the player.
+// It requires TiltLosses losses within TiltWindow before the last game and at least two metrics dropping.
+func DetectTilt(player *Player, session *PlaySession, usual PerformanceMetrics) *TiltAlert {
+	last := session.Matches[len(session.Matches)-1]
+	if last.Victory {
+		return nil
+	}
+
+	var window, losses []*MatchPlayerInfo
+	for _, match := range session.Matches {
+		if last.CreatedAt.Sub(match.CreatedAt) <= TiltWindow {
+			window = append(window, match)
+			if !match.Victory {
+				losses = append(losses, match)
+			}
+		}
+	}
+	if len(losses) < TiltLosses {
+		return nil
+	}
+
+	recent := AveragePerformance(window)
+	if recent.DroppingMetrics(usual) < 2 {
+		return nil
+	}
+
+	return &TiltAlert{
+		Player:  player,
+		Session: session,
+		Losses:  len(losses),
+		Recent:  recent,
+		Usual:   usual,
+	}
+}
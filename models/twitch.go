@@ -0,0 +1,29 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// twitchLoginPattern matches the Twitch logins (4 to 25 letters, digits or underscores)
+var twitchLoginPattern = regexp.MustCompile(`^[a-z0-9_]{4,25}$`)
+
+// NormalizeTwitchChannel extracts the lower case login from a Twitch channel name or URL (ex: "https://twitch.tv/Foo" -> "foo")
+func NormalizeTwitchChannel(channel string) (string, error) {
+	login := strings.ToLower(strings.TrimSpace(channel))
+	for _, prefix := range []string{"https://", "http://", "www.", "twitch.tv/"} {
+		login = strings.TrimPrefix(login, prefix)
+	}
+	login = strings.TrimSuffix(login, "/")
+
+	if !twitchLoginPattern.MatchString(login) {
+		return "", fmt.Errorf("%q is not a valid Twitch channel", channel)
+	}
+	return login, nil
+}
+
+// TwitchChannelURL returns the link to a Twitch channel
+func TwitchChannelURL(login string) string {
+	return "https://twitch.tv/" + login
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"math/rand"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// VerificationStatus is the state of an account verification: pending -> verified, or pending -> expired
+type VerificationStatus string
+
+const (
+	VerificationPending  VerificationStatus = "pending"
+	VerificationVerified VerificationStatus = "verified"
+	VerificationExpired  VerificationStatus = "expired"
+)
+
+// VerificationTTL is the time given to switch the profile icon, long enough to open the client
+const VerificationTTL = 15 * time.Minute
+
+// The profile icons 0 to 28 are owned by every account, so any player can switch to them
+const verificationIconCount = 29
+
+// AccountVerification proves that a Discord user owns a Riot account: the user is asked to switch to a
+// given profile icon, which only the owner of the account can do
+type AccountVerification struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	DiscordUserID string             `bson:"discordUserId" json:"discordUserId"`
+	PlayerPUUID   string             `bson:"playerPuuid" json:"playerPuuid"`
+	GameName      string             `bson:"gameName" json:"gameName"`
+	TagLine       string             `bson:"tagLine" json:"tagLine"`
+	Server        string             `bson:"server" json:"server"`
+
+	IconID         int `bson:"iconId" json:"iconId"`                 // Icon to switch to
+	PreviousIconID int `bson:"previousIconId" json:"previousIconId"` // Icon when the verification started, to switch back
+
+	Status     VerificationStatus `bson:"status" json:"status"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	ExpiresAt  time.Time          `bson:"expiresAt" json:"expiresAt"`
+	VerifiedAt *time.Time         `bson:"verifiedAt,omitempty" json:"verifiedAt,omitempty"`
+}
+
+// NewAccountVerification starts the verification of a player by a Discord user, the icon to switch to
+// is picked at random among the default icons, different from the current one
+func NewAccountVerification(discordUserID string, player *Player, currentIconID int, now time.Time) *AccountVerification {
+	iconID := rand.Intn(verificationIconCount - 1)
+	if iconID >= currentIconID && currentIconID < verificationIconCount {
+		iconID++
+	}
+
+	return &AccountVerification{
+		DiscordUserID:  discordUserID,
+		PlayerPUUID:    player.PUUID,
+		GameName:       player.GameName,
+		TagLine:        player.TagLine,
+		Server:         player.Server,
+		IconID:         iconID,
+		PreviousIconID: currentIconID,
+		Status:         VerificationPending,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(VerificationTTL),
+	}
+}
+
+// IsExpired checks if a pending verification can no longer be completed
+func (v *AccountVerification) IsExpired(now time.Time) bool {
+	return v.Status == VerificationPending && !now.Before(v.ExpiresAt)
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AchievementRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAchievementRepository(db *mongo.Database) *AchievementRepository {
+	return &AchievementRepository{
+		collection: db.Collection("player_achievements"),
+	}
+}
+
+// Create records an earned achievement.
+// It returns false without error if the player already earned it.
+func (r *AchievementRepository) Create(ctx context.Context, achievement *models.PlayerAchievement) (bool, error) {
+	if achievement.EarnedAt.IsZero() {
+		achievement.EarnedAt = time.Now()
+	}
+
+	result, err := r.collection.InsertOne(ctx, achievement)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create achievement: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		achievement.ID = oid
+	}
+
+	return true, nil
+}
+
+// FindByPlayer returns the achievements earned by a player, oldest first
+func (r *AchievementRepository) FindByPlayer(ctx context.Context, puuid string) ([]*models.PlayerAchievement, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "earnedAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"playerPuuid": puuid}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find achievements: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var achievements []*models.PlayerAchievement
+	for cursor.Next(ctx) {
+		var achievement models.PlayerAchievement
+		if err := cursor.Decode(&achievement); err != nil {
+			return nil, fmt.Errorf("failed to decode achievement: %w", err)
+		}
+		achievements = append(achievements, &achievement)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return achievements, nil
+}
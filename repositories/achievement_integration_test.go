@@ -0,0 +1,42 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+)
+
+func TestAchievementRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewAchievementRepository(newTestDatabase(t))
+
+	now := time.Now()
+	diamond := &models.PlayerAchievement{PlayerPUUID: "puuid-Faker", AchievementID: models.AchievementReachedDiamond, EarnedAt: now}
+	created, err := repo.Create(ctx, diamond)
+	mustNoError(t, err)
+	if !created || diamond.ID.IsZero() {
+		t.Fatalf("Create = %t with ID %s, want the achievement created", created, diamond.ID.Hex())
+	}
+
+	// A badge is earned once per player
+	created, err = repo.Create(ctx, &models.PlayerAchievement{PlayerPUUID: "puuid-Faker", AchievementID: models.AchievementReachedDiamond})
+	mustNoError(t, err)
+	if created {
+		t.Error("Create of an earned achievement = true, want false")
+	}
+
+	pentakill := &models.PlayerAchievement{PlayerPUUID: "puuid-Faker", AchievementID: models.AchievementFirstPentakill, MatchID: "EUW1_1", EarnedAt: now.Add(-time.Hour)}
+	_, err = repo.Create(ctx, pentakill)
+	mustNoError(t, err)
+	_, err = repo.Create(ctx, &models.PlayerAchievement{PlayerPUUID: "puuid-other", AchievementID: models.AchievementReachedDiamond})
+	mustNoError(t, err)
+
+	achievements, err := repo.FindByPlayer(ctx, "puuid-Faker")
+	mustNoError(t, err)
+	if len(achievements) != 2 || achievements[0].AchievementID != models.AchievementFirstPentakill || achievements[1].AchievementID != models.AchievementReachedDiamond {
+		t.Errorf("FindByPlayer returned %d achievements, want the pentakill then diamond", len(achievements))
+	}
+}
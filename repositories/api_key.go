@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type APIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAPIKeyRepository(db *mongo.Database) *APIKeyRepository {
+	return &APIKeyRepository{
+		collection: db.Collection("api_keys"),
+	}
+}
+
+// Create saves a new key.
+// It returns false without error if a key already has the same name.
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) (bool, error) {
+	result, err := r.collection.InsertOne(ctx, key)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		key.ID = oid
+	}
+
+	return true, nil
+}
+
+// FindByHash returns the key matching a hash, nil if not found
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.collection.FindOne(ctx, bson.M{"hash": hash}).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find API key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// FindAll returns every key, oldest first
+func (r *APIKeyRepository) FindAll(ctx context.Context) ([]*models.APIKey, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var keys []*models.APIKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode API keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// DeleteByName revokes a key, it returns false if no key has that name
+func (r *APIKeyRepository) DeleteByName(ctx context.Context, name string) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete API key: %w", err)
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// SetLastUsedAt records the last request made with a key
+func (r *APIKeyRepository) SetLastUsedAt(ctx context.Context, id primitive.ObjectID, usedAt time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"lastUsedAt": usedAt}})
+	if err != nil {
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	return nil
+}
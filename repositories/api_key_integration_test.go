@@ -0,0 +1,71 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewAPIKeyRepository(newTestDatabase(t))
+
+	now := time.Now()
+	dashboard := &models.APIKey{Name: "dashboard", Prefix: "lpt_1a2b", Hash: "hash-1", Scope: models.APIKeyScopeRead, CreatedAt: now.Add(-time.Hour)}
+	created, err := repo.Create(ctx, dashboard)
+	mustNoError(t, err)
+	if !created || dashboard.ID.IsZero() {
+		t.Fatalf("Create = %t with ID %s, want the key created", created, dashboard.ID.Hex())
+	}
+
+	created, err = repo.Create(ctx, &models.APIKey{Name: "dashboard", Hash: "hash-2", CreatedAt: now})
+	mustNoError(t, err)
+	if created {
+		t.Error("Create with a used name = true, want false")
+	}
+	created, err = repo.Create(ctx, &models.APIKey{Name: "other", Hash: "hash-1", CreatedAt: now})
+	mustNoError(t, err)
+	if created {
+		t.Error("Create with a used hash = true, want false")
+	}
+
+	_, err = repo.Create(ctx, &models.APIKey{Name: "stream", Hash: "hash-3", Scope: models.APIKeyScopeRefresh, CreatedAt: now})
+	mustNoError(t, err)
+
+	found, err := repo.FindByHash(ctx, "hash-1")
+	mustNoError(t, err)
+	if found == nil || found.Name != "dashboard" {
+		t.Fatalf("FindByHash = %+v, want the dashboard key", found)
+	}
+	found, err = repo.FindByHash(ctx, "unknown")
+	mustNoError(t, err)
+	if found != nil {
+		t.Errorf("FindByHash of an unknown hash = %+v, want nil", found)
+	}
+
+	usedAt := time.Now()
+	mustNoError(t, repo.SetLastUsedAt(ctx, dashboard.ID, usedAt))
+
+	keys, err := repo.FindAll(ctx)
+	mustNoError(t, err)
+	if len(keys) != 2 || keys[0].Name != "dashboard" || keys[1].Name != "stream" {
+		t.Fatalf("FindAll returned %d keys, want dashboard then stream", len(keys))
+	}
+	if keys[0].LastUsedAt == nil || !sameTime(*keys[0].LastUsedAt, usedAt) {
+		t.Errorf("LastUsedAt = %v, want %v", keys[0].LastUsedAt, usedAt)
+	}
+
+	deleted, err := repo.DeleteByName(ctx, "dashboard")
+	mustNoError(t, err)
+	if !deleted {
+		t.Error("DeleteByName(dashboard) = false, want true")
+	}
+	deleted, err = repo.DeleteByName(ctx, "dashboard")
+	mustNoError(t, err)
+	if deleted {
+		t.Error("DeleteByName of a revoked key = true, want false")
+	}
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ChallengeProgressRepository struct {
+	collection *mongo.Collection
+}
+
+func NewChallengeProgressRepository(db *mongo.Database) *ChallengeProgressRepository {
+	return &ChallengeProgressRepository{
+		collection: db.Collection("challenge_progress"),
+	}
+}
+
+// Save replaces the challenge progress of a player
+func (r *ChallengeProgressRepository) Save(ctx context.Context, progress *models.ChallengeProgress) error {
+	progress.UpdatedAt = time.Now()
+	filter := bson.M{"playerPuuid": progress.PlayerPUUID}
+	update := bson.M{
+		"$set": bson.M{
+			"gameName":   progress.GameName,
+			"tagLine":    progress.TagLine,
+			"level":      progress.Level,
+			"points":     progress.Points,
+			"maxPoints":  progress.MaxPoints,
+			"percentile": progress.Percentile,
+			"title":      progress.Title,
+			"challenges": progress.Challenges,
+			"updatedAt":  progress.UpdatedAt,
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save challenge progress: %w", err)
+	}
+
+	return nil
+}
+
+// FindByPlayer returns the last known challenge progress of a player, nil if never synced
+func (r *ChallengeProgressRepository) FindByPlayer(ctx context.Context, puuid string) (*models.ChallengeProgress, error) {
+	var progress models.ChallengeProgress
+	err := r.collection.FindOne(ctx, bson.M{"playerPuuid": puuid}).Decode(&progress)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find challenge progress: %w", err)
+	}
+
+	return &progress, nil
+}
+
+type ChallengeCompletionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewChallengeCompletionRepository(db *mongo.Database) *ChallengeCompletionRepository {
+	return &ChallengeCompletionRepository{
+		collection: db.Collection("challenge_completions"),
+	}
+}
+
+// Record saves a challenge level reached by a player, a level is only recorded once per player
+func (r *ChallengeCompletionRepository) Record(ctx context.Context, completion *models.ChallengeCompletion) error {
+	filter := bson.M{"playerPuuid": completion.PlayerPUUID, "challengeId": completion.ChallengeID, "level": completion.Level}
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"gameName":      completion.GameName,
+			"tagLine":       completion.TagLine,
+			"challengeName": completion.ChallengeName,
+			"achievedAt":    completion.AchievedAt,
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to record challenge completion: %w", err)
+	}
+
+	return nil
+}
+
+// FindBetween returns the completions achieved between start and end, oldest first
+func (r *ChallengeCompletionRepository) FindBetween(ctx context.Context, start, end time.Time) ([]*models.ChallengeCompletion, error) {
+	filter := bson.M{"achievedAt": bson.M{"$gte": start, "$lt": end}}
+	opts := options.Find().SetSort(bson.D{{Key: "achievedAt", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find challenge completions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var completions []*models.ChallengeCompletion
+	if err := cursor.All(ctx, &completions); err != nil {
+		return nil, fmt.Errorf("failed to decode challenge completions: %w", err)
+	}
+
+	return completions, nil
+}
@@ -0,0 +1,76 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+)
+
+func TestChallengeProgressRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewChallengeProgressRepository(newTestDatabase(t))
+
+	found, err := repo.FindByPlayer(ctx, "puuid-Faker")
+	mustNoError(t, err)
+	if found != nil {
+		t.Fatalf("FindByPlayer before the first sync = %+v, want nil", found)
+	}
+
+	progress := &models.ChallengeProgress{
+		PlayerPUUID: "puuid-Faker",
+		GameName:    "Faker",
+		TagLine:     "EUW",
+		Level:       "GOLD",
+		Points:      1000,
+		Challenges:  []*models.ChallengeLevel{{ChallengeID: 101, Level: "GOLD", Value: 12}},
+	}
+	mustNoError(t, repo.Save(ctx, progress))
+
+	progress.Level = "PLATINUM"
+	progress.Points = 1500
+	mustNoError(t, repo.Save(ctx, progress))
+
+	found, err = repo.FindByPlayer(ctx, "puuid-Faker")
+	mustNoError(t, err)
+	if found == nil || found.Level != "PLATINUM" || found.Points != 1500 || len(found.Challenges) != 1 {
+		t.Fatalf("FindByPlayer = %+v, want the replaced progress", found)
+	}
+}
+
+func TestChallengeCompletionRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewChallengeCompletionRepository(newTestDatabase(t))
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	completion := &models.ChallengeCompletion{
+		PlayerPUUID:   "puuid-Faker",
+		ChallengeID:   101,
+		ChallengeName: "Jack of All Champs",
+		Level:         "MASTER",
+		AchievedAt:    start.Add(time.Hour),
+	}
+	mustNoError(t, repo.Record(ctx, completion))
+
+	// Recorded again by the next sync, the first date is kept
+	again := *completion
+	again.AchievedAt = start.Add(2 * time.Hour)
+	mustNoError(t, repo.Record(ctx, &again))
+
+	mustNoError(t, repo.Record(ctx, &models.ChallengeCompletion{PlayerPUUID: "puuid-Faker", ChallengeID: 101, Level: "GRANDMASTER", AchievedAt: start.Add(3 * time.Hour)}))
+	mustNoError(t, repo.Record(ctx, &models.ChallengeCompletion{PlayerPUUID: "puuid-Faker", ChallengeID: 102, Level: "MASTER", AchievedAt: start.Add(-time.Hour)}))
+
+	completions, err := repo.FindBetween(ctx, start, start.Add(24*time.Hour))
+	mustNoError(t, err)
+	if len(completions) != 2 {
+		t.Fatalf("FindBetween returned %d completions, want MASTER then GRANDMASTER", len(completions))
+	}
+	if completions[0].Level != "MASTER" || !sameTime(completions[0].AchievedAt, start.Add(time.Hour)) {
+		t.Errorf("first completion = %+v, want MASTER achieved at %v", completions[0], start.Add(time.Hour))
+	}
+	if completions[1].Level != "GRANDMASTER" {
+		t.Errorf("second completion = %+v, want GRANDMASTER", completions[1])
+	}
+}
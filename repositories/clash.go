@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ClashRegistrationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewClashRegistrationRepository(db *mongo.Database) *ClashRegistrationRepository {
+	return &ClashRegistrationRepository{
+		collection: db.Collection("clash_registrations"),
+	}
+}
+
+// Upsert saves the registration of a player in a tournament, it returns true when it is new
+func (r *ClashRegistrationRepository) Upsert(ctx context.Context, registration *models.ClashRegistration) (bool, error) {
+	registration.UpdatedAt = time.Now()
+	filter := bson.M{"playerPuuid": registration.PlayerPUUID, "tournamentId": registration.TournamentID}
+	update := bson.M{
+		"$set": bson.M{
+			"gameName":         registration.GameName,
+			"tagLine":          registration.TagLine,
+			"server":           registration.Server,
+			"tournamentName":   registration.TournamentName,
+			"teamId":           registration.TeamID,
+			"teamName":         registration.TeamName,
+			"position":         registration.Position,
+			"registrationTime": registration.RegistrationTime,
+			"startTime":        registration.StartTime,
+			"updatedAt":        registration.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, fmt.Errorf("failed to save clash registration: %w", err)
+	}
+
+	return result.UpsertedCount > 0, nil
+}
+
+// FindByPlayer returns the registrations of a player
+func (r *ClashRegistrationRepository) FindByPlayer(ctx context.Context, puuid string) ([]*models.ClashRegistration, error) {
+	return r.find(ctx, bson.M{"playerPuuid": puuid})
+}
+
+// FindUpcoming returns the registrations of the tournaments starting after since, soonest first
+func (r *ClashRegistrationRepository) FindUpcoming(ctx context.Context, since time.Time) ([]*models.ClashRegistration, error) {
+	return r.find(ctx, bson.M{"startTime": bson.M{"$gte": since}})
+}
+
+// Delete removes the registration of a player in a tournament
+func (r *ClashRegistrationRepository) Delete(ctx context.Context, puuid string, tournamentID int) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"playerPuuid": puuid, "tournamentId": tournamentID})
+	if err != nil {
+		return fmt.Errorf("failed to delete clash registration: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStartedBefore removes the registrations of the tournaments that started before the given time
+func (r *ClashRegistrationRepository) DeleteStartedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"startTime": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete past clash registrations: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (r *ClashRegistrationRepository) find(ctx context.Context, filter bson.M) ([]*models.ClashRegistration, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "startTime", Value: 1}, {Key: "gameName", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find clash registrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var registrations []*models.ClashRegistration
+	if err := cursor.All(ctx, &registrations); err != nil {
+		return nil, fmt.Errorf("failed to decode clash registrations: %w", err)
+	}
+
+	return registrations, nil
+}
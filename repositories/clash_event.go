@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ClashEventRepository struct {
+	collection *mongo.Collection
+}
+
+func NewClashEventRepository(db *mongo.Database) *ClashEventRepository {
+	return &ClashEventRepository{
+		collection: db.Collection("clash_events"),
+	}
+}
+
+// FindByGuild returns the Clash events created in a guild
+func (r *ClashEventRepository) FindByGuild(ctx context.Context, guildID string) ([]*models.ClashEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "startTime", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"guildId": guildID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find clash events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []*models.ClashEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode clash events: %w", err)
+	}
+
+	return events, nil
+}
+
+// Save creates or updates the event of a guild for a tournament of a server
+func (r *ClashEventRepository) Save(ctx context.Context, event *models.ClashEvent) error {
+	now := time.Now()
+	event.UpdatedAt = now
+	filter := bson.M{"guildId": event.GuildID, "server": event.Server, "tournamentId": event.TournamentID}
+	update := bson.M{
+		"$set": bson.M{
+			"eventId":      event.EventID,
+			"playerPuuids": event.PlayerPUUIDs,
+			"startTime":    event.StartTime,
+			"updatedAt":    now,
+		},
+		"$setOnInsert": bson.M{"createdAt": now},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save clash event: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the record of an event
+func (r *ClashEventRepository) Delete(ctx context.Context, event *models.ClashEvent) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"guildId": event.GuildID, "server": event.Server, "tournamentId": event.TournamentID})
+	if err != nil {
+		return fmt.Errorf("failed to delete clash event: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+)
+
+func TestClashEventRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewClashEventRepository(newTestDatabase(t))
+
+	start := time.Now().Add(24 * time.Hour)
+	event := &models.ClashEvent{GuildID: "guild-1", Server: "euw1", TournamentID: 1, EventID: "event-1", PlayerPUUIDs: []string{"puuid-Faker"}, StartTime: start}
+	mustNoError(t, repo.Save(ctx, event))
+
+	event.PlayerPUUIDs = append(event.PlayerPUUIDs, "puuid-other")
+	mustNoError(t, repo.Save(ctx, event))
+
+	mustNoError(t, repo.Save(ctx, &models.ClashEvent{GuildID: "guild-1", Server: "kr", TournamentID: 1, EventID: "event-2", StartTime: start.Add(-time.Hour)}))
+	mustNoError(t, repo.Save(ctx, &models.ClashEvent{GuildID: "guild-2", Server: "euw1", TournamentID: 1, EventID: "event-3", StartTime: start}))
+
+	events, err := repo.FindByGuild(ctx, "guild-1")
+	mustNoError(t, err)
+	if len(events) != 2 || events[0].EventID != "event-2" || events[1].EventID != "event-1" {
+		t.Fatalf("FindByGuild returned %d events, want event-2 then event-1", len(events))
+	}
+	if len(events[1].PlayerPUUIDs) != 2 || events[1].CreatedAt.IsZero() {
+		t.Errorf("updated event = %+v, want 2 players and its creation date", events[1])
+	}
+
+	mustNoError(t, repo.Delete(ctx, event))
+	events, err = repo.FindByGuild(ctx, "guild-1")
+	mustNoError(t, err)
+	if len(events) != 1 || events[0].EventID != "event-2" {
+		t.Errorf("FindByGuild after the deletion returned %d events, want event-2", len(events))
+	}
+}
@@ -0,0 +1,70 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+)
+
+func TestClashRegistrationRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewClashRegistrationRepository(newTestDatabase(t))
+
+	now := time.Now()
+	registration := &models.ClashRegistration{
+		PlayerPUUID:  "puuid-Faker",
+		GameName:     "Faker",
+		TournamentID: 1,
+		Position:     "MIDDLE",
+		StartTime:    now.Add(24 * time.Hour),
+	}
+	isNew, err := repo.Upsert(ctx, registration)
+	mustNoError(t, err)
+	if !isNew {
+		t.Error("Upsert of a new registration = false, want true")
+	}
+
+	registration.Position = "TOP"
+	isNew, err = repo.Upsert(ctx, registration)
+	mustNoError(t, err)
+	if isNew {
+		t.Error("Upsert of a known registration = true, want false")
+	}
+
+	for _, other := range []*models.ClashRegistration{
+		{PlayerPUUID: "puuid-Faker", GameName: "Faker", TournamentID: 2, StartTime: now.Add(48 * time.Hour)},
+		{PlayerPUUID: "puuid-other", GameName: "Other", TournamentID: 1, StartTime: now.Add(24 * time.Hour)},
+		{PlayerPUUID: "puuid-other", GameName: "Other", TournamentID: 0, StartTime: now.Add(-24 * time.Hour)},
+	} {
+		_, err := repo.Upsert(ctx, other)
+		mustNoError(t, err)
+	}
+
+	registrations, err := repo.FindByPlayer(ctx, "puuid-Faker")
+	mustNoError(t, err)
+	if len(registrations) != 2 || registrations[0].TournamentID != 1 || registrations[0].Position != "TOP" {
+		t.Fatalf("FindByPlayer returned %d registrations, want tournament 1 (TOP) then 2", len(registrations))
+	}
+
+	upcoming, err := repo.FindUpcoming(ctx, now)
+	mustNoError(t, err)
+	if len(upcoming) != 3 || upcoming[0].GameName != "Faker" || upcoming[1].GameName != "Other" || upcoming[2].TournamentID != 2 {
+		t.Errorf("FindUpcoming returned %d registrations, want Faker and Other in tournament 1, then tournament 2", len(upcoming))
+	}
+
+	deleted, err := repo.DeleteStartedBefore(ctx, now)
+	mustNoError(t, err)
+	if deleted != 1 {
+		t.Errorf("DeleteStartedBefore = %d, want 1", deleted)
+	}
+
+	mustNoError(t, repo.Delete(ctx, "puuid-Faker", 2))
+	registrations, err = repo.FindByPlayer(ctx, "puuid-Faker")
+	mustNoError(t, err)
+	if len(registrations) != 1 {
+		t.Errorf("FindByPlayer after the deletion returned %d registrations, want 1", len(registrations))
+	}
+}
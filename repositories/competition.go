@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CompetitionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCompetitionRepository(db *mongo.Database) *CompetitionRepository {
+	return &CompetitionRepository{
+		collection: db.Collection("competitions"),
+	}
+}
+
+// Create adds a new competition
+func (r *CompetitionRepository) Create(ctx context.Context, competition *models.Competition) error {
+	competition.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, competition)
+	if err != nil {
+		return fmt.Errorf("failed to create competition: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		competition.ID = oid
+	}
+
+	return nil
+}
+
+// FindCurrentByGuild returns the latest competition of a guild whose results are not announced yet
+func (r *CompetitionRepository) FindCurrentByGuild(ctx context.Context, guildID string) (*models.Competition, error) {
+	var competition models.Competition
+
+	filter := bson.M{
+		"guildId":     guildID,
+		"announcedAt": bson.M{"$exists": false},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "startAt", Value: -1}})
+
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&competition)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find competition: %w", err)
+	}
+
+	return &competition, nil
+}
+
+// FindEndedUnannounced returns the competitions ended before now whose results were not announced
+func (r *CompetitionRepository) FindEndedUnannounced(ctx context.Context, now time.Time) ([]*models.Competition, error) {
+	filter := bson.M{
+		"endAt":       bson.M{"$lte": now},
+		"announcedAt": bson.M{"$exists": false},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ended competitions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var competitions []*models.Competition
+	for cursor.Next(ctx) {
+		var competition models.Competition
+		if err := cursor.Decode(&competition); err != nil {
+			return nil, fmt.Errorf("failed to decode competition: %w", err)
+		}
+		competitions = append(competitions, &competition)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return competitions, nil
+}
+
+// MarkAnnounced records that the final results of a competition were announced
+func (r *CompetitionRepository) MarkAnnounced(ctx context.Context, id primitive.ObjectID, announcedAt time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"announcedAt": announcedAt}})
+	if err != nil {
+		return fmt.Errorf("failed to mark competition announced: %w", err)
+	}
+
+	return nil
+}
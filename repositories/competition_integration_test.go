@@ -0,0 +1,57 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+)
+
+func TestCompetitionRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewCompetitionRepository(newTestDatabase(t))
+
+	now := time.Now()
+	found, err := repo.FindCurrentByGuild(ctx, "guild-1")
+	mustNoError(t, err)
+	if found != nil {
+		t.Fatalf("FindCurrentByGuild without competition = %+v, want nil", found)
+	}
+
+	ended := &models.Competition{GuildID: "guild-1", Name: "March", StartAt: now.Add(-48 * time.Hour), EndAt: now.Add(-time.Hour)}
+	current := &models.Competition{GuildID: "guild-1", Name: "April", StartAt: now.Add(-time.Hour), EndAt: now.Add(48 * time.Hour)}
+	for _, competition := range []*models.Competition{ended, current} {
+		mustNoError(t, repo.Create(ctx, competition))
+		if competition.ID.IsZero() {
+			t.Fatal("Create did not set the ID of the competition")
+		}
+	}
+
+	found, err = repo.FindCurrentByGuild(ctx, "guild-1")
+	mustNoError(t, err)
+	if found == nil || found.ID != current.ID {
+		t.Fatalf("FindCurrentByGuild = %+v, want the April competition", found)
+	}
+
+	competitions, err := repo.FindEndedUnannounced(ctx, now)
+	mustNoError(t, err)
+	if len(competitions) != 1 || competitions[0].ID != ended.ID {
+		t.Fatalf("FindEndedUnannounced returned %d competitions, want the March competition", len(competitions))
+	}
+
+	mustNoError(t, repo.MarkAnnounced(ctx, ended.ID, now))
+	competitions, err = repo.FindEndedUnannounced(ctx, now)
+	mustNoError(t, err)
+	if len(competitions) != 0 {
+		t.Errorf("FindEndedUnannounced after the announcement returned %d competitions, want none", len(competitions))
+	}
+
+	mustNoError(t, repo.MarkAnnounced(ctx, current.ID, now))
+	found, err = repo.FindCurrentByGuild(ctx, "guild-1")
+	mustNoError(t, err)
+	if found != nil {
+		t.Errorf("FindCurrentByGuild once every result is announced = %+v, want nil", found)
+	}
+}
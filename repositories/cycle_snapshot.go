@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CycleSnapshotRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCycleSnapshotRepository(db *mongo.Database) *CycleSnapshotRepository {
+	return &CycleSnapshotRepository{
+		collection: db.Collection("cycle_snapshots"),
+	}
+}
+
+// Create saves the snapshot of a completed poll cycle, cycle numbers are unique
+func (r *CycleSnapshotRepository) Create(ctx context.Context, snapshot *models.CycleSnapshot) error {
+	snapshot.CompletedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to create cycle snapshot: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		snapshot.ID = oid
+	}
+
+	return nil
+}
+
+// FindLatest returns the snapshot of the last completed poll cycle, nil before the first one
+func (r *CycleSnapshotRepository) FindLatest(ctx context.Context) (*models.CycleSnapshot, error) {
+	var snapshot models.CycleSnapshot
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "cycle", Value: -1}})
+	err := r.collection.FindOne(ctx, bson.M{}, opts).Decode(&snapshot)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find cycle snapshot: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// DeleteBefore deletes the snapshots of the cycles older than cycle
+func (r *CycleSnapshotRepository) DeleteBefore(ctx context.Context, cycle int64) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"cycle": bson.M{"$lt": cycle}})
+	if err != nil {
+		return fmt.Errorf("failed to delete cycle snapshots: %w", err)
+	}
+
+	return nil
+}
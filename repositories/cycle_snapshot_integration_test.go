@@ -0,0 +1,49 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestCycleSnapshotRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewCycleSnapshotRepository(newTestDatabase(t))
+
+	found, err := repo.FindLatest(ctx)
+	mustNoError(t, err)
+	if found != nil {
+		t.Fatalf("FindLatest before the first cycle = %+v, want nil", found)
+	}
+
+	for cycle := int64(1); cycle <= 3; cycle++ {
+		snapshot := &models.CycleSnapshot{
+			Cycle:   cycle,
+			Entries: []models.SnapshotEntry{{Position: 1, PUUID: "puuid-Faker", LeaguePoints: int(cycle) * 10}},
+		}
+		mustNoError(t, repo.Create(ctx, snapshot))
+	}
+
+	err = repo.Create(ctx, &models.CycleSnapshot{Cycle: 3})
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("Create of a completed cycle = %v, want a duplicate key error", err)
+	}
+
+	found, err = repo.FindLatest(ctx)
+	mustNoError(t, err)
+	if found == nil || found.Cycle != 3 || len(found.Entries) != 1 || found.Entries[0].LeaguePoints != 30 {
+		t.Fatalf("FindLatest = %+v, want cycle 3", found)
+	}
+
+	mustNoError(t, repo.DeleteBefore(ctx, 3))
+	count, err := repo.collection.CountDocuments(ctx, bson.M{})
+	mustNoError(t, err)
+	if count != 1 {
+		t.Errorf("%d snapshots left after DeleteBefore(3), want 1", count)
+	}
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FeatureFlagRepository struct {
+	collection *mongo.Collection
+}
+
+func NewFeatureFlagRepository(db *mongo.Database) *FeatureFlagRepository {
+	return &FeatureFlagRepository{
+		collection: db.Collection("feature_flags"),
+	}
+}
+
+// FindAll returns every flag value, global and per guild
+func (r *FeatureFlagRepository) FindAll(ctx context.Context) ([]*models.FeatureFlag, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find feature flags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var flags []*models.FeatureFlag
+	for cursor.Next(ctx) {
+		var flag models.FeatureFlag
+		if err := cursor.Decode(&flag); err != nil {
+			return nil, fmt.Errorf("failed to decode feature flag: %w", err)
+		}
+		flags = append(flags, &flag)
+	}
+
+	return flags, cursor.Err()
+}
+
+// Set creates or updates the value of a flag for a guild (empty guild ID for the global value)
+func (r *FeatureFlagRepository) Set(ctx context.Context, flag *models.FeatureFlag) error {
+	flag.UpdatedAt = time.Now()
+
+	filter := bson.M{"name": flag.Name, "guildId": flag.GuildID}
+	update := bson.M{"$set": bson.M{
+		"enabled":   flag.Enabled,
+		"updatedBy": flag.UpdatedBy,
+		"updatedAt": flag.UpdatedAt,
+	}}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save feature flag: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the value of a flag for a guild (empty guild ID for the global value)
+func (r *FeatureFlagRepository) Delete(ctx context.Context, name, guildID string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"name": name, "guildId": guildID})
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	return nil
+}
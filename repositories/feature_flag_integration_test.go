@@ -0,0 +1,40 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+
+	"lp_tracker/models"
+)
+
+func TestFeatureFlagRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewFeatureFlagRepository(newTestDatabase(t))
+
+	mustNoError(t, repo.Set(ctx, &models.FeatureFlag{Name: models.FlagLiveGame, Enabled: true}))
+	mustNoError(t, repo.Set(ctx, &models.FeatureFlag{Name: models.FlagLiveGame, GuildID: "guild-1", Enabled: true}))
+	// Set again, the value of the guild is replaced
+	mustNoError(t, repo.Set(ctx, &models.FeatureFlag{Name: models.FlagLiveGame, GuildID: "guild-1", Enabled: false, UpdatedBy: "user-1"}))
+
+	flags, err := repo.FindAll(ctx)
+	mustNoError(t, err)
+	if len(flags) != 2 {
+		t.Fatalf("FindAll returned %d values, want the global one and the guild one", len(flags))
+	}
+	for _, flag := range flags {
+		if flag.GuildID == "guild-1" && (flag.Enabled || flag.UpdatedBy != "user-1") {
+			t.Errorf("value of the guild = %+v, want disabled by user-1", flag)
+		}
+		if flag.GuildID == "" && !flag.Enabled {
+			t.Errorf("global value = %+v, want enabled", flag)
+		}
+	}
+
+	mustNoError(t, repo.Delete(ctx, models.FlagLiveGame, "guild-1"))
+	flags, err = repo.FindAll(ctx)
+	mustNoError(t, err)
+	if len(flags) != 1 || flags[0].GuildID != "" {
+		t.Errorf("FindAll after the deletion returned %d values, want the global one", len(flags))
+	}
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type GuildConfigRepository struct {
+	collection *mongo.Collection
+}
+
+func NewGuildConfigRepository(db *mongo.Database) *GuildConfigRepository {
+	return &GuildConfigRepository{
+		collection: db.Collection("guild_configs"),
+	}
+}
+
+// FindByGuildID finds the configuration of a guild
+func (r *GuildConfigRepository) FindByGuildID(ctx context.Context, guildID string) (*models.GuildConfig, error) {
+	var config models.GuildConfig
+
+	err := r.collection.FindOne(ctx, bson.M{"guildId": guildID}).Decode(&config)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil // Guild not configured yet
+		}
+		return nil, fmt.Errorf("failed to find guild config: %w", err)
+	}
+
+	return &config, nil
+}
+
+// Upsert creates or replaces the configuration of a guild
+func (r *GuildConfigRepository) Upsert(ctx context.Context, config *models.GuildConfig) error {
+	now := time.Now()
+	if config.CreatedAt.IsZero() {
+		config.CreatedAt = now
+	}
+	config.UpdatedAt = now
+
+	filter := bson.M{"guildId": config.GuildID}
+	_, err := r.collection.ReplaceOne(ctx, filter, config, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to upsert guild config: %w", err)
+	}
+
+	return nil
+}
+
+// FindWithNotificationChannel returns all active guilds that have a notification channel set
+func (r *GuildConfigRepository) FindWithNotificationChannel(ctx context.Context) ([]*models.GuildConfig, error) {
+	return r.find(ctx, bson.M{
+		"notificationChannelId": bson.M{"$ne": ""},
+		"leftAt":                nil, // Missing or null
+	})
+}
+
+// FindWithLiveLeaderboard returns all active guilds that have the live leaderboard enabled
+func (r *GuildConfigRepository) FindWithLiveLeaderboard(ctx context.Context) ([]*models.GuildConfig, error) {
+	return r.find(ctx, bson.M{
+		"liveLeaderboardChannelId": bson.M{"$exists": true, "$ne": ""},
+		"leftAt":                   nil,
+	})
+}
+
+// SetLiveLeaderboardMessage records the message holding the live leaderboard of a guild
+func (r *GuildConfigRepository) SetLiveLeaderboardMessage(ctx context.Context, guildID, messageID string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"guildId": guildID}, bson.M{"$set": bson.M{"liveLeaderboardMessageId": messageID}})
+	if err != nil {
+		return fmt.Errorf("failed to set live leaderboard message: %w", err)
+	}
+
+	return nil
+}
+
+// SetPlayerMuted mutes or unmutes the notifications of a player in a guild
+func (r *GuildConfigRepository) SetPlayerMuted(ctx context.Context, guildID, puuid string, muted bool) error {
+	update := bson.M{"$pull": bson.M{"mutedPlayers": puuid}}
+	if muted {
+		update = bson.M{"$addToSet": bson.M{"mutedPlayers": puuid}}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"guildId": guildID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update muted players: %w", err)
+	}
+
+	return nil
+}
+
+// FindAll returns the configuration of every known guild, departed ones included
+func (r *GuildConfigRepository) FindAll(ctx context.Context) ([]*models.GuildConfig, error) {
+	return r.find(ctx, bson.M{})
+}
+
+// SetLeftAt records when the bot left a guild, nil when it (re)joined
+func (r *GuildConfigRepository) SetLeftAt(ctx context.Context, guildID string, leftAt *time.Time) error {
+	update := bson.M{"$unset": bson.M{"leftAt": ""}}
+	if leftAt != nil {
+		update = bson.M{"$set": bson.M{"leftAt": *leftAt}}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"guildId": guildID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update guild membership: %w", err)
+	}
+
+	return nil
+}
+
+func (r *GuildConfigRepository) find(ctx context.Context, filter bson.M) ([]*models.GuildConfig, error) {
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find guild configs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var configs []*models.GuildConfig
+	for cursor.Next(ctx) {
+		var config models.GuildConfig
+		if err := cursor.Decode(&config); err != nil {
+			return nil, fmt.Errorf("failed to decode guild config: %w", err)
+		}
+		configs = append(configs, &config)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return configs, nil
+}
+
+// MarkRecapSent records when the last recap of a period was sent to a guild
+func (r *GuildConfigRepository) MarkRecapSent(ctx context.Context, guildID string, period models.RecapPeriod, sentAt time.Time) error {
+	field := "lastDailyRecapAt"
+	if period == models.RecapPeriodWeekly {
+		field = "lastWeeklyRecapAt"
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"guildId": guildID}, bson.M{"$set": bson.M{field: sentAt}})
+	if err != nil {
+		return fmt.Errorf("failed to mark recap sent: %w", err)
+	}
+
+	return nil
+}
+
+// SetLastPatch records the last patch announced to a guild
+func (r *GuildConfigRepository) SetLastPatch(ctx context.Context, guildID, patch string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"guildId": guildID}, bson.M{"$set": bson.M{"lastPatch": patch}})
+	if err != nil {
+		return fmt.Errorf("failed to set last patch: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestGuildConfigRepositoryUpsert(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewGuildConfigRepository(newTestDatabase(t))
+
+	found, err := repo.FindByGuildID(ctx, "guild-1")
+	mustNoError(t, err)
+	if found != nil {
+		t.Fatalf("FindByGuildID of an unknown guild = %+v, want nil", found)
+	}
+
+	config := &models.GuildConfig{GuildID: "guild-1", NotificationChannelID: "channel-1", MinLPDelta: 10}
+	mustNoError(t, repo.Upsert(ctx, config))
+	createdAt := config.CreatedAt
+
+	config.MinLPDelta = 20
+	mustNoError(t, repo.Upsert(ctx, config))
+
+	found, err = repo.FindByGuildID(ctx, "guild-1")
+	mustNoError(t, err)
+	if found == nil || found.MinLPDelta != 20 || found.NotificationChannelID != "channel-1" {
+		t.Fatalf("FindByGuildID = %+v, want the replaced configuration", found)
+	}
+	if !sameTime(found.CreatedAt, createdAt) {
+		t.Errorf("CreatedAt = %v after the replacement, want %v", found.CreatedAt, createdAt)
+	}
+
+	all, err := repo.FindAll(ctx)
+	mustNoError(t, err)
+	if len(all) != 1 {
+		t.Errorf("the upserts stored %d configurations, want 1", len(all))
+	}
+}
+
+func TestGuildConfigRepositoryActiveGuilds(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewGuildConfigRepository(newTestDatabase(t))
+
+	for _, config := range []*models.GuildConfig{
+		{GuildID: "notified", NotificationChannelID: "channel-1", LiveLeaderboardChannelID: "channel-2"},
+		{GuildID: "silent"},
+		{GuildID: "departed", NotificationChannelID: "channel-3", LiveLeaderboardChannelID: "channel-4"},
+	} {
+		mustNoError(t, repo.Upsert(ctx, config))
+	}
+	leftAt := time.Now()
+	mustNoError(t, repo.SetLeftAt(ctx, "departed", &leftAt))
+
+	notified, err := repo.FindWithNotificationChannel(ctx)
+	mustNoError(t, err)
+	if len(notified) != 1 || notified[0].GuildID != "notified" {
+		t.Errorf("FindWithNotificationChannel = %v, want the notified guild", guildIDs(notified))
+	}
+
+	live, err := repo.FindWithLiveLeaderboard(ctx)
+	mustNoError(t, err)
+	if len(live) != 1 || live[0].GuildID != "notified" {
+		t.Errorf("FindWithLiveLeaderboard = %v, want the notified guild", guildIDs(live))
+	}
+
+	all, err := repo.FindAll(ctx)
+	mustNoError(t, err)
+	if len(all) != 3 {
+		t.Errorf("FindAll = %v, want the 3 guilds, departed ones included", guildIDs(all))
+	}
+
+	// The bot joined the guild again
+	mustNoError(t, repo.SetLeftAt(ctx, "departed", nil))
+	notified, err = repo.FindWithNotificationChannel(ctx)
+	mustNoError(t, err)
+	if len(notified) != 2 {
+		t.Errorf("FindWithNotificationChannel after the rejoin = %v, want 2 guilds", guildIDs(notified))
+	}
+}
+
+func TestGuildConfigRepositorySetters(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewGuildConfigRepository(newTestDatabase(t))
+
+	mustNoError(t, repo.Upsert(ctx, &models.GuildConfig{GuildID: "guild-1"}))
+
+	dailyAt := time.Now().Add(-time.Hour)
+	weeklyAt := time.Now()
+	mustNoError(t, repo.SetLiveLeaderboardMessage(ctx, "guild-1", "message-1"))
+	mustNoError(t, repo.SetPlayerMuted(ctx, "guild-1", "puuid-1", true))
+	mustNoError(t, repo.SetPlayerMuted(ctx, "guild-1", "puuid-1", true))
+	mustNoError(t, repo.SetPlayerMuted(ctx, "guild-1", "puuid-2", true))
+	mustNoError(t, repo.SetPlayerMuted(ctx, "guild-1", "puuid-2", false))
+	mustNoError(t, repo.MarkRecapSent(ctx, "guild-1", models.RecapPeriodDaily, dailyAt))
+	mustNoError(t, repo.MarkRecapSent(ctx, "guild-1", models.RecapPeriodWeekly, weeklyAt))
+	mustNoError(t, repo.SetLastPatch(ctx, "guild-1", "14.5"))
+
+	found, err := repo.FindByGuildID(ctx, "guild-1")
+	mustNoError(t, err)
+	if found.LiveLeaderboardMessageID != "message-1" || found.LastPatch != "14.5" {
+		t.Errorf("live leaderboard message = %q, last patch = %q, want message-1 and 14.5", found.LiveLeaderboardMessageID, found.LastPatch)
+	}
+	if len(found.MutedPlayers) != 1 || found.MutedPlayers[0] != "puuid-1" {
+		t.Errorf("muted players = %v, want puuid-1 once", found.MutedPlayers)
+	}
+	if !sameTime(found.LastDailyRecapAt, dailyAt) || !sameTime(found.LastWeeklyRecapAt, weeklyAt) {
+		t.Errorf("recaps sent at %v and %v, want %v and %v", found.LastDailyRecapAt, found.LastWeeklyRecapAt, dailyAt, weeklyAt)
+	}
+}
+
+func TestGuildConfigRepositoryUniqueGuild(t *testing.T) {
+	ctx := testContext(t)
+	db := newTestDatabase(t)
+	repo := NewGuildConfigRepository(db)
+
+	mustNoError(t, repo.Upsert(ctx, &models.GuildConfig{GuildID: "guild-1"}))
+
+	_, err := db.Collection("guild_configs").InsertOne(ctx, &models.GuildConfig{GuildID: "guild-1"})
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("insert of a second configuration of the guild = %v, want a duplicate key error", err)
+	}
+}
+
+func guildIDs(configs []*models.GuildConfig) []string {
+	ids := make([]string, 0, len(configs))
+	for _, config := range configs {
+		ids = append(ids, config.GuildID)
+	}
+	return ids
+}
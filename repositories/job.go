@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type JobRepository struct {
+	collection *mongo.Collection
+}
+
+func NewJobRepository(db *mongo.Database) *JobRepository {
+	return &JobRepository{
+		collection: db.Collection("jobs"),
+	}
+}
+
+// Create saves a new pending job
+func (r *JobRepository) Create(ctx context.Context, job *models.Job) error {
+	job.Status = models.JobPending
+	job.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, job)
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		job.ID = oid
+	}
+
+	return nil
+}
+
+// FindByID finds a job by its ID
+func (r *JobRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Job, error) {
+	var job models.Job
+
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&job)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// FindByGuild returns the latest jobs of a guild, newest first
+func (r *JobRepository) FindByGuild(ctx context.Context, guildID string, limit int) ([]*models.Job, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"guildId": guildID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find jobs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []*models.Job
+	for cursor.Next(ctx) {
+		var job models.Job
+		if err := cursor.Decode(&job); err != nil {
+			return nil, fmt.Errorf("failed to decode job: %w", err)
+		}
+		jobs = append(jobs, &job)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// MarkRunning flags a job as started
+func (r *JobRepository) MarkRunning(ctx context.Context, id primitive.ObjectID) error {
+	now := time.Now()
+	return r.update(ctx, id, bson.M{"status": models.JobRunning, "startedAt": now})
+}
+
+// UpdateProgress saves the progress percentage of a running job
+func (r *JobRepository) UpdateProgress(ctx context.Context, id primitive.ObjectID, progress int) error {
+	return r.update(ctx, id, bson.M{"progress": progress})
+}
+
+// Finish saves the final status of a job with its result or error
+func (r *JobRepository) Finish(ctx context.Context, id primitive.ObjectID, status, result, errMessage string) error {
+	fields := bson.M{
+		"status":     status,
+		"result":     result,
+		"error":      errMessage,
+		"finishedAt": time.Now(),
+	}
+	if status == models.JobSucceeded {
+		fields["progress"] = 100
+	}
+
+	return r.update(ctx, id, fields)
+}
+
+// FailUnfinished marks the jobs left pending or running (ex: by a restart) as failed
+func (r *JobRepository) FailUnfinished(ctx context.Context, reason string) (int64, error) {
+	filter := bson.M{"status": bson.M{"$in": []string{models.JobPending, models.JobRunning}}}
+	update := bson.M{"$set": bson.M{
+		"status":     models.JobFailed,
+		"error":      reason,
+		"finishedAt": time.Now(),
+	}}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail unfinished jobs: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (r *JobRepository) update(ctx context.Context, id primitive.ObjectID, fields bson.M) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": fields})
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+
+	return nil
+}
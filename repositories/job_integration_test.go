@@ -0,0 +1,85 @@
+//go:build integration
+
+package repositories
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+)
+
+func TestJobRepositoryLifecycle(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewJobRepository(newTestDatabase(t))
+
+	job := &models.Job{Type: models.JobTypeBackfill, Name: "Backfill Faker", GuildID: "guild-1"}
+	mustNoError(t, repo.Create(ctx, job))
+	if job.ID.IsZero() || job.Status != models.JobPending {
+		t.Fatalf("created job = %+v, want a pending job with an ID", job)
+	}
+
+	mustNoError(t, repo.MarkRunning(ctx, job.ID))
+	mustNoError(t, repo.UpdateProgress(ctx, job.ID, 40))
+	found, err := repo.FindByID(ctx, job.ID)
+	mustNoError(t, err)
+	if found == nil || found.Status != models.JobRunning || found.Progress != 40 || found.StartedAt == nil {
+		t.Fatalf("running job = %+v, want running at 40%%", found)
+	}
+
+	mustNoError(t, repo.Finish(ctx, job.ID, models.JobSucceeded, "12 games imported", ""))
+	found, err = repo.FindByID(ctx, job.ID)
+	mustNoError(t, err)
+	if found.Status != models.JobSucceeded || found.Progress != 100 || found.Result != "12 games imported" || found.FinishedAt == nil {
+		t.Errorf("finished job = %+v, want succeeded at 100%%", found)
+	}
+}
+
+func TestJobRepositoryFindByGuild(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewJobRepository(newTestDatabase(t))
+
+	for i := 1; i <= 3; i++ {
+		mustNoError(t, repo.Create(ctx, &models.Job{Type: models.JobTypeReport, Name: fmt.Sprintf("Report %d", i), GuildID: "guild-1"}))
+		time.Sleep(2 * time.Millisecond) // Distinct creation dates
+	}
+	mustNoError(t, repo.Create(ctx, &models.Job{Type: models.JobTypeReport, Name: "Other guild", GuildID: "guild-2"}))
+
+	jobs, err := repo.FindByGuild(ctx, "guild-1", 2)
+	mustNoError(t, err)
+	if len(jobs) != 2 || jobs[0].Name != "Report 3" || jobs[1].Name != "Report 2" {
+		t.Errorf("FindByGuild returned %d jobs, want Report 3 then Report 2", len(jobs))
+	}
+}
+
+func TestJobRepositoryFailUnfinished(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewJobRepository(newTestDatabase(t))
+
+	pending := &models.Job{Type: models.JobTypeRefresh, Name: "pending"}
+	running := &models.Job{Type: models.JobTypeRefresh, Name: "running"}
+	finished := &models.Job{Type: models.JobTypeRefresh, Name: "finished"}
+	for _, job := range []*models.Job{pending, running, finished} {
+		mustNoError(t, repo.Create(ctx, job))
+	}
+	mustNoError(t, repo.MarkRunning(ctx, running.ID))
+	mustNoError(t, repo.Finish(ctx, finished.ID, models.JobSucceeded, "done", ""))
+
+	failed, err := repo.FailUnfinished(ctx, "restarted")
+	mustNoError(t, err)
+	if failed != 2 {
+		t.Errorf("FailUnfinished = %d, want 2", failed)
+	}
+
+	found, err := repo.FindByID(ctx, running.ID)
+	mustNoError(t, err)
+	if found.Status != models.JobFailed || found.Error != "restarted" {
+		t.Errorf("interrupted job = %+v, want failed with the reason", found)
+	}
+	found, err = repo.FindByID(ctx, finished.ID)
+	mustNoError(t, err)
+	if found.Status != models.JobSucceeded {
+		t.Errorf("finished job = %+v, want it untouched", found)
+	}
+}
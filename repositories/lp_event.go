@@ -0,0 +1,257 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LPEventRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLPEventRepository(db *mongo.Database) *LPEventRepository {
+	return &LPEventRepository{
+		collection: db.Collection("lp_events"),
+	}
+}
+
+// Create adds a new event to the ledger
+func (r *LPEventRepository) Create(ctx context.Context, event *models.LPEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	result, err := r.collection.InsertOne(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to create LP event: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		event.ID = oid
+	}
+
+	return nil
+}
+
+// CreateMany adds several events to the ledger in one round trip. The events get their IDs before
+// the insert, so a batch sent again after a failure skips the events already inserted.
+func (r *LPEventRepository) CreateMany(ctx context.Context, events []*models.LPEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		if event.ID.IsZero() {
+			event.ID = primitive.NewObjectID()
+		}
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now()
+		}
+		documents = append(documents, event)
+	}
+
+	_, err := r.collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if err != nil && !onlyDuplicateKeys(err) {
+		return fmt.Errorf("failed to create LP events: %w", err)
+	}
+
+	return nil
+}
+
+// duplicateKeyCode is the code of the write errors on a unique index
+const duplicateKeyCode = 11000
+
+// onlyDuplicateKeys checks if every write of a failed bulk write was rejected as a duplicate (the
+// documents are already stored). mongo.IsDuplicateKeyError would also match a bulk write where
+// other documents failed for another reason.
+func onlyDuplicateKeys(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != duplicateKeyCode {
+			return false
+		}
+	}
+	return true
+}
+
+// FindByID finds an event by its ID
+func (r *LPEventRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.LPEvent, error) {
+	var event models.LPEvent
+
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&event)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find LP event: %w", err)
+	}
+
+	return &event, nil
+}
+
+// FindByPlayerAndMatch finds the event recorded for a game of a player
+func (r *LPEventRepository) FindByPlayerAndMatch(ctx context.Context, puuid, matchID string) (*models.LPEvent, error) {
+	var event models.LPEvent
+
+	err := r.collection.FindOne(ctx, bson.M{"playerPuuid": puuid, "matchId": matchID}).Decode(&event)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find LP event: %w", err)
+	}
+
+	return &event, nil
+}
+
+// FindByPlayerAndMatches returns the events recorded for some games of a player, games without event are missing
+func (r *LPEventRepository) FindByPlayerAndMatches(ctx context.Context, puuid string, matchIDs []string) ([]*models.LPEvent, error) {
+	filter := bson.M{
+		"playerPuuid": puuid,
+		"matchId":     bson.M{"$in": matchIDs},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find LP events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []*models.LPEvent
+	for cursor.Next(ctx) {
+		var event models.LPEvent
+		if err := cursor.Decode(&event); err != nil {
+			return nil, fmt.Errorf("failed to decode LP event: %w", err)
+		}
+		events = append(events, &event)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkNotified records when an event was announced on Discord
+func (r *LPEventRepository) MarkNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"notifiedAt": at}})
+	if err != nil {
+		return fmt.Errorf("failed to mark LP event as notified: %w", err)
+	}
+
+	return nil
+}
+
+// FindBetween returns the events recorded in [start, end), oldest first
+func (r *LPEventRepository) FindBetween(ctx context.Context, start, end time.Time) ([]*models.LPEvent, error) {
+	filter := bson.M{
+		"createdAt": bson.M{
+			"$gte": start,
+			"$lt":  end,
+		},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find LP events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []*models.LPEvent
+	for cursor.Next(ctx) {
+		var event models.LPEvent
+		if err := cursor.Decode(&event); err != nil {
+			return nil, fmt.Errorf("failed to decode LP event: %w", err)
+		}
+		events = append(events, &event)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return events, nil
+}
+
+// FindRecentByPlayer returns the latest events of a player, newest first
+func (r *LPEventRepository) FindRecentByPlayer(ctx context.Context, puuid string, limit int) ([]*models.LPEvent, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"playerPuuid": puuid}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find LP events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []*models.LPEvent
+	for cursor.Next(ctx) {
+		var event models.LPEvent
+		if err := cursor.Decode(&event); err != nil {
+			return nil, fmt.Errorf("failed to decode LP event: %w", err)
+		}
+		events = append(events, &event)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return events, nil
+}
+
+// FindByPlayerSince returns the events of a player recorded since a date, oldest first
+func (r *LPEventRepository) FindByPlayerSince(ctx context.Context, puuid string, since time.Time) ([]*models.LPEvent, error) {
+	filter := bson.M{
+		"playerPuuid": puuid,
+		"createdAt":   bson.M{"$gte": since},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find LP events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []*models.LPEvent
+	for cursor.Next(ctx) {
+		var event models.LPEvent
+		if err := cursor.Decode(&event); err != nil {
+			return nil, fmt.Errorf("failed to decode LP event: %w", err)
+		}
+		events = append(events, &event)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return events, nil
+}
+
+// CountByPlayer returns the number of events (tracked games) of a player
+func (r *LPEventRepository) CountByPlayer(ctx context.Context, puuid string) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"playerPuuid": puuid})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count LP events: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,132 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+)
+
+func testLPEvent(puuid, matchID string, delta int, createdAt time.Time) *models.LPEvent {
+	return &models.LPEvent{
+		PlayerPUUID:  puuid,
+		GameName:     "Faker",
+		TagLine:      "EUW",
+		Server:       "euw1",
+		Tier:         "GOLD",
+		Rank:         "II",
+		LeaguePoints: 50 + delta,
+		LPDelta:      delta,
+		MatchID:      matchID,
+		Victory:      delta > 0,
+		CreatedAt:    createdAt,
+	}
+}
+
+func TestLPEventRepositoryCreateAndFind(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewLPEventRepository(newTestDatabase(t))
+
+	event := testLPEvent("puuid-Faker", "EUW1_1", 20, time.Time{})
+	mustNoError(t, repo.Create(ctx, event))
+	if event.ID.IsZero() || event.CreatedAt.IsZero() {
+		t.Fatal("Create did not set the ID and the creation date of the event")
+	}
+
+	found, err := repo.FindByID(ctx, event.ID)
+	mustNoError(t, err)
+	if found == nil || found.LPDelta != 20 {
+		t.Fatalf("FindByID = %+v, want the event", found)
+	}
+
+	found, err = repo.FindByPlayerAndMatch(ctx, "puuid-Faker", "EUW1_1")
+	mustNoError(t, err)
+	if found == nil || found.ID != event.ID {
+		t.Fatalf("FindByPlayerAndMatch = %+v, want the event", found)
+	}
+
+	found, err = repo.FindByPlayerAndMatch(ctx, "puuid-Faker", "EUW1_2")
+	mustNoError(t, err)
+	if found != nil {
+		t.Errorf("FindByPlayerAndMatch of a game without event = %+v, want nil", found)
+	}
+
+	notifiedAt := time.Now()
+	mustNoError(t, repo.MarkNotified(ctx, event.ID, notifiedAt))
+	found, err = repo.FindByID(ctx, event.ID)
+	mustNoError(t, err)
+	if found.NotifiedAt == nil || !sameTime(*found.NotifiedAt, notifiedAt) {
+		t.Errorf("NotifiedAt = %v, want %v", found.NotifiedAt, notifiedAt)
+	}
+}
+
+func TestLPEventRepositoryCreateManyIsRetryable(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewLPEventRepository(newTestDatabase(t))
+
+	now := time.Now()
+	first := testLPEvent("puuid-Faker", "EUW1_1", 20, now.Add(-time.Hour))
+	mustNoError(t, repo.CreateMany(ctx, nil))
+	mustNoError(t, repo.CreateMany(ctx, []*models.LPEvent{first}))
+
+	// The batch is sent again with a new event after a failed flush, the inserted event is skipped
+	second := testLPEvent("puuid-Faker", "EUW1_2", -15, now)
+	mustNoError(t, repo.CreateMany(ctx, []*models.LPEvent{first, second}))
+
+	count, err := repo.CountByPlayer(ctx, "puuid-Faker")
+	mustNoError(t, err)
+	if count != 2 {
+		t.Errorf("CountByPlayer = %d, want 2", count)
+	}
+
+	events, err := repo.FindByPlayerAndMatches(ctx, "puuid-Faker", []string{"EUW1_1", "EUW1_2", "EUW1_3"})
+	mustNoError(t, err)
+	if len(events) != 2 {
+		t.Errorf("FindByPlayerAndMatches returned %d events, want 2", len(events))
+	}
+}
+
+func TestLPEventRepositoryTimeRanges(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewLPEventRepository(newTestDatabase(t))
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	for _, event := range []*models.LPEvent{
+		testLPEvent("puuid-Faker", "EUW1_1", 20, start.Add(-time.Minute)),
+		testLPEvent("puuid-Faker", "EUW1_2", -15, start.Add(time.Hour)),
+		testLPEvent("puuid-Faker", "EUW1_3", 18, start),
+		testLPEvent("puuid-other", "EUW1_4", 22, start.Add(2*time.Hour)),
+		testLPEvent("puuid-Faker", "EUW1_5", 19, end),
+	} {
+		mustNoError(t, repo.Create(ctx, event))
+	}
+
+	events, err := repo.FindBetween(ctx, start, end)
+	mustNoError(t, err)
+	if got := matchIDs(events); len(got) != 3 || got[0] != "EUW1_3" || got[1] != "EUW1_2" || got[2] != "EUW1_4" {
+		t.Errorf("FindBetween = %v, want EUW1_3, EUW1_2 and EUW1_4", got)
+	}
+
+	events, err = repo.FindByPlayerSince(ctx, "puuid-Faker", start)
+	mustNoError(t, err)
+	if got := matchIDs(events); len(got) != 3 || got[0] != "EUW1_3" || got[2] != "EUW1_5" {
+		t.Errorf("FindByPlayerSince = %v, want EUW1_3, EUW1_2 and EUW1_5", got)
+	}
+
+	events, err = repo.FindRecentByPlayer(ctx, "puuid-Faker", 2)
+	mustNoError(t, err)
+	if got := matchIDs(events); len(got) != 2 || got[0] != "EUW1_5" || got[1] != "EUW1_2" {
+		t.Errorf("FindRecentByPlayer = %v, want EUW1_5 and EUW1_2", got)
+	}
+}
+
+func matchIDs(events []*models.LPEvent) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.MatchID)
+	}
+	return ids
+}
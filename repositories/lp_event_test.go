@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestOnlyDuplicateKeys(t *testing.T) {
+	duplicate := mongo.BulkWriteError{WriteError: mongo.WriteError{Code: duplicateKeyCode}}
+	validation := mongo.BulkWriteError{WriteError: mongo.WriteError{Code: 121}} // Document failed validation
+
+	for _, test := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"duplicates", mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{duplicate, duplicate}}, true},
+		{"wrapped duplicates", fmt.Errorf("insert: %w", mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{duplicate}}), true},
+		{"duplicates and another error", mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{duplicate, validation}}, false},
+		{"write concern", mongo.BulkWriteException{
+			WriteErrors:       []mongo.BulkWriteError{duplicate},
+			WriteConcernError: &mongo.WriteConcernError{Code: 64},
+		}, false},
+		{"not a bulk write", errors.New("connection reset"), false},
+	} {
+		if got := onlyDuplicateKeys(test.err); got != test.want {
+			t.Errorf("%s: onlyDuplicateKeys = %t, want %t", test.name, got, test.want)
+		}
+	}
+}
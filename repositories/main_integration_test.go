@@ -0,0 +1,123 @@
+//go:build integration
+
+// The integration tests run the repositories against a real MongoDB:
+//
+//	go test -tags integration ./repositories/
+//
+// A MongoDB 7.0 container is started with testcontainers (Docker is required), or MONGODB_TEST_URI
+// points the tests to an existing server (ex: a CI service). Every test gets its own database,
+// with the indexes and migrations of the bot, dropped at the end of the test.
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"lp_tracker/database"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	testManager   *database.Manager
+	testDatabases atomic.Int64
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(runIntegrationTests(m))
+}
+
+func runIntegrationTests(m *testing.M) int {
+	ctx := context.Background()
+
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		container, err := startMongoDB(ctx)
+		if err != nil {
+			log.Printf("Failed to start MongoDB: %v", err)
+			return 1
+		}
+		defer func() {
+			if err := testcontainers.TerminateContainer(container); err != nil {
+				log.Printf("Failed to stop MongoDB: %v", err)
+			}
+		}()
+
+		uri, err = container.ConnectionString(ctx)
+		if err != nil {
+			log.Printf("Failed to get the MongoDB URI: %v", err)
+			return 1
+		}
+	}
+
+	manager, err := database.NewManager(database.Config{URI: uri, DatabaseName: "lp_tracker_test"})
+	if err != nil {
+		log.Printf("Failed to connect to MongoDB: %v", err)
+		return 1
+	}
+	defer manager.Close(ctx)
+	testManager = manager
+
+	return m.Run()
+}
+
+// startMongoDB starts the MongoDB container, testcontainers panics when it finds no Docker host
+func startMongoDB(ctx context.Context) (container *mongodb.MongoDBContainer, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("docker is not available (%v), set MONGODB_TEST_URI to use another server", r)
+		}
+	}()
+
+	return mongodb.Run(ctx, "mongo:7.0")
+}
+
+// newTestDatabase returns an empty database with the indexes of the bot, dropped at the end of the test
+func newTestDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	ctx := context.Background()
+	name := fmt.Sprintf("lp_tracker_test_%d", testDatabases.Add(1))
+
+	manager, err := testManager.ForDatabase(ctx, name)
+	if err != nil {
+		t.Fatalf("failed to create database %s: %v", name, err)
+	}
+
+	db := manager.GetDatabase()
+	t.Cleanup(func() {
+		if err := db.Drop(context.Background()); err != nil {
+			t.Errorf("failed to drop database %s: %v", name, err)
+		}
+	})
+	return db
+}
+
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// mustNoError stops the test on an unexpected repository error
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// sameTime compares dates at the millisecond precision of MongoDB
+func sameTime(a, b time.Time) bool {
+	return a.Truncate(time.Millisecond).Equal(b.Truncate(time.Millisecond))
+}
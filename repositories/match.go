@@ -0,0 +1,261 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MatchRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMatchRepository(db *mongo.Database) *MatchRepository {
+	return &MatchRepository{
+		collection: db.Collection("player_matches"),
+	}
+}
+
+// Upsert saves the match of a player, replacing it if it was already stored.
+// The notification date of a stored match is kept.
+func (r *MatchRepository) Upsert(ctx context.Context, match *models.MatchPlayerInfo) error {
+	fields, err := matchFields(match)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx, matchFilter(match), bson.M{"$set": fields}, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save match: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertMany saves several matches in one round trip, like Upsert
+func (r *MatchRepository) UpsertMany(ctx context.Context, matches []*models.MatchPlayerInfo) error {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(matches))
+	for _, match := range matches {
+		fields, err := matchFields(match)
+		if err != nil {
+			return err
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(matchFilter(match)).SetUpdate(bson.M{"$set": fields}).SetUpsert(true))
+	}
+
+	_, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("failed to save matches: %w", err)
+	}
+
+	return nil
+}
+
+func matchFilter(match *models.MatchPlayerInfo) bson.M {
+	return bson.M{
+		"player_puuid": match.PlayerPUUID,
+		"match_id":     match.MatchID,
+	}
+}
+
+// matchFields returns the stored fields of a match, without its ID and notification date
+func matchFields(match *models.MatchPlayerInfo) (bson.M, error) {
+	data, err := bson.Marshal(match)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode match: %w", err)
+	}
+	var fields bson.M
+	if err := bson.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode match: %w", err)
+	}
+	delete(fields, "_id")
+	delete(fields, "notified_at")
+	return fields, nil
+}
+
+// FindByPlayerAndMatch returns the stored match of a player, nil if it was not stored
+func (r *MatchRepository) FindByPlayerAndMatch(ctx context.Context, puuid, matchID string) (*models.MatchPlayerInfo, error) {
+	var match models.MatchPlayerInfo
+
+	err := r.collection.FindOne(ctx, bson.M{"player_puuid": puuid, "match_id": matchID}).Decode(&match)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find match: %w", err)
+	}
+
+	return &match, nil
+}
+
+// MarkNotified records when the match of a player was announced on Discord
+func (r *MatchRepository) MarkNotified(ctx context.Context, puuid, matchID string, at time.Time) error {
+	filter := bson.M{"player_puuid": puuid, "match_id": matchID}
+
+	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"notified_at": at}})
+	if err != nil {
+		return fmt.Errorf("failed to mark match as notified: %w", err)
+	}
+
+	return nil
+}
+
+// FindByPlayerBetween returns the matches of a player played in [start, end), oldest first
+func (r *MatchRepository) FindByPlayerBetween(ctx context.Context, puuid string, start, end time.Time) ([]*models.MatchPlayerInfo, error) {
+	filter := bson.M{
+		"player_puuid": puuid,
+		"created_at": bson.M{
+			"$gte": start,
+			"$lt":  end,
+		},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	return r.find(ctx, filter, opts)
+}
+
+// HasMatchSince checks if a player has a stored match started after since
+func (r *MatchRepository) HasMatchSince(ctx context.Context, puuid string, since time.Time) (bool, error) {
+	filter := bson.M{"player_puuid": puuid, "created_at": bson.M{"$gte": since}}
+	count, err := r.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to count matches: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// FindRecentByPlayer returns the latest matches of a player, newest first
+func (r *MatchRepository) FindRecentByPlayer(ctx context.Context, puuid string, limit int) ([]*models.MatchPlayerInfo, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit))
+	return r.find(ctx, bson.M{"player_puuid": puuid}, opts)
+}
+
+// LastGameAtByPlayer returns the date of the latest stored game of every player, keyed by PUUID
+func (r *MatchRepository) LastGameAtByPlayer(ctx context.Context) (map[string]time.Time, error) {
+	pipeline := newPipeline().
+		group("$player_puuid", bson.M{"lastGameAt": bson.M{"$max": "$created_at"}}).
+		build()
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate last games: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		PUUID      string    `bson:"_id"`
+		LastGameAt time.Time `bson:"lastGameAt"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode last games: %w", err)
+	}
+
+	lastGames := make(map[string]time.Time, len(results))
+	for _, result := range results {
+		lastGames[result.PUUID] = result.LastGameAt
+	}
+	return lastGames, nil
+}
+
+// RoleStatsByPlayer aggregates the games and wins of a player per role, most played first
+func (r *MatchRepository) RoleStatsByPlayer(ctx context.Context, puuid string) ([]*models.RoleStats, error) {
+	pipeline := newPipeline().
+		match(bson.M{"player_puuid": puuid, "role": bson.M{"$ne": ""}}).
+		group("$role", gamesAndWins()).
+		sortDesc("games").
+		build()
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate role stats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var stats []*models.RoleStats
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode role stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// ChampionStatsByPlayer aggregates the games and wins of a player per champion, most played first
+func (r *MatchRepository) ChampionStatsByPlayer(ctx context.Context, puuid string, limit int) ([]*models.ChampionStats, error) {
+	pipeline := newPipeline().
+		match(bson.M{"player_puuid": puuid}).
+		groupByChampion(gamesAndWins()).
+		sortDesc("games").
+		limit(limit).
+		project(bson.M{"champion": "$_id", "games": 1, "wins": 1}).
+		build()
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate champion stats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var stats []*models.ChampionStats
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode champion stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// PatchStatsByPlayer aggregates the Solo/Duo games and wins of a player per patch
+func (r *MatchRepository) PatchStatsByPlayer(ctx context.Context, puuid string) ([]*models.PatchStats, error) {
+	pipeline := newPipeline().
+		match(bson.M{"player_puuid": puuid, "queue_type": "RANKED_SOLO_5x5", "patch": bson.M{"$nin": bson.A{"", nil}}}).
+		group("$patch", gamesAndWins()).
+		build()
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate patch stats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var stats []*models.PatchStats
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode patch stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (r *MatchRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*models.MatchPlayerInfo, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find matches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var matches []*models.MatchPlayerInfo
+	for cursor.Next(ctx) {
+		var match models.MatchPlayerInfo
+		if err := cursor.Decode(&match); err != nil {
+			return nil, fmt.Errorf("failed to decode match: %w", err)
+		}
+		matches = append(matches, &match)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return matches, nil
+}
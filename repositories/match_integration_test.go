@@ -0,0 +1,207 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+)
+
+func testMatch(matchID, champion, role string, victory bool, playedAt time.Time) *models.MatchPlayerInfo {
+	return &models.MatchPlayerInfo{
+		PlayerPUUID: "puuid-Faker",
+		MatchID:     matchID,
+		Champion:    champion,
+		Role:        role,
+		Victory:     victory,
+		QueueType:   "RANKED_SOLO_5x5",
+		Patch:       "14.5",
+		CreatedAt:   playedAt,
+	}
+}
+
+func TestMatchRepositoryUpsertKeepsNotificationDate(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewMatchRepository(newTestDatabase(t))
+
+	match := testMatch("EUW1_1", "Ahri", "MIDDLE", true, time.Now())
+	mustNoError(t, repo.Upsert(ctx, match))
+
+	notifiedAt := time.Now()
+	mustNoError(t, repo.MarkNotified(ctx, match.PlayerPUUID, match.MatchID, notifiedAt))
+
+	// Ingested again (ex: after a crash), the game stays announced
+	match.Kills = 10
+	mustNoError(t, repo.Upsert(ctx, match))
+
+	found, err := repo.FindByPlayerAndMatch(ctx, match.PlayerPUUID, match.MatchID)
+	mustNoError(t, err)
+	if found == nil {
+		t.Fatal("FindByPlayerAndMatch = nil, want the game")
+	}
+	if found.Kills != 10 {
+		t.Errorf("kills = %d, want 10", found.Kills)
+	}
+	if found.NotifiedAt == nil || !sameTime(*found.NotifiedAt, notifiedAt) {
+		t.Errorf("NotifiedAt = %v, want %v", found.NotifiedAt, notifiedAt)
+	}
+
+	recent, err := repo.FindRecentByPlayer(ctx, match.PlayerPUUID, 10)
+	mustNoError(t, err)
+	if len(recent) != 1 {
+		t.Errorf("the upserts stored %d games, want 1", len(recent))
+	}
+
+	found, err = repo.FindByPlayerAndMatch(ctx, match.PlayerPUUID, "EUW1_2")
+	mustNoError(t, err)
+	if found != nil {
+		t.Errorf("FindByPlayerAndMatch of an unknown game = %+v, want nil", found)
+	}
+}
+
+func TestMatchRepositoryUpsertMany(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewMatchRepository(newTestDatabase(t))
+
+	now := time.Now()
+	matches := []*models.MatchPlayerInfo{
+		testMatch("EUW1_1", "Ahri", "MIDDLE", true, now.Add(-2*time.Hour)),
+		testMatch("EUW1_2", "Ahri", "MIDDLE", false, now.Add(-time.Hour)),
+	}
+	mustNoError(t, repo.UpsertMany(ctx, nil))
+	mustNoError(t, repo.UpsertMany(ctx, matches))
+	// A batch sent again after a failure does not duplicate the games
+	mustNoError(t, repo.UpsertMany(ctx, matches))
+
+	recent, err := repo.FindRecentByPlayer(ctx, "puuid-Faker", 10)
+	mustNoError(t, err)
+	if len(recent) != 2 || recent[0].MatchID != "EUW1_2" {
+		t.Fatalf("FindRecentByPlayer returned %d games, want EUW1_2 then EUW1_1", len(recent))
+	}
+
+	recent, err = repo.FindRecentByPlayer(ctx, "puuid-Faker", 1)
+	mustNoError(t, err)
+	if len(recent) != 1 {
+		t.Errorf("FindRecentByPlayer with a limit of 1 returned %d games", len(recent))
+	}
+}
+
+func TestMatchRepositoryFindByPlayerBetween(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewMatchRepository(newTestDatabase(t))
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	for _, match := range []*models.MatchPlayerInfo{
+		testMatch("EUW1_1", "Ahri", "MIDDLE", true, start.Add(-time.Minute)),
+		testMatch("EUW1_2", "Ahri", "MIDDLE", true, start.Add(2*time.Hour)),
+		testMatch("EUW1_3", "Ahri", "MIDDLE", true, start),
+		testMatch("EUW1_4", "Ahri", "MIDDLE", true, end),
+	} {
+		mustNoError(t, repo.Upsert(ctx, match))
+	}
+
+	matches, err := repo.FindByPlayerBetween(ctx, "puuid-Faker", start, end)
+	mustNoError(t, err)
+	if len(matches) != 2 || matches[0].MatchID != "EUW1_3" || matches[1].MatchID != "EUW1_2" {
+		t.Fatalf("FindByPlayerBetween returned %d games, want EUW1_3 then EUW1_2", len(matches))
+	}
+
+	played, err := repo.HasMatchSince(ctx, "puuid-Faker", end)
+	mustNoError(t, err)
+	if !played {
+		t.Error("HasMatchSince(end) = false, want true")
+	}
+	played, err = repo.HasMatchSince(ctx, "puuid-Faker", end.Add(time.Second))
+	mustNoError(t, err)
+	if played {
+		t.Error("HasMatchSince after the last game = true, want false")
+	}
+}
+
+func TestMatchRepositoryAggregations(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewMatchRepository(newTestDatabase(t))
+
+	now := time.Now()
+	flex := testMatch("EUW1_5", "Lux", "UTILITY", true, now)
+	flex.QueueType = "RANKED_FLEX_SR"
+	oldPatch := testMatch("EUW1_6", "Ahri", "", false, now.Add(-48*time.Hour))
+	oldPatch.Patch = "14.4"
+	other := testMatch("EUW1_7", "Zed", "MIDDLE", true, now.Add(-time.Hour))
+	other.PlayerPUUID = "puuid-other"
+
+	for _, match := range []*models.MatchPlayerInfo{
+		testMatch("EUW1_1", "Ahri", "MIDDLE", true, now.Add(-4*time.Hour)),
+		testMatch("EUW1_2", "Ahri", "MIDDLE", true, now.Add(-3*time.Hour)),
+		testMatch("EUW1_3", "Ahri", "MIDDLE", false, now.Add(-2*time.Hour)),
+		testMatch("EUW1_4", "", "UTILITY", false, now.Add(-time.Hour)),
+		flex,
+		oldPatch,
+		other,
+	} {
+		mustNoError(t, repo.Upsert(ctx, match))
+	}
+
+	roles, err := repo.RoleStatsByPlayer(ctx, "puuid-Faker")
+	mustNoError(t, err)
+	if len(roles) != 2 {
+		t.Fatalf("RoleStatsByPlayer returned %d roles, want MIDDLE and UTILITY (games without role skipped)", len(roles))
+	}
+	if roles[0].Role != "MIDDLE" || roles[0].Games != 3 || roles[0].Wins != 2 {
+		t.Errorf("first role = %+v, want MIDDLE with 3 games and 2 wins", roles[0])
+	}
+	if roles[1].Role != "UTILITY" || roles[1].Games != 2 || roles[1].Wins != 1 {
+		t.Errorf("second role = %+v, want UTILITY with 2 games and 1 win", roles[1])
+	}
+
+	champions, err := repo.ChampionStatsByPlayer(ctx, "puuid-Faker", 1)
+	mustNoError(t, err)
+	if len(champions) != 1 {
+		t.Fatalf("ChampionStatsByPlayer with a limit of 1 returned %d champions", len(champions))
+	}
+	if champions[0].Champion != "Ahri" || champions[0].Games != 4 || champions[0].Wins != 2 {
+		t.Errorf("most played champion = %+v, want Ahri with 4 games and 2 wins", champions[0])
+	}
+
+	champions, err = repo.ChampionStatsByPlayer(ctx, "puuid-Faker", 10)
+	mustNoError(t, err)
+	if len(champions) != 2 {
+		t.Errorf("ChampionStatsByPlayer returned %d champions, want Ahri and Lux (games without champion skipped)", len(champions))
+	}
+
+	patches, err := repo.PatchStatsByPlayer(ctx, "puuid-Faker")
+	mustNoError(t, err)
+	if len(patches) != 2 {
+		t.Fatalf("PatchStatsByPlayer returned %d patches, want 14.4 and 14.5", len(patches))
+	}
+	for _, patch := range patches {
+		switch patch.Patch {
+		case "14.5":
+			// The Flex game is not counted
+			if patch.Games != 4 || patch.Wins != 2 {
+				t.Errorf("patch 14.5 = %+v, want 4 games and 2 wins", patch)
+			}
+		case "14.4":
+			if patch.Games != 1 || patch.Wins != 0 {
+				t.Errorf("patch 14.4 = %+v, want 1 game and no win", patch)
+			}
+		default:
+			t.Errorf("unexpected patch %q", patch.Patch)
+		}
+	}
+
+	lastGames, err := repo.LastGameAtByPlayer(ctx)
+	mustNoError(t, err)
+	if len(lastGames) != 2 {
+		t.Fatalf("LastGameAtByPlayer returned %d players, want 2", len(lastGames))
+	}
+	if !sameTime(lastGames["puuid-Faker"], now) {
+		t.Errorf("last game of Faker = %v, want %v", lastGames["puuid-Faker"], now)
+	}
+	if !sameTime(lastGames["puuid-other"], now.Add(-time.Hour)) {
+		t.Errorf("last game of the other player = %v, want %v", lastGames["puuid-other"], now.Add(-time.Hour))
+	}
+}
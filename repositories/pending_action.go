@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PendingActionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPendingActionRepository(db *mongo.Database) *PendingActionRepository {
+	return &PendingActionRepository{
+		collection: db.Collection("pending_actions"),
+	}
+}
+
+// Enqueue saves a pending action, replacing the pending action of the same kind of the user
+func (r *PendingActionRepository) Enqueue(ctx context.Context, action *models.PendingAction) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"discordUserId": action.DiscordUserID, "kind": action.Kind})
+	if err != nil {
+		return fmt.Errorf("failed to replace pending action: %w", err)
+	}
+
+	result, err := r.collection.InsertOne(ctx, action)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue pending action: %w", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		action.ID = oid
+	}
+
+	return nil
+}
+
+// FindDue returns the actions to check, expired ones included, oldest check first
+func (r *PendingActionRepository) FindDue(ctx context.Context, now time.Time) ([]*models.PendingAction, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"nextCheckAt": bson.M{"$lte": now}},
+		{"expiresAt": bson.M{"$lte": now}},
+	}}
+	opts := options.Find().SetSort(bson.D{{Key: "nextCheckAt", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending actions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var actions []*models.PendingAction
+	if err := cursor.All(ctx, &actions); err != nil {
+		return nil, fmt.Errorf("failed to decode pending actions: %w", err)
+	}
+
+	return actions, nil
+}
+
+// Reschedule sets the next check of an action and its reminders count
+func (r *PendingActionRepository) Reschedule(ctx context.Context, action *models.PendingAction) error {
+	update := bson.M{"$set": bson.M{"nextCheckAt": action.NextCheckAt, "prompts": action.Prompts}}
+	_, err := r.collection.UpdateByID(ctx, action.ID, update)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule pending action: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an action once its flow is over
+func (r *PendingActionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete pending action: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByRef removes the actions of a flow, used when the user completes it from a command
+func (r *PendingActionRepository) DeleteByRef(ctx context.Context, kind models.PendingActionKind, refID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"kind": kind, "refId": refID})
+	if err != nil {
+		return fmt.Errorf("failed to delete pending actions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPendingActionRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewPendingActionRepository(newTestDatabase(t))
+
+	now := time.Now()
+	first := &models.PendingAction{
+		Kind:          models.PendingAccountVerification,
+		DiscordUserID: "user-1",
+		RefID:         primitive.NewObjectID(),
+		NextCheckAt:   now.Add(-time.Minute),
+		ExpiresAt:     now.Add(time.Hour),
+	}
+	mustNoError(t, repo.Enqueue(ctx, first))
+
+	// A new verification of the user replaces the first one
+	second := &models.PendingAction{
+		Kind:          models.PendingAccountVerification,
+		DiscordUserID: "user-1",
+		RefID:         primitive.NewObjectID(),
+		NextCheckAt:   now.Add(-2 * time.Minute),
+		ExpiresAt:     now.Add(time.Hour),
+	}
+	mustNoError(t, repo.Enqueue(ctx, second))
+
+	expired := &models.PendingAction{
+		Kind:          models.PendingAccountVerification,
+		DiscordUserID: "user-2",
+		RefID:         primitive.NewObjectID(),
+		NextCheckAt:   now.Add(time.Hour),
+		ExpiresAt:     now.Add(-time.Minute),
+	}
+	later := &models.PendingAction{
+		Kind:          models.PendingAccountVerification,
+		DiscordUserID: "user-3",
+		RefID:         primitive.NewObjectID(),
+		NextCheckAt:   now.Add(time.Hour),
+		ExpiresAt:     now.Add(2 * time.Hour),
+	}
+	mustNoError(t, repo.Enqueue(ctx, expired))
+	mustNoError(t, repo.Enqueue(ctx, later))
+
+	due, err := repo.FindDue(ctx, now)
+	mustNoError(t, err)
+	if len(due) != 2 || due[0].ID != second.ID || due[1].ID != expired.ID {
+		t.Fatalf("FindDue returned %d actions, want the second action of user-1 then the expired one", len(due))
+	}
+
+	second.NextCheckAt = now.Add(time.Minute)
+	second.Prompts = 1
+	mustNoError(t, repo.Reschedule(ctx, second))
+	mustNoError(t, repo.Delete(ctx, expired.ID))
+	due, err = repo.FindDue(ctx, now)
+	mustNoError(t, err)
+	if len(due) != 0 {
+		t.Errorf("FindDue after the reschedule returned %d actions, want none", len(due))
+	}
+
+	due, err = repo.FindDue(ctx, now.Add(90*time.Second))
+	mustNoError(t, err)
+	if len(due) != 1 || due[0].Prompts != 1 {
+		t.Fatalf("FindDue at the next check returned %d actions, want the rescheduled one", len(due))
+	}
+
+	mustNoError(t, repo.DeleteByRef(ctx, models.PendingAccountVerification, second.RefID))
+	due, err = repo.FindDue(ctx, now.Add(3*time.Hour))
+	mustNoError(t, err)
+	if len(due) != 1 || due[0].ID != later.ID {
+		t.Errorf("FindDue after DeleteByRef returned %d actions, want the action of user-3", len(due))
+	}
+}
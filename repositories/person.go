@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PersonRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPersonRepository(db *mongo.Database) *PersonRepository {
+	return &PersonRepository{
+		collection: db.Collection("persons"),
+	}
+}
+
+// FindAll returns all persons sorted by name
+func (r *PersonRepository) FindAll(ctx context.Context) ([]*models.Person, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find persons: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var persons []*models.Person
+	if err := cursor.All(ctx, &persons); err != nil {
+		return nil, fmt.Errorf("failed to decode persons: %w", err)
+	}
+
+	return persons, nil
+}
+
+// FindByName finds a person by name, nil if it does not exist
+func (r *PersonRepository) FindByName(ctx context.Context, name string) (*models.Person, error) {
+	var person models.Person
+
+	err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&person)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find person: %w", err)
+	}
+
+	return &person, nil
+}
+
+// AddPlayer links an account to a person, creating the person if needed
+func (r *PersonRepository) AddPlayer(ctx context.Context, name, puuid string) error {
+	now := time.Now()
+	update := bson.M{
+		"$addToSet":    bson.M{"playerPuuids": puuid},
+		"$set":         bson.M{"updatedAt": now},
+		"$setOnInsert": bson.M{"createdAt": now},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"name": name}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to link account: %w", err)
+	}
+
+	return nil
+}
+
+// RemovePlayer unlinks an account from any person and deletes the persons left without account
+func (r *PersonRepository) RemovePlayer(ctx context.Context, puuid string) error {
+	update := bson.M{
+		"$pull": bson.M{"playerPuuids": puuid},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+
+	_, err := r.collection.UpdateMany(ctx, bson.M{"playerPuuids": puuid}, update)
+	if err != nil {
+		return fmt.Errorf("failed to unlink account: %w", err)
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"playerPuuids": bson.M{"$size": 0}})
+	if err != nil {
+		return fmt.Errorf("failed to delete empty persons: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+)
+
+func TestPersonRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewPersonRepository(newTestDatabase(t))
+
+	mustNoError(t, repo.AddPlayer(ctx, "bob", "puuid-main"))
+	mustNoError(t, repo.AddPlayer(ctx, "bob", "puuid-smurf"))
+	mustNoError(t, repo.AddPlayer(ctx, "bob", "puuid-smurf"))
+	mustNoError(t, repo.AddPlayer(ctx, "alice", "puuid-alice"))
+
+	bob, err := repo.FindByName(ctx, "bob")
+	mustNoError(t, err)
+	if bob == nil || len(bob.PlayerPUUIDs) != 2 || bob.CreatedAt.IsZero() {
+		t.Fatalf("FindByName(bob) = %+v, want 2 accounts", bob)
+	}
+
+	persons, err := repo.FindAll(ctx)
+	mustNoError(t, err)
+	if len(persons) != 2 || persons[0].Name != "alice" || persons[1].Name != "bob" {
+		t.Fatalf("FindAll returned %d persons, want alice then bob", len(persons))
+	}
+
+	mustNoError(t, repo.RemovePlayer(ctx, "puuid-smurf"))
+	mustNoError(t, repo.RemovePlayer(ctx, "puuid-alice"))
+
+	alice, err := repo.FindByName(ctx, "alice")
+	mustNoError(t, err)
+	if alice != nil {
+		t.Errorf("FindByName(alice) without account = %+v, want the person deleted", alice)
+	}
+	bob, err = repo.FindByName(ctx, "bob")
+	mustNoError(t, err)
+	if bob == nil || len(bob.PlayerPUUIDs) != 1 || bob.PlayerPUUIDs[0] != "puuid-main" {
+		t.Errorf("FindByName(bob) = %+v, want the main account left", bob)
+	}
+}
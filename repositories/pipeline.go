@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// pipelineBuilder assembles an aggregation pipeline stage by stage, so the repositories describe
+// what they aggregate instead of nesting bson documents by hand
+type pipelineBuilder struct {
+	stages mongo.Pipeline
+}
+
+func newPipeline() *pipelineBuilder {
+	return &pipelineBuilder{}
+}
+
+func (p *pipelineBuilder) stage(name string, value any) *pipelineBuilder {
+	p.stages = append(p.stages, bson.D{{Key: name, Value: value}})
+	return p
+}
+
+func (p *pipelineBuilder) match(filter bson.M) *pipelineBuilder {
+	return p.stage("$match", filter)
+}
+
+// group groups the documents by key (a "$field" path or an expression), the accumulators become the fields of the groups
+func (p *pipelineBuilder) group(key any, accumulators bson.M) *pipelineBuilder {
+	group := bson.M{"_id": key}
+	for name, accumulator := range accumulators {
+		group[name] = accumulator
+	}
+	return p.stage("$group", group)
+}
+
+// groupByTimeWindow groups the documents by window of binSize units ("hour", "day", "week", "month"...) of a date field,
+// the _id of a group is the start of its window (in UTC)
+func (p *pipelineBuilder) groupByTimeWindow(dateField, unit string, binSize int, accumulators bson.M) *pipelineBuilder {
+	return p.group(bson.M{"$dateTrunc": bson.M{"date": "$" + dateField, "unit": unit, "binSize": binSize}}, accumulators)
+}
+
+// groupByChampion groups the games by champion, the games without champion are skipped
+func (p *pipelineBuilder) groupByChampion(accumulators bson.M) *pipelineBuilder {
+	return p.match(bson.M{"champion": bson.M{"$ne": ""}}).group("$champion", accumulators)
+}
+
+func (p *pipelineBuilder) sort(fields bson.D) *pipelineBuilder {
+	return p.stage("$sort", fields)
+}
+
+// sortDesc sorts on a single field, highest first
+func (p *pipelineBuilder) sortDesc(field string) *pipelineBuilder {
+	return p.sort(bson.D{{Key: field, Value: -1}})
+}
+
+func (p *pipelineBuilder) limit(n int) *pipelineBuilder {
+	return p.stage("$limit", n)
+}
+
+func (p *pipelineBuilder) project(fields bson.M) *pipelineBuilder {
+	return p.stage("$project", fields)
+}
+
+func (p *pipelineBuilder) build() mongo.Pipeline {
+	return p.stages
+}
+
+// countAccumulator counts the documents of a group
+func countAccumulator() bson.M {
+	return bson.M{"$sum": 1}
+}
+
+// countIfAccumulator counts the documents of a group matching a boolean expression (ex: "$victory")
+func countIfAccumulator(condition any) bson.M {
+	return bson.M{"$sum": bson.M{"$cond": bson.A{condition, 1, 0}}}
+}
+
+// percentileAccumulator computes percentiles of a numeric field (ex: 0.5, 0.9), returned as an array in the same order.
+// The percentiles are approximated by the server, which needs MongoDB 7.0.
+func percentileAccumulator(field string, percentiles ...float64) bson.M {
+	return bson.M{"$percentile": bson.M{"input": "$" + field, "p": percentiles, "method": "approximate"}}
+}
+
+// gamesAndWins are the accumulators of the game stats (ex: per role or per champion)
+func gamesAndWins() bson.M {
+	return bson.M{
+		"games": countAccumulator(),
+		"wins":  countIfAccumulator("$victory"),
+	}
+}
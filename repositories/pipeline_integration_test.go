@@ -0,0 +1,64 @@
+//go:build integration
+
+package repositories
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// The helpers are run by the server, the unit tests only check the stages they build
+func TestPipelineBuilderTimeWindowAndPercentiles(t *testing.T) {
+	ctx := testContext(t)
+	db := newTestDatabase(t)
+	repo := NewMatchRepository(db)
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for idx, game := range []struct {
+		playedAt time.Time
+		kills    int
+	}{
+		{day.Add(1 * time.Hour), 2},
+		{day.Add(5 * time.Hour), 4},
+		{day.Add(23 * time.Hour), 12},
+		{day.Add(26 * time.Hour), 7},
+	} {
+		match := testMatch(fmt.Sprintf("EUW1_%d", idx+1), "Ahri", "MIDDLE", true, game.playedAt)
+		match.Kills = game.kills
+		mustNoError(t, repo.Upsert(ctx, match))
+	}
+
+	pipeline := newPipeline().
+		match(bson.M{"player_puuid": "puuid-Faker"}).
+		groupByTimeWindow("created_at", "day", 1, bson.M{
+			"games": countAccumulator(),
+			"kills": percentileAccumulator("kills", 0.5, 1),
+		}).
+		sort(bson.D{{Key: "_id", Value: 1}}).
+		build()
+
+	cursor, err := db.Collection("matches").Aggregate(ctx, pipeline)
+	mustNoError(t, err)
+	var windows []struct {
+		Start time.Time `bson:"_id"`
+		Games int       `bson:"games"`
+		Kills []float64 `bson:"kills"`
+	}
+	mustNoError(t, cursor.All(ctx, &windows))
+
+	if len(windows) != 2 {
+		t.Fatalf("%d windows, want the 2 days of games", len(windows))
+	}
+	if !windows[0].Start.Equal(day) || windows[0].Games != 3 {
+		t.Errorf("first window = %+v, want 3 games on %v", windows[0], day)
+	}
+	if len(windows[0].Kills) != 2 || windows[0].Kills[0] != 4 || windows[0].Kills[1] != 12 {
+		t.Errorf("kills percentiles of the first day = %v, want a median of 4 and a max of 12", windows[0].Kills)
+	}
+	if !windows[1].Start.Equal(day.Add(24*time.Hour)) || windows[1].Games != 1 {
+		t.Errorf("second window = %+v, want 1 game the next day", windows[1])
+	}
+}
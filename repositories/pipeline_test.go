@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestPipelineBuilderStages(t *testing.T) {
+	pipeline := newPipeline().
+		match(bson.M{"player_puuid": "puuid-1"}).
+		group("$role", gamesAndWins()).
+		sortDesc("games").
+		limit(5).
+		project(bson.M{"role": "$_id", "games": 1}).
+		build()
+
+	want := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"player_puuid": "puuid-1"}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$role",
+			"games": bson.M{"$sum": 1},
+			"wins":  bson.M{"$sum": bson.M{"$cond": bson.A{"$victory", 1, 0}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "games", Value: -1}}}},
+		{{Key: "$limit", Value: 5}},
+		{{Key: "$project", Value: bson.M{"role": "$_id", "games": 1}}},
+	}
+	if !reflect.DeepEqual(pipeline, want) {
+		t.Errorf("pipeline = %v, want %v", pipeline, want)
+	}
+}
+
+func TestPipelineBuilderEmpty(t *testing.T) {
+	if pipeline := newPipeline().build(); len(pipeline) != 0 {
+		t.Errorf("empty builder = %v, want no stage", pipeline)
+	}
+}
+
+func TestPipelineBuilderGroupKeepsAccumulators(t *testing.T) {
+	accumulators := gamesAndWins()
+	newPipeline().group("$champion", accumulators)
+
+	// The _id of the group is not added to the accumulators of the caller, they can be reused
+	if _, ok := accumulators["_id"]; ok || len(accumulators) != 2 {
+		t.Errorf("accumulators = %v after group, want them unchanged", accumulators)
+	}
+}
+
+func TestPipelineBuilderGroupByChampion(t *testing.T) {
+	pipeline := newPipeline().groupByChampion(bson.M{"games": countAccumulator()}).build()
+
+	want := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"champion": bson.M{"$ne": ""}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$champion", "games": bson.M{"$sum": 1}}}},
+	}
+	if !reflect.DeepEqual(pipeline, want) {
+		t.Errorf("pipeline = %v, want %v", pipeline, want)
+	}
+}
+
+func TestPipelineBuilderGroupByTimeWindow(t *testing.T) {
+	pipeline := newPipeline().groupByTimeWindow("created_at", "day", 7, bson.M{"games": countAccumulator()}).build()
+
+	want := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$dateTrunc": bson.M{"date": "$created_at", "unit": "day", "binSize": 7}},
+			"games": bson.M{"$sum": 1},
+		}}},
+	}
+	if !reflect.DeepEqual(pipeline, want) {
+		t.Errorf("pipeline = %v, want %v", pipeline, want)
+	}
+}
+
+func TestPercentileAccumulator(t *testing.T) {
+	got := percentileAccumulator("kills", 0.5, 0.9)
+
+	want := bson.M{"$percentile": bson.M{"input": "$kills", "p": []float64{0.5, 0.9}, "method": "approximate"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("percentileAccumulator = %v, want %v", got, want)
+	}
+}
+
+func TestPipelineBuilderMarshals(t *testing.T) {
+	pipeline := newPipeline().
+		match(bson.M{"player_puuid": "puuid-1"}).
+		groupByTimeWindow("created_at", "week", 1, bson.M{"kills": percentileAccumulator("kills", 0.5)}).
+		sortDesc("_id").
+		build()
+
+	// The driver sends every stage as a document, in the order of the builder
+	for idx, stage := range pipeline {
+		raw, err := bson.Marshal(stage)
+		if err != nil {
+			t.Fatalf("stage %d cannot be marshalled: %v", idx, err)
+		}
+		elements, err := bson.Raw(raw).Elements()
+		if err != nil || len(elements) != 1 {
+			t.Fatalf("stage %d = %v, want a single operator", idx, bson.Raw(raw))
+		}
+	}
+	if pipeline[1][0].Key != "$group" || pipeline[2][0].Key != "$sort" {
+		t.Errorf("stages %s then %s, want $group then $sort", pipeline[1][0].Key, pipeline[2][0].Key)
+	}
+}
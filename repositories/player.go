@@ -92,12 +92,30 @@ func (r *PlayerRepository) FindByPUUID(ctx context.Context, puuid string) (*mode
 	return &player, nil
 }
 
-// Update updates an existing player
-func (r *PlayerRepository) Update(ctx context.Context, player *models.Player) error {
+// UpdatePolled saves the fields owned by the poll: rank, summoner data, match checkpoint and poll date.
+// The other fields are edited meanwhile by the commands and the watchers (pause, tags, Twitch, live game...)
+// with their own setters, they are left untouched.
+func (r *PlayerRepository) UpdatePolled(ctx context.Context, player *models.Player) error {
 	player.UpdatedAt = time.Now()
 
-	filter := bson.M{"_id": player.ID}
-	_, err := r.collection.ReplaceOne(ctx, filter, player)
+	fields := bson.M{
+		"summonerId":    player.SummonerID,
+		"summonerLevel": player.SummonerLevel,
+		"profileIconId": player.ProfileIconID,
+		"tier":          player.Tier,
+		"rank":          player.Rank,
+		"leaguePoints":  player.LeaguePoints,
+		"wins":          player.Wins,
+		"losses":        player.Losses,
+		"lastPolledAt":  player.LastPolledAt,
+		"updatedAt":     player.UpdatedAt,
+	}
+	// Buffered cycles move the checkpoints on their own, see SetLastMatchIDs
+	if player.LastMatchID != "" {
+		fields["lastMatchId"] = player.LastMatchID
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": player.ID}, bson.M{"$set": fields})
 	if err != nil {
 		return fmt.Errorf("failed to update player: %w", err)
 	}
@@ -129,6 +147,22 @@ func (r *PlayerRepository) FindAll(ctx context.Context) ([]*models.Player, error
 	return players, nil
 }
 
+// FindByPUUIDs returns the players with the given PUUIDs
+func (r *PlayerRepository) FindByPUUIDs(ctx context.Context, puuids []string) ([]*models.Player, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"puuid": bson.M{"$in": puuids}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find players: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var players []*models.Player
+	if err := cursor.All(ctx, &players); err != nil {
+		return nil, fmt.Errorf("failed to decode players: %w", err)
+	}
+
+	return players, nil
+}
+
 // FindAllWithPagination returns players with pagination
 func (r *PlayerRepository) FindAllWithPagination(ctx context.Context, page, limit int) ([]*models.Player, int64, error) {
 	// Count total documents
@@ -186,6 +220,170 @@ func (r *PlayerRepository) DeleteByRiotID(ctx context.Context, gameName, tagLine
 	return nil
 }
 
+// SetPaused pauses or resumes the polling of a player
+func (r *PlayerRepository) SetPaused(ctx context.Context, id primitive.ObjectID, paused bool) error {
+	update := bson.M{
+		"$set": bson.M{
+			"paused":    paused,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update player: %w", err)
+	}
+
+	return nil
+}
+
+// SetLastMatchIDs moves the match checkpoints of several players (keyed by player ID) in one round trip
+func (r *PlayerRepository) SetLastMatchIDs(ctx context.Context, checkpoints map[primitive.ObjectID]string) error {
+	if len(checkpoints) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(checkpoints))
+	for id, matchID := range checkpoints {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$set": bson.M{"lastMatchId": matchID}}))
+	}
+
+	_, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("failed to update match checkpoints: %w", err)
+	}
+
+	return nil
+}
+
+// SetAliasAndNote updates the alias and the note of a player, empty values clear them
+func (r *PlayerRepository) SetAliasAndNote(ctx context.Context, id primitive.ObjectID, alias, note string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"alias":     alias,
+			"note":      note,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update player: %w", err)
+	}
+
+	return nil
+}
+
+// SetTwitchChannel maps a player to a Twitch channel, an empty channel removes the mapping
+func (r *PlayerRepository) SetTwitchChannel(ctx context.Context, id primitive.ObjectID, channel string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"twitchChannel": channel,
+			"twitchLive":    false,
+			"updatedAt":     time.Now(),
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update player: %w", err)
+	}
+
+	return nil
+}
+
+// SetTwitchLive records the stream state of a player at the last check
+func (r *PlayerRepository) SetTwitchLive(ctx context.Context, id primitive.ObjectID, live bool) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"twitchLive": live}})
+	if err != nil {
+		return fmt.Errorf("failed to update player: %w", err)
+	}
+
+	return nil
+}
+
+// FindWithTwitchChannel returns the players mapped to a Twitch channel
+func (r *PlayerRepository) FindWithTwitchChannel(ctx context.Context) ([]*models.Player, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"twitchChannel": bson.M{"$nin": bson.A{"", nil}}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find players: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var players []*models.Player
+	if err := cursor.All(ctx, &players); err != nil {
+		return nil, fmt.Errorf("failed to decode players: %w", err)
+	}
+
+	return players, nil
+}
+
+// SetPublicProfile publishes or hides the public profile page of a player
+func (r *PlayerRepository) SetPublicProfile(ctx context.Context, id primitive.ObjectID, public bool) error {
+	update := bson.M{
+		"$set": bson.M{
+			"publicProfile": public,
+			"updatedAt":     time.Now(),
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update player: %w", err)
+	}
+
+	return nil
+}
+
+// SetLiveGameID records the last live game seen for a player
+func (r *PlayerRepository) SetLiveGameID(ctx context.Context, id primitive.ObjectID, gameID int64) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"liveGameId": gameID}})
+	if err != nil {
+		return fmt.Errorf("failed to update player: %w", err)
+	}
+
+	return nil
+}
+
+// SetTiltAlertsOptOut opts a player out of the tilt alerts, or back in
+func (r *PlayerRepository) SetTiltAlertsOptOut(ctx context.Context, id primitive.ObjectID, optOut bool) error {
+	update := bson.M{
+		"$set": bson.M{
+			"tiltAlertsOptOut": optOut,
+			"updatedAt":        time.Now(),
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update player: %w", err)
+	}
+
+	return nil
+}
+
+// SetLastTiltAlertAt records when the last tilt alert of a player was sent
+func (r *PlayerRepository) SetLastTiltAlertAt(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"lastTiltAlertAt": at}})
+	if err != nil {
+		return fmt.Errorf("failed to update player: %w", err)
+	}
+
+	return nil
+}
+
+// SetLastSessionSummaryAt records the end of the last play session summarized for a player
+func (r *PlayerRepository) SetLastSessionSummaryAt(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"lastSessionSummaryAt": at}})
+	if err != nil {
+		return fmt.Errorf("failed to update player: %w", err)
+	}
+
+	return nil
+}
+
 // Exists checks if a player exists
 func (r *PlayerRepository) Exists(ctx context.Context, gameName, tagLine, server string) (bool, error) {
 	filter := bson.M{
@@ -202,6 +400,16 @@ func (r *PlayerRepository) Exists(ctx context.Context, gameName, tagLine, server
 	return count > 0, nil
 }
 
+// Count returns the number of stored players, paused ones included
+func (r *PlayerRepository) Count(ctx context.Context) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count players: %w", err)
+	}
+
+	return count, nil
+}
+
 // FindByServer returns all players from a specific server
 func (r *PlayerRepository) FindByServer(ctx context.Context, server string) ([]*models.Player, error) {
 	cursor, err := r.collection.Find(ctx, bson.M{"server": server})
@@ -221,3 +429,57 @@ func (r *PlayerRepository) FindByServer(ctx context.Context, server string) ([]*
 
 	return players, nil
 }
+
+// FindByTag returns all players with the given tag
+func (r *PlayerRepository) FindByTag(ctx context.Context, tag string) ([]*models.Player, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"tags": tag})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find players by tag: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var players []*models.Player
+	for cursor.Next(ctx) {
+		var player models.Player
+		if err := cursor.Decode(&player); err != nil {
+			return nil, fmt.Errorf("failed to decode player: %w", err)
+		}
+		players = append(players, &player)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return players, nil
+}
+
+// AddTag adds a tag to a player (no-op if already present)
+func (r *PlayerRepository) AddTag(ctx context.Context, id primitive.ObjectID, tag string) error {
+	update := bson.M{
+		"$addToSet": bson.M{"tags": tag},
+		"$set":      bson.M{"updatedAt": time.Now()},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to add tag: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveTag removes a tag from a player
+func (r *PlayerRepository) RemoveTag(ctx context.Context, id primitive.ObjectID, tag string) error {
+	update := bson.M{
+		"$pull": bson.M{"tags": tag},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to remove tag: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,327 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func createTestPlayer(t *testing.T, ctx context.Context, repo *PlayerRepository, gameName string) *models.Player {
+	t.Helper()
+
+	player := &models.Player{
+		PUUID:    "puuid-" + gameName,
+		GameName: gameName,
+		TagLine:  "EUW",
+		Server:   "euw1",
+		Tier:     "GOLD",
+		Rank:     "II",
+	}
+	mustNoError(t, repo.Create(ctx, player))
+	return player
+}
+
+func TestPlayerRepositoryCreateAndFind(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewPlayerRepository(newTestDatabase(t))
+
+	player := createTestPlayer(t, ctx, repo, "Faker")
+	if player.ID.IsZero() {
+		t.Fatal("Create did not set the ID of the player")
+	}
+
+	found, err := repo.FindByRiotID(ctx, "Faker", "EUW", "euw1")
+	mustNoError(t, err)
+	if found == nil || found.ID != player.ID {
+		t.Fatalf("FindByRiotID = %+v, want player %s", found, player.ID.Hex())
+	}
+
+	found, err = repo.FindByPUUID(ctx, "puuid-Faker")
+	mustNoError(t, err)
+	if found == nil || found.ID != player.ID {
+		t.Fatalf("FindByPUUID = %+v, want player %s", found, player.ID.Hex())
+	}
+
+	found, err = repo.FindByRiotID(ctx, "Faker", "KR1", "euw1")
+	mustNoError(t, err)
+	if found != nil {
+		t.Fatalf("FindByRiotID of an unknown player = %+v, want nil", found)
+	}
+
+	found, err = repo.FindByPUUID(ctx, "unknown")
+	mustNoError(t, err)
+	if found != nil {
+		t.Fatalf("FindByPUUID of an unknown player = %+v, want nil", found)
+	}
+
+	exists, err := repo.Exists(ctx, "Faker", "EUW", "euw1")
+	mustNoError(t, err)
+	if !exists {
+		t.Fatal("Exists = false, want true")
+	}
+}
+
+func TestPlayerRepositoryUniqueIndexes(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewPlayerRepository(newTestDatabase(t))
+
+	createTestPlayer(t, ctx, repo, "Faker")
+
+	sameRiotID := &models.Player{PUUID: "other-puuid", GameName: "Faker", TagLine: "EUW", Server: "euw1"}
+	err := repo.Create(ctx, sameRiotID)
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("Create with a tracked Riot ID = %v, want a duplicate key error", err)
+	}
+
+	samePUUID := &models.Player{PUUID: "puuid-Faker", GameName: "Renamed", TagLine: "EUW", Server: "euw1"}
+	err = repo.Create(ctx, samePUUID)
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("Create with a tracked PUUID = %v, want a duplicate key error", err)
+	}
+
+	otherServer := &models.Player{PUUID: "puuid-kr", GameName: "Faker", TagLine: "EUW", Server: "kr"}
+	mustNoError(t, repo.Create(ctx, otherServer))
+}
+
+func TestPlayerRepositoryUpdatePolledKeepsOtherFields(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewPlayerRepository(newTestDatabase(t))
+
+	player := createTestPlayer(t, ctx, repo, "Faker")
+	mustNoError(t, repo.SetPaused(ctx, player.ID, true))
+	mustNoError(t, repo.AddTag(ctx, player.ID, "team-a"))
+	mustNoError(t, repo.SetAliasAndNote(ctx, player.ID, "Bob", "main account"))
+
+	// The poll saves its stale copy of the player, loaded before the edits
+	polledAt := time.Now()
+	player.Tier = "PLATINUM"
+	player.Rank = "IV"
+	player.LeaguePoints = 12
+	player.Wins = 10
+	player.Losses = 8
+	player.SummonerLevel = 300
+	player.LastPolledAt = &polledAt
+	player.LastMatchID = "EUW1_1"
+	mustNoError(t, repo.UpdatePolled(ctx, player))
+
+	found, err := repo.FindByPUUID(ctx, player.PUUID)
+	mustNoError(t, err)
+	if found.Tier != "PLATINUM" || found.Rank != "IV" || found.LeaguePoints != 12 || found.Wins != 10 || found.Losses != 8 {
+		t.Errorf("rank = %s %s %d LP %dW/%dL, want PLATINUM IV 12 LP 10W/8L", found.Tier, found.Rank, found.LeaguePoints, found.Wins, found.Losses)
+	}
+	if found.SummonerLevel != 300 || found.LastMatchID != "EUW1_1" {
+		t.Errorf("level = %d, checkpoint = %q, want 300 and EUW1_1", found.SummonerLevel, found.LastMatchID)
+	}
+	if found.LastPolledAt == nil || !sameTime(*found.LastPolledAt, polledAt) {
+		t.Errorf("LastPolledAt = %v, want %v", found.LastPolledAt, polledAt)
+	}
+	if !found.Paused || found.Alias != "Bob" || found.Note != "main account" || len(found.Tags) != 1 {
+		t.Errorf("UpdatePolled overwrote the fields edited meanwhile: paused = %t, alias = %q, note = %q, tags = %v",
+			found.Paused, found.Alias, found.Note, found.Tags)
+	}
+
+	// Buffered cycles leave the checkpoint empty, it is moved by SetLastMatchIDs
+	player.LastMatchID = ""
+	mustNoError(t, repo.UpdatePolled(ctx, player))
+	found, err = repo.FindByPUUID(ctx, player.PUUID)
+	mustNoError(t, err)
+	if found.LastMatchID != "EUW1_1" {
+		t.Errorf("checkpoint = %q after an update without checkpoint, want EUW1_1", found.LastMatchID)
+	}
+}
+
+func TestPlayerRepositorySetLastMatchIDs(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewPlayerRepository(newTestDatabase(t))
+
+	first := createTestPlayer(t, ctx, repo, "First")
+	second := createTestPlayer(t, ctx, repo, "Second")
+
+	mustNoError(t, repo.SetLastMatchIDs(ctx, nil))
+	mustNoError(t, repo.SetLastMatchIDs(ctx, map[primitive.ObjectID]string{
+		first.ID:  "EUW1_1",
+		second.ID: "EUW1_2",
+	}))
+
+	for _, want := range []struct {
+		puuid   string
+		matchID string
+	}{{first.PUUID, "EUW1_1"}, {second.PUUID, "EUW1_2"}} {
+		found, err := repo.FindByPUUID(ctx, want.puuid)
+		mustNoError(t, err)
+		if found.LastMatchID != want.matchID {
+			t.Errorf("checkpoint of %s = %q, want %q", want.puuid, found.LastMatchID, want.matchID)
+		}
+	}
+}
+
+func TestPlayerRepositoryFindAllWithPagination(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewPlayerRepository(newTestDatabase(t))
+
+	for i := 1; i <= 5; i++ {
+		createTestPlayer(t, ctx, repo, fmt.Sprintf("Player%d", i))
+		time.Sleep(2 * time.Millisecond) // Distinct creation dates
+	}
+
+	players, total, err := repo.FindAllWithPagination(ctx, 1, 2)
+	mustNoError(t, err)
+	if total != 5 {
+		t.Errorf("total = %d, want 5", total)
+	}
+	if len(players) != 2 || players[0].GameName != "Player5" || players[1].GameName != "Player4" {
+		t.Errorf("first page = %v, want Player5 and Player4", gameNames(players))
+	}
+
+	players, _, err = repo.FindAllWithPagination(ctx, 3, 2)
+	mustNoError(t, err)
+	if len(players) != 1 || players[0].GameName != "Player1" {
+		t.Errorf("last page = %v, want Player1", gameNames(players))
+	}
+
+	players, _, err = repo.FindAllWithPagination(ctx, 4, 2)
+	mustNoError(t, err)
+	if len(players) != 0 {
+		t.Errorf("page after the last one = %v, want no player", gameNames(players))
+	}
+
+	all, err := repo.FindAll(ctx)
+	mustNoError(t, err)
+	count, err := repo.Count(ctx)
+	mustNoError(t, err)
+	if len(all) != 5 || count != 5 {
+		t.Errorf("FindAll returned %d players and Count %d, want 5", len(all), count)
+	}
+}
+
+func TestPlayerRepositoryFindByPUUIDsAndServer(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewPlayerRepository(newTestDatabase(t))
+
+	createTestPlayer(t, ctx, repo, "First")
+	createTestPlayer(t, ctx, repo, "Second")
+	korean := &models.Player{PUUID: "puuid-kr", GameName: "Faker", TagLine: "KR1", Server: "kr"}
+	mustNoError(t, repo.Create(ctx, korean))
+
+	players, err := repo.FindByPUUIDs(ctx, []string{"puuid-First", "puuid-kr", "unknown"})
+	mustNoError(t, err)
+	if len(players) != 2 {
+		t.Errorf("FindByPUUIDs = %v, want First and Faker", gameNames(players))
+	}
+
+	players, err = repo.FindByServer(ctx, "kr")
+	mustNoError(t, err)
+	if len(players) != 1 || players[0].PUUID != "puuid-kr" {
+		t.Errorf("FindByServer(kr) = %v, want Faker", gameNames(players))
+	}
+}
+
+func TestPlayerRepositoryTags(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewPlayerRepository(newTestDatabase(t))
+
+	first := createTestPlayer(t, ctx, repo, "First")
+	second := createTestPlayer(t, ctx, repo, "Second")
+
+	mustNoError(t, repo.AddTag(ctx, first.ID, "team-a"))
+	mustNoError(t, repo.AddTag(ctx, first.ID, "team-a"))
+	mustNoError(t, repo.AddTag(ctx, second.ID, "team-a"))
+	mustNoError(t, repo.AddTag(ctx, second.ID, "friends"))
+
+	players, err := repo.FindByTag(ctx, "team-a")
+	mustNoError(t, err)
+	if len(players) != 2 {
+		t.Fatalf("FindByTag(team-a) = %v, want First and Second", gameNames(players))
+	}
+	for _, player := range players {
+		if player.ID == first.ID && len(player.Tags) != 1 {
+			t.Errorf("tags of First = %v, the tag was added twice", player.Tags)
+		}
+	}
+
+	mustNoError(t, repo.RemoveTag(ctx, second.ID, "team-a"))
+	players, err = repo.FindByTag(ctx, "team-a")
+	mustNoError(t, err)
+	if len(players) != 1 || players[0].ID != first.ID {
+		t.Errorf("FindByTag(team-a) after the removal = %v, want First", gameNames(players))
+	}
+}
+
+func TestPlayerRepositorySetters(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewPlayerRepository(newTestDatabase(t))
+
+	player := createTestPlayer(t, ctx, repo, "Faker")
+	tiltAlertAt := time.Now().Add(-time.Hour)
+	sessionEnd := time.Now().Add(-2 * time.Hour)
+
+	mustNoError(t, repo.SetTwitchChannel(ctx, player.ID, "faker"))
+	mustNoError(t, repo.SetTwitchLive(ctx, player.ID, true))
+	mustNoError(t, repo.SetPublicProfile(ctx, player.ID, true))
+	mustNoError(t, repo.SetLiveGameID(ctx, player.ID, 42))
+	mustNoError(t, repo.SetTiltAlertsOptOut(ctx, player.ID, true))
+	mustNoError(t, repo.SetLastTiltAlertAt(ctx, player.ID, tiltAlertAt))
+	mustNoError(t, repo.SetLastSessionSummaryAt(ctx, player.ID, sessionEnd))
+
+	found, err := repo.FindByPUUID(ctx, player.PUUID)
+	mustNoError(t, err)
+	if found.TwitchChannel != "faker" || !found.TwitchLive {
+		t.Errorf("Twitch = %q live %t, want faker live", found.TwitchChannel, found.TwitchLive)
+	}
+	if !found.PublicProfile || found.LiveGameID != 42 || !found.TiltAlertsOptOut {
+		t.Errorf("public = %t, live game = %d, tilt opt-out = %t, want true, 42, true", found.PublicProfile, found.LiveGameID, found.TiltAlertsOptOut)
+	}
+	if found.LastTiltAlertAt == nil || !sameTime(*found.LastTiltAlertAt, tiltAlertAt) {
+		t.Errorf("LastTiltAlertAt = %v, want %v", found.LastTiltAlertAt, tiltAlertAt)
+	}
+	if found.LastSessionSummaryAt == nil || !sameTime(*found.LastSessionSummaryAt, sessionEnd) {
+		t.Errorf("LastSessionSummaryAt = %v, want %v", found.LastSessionSummaryAt, sessionEnd)
+	}
+
+	streamers, err := repo.FindWithTwitchChannel(ctx)
+	mustNoError(t, err)
+	if len(streamers) != 1 {
+		t.Errorf("FindWithTwitchChannel = %v, want Faker", gameNames(streamers))
+	}
+
+	// Changing the channel resets the stream state
+	mustNoError(t, repo.SetTwitchChannel(ctx, player.ID, ""))
+	streamers, err = repo.FindWithTwitchChannel(ctx)
+	mustNoError(t, err)
+	if len(streamers) != 0 {
+		t.Errorf("FindWithTwitchChannel after the removal = %v, want no player", gameNames(streamers))
+	}
+}
+
+func TestPlayerRepositoryDelete(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewPlayerRepository(newTestDatabase(t))
+
+	first := createTestPlayer(t, ctx, repo, "First")
+	createTestPlayer(t, ctx, repo, "Second")
+
+	mustNoError(t, repo.Delete(ctx, first.ID))
+	mustNoError(t, repo.DeleteByRiotID(ctx, "Second", "EUW", "euw1"))
+
+	count, err := repo.Count(ctx)
+	mustNoError(t, err)
+	if count != 0 {
+		t.Errorf("Count after the deletions = %d, want 0", count)
+	}
+}
+
+func gameNames(players []*models.Player) []string {
+	names := make([]string, 0, len(players))
+	for _, player := range players {
+		names = append(names, player.GameName)
+	}
+	return names
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SharedMatchRepository stores the data shared by the participants of a game, once per game
+type SharedMatchRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSharedMatchRepository(db *mongo.Database) *SharedMatchRepository {
+	return &SharedMatchRepository{
+		collection: db.Collection("matches"),
+	}
+}
+
+// Save stores a game if it was not stored yet (ex: by another tracked player of the same game)
+func (r *SharedMatchRepository) Save(ctx context.Context, match *models.Match) error {
+	match.CreatedAt = time.Now()
+
+	filter := bson.M{"matchId": match.MatchID}
+	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$setOnInsert": match}, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save shared match: %w", err)
+	}
+
+	return nil
+}
+
+// FindByMatchID finds a game by its Riot match ID
+func (r *SharedMatchRepository) FindByMatchID(ctx context.Context, matchID string) (*models.Match, error) {
+	var match models.Match
+
+	err := r.collection.FindOne(ctx, bson.M{"matchId": matchID}).Decode(&match)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find shared match: %w", err)
+	}
+
+	return &match, nil
+}
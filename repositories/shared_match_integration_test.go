@@ -0,0 +1,41 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+)
+
+func TestSharedMatchRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewSharedMatchRepository(newTestDatabase(t))
+
+	match := &models.Match{
+		MatchID:   "EUW1_1",
+		QueueType: "RANKED_SOLO_5x5",
+		Patch:     "14.5",
+		Teams:     []models.MatchTeam{{TeamID: 100, Victory: true, Bans: []int{1, 2}}, {TeamID: 200}},
+		PlayedAt:  time.Now(),
+	}
+	mustNoError(t, repo.Save(ctx, match))
+
+	// Saved again by another tracked player of the game, the stored game is kept
+	other := *match
+	other.Patch = "14.6"
+	mustNoError(t, repo.Save(ctx, &other))
+
+	found, err := repo.FindByMatchID(ctx, "EUW1_1")
+	mustNoError(t, err)
+	if found == nil || found.Patch != "14.5" || len(found.Teams) != 2 || len(found.Teams[0].Bans) != 2 {
+		t.Fatalf("FindByMatchID = %+v, want the first saved game", found)
+	}
+
+	found, err = repo.FindByMatchID(ctx, "EUW1_2")
+	mustNoError(t, err)
+	if found != nil {
+		t.Errorf("FindByMatchID of an unknown game = %+v, want nil", found)
+	}
+}
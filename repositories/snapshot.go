@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SnapshotRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSnapshotRepository(db *mongo.Database) *SnapshotRepository {
+	return &SnapshotRepository{
+		collection: db.Collection("leaderboard_snapshots"),
+	}
+}
+
+// Create saves a new snapshot, names are unique per guild
+func (r *SnapshotRepository) Create(ctx context.Context, snapshot *models.LeaderboardSnapshot) error {
+	snapshot.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, snapshot)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("a snapshot named %s already exists", snapshot.Name)
+		}
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		snapshot.ID = oid
+	}
+
+	return nil
+}
+
+// FindByName finds a snapshot of a guild by its name
+func (r *SnapshotRepository) FindByName(ctx context.Context, guildID, name string) (*models.LeaderboardSnapshot, error) {
+	var snapshot models.LeaderboardSnapshot
+
+	err := r.collection.FindOne(ctx, bson.M{"guildId": guildID, "name": name}).Decode(&snapshot)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find snapshot: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// FindByGuild returns the snapshots of a guild without their entries, newest first
+func (r *SnapshotRepository) FindByGuild(ctx context.Context, guildID string) ([]*models.LeaderboardSnapshot, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetProjection(bson.M{"entries": 0})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"guildId": guildID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snapshots: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var snapshots []*models.LeaderboardSnapshot
+	for cursor.Next(ctx) {
+		var snapshot models.LeaderboardSnapshot
+		if err := cursor.Decode(&snapshot); err != nil {
+			return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return snapshots, nil
+}
@@ -0,0 +1,50 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+)
+
+func TestSnapshotRepository(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewSnapshotRepository(newTestDatabase(t))
+
+	entries := []models.SnapshotEntry{{Position: 1, PUUID: "puuid-Faker", Tier: "GOLD", Rank: "II", LeaguePoints: 50}}
+	before := &models.LeaderboardSnapshot{GuildID: "guild-1", Name: "before", Entries: entries}
+	mustNoError(t, repo.Create(ctx, before))
+	if before.ID.IsZero() {
+		t.Fatal("Create did not set the ID of the snapshot")
+	}
+	time.Sleep(2 * time.Millisecond) // Distinct creation dates
+	mustNoError(t, repo.Create(ctx, &models.LeaderboardSnapshot{GuildID: "guild-1", Name: "after", Entries: entries}))
+	mustNoError(t, repo.Create(ctx, &models.LeaderboardSnapshot{GuildID: "guild-2", Name: "before", Entries: entries}))
+
+	err := repo.Create(ctx, &models.LeaderboardSnapshot{GuildID: "guild-1", Name: "before"})
+	if err == nil {
+		t.Fatal("Create with a used name succeeded, want an error")
+	}
+
+	found, err := repo.FindByName(ctx, "guild-1", "before")
+	mustNoError(t, err)
+	if found == nil || found.ID != before.ID || len(found.Entries) != 1 {
+		t.Fatalf("FindByName = %+v, want the first snapshot with its entries", found)
+	}
+	found, err = repo.FindByName(ctx, "guild-1", "unknown")
+	mustNoError(t, err)
+	if found != nil {
+		t.Errorf("FindByName of an unknown snapshot = %+v, want nil", found)
+	}
+
+	snapshots, err := repo.FindByGuild(ctx, "guild-1")
+	mustNoError(t, err)
+	if len(snapshots) != 2 || snapshots[0].Name != "after" || snapshots[1].Name != "before" {
+		t.Fatalf("FindByGuild returned %d snapshots, want after then before", len(snapshots))
+	}
+	if len(snapshots[0].Entries) != 0 {
+		t.Errorf("FindByGuild returned %d entries, want the snapshots without entries", len(snapshots[0].Entries))
+	}
+}
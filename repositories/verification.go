@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AccountVerificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAccountVerificationRepository(db *mongo.Database) *AccountVerificationRepository {
+	return &AccountVerificationRepository{
+		collection: db.Collection("account_verifications"),
+	}
+}
+
+// Create saves a new verification, the pending verifications of the user are expired first
+// since a user verifies one account at a time
+func (r *AccountVerificationRepository) Create(ctx context.Context, verification *models.AccountVerification) error {
+	filter := bson.M{"discordUserId": verification.DiscordUserID, "status": models.VerificationPending}
+	_, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"status": models.VerificationExpired}})
+	if err != nil {
+		return fmt.Errorf("failed to expire pending verifications: %w", err)
+	}
+
+	result, err := r.collection.InsertOne(ctx, verification)
+	if err != nil {
+		return fmt.Errorf("failed to create verification: %w", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		verification.ID = oid
+	}
+
+	return nil
+}
+
+// FindPending returns the pending verification of a user, nil if none
+func (r *AccountVerificationRepository) FindPending(ctx context.Context, discordUserID string) (*models.AccountVerification, error) {
+	filter := bson.M{"discordUserId": discordUserID, "status": models.VerificationPending}
+	return r.findOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+}
+
+// FindByID returns a verification, nil if not found
+func (r *AccountVerificationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.AccountVerification, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindVerifiedByPlayer returns the verification proving the owner of a player, nil if not verified
+func (r *AccountVerificationRepository) FindVerifiedByPlayer(ctx context.Context, puuid string) (*models.AccountVerification, error) {
+	filter := bson.M{"playerPuuid": puuid, "status": models.VerificationVerified}
+	return r.findOne(ctx, filter)
+}
+
+// FindVerifiedByUser returns the accounts verified by a user, oldest first
+func (r *AccountVerificationRepository) FindVerifiedByUser(ctx context.Context, discordUserID string) ([]*models.AccountVerification, error) {
+	filter := bson.M{"discordUserId": discordUserID, "status": models.VerificationVerified}
+	opts := options.Find().SetSort(bson.D{{Key: "verifiedAt", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find verifications: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var verifications []*models.AccountVerification
+	if err := cursor.All(ctx, &verifications); err != nil {
+		return nil, fmt.Errorf("failed to decode verifications: %w", err)
+	}
+
+	return verifications, nil
+}
+
+// UpdateStatus moves a verification to a new status
+func (r *AccountVerificationRepository) UpdateStatus(ctx context.Context, verification *models.AccountVerification, status models.VerificationStatus, now time.Time) error {
+	set := bson.M{"status": status}
+	if status == models.VerificationVerified {
+		set["verifiedAt"] = now
+		verification.VerifiedAt = &now
+	}
+
+	_, err := r.collection.UpdateByID(ctx, verification.ID, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("failed to update verification: %w", err)
+	}
+	verification.Status = status
+
+	return nil
+}
+
+// DeleteVerified removes the verified link between a user and a player
+func (r *AccountVerificationRepository) DeleteVerified(ctx context.Context, discordUserID, puuid string) (bool, error) {
+	filter := bson.M{"discordUserId": discordUserID, "playerPuuid": puuid, "status": models.VerificationVerified}
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete verification: %w", err)
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// DeleteExpiredBefore removes the verifications expired before a date, it returns the number removed
+func (r *AccountVerificationRepository) DeleteExpiredBefore(ctx context.Context, before time.Time) (int64, error) {
+	filter := bson.M{"status": models.VerificationExpired, "expiresAt": bson.M{"$lt": before}}
+	result, err := r.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired verifications: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (r *AccountVerificationRepository) findOne(ctx context.Context, filter bson.M, opts ...*options.FindOneOptions) (*models.AccountVerification, error) {
+	var verification models.AccountVerification
+	err := r.collection.FindOne(ctx, filter, opts...).Decode(&verification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find verification: %w", err)
+	}
+
+	return &verification, nil
+}
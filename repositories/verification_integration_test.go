@@ -0,0 +1,128 @@
+//go:build integration
+
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func testVerification(userID, puuid string, createdAt time.Time) *models.AccountVerification {
+	return &models.AccountVerification{
+		DiscordUserID: userID,
+		PlayerPUUID:   puuid,
+		GameName:      "Faker",
+		TagLine:       "EUW",
+		Server:        "euw1",
+		Status:        models.VerificationPending,
+		CreatedAt:     createdAt,
+		ExpiresAt:     createdAt.Add(models.VerificationTTL),
+	}
+}
+
+func TestAccountVerificationRepositoryFlow(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewAccountVerificationRepository(newTestDatabase(t))
+
+	now := time.Now()
+	first := testVerification("user-1", "puuid-main", now.Add(-time.Minute))
+	mustNoError(t, repo.Create(ctx, first))
+
+	// A user verifies one account at a time, the first verification expires
+	second := testVerification("user-1", "puuid-smurf", now)
+	mustNoError(t, repo.Create(ctx, second))
+
+	found, err := repo.FindByID(ctx, first.ID)
+	mustNoError(t, err)
+	if found == nil || found.Status != models.VerificationExpired {
+		t.Fatalf("first verification = %+v, want it expired", found)
+	}
+
+	pending, err := repo.FindPending(ctx, "user-1")
+	mustNoError(t, err)
+	if pending == nil || pending.ID != second.ID {
+		t.Fatalf("FindPending = %+v, want the second verification", pending)
+	}
+
+	mustNoError(t, repo.UpdateStatus(ctx, second, models.VerificationVerified, now))
+	if second.Status != models.VerificationVerified || second.VerifiedAt == nil {
+		t.Errorf("UpdateStatus did not update the verification: %+v", second)
+	}
+
+	pending, err = repo.FindPending(ctx, "user-1")
+	mustNoError(t, err)
+	if pending != nil {
+		t.Errorf("FindPending after the verification = %+v, want nil", pending)
+	}
+
+	owner, err := repo.FindVerifiedByPlayer(ctx, "puuid-smurf")
+	mustNoError(t, err)
+	if owner == nil || owner.DiscordUserID != "user-1" {
+		t.Fatalf("FindVerifiedByPlayer = %+v, want the verification of user-1", owner)
+	}
+
+	accounts, err := repo.FindVerifiedByUser(ctx, "user-1")
+	mustNoError(t, err)
+	if len(accounts) != 1 || accounts[0].PlayerPUUID != "puuid-smurf" {
+		t.Fatalf("FindVerifiedByUser returned %d accounts, want puuid-smurf", len(accounts))
+	}
+
+	deleted, err := repo.DeleteVerified(ctx, "user-1", "puuid-smurf")
+	mustNoError(t, err)
+	if !deleted {
+		t.Error("DeleteVerified = false, want true")
+	}
+	deleted, err = repo.DeleteVerified(ctx, "user-1", "puuid-smurf")
+	mustNoError(t, err)
+	if deleted {
+		t.Error("DeleteVerified of an unlinked account = true, want false")
+	}
+}
+
+func TestAccountVerificationRepositoryUniqueOwner(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewAccountVerificationRepository(newTestDatabase(t))
+
+	now := time.Now()
+	first := testVerification("user-1", "puuid-main", now)
+	second := testVerification("user-2", "puuid-main", now)
+	mustNoError(t, repo.Create(ctx, first))
+	mustNoError(t, repo.Create(ctx, second))
+
+	mustNoError(t, repo.UpdateStatus(ctx, first, models.VerificationVerified, now))
+
+	// An account is owned by a single Discord user
+	err := repo.UpdateStatus(ctx, second, models.VerificationVerified, now)
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("second verification of the account = %v, want a duplicate key error", err)
+	}
+}
+
+func TestAccountVerificationRepositoryDeleteExpiredBefore(t *testing.T) {
+	ctx := testContext(t)
+	repo := NewAccountVerificationRepository(newTestDatabase(t))
+
+	now := time.Now()
+	old := testVerification("user-1", "puuid-main", now.Add(-48*time.Hour))
+	mustNoError(t, repo.Create(ctx, old))
+	// Expires the old verification
+	recent := testVerification("user-1", "puuid-smurf", now)
+	mustNoError(t, repo.Create(ctx, recent))
+	mustNoError(t, repo.UpdateStatus(ctx, recent, models.VerificationExpired, now))
+
+	deleted, err := repo.DeleteExpiredBefore(ctx, now.Add(-24*time.Hour))
+	mustNoError(t, err)
+	if deleted != 1 {
+		t.Errorf("DeleteExpiredBefore = %d, want the old verification only", deleted)
+	}
+
+	found, err := repo.FindByID(ctx, recent.ID)
+	mustNoError(t, err)
+	if found == nil {
+		t.Error("the recent expired verification was deleted")
+	}
+}
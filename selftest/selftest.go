@@ -0,0 +1,217 @@
+package selftest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"lp_tracker/config"
+	"lp_tracker/services"
+	"lp_tracker/version"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Timeout bounds the whole self-test
+const Timeout = 30 * time.Second
+
+// Platform used for the Riot API status call
+const statusServer = "euw1"
+
+// Config holds the settings checked by the self-test
+type Config struct {
+	DiscordToken string
+	RiotAPIKey   string
+	Mongo        config.MongoConfig
+	MongoErr     error // Missing or invalid MONGO_* settings, reported by the configuration check
+
+	RiotClient    services.RiotClientConfig
+	RiotClientErr error // Invalid RIOT_HTTP_* settings, reported by the configuration check
+}
+
+// ConfigFromEnv reads the self-test settings from the environment variables used by the processes
+func ConfigFromEnv() Config {
+	riotClient, riotClientErr := services.RiotClientConfigFromEnv()
+	mongoConfig, mongoErr := config.MongoConfigFromEnv()
+	return Config{
+		DiscordToken:  os.Getenv("DISCORD_TOKEN"),
+		RiotAPIKey:    os.Getenv("RIOT_API_KEY"),
+		Mongo:         mongoConfig,
+		MongoErr:      mongoErr,
+		RiotClient:    riotClient,
+		RiotClientErr: riotClientErr,
+	}
+}
+
+// Check is the outcome of a single self-test step
+type Check struct {
+	Name     string
+	Detail   string
+	Err      error
+	Skipped  bool
+	Duration time.Duration
+}
+
+// Report lists the outcome of every self-test step
+type Report struct {
+	Checks []Check
+}
+
+// OK checks if every step passed
+func (r *Report) OK() bool {
+	for _, check := range r.Checks {
+		if check.Err != nil || check.Skipped {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Report) String() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("🩺 Self-test report of lp_tracker %s\n", version.Get()))
+	for _, check := range r.Checks {
+		switch {
+		case check.Skipped:
+			builder.WriteString(fmt.Sprintf("⏭️  %s: skipped (%s)\n", check.Name, check.Detail))
+		case check.Err != nil:
+			builder.WriteString(fmt.Sprintf("❌ %s: %v (%v)\n", check.Name, check.Err, check.Duration.Round(time.Millisecond)))
+		default:
+			builder.WriteString(fmt.Sprintf("✅ %s: %s (%v)\n", check.Name, check.Detail, check.Duration.Round(time.Millisecond)))
+		}
+	}
+
+	if r.OK() {
+		builder.WriteString("All checks passed")
+	} else {
+		builder.WriteString("Self-test failed")
+	}
+	return builder.String()
+}
+
+// Main runs the self-test, prints the report and returns the process exit code
+func Main(config Config) int {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	report := Run(ctx, config)
+	fmt.Println(report.String())
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
+
+// Run validates the configuration, then checks MongoDB, the Riot API key and the Discord token.
+// The checks depending on a missing setting are skipped.
+func Run(ctx context.Context, config Config) *Report {
+	report := &Report{}
+
+	report.Checks = append(report.Checks, checkConfig(config))
+
+	if config.MongoErr != nil {
+		report.Checks = append(report.Checks, Check{Name: "MongoDB", Skipped: true, Detail: "no valid URI or database"})
+	} else {
+		report.Checks = append(report.Checks, run("MongoDB", func() (string, error) {
+			return checkMongo(ctx, config.Mongo.URI, config.Mongo.Database)
+		}))
+	}
+
+	if config.RiotAPIKey == "" {
+		report.Checks = append(report.Checks, Check{Name: "Riot API", Skipped: true, Detail: "no API key"})
+	} else {
+		report.Checks = append(report.Checks, run("Riot API", func() (string, error) {
+			return checkRiot(ctx, config.RiotAPIKey, config.RiotClient)
+		}))
+	}
+
+	if config.DiscordToken == "" {
+		report.Checks = append(report.Checks, Check{Name: "Discord", Skipped: true, Detail: "no token"})
+	} else {
+		report.Checks = append(report.Checks, run("Discord", func() (string, error) {
+			return checkDiscord(ctx, config.DiscordToken)
+		}))
+	}
+
+	return report
+}
+
+func run(name string, check func() (string, error)) Check {
+	start := time.Now()
+	detail, err := check()
+	return Check{Name: name, Detail: detail, Err: err, Duration: time.Since(start)}
+}
+
+func checkConfig(config Config) Check {
+	required := []struct {
+		name  string
+		value string
+	}{
+		{"DISCORD_TOKEN", config.DiscordToken},
+		{"RIOT_API_KEY", config.RiotAPIKey},
+	}
+
+	var missing []string
+	for _, setting := range required {
+		if setting.value == "" {
+			missing = append(missing, setting.name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return Check{Name: "Configuration", Err: fmt.Errorf("missing %s", strings.Join(missing, ", "))}
+	}
+	if config.MongoErr != nil {
+		return Check{Name: "Configuration", Err: config.MongoErr}
+	}
+	if config.RiotClientErr != nil {
+		return Check{Name: "Configuration", Err: config.RiotClientErr}
+	}
+	return Check{Name: "Configuration", Detail: "all required variables are set"}
+}
+
+// checkMongo connects and pings the database, without creating indexes or running migrations
+func checkMongo(ctx context.Context, uri, databaseName string) (string, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return "", fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	err = client.Ping(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to ping: %w", err)
+	}
+
+	collections, err := client.Database(databaseName).ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list collections: %w", err)
+	}
+	return fmt.Sprintf("connected to %s (%d collections)", databaseName, len(collections)), nil
+}
+
+func checkRiot(ctx context.Context, apiKey string, client services.RiotClientConfig) (string, error) {
+	status, err := services.NewRiotService(apiKey, client).GetPlatformStatus(ctx, statusServer)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s status: %d maintenances, %d incidents", status.Name, len(status.Maintenances), len(status.Incidents)), nil
+}
+
+func checkDiscord(ctx context.Context, token string) (string, error) {
+	session, err := discordgo.New("Bot " + token)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := session.User("@me", discordgo.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("authenticated as %s", user.Username), nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+const (
+	winStreakAchievementLength    = 10
+	gamesTrackedAchievementAmount = 100
+)
+
+// achievementRule checks if a rank change unlocks an achievement
+type achievementRule struct {
+	achievementID string
+	check         func(ctx context.Context, as *AchievementService, change *models.RankChange) (bool, error)
+}
+
+var achievementRules = []achievementRule{
+	{
+		achievementID: models.AchievementFirstPentakill,
+		check: func(ctx context.Context, as *AchievementService, change *models.RankChange) (bool, error) {
+			return change.Match != nil && change.Match.PentaKills > 0, nil
+		},
+	},
+	{
+		achievementID: models.AchievementWinStreak10,
+		check: func(ctx context.Context, as *AchievementService, change *models.RankChange) (bool, error) {
+			// Fetch extra events since games with an AFK are excluded from streaks
+			events, err := as.lpEventRepo.FindRecentByPlayer(ctx, change.Player.PUUID, winStreakAchievementLength*2)
+			if err != nil {
+				return false, err
+			}
+
+			streak := 0
+			for _, event := range events {
+				if event.SuspectedAFK {
+					continue
+				}
+				if !event.Victory {
+					return false, nil
+				}
+				streak++
+				if streak >= winStreakAchievementLength {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+	},
+	{
+		achievementID: models.AchievementReachedDiamond,
+		check: func(ctx context.Context, as *AchievementService, change *models.RankChange) (bool, error) {
+			player := change.Player
+			return models.RankValue(player.Tier, player.Rank, player.LeaguePoints) >= models.RankValue("DIAMOND", "IV", 0), nil
+		},
+	},
+	{
+		achievementID: models.AchievementGamesTracked,
+		check: func(ctx context.Context, as *AchievementService, change *models.RankChange) (bool, error) {
+			count, err := as.lpEventRepo.CountByPlayer(ctx, change.Player.PUUID)
+			if err != nil {
+				return false, err
+			}
+			return count >= gamesTrackedAchievementAmount, nil
+		},
+	},
+}
+
+type AchievementService struct {
+	achievementRepo *repositories.AchievementRepository
+	lpEventRepo     *repositories.LPEventRepository
+}
+
+func NewAchievementService(achievementRepo *repositories.AchievementRepository, lpEventRepo *repositories.LPEventRepository) *AchievementService {
+	return &AchievementService{
+		achievementRepo: achievementRepo,
+		lpEventRepo:     lpEventRepo,
+	}
+}
+
+// EvaluateRankChanges runs the achievement rules on rank changes and returns the newly earned badges
+func (as *AchievementService) EvaluateRankChanges(ctx context.Context, changes []*models.RankChange) ([]*models.AchievementUnlock, error) {
+	var unlocks []*models.AchievementUnlock
+	var errors []string
+
+	for _, change := range changes {
+		for _, rule := range achievementRules {
+			unlocked, err := rule.check(ctx, as, change)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s for %s#%s: %v", rule.achievementID, change.Player.GameName, change.Player.TagLine, err))
+				continue
+			}
+			if !unlocked {
+				continue
+			}
+
+			earned := &models.PlayerAchievement{
+				PlayerPUUID:   change.Player.PUUID,
+				AchievementID: rule.achievementID,
+			}
+			if change.Match != nil {
+				earned.MatchID = change.Match.MatchID
+			}
+
+			isNew, err := as.achievementRepo.Create(ctx, earned)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s for %s#%s: %v", rule.achievementID, change.Player.GameName, change.Player.TagLine, err))
+				continue
+			}
+			if isNew {
+				unlocks = append(unlocks, &models.AchievementUnlock{
+					Player:      change.Player,
+					Achievement: models.FindAchievement(rule.achievementID),
+				})
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return unlocks, fmt.Errorf("some achievements failed to evaluate: %v", errors)
+	}
+
+	return unlocks, nil
+}
+
+// GetPlayerAchievements returns the badges earned by a player
+func (as *AchievementService) GetPlayerAchievements(ctx context.Context, puuid string) ([]*models.PlayerAchievement, error) {
+	return as.achievementRepo.FindByPlayer(ctx, puuid)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+// The last use of a key is recorded at most once per interval, not on every request
+const apiKeyUsageInterval = time.Minute
+
+// APIKeyService manages the keys of the REST API, created and revoked by the bot owner
+type APIKeyService struct {
+	apiKeyRepo *repositories.APIKeyRepository
+}
+
+func NewAPIKeyService(apiKeyRepo *repositories.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// CreateKey generates a named key, the clear key is returned once and never stored
+func (as *APIKeyService) CreateKey(ctx context.Context, name string, scope models.APIKeyScope, rateLimit int, createdBy string) (*models.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", fmt.Errorf("the key needs a name")
+	}
+
+	key, secret, err := models.NewAPIKey(name, scope, rateLimit, createdBy, time.Now())
+	if err != nil {
+		return nil, "", err
+	}
+
+	created, err := as.apiKeyRepo.Create(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+	if !created {
+		return nil, "", fmt.Errorf("a key named %s already exists", name)
+	}
+
+	return key, secret, nil
+}
+
+// Authenticate returns the key matching a clear key, nil if the key is unknown or revoked
+func (as *APIKeyService) Authenticate(ctx context.Context, secret string) (*models.APIKey, error) {
+	if secret == "" {
+		return nil, nil
+	}
+
+	key, err := as.apiKeyRepo.FindByHash(ctx, models.HashAPIKey(secret))
+	if err != nil || key == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyUsageInterval {
+		// The request is served even if the usage could not be recorded
+		err = as.apiKeyRepo.SetLastUsedAt(ctx, key.ID, now)
+		if err != nil {
+			fmt.Printf("Failed to record the use of API key %s: %v\n", key.Name, err)
+		}
+	}
+
+	return key, nil
+}
+
+// ListKeys returns every key, oldest first
+func (as *APIKeyService) ListKeys(ctx context.Context) ([]*models.APIKey, error) {
+	return as.apiKeyRepo.FindAll(ctx)
+}
+
+// RevokeKey deletes a key, it returns false if no key has that name
+func (as *APIKeyService) RevokeKey(ctx context.Context, name string) (bool, error) {
+	return as.apiKeyRepo.DeleteByName(ctx, strings.TrimSpace(name))
+}
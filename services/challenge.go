@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+// Challenge definitions barely change between patches
+const challengeNamesTTL = 24 * time.Hour
+
+// Locale of the challenge names, the config lists every locale
+const challengeNamesLocale = "en_US"
+
+// ChallengeService tracks the challenge progress (Challenges-V1) of the tracked players and records the
+// notable levels they reach. The sync costs one Riot API call per player.
+type ChallengeService struct {
+	progressRepo   *repositories.ChallengeProgressRepository
+	completionRepo *repositories.ChallengeCompletionRepository
+	playerRepo     *repositories.PlayerRepository
+	riotService    *RiotService
+
+	mu      sync.Mutex
+	names   map[int64]string // Challenge ID -> name
+	namesAt time.Time
+}
+
+func NewChallengeService(progressRepo *repositories.ChallengeProgressRepository, completionRepo *repositories.ChallengeCompletionRepository, playerRepo *repositories.PlayerRepository, riotService *RiotService) *ChallengeService {
+	return &ChallengeService{
+		progressRepo:   progressRepo,
+		completionRepo: completionRepo,
+		playerRepo:     playerRepo,
+		riotService:    riotService,
+	}
+}
+
+// SyncAll refreshes the challenge progress of every tracked player, it returns the notable levels reached
+func (cs *ChallengeService) SyncAll(ctx context.Context) ([]*models.ChallengeCompletion, error) {
+	players, err := cs.playerRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+
+	var completions []*models.ChallengeCompletion
+	var errors []string
+	for _, player := range players {
+		if player.Paused {
+			continue
+		}
+		if ctx.Err() != nil {
+			return completions, ctx.Err()
+		}
+
+		_, reached, err := cs.SyncPlayer(ctx, player)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to sync challenges of %s#%s: %v", player.GameName, player.TagLine, err)
+			errors = append(errors, errorMsg)
+			fmt.Println(errorMsg)
+		}
+		completions = append(completions, reached...)
+
+		// Rate limiting: wait between API calls
+		time.Sleep(1 * time.Second)
+	}
+
+	if len(errors) > 0 {
+		return completions, fmt.Errorf("some challenges failed to sync: %v", errors)
+	}
+
+	return completions, nil
+}
+
+// SyncPlayer fetches the challenge progress of a player and stores it. The levels reached since the previous
+// sync from NotableChallengeLevel up are recorded as completions, the first sync only sets the baseline.
+func (cs *ChallengeService) SyncPlayer(ctx context.Context, player *models.Player) (*models.ChallengeProgress, []*models.ChallengeCompletion, error) {
+	dto, err := cs.riotService.GetChallengeProgress(ctx, player.PUUID, player.Server)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previous, err := cs.progressRepo.FindByPlayer(ctx, player.PUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	progress := &models.ChallengeProgress{
+		PlayerPUUID: player.PUUID,
+		GameName:    player.GameName,
+		TagLine:     player.TagLine,
+		Level:       dto.TotalPoints.Level,
+		Points:      dto.TotalPoints.Current,
+		MaxPoints:   dto.TotalPoints.Max,
+		Percentile:  dto.TotalPoints.Percentile,
+		Title:       dto.Preferences.Title,
+	}
+
+	var completions []*models.ChallengeCompletion
+	notable := models.ChallengeLevelValue(models.NotableChallengeLevel)
+	for _, challenge := range dto.Challenges {
+		level := &models.ChallengeLevel{
+			ChallengeID: challenge.ChallengeID,
+			Level:       challenge.Level,
+			Value:       challenge.Value,
+			Percentile:  challenge.Percentile,
+		}
+		if challenge.AchievedTime > 0 {
+			achievedAt := time.UnixMilli(challenge.AchievedTime)
+			level.AchievedAt = &achievedAt
+		}
+		progress.Challenges = append(progress.Challenges, level)
+
+		reached := models.ChallengeLevelValue(challenge.Level)
+		if previous == nil || reached < notable || reached <= models.ChallengeLevelValue(previous.LevelOf(challenge.ChallengeID)) {
+			continue
+		}
+
+		completion := &models.ChallengeCompletion{
+			PlayerPUUID:   player.PUUID,
+			GameName:      player.GameName,
+			TagLine:       player.TagLine,
+			ChallengeID:   challenge.ChallengeID,
+			ChallengeName: cs.ChallengeName(ctx, player.Server, challenge.ChallengeID),
+			Level:         challenge.Level,
+			AchievedAt:    time.Now(),
+		}
+		if level.AchievedAt != nil {
+			completion.AchievedAt = *level.AchievedAt
+		}
+
+		err := cs.completionRepo.Record(ctx, completion)
+		if err != nil {
+			return nil, nil, err
+		}
+		completions = append(completions, completion)
+	}
+
+	err = cs.progressRepo.Save(ctx, progress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return progress, completions, nil
+}
+
+// GetCompletionsBetween returns the notable challenge levels reached between start and end, oldest first
+func (cs *ChallengeService) GetCompletionsBetween(ctx context.Context, start, end time.Time) ([]*models.ChallengeCompletion, error) {
+	return cs.completionRepo.FindBetween(ctx, start, end)
+}
+
+// ChallengeName returns the name of a challenge, or its ID when the definitions can't be fetched
+func (cs *ChallengeService) ChallengeName(ctx context.Context, server string, challengeID int64) string {
+	names := cs.challengeNames(ctx, server)
+	if name, ok := names[challengeID]; ok {
+		return name
+	}
+	return fmt.Sprintf("Challenge %d", challengeID)
+}
+
+// challengeNames returns the cached challenge names, refreshed once a day
+func (cs *ChallengeService) challengeNames(ctx context.Context, server string) map[int64]string {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.names != nil && time.Since(cs.namesAt) < challengeNamesTTL {
+		return cs.names
+	}
+
+	configs, err := cs.riotService.GetChallengeConfigs(ctx, server)
+	if err != nil {
+		// Keep the stale names, the lookup is retried on the next call
+		fmt.Printf("Failed to fetch challenge definitions: %v\n", err)
+		return cs.names
+	}
+
+	names := make(map[int64]string, len(configs))
+	for _, config := range configs {
+		if localized, ok := config.LocalizedNames[challengeNamesLocale]; ok && localized.Name != "" {
+			names[config.ID] = localized.Name
+		}
+	}
+	cs.names = names
+	cs.namesAt = time.Now()
+	return cs.names
+}
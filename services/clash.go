@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+// ClashSync is the outcome of a Clash registrations sync
+type ClashSync struct {
+	Added   []*models.ClashRegistration
+	Removed []*models.ClashRegistration // Registrations cancelled by the players, or tournaments cancelled by Riot
+}
+
+// ClashService mirrors the Clash registrations of the tracked players. Registrations are only
+// exposed by player, the sync costs one Riot API call per player (plus one per new team).
+type ClashService struct {
+	clashRepo   *repositories.ClashRegistrationRepository
+	eventRepo   *repositories.ClashEventRepository
+	playerRepo  *repositories.PlayerRepository
+	riotService *RiotService
+}
+
+func NewClashService(clashRepo *repositories.ClashRegistrationRepository, eventRepo *repositories.ClashEventRepository, playerRepo *repositories.PlayerRepository, riotService *RiotService) *ClashService {
+	return &ClashService{
+		clashRepo:   clashRepo,
+		eventRepo:   eventRepo,
+		playerRepo:  playerRepo,
+		riotService: riotService,
+	}
+}
+
+// GetUpcomingRegistrations returns the registrations of the tournaments not started yet, soonest first
+func (cs *ClashService) GetUpcomingRegistrations(ctx context.Context) ([]*models.ClashRegistration, error) {
+	return cs.clashRepo.FindUpcoming(ctx, time.Now())
+}
+
+// GetGuildEvents returns the Discord events created in a guild for the Clash tournaments
+func (cs *ClashService) GetGuildEvents(ctx context.Context, guildID string) ([]*models.ClashEvent, error) {
+	return cs.eventRepo.FindByGuild(ctx, guildID)
+}
+
+// SaveGuildEvent records the Discord event created or updated in a guild
+func (cs *ClashService) SaveGuildEvent(ctx context.Context, event *models.ClashEvent) error {
+	return cs.eventRepo.Save(ctx, event)
+}
+
+// DeleteGuildEvent forgets a Discord event, once deleted or finished
+func (cs *ClashService) DeleteGuildEvent(ctx context.Context, event *models.ClashEvent) error {
+	return cs.eventRepo.Delete(ctx, event)
+}
+
+// SyncRegistrations fetches the Clash registrations of every tracked player and stores them.
+// Registrations of finished tournaments are dropped.
+func (cs *ClashService) SyncRegistrations(ctx context.Context) (*ClashSync, error) {
+	players, err := cs.playerRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+
+	result := &ClashSync{}
+	tournaments := make(map[string]map[int]*ClashTournamentDTO) // Keyed by server, then tournament ID
+	teams := make(map[string]*ClashTeamDTO)
+	var errors []string
+	for _, player := range players {
+		if player.Paused {
+			continue
+		}
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		if _, ok := tournaments[player.Server]; !ok {
+			serverTournaments, err := cs.riotService.GetClashTournaments(ctx, player.Server)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("tournaments of %s: %v", player.Server, err))
+				continue
+			}
+			tournaments[player.Server] = make(map[int]*ClashTournamentDTO, len(serverTournaments))
+			for idx := range serverTournaments {
+				tournaments[player.Server][serverTournaments[idx].ID] = &serverTournaments[idx]
+			}
+		}
+
+		err := cs.syncPlayer(ctx, player, tournaments[player.Server], teams, result)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to sync clash registrations of %s#%s: %v", player.GameName, player.TagLine, err)
+			errors = append(errors, errorMsg)
+			fmt.Println(errorMsg)
+		}
+
+		// Rate limiting: wait between API calls
+		time.Sleep(1 * time.Second)
+	}
+
+	_, err = cs.clashRepo.DeleteStartedBefore(ctx, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	if len(errors) > 0 {
+		return result, fmt.Errorf("some clash registrations failed to sync: %v", errors)
+	}
+
+	return result, nil
+}
+
+func (cs *ClashService) syncPlayer(ctx context.Context, player *models.Player, tournaments map[int]*ClashTournamentDTO, teams map[string]*ClashTeamDTO, result *ClashSync) error {
+	entries, err := cs.riotService.GetClashRegistrations(ctx, player.PUUID, player.Server)
+	if err != nil {
+		return err
+	}
+
+	stored, err := cs.clashRepo.FindByPlayer(ctx, player.PUUID)
+	if err != nil {
+		return err
+	}
+
+	registered := make(map[int]bool)
+	for _, entry := range entries {
+		team, ok := teams[entry.TeamID]
+		if !ok {
+			team, err = cs.riotService.GetClashTeam(ctx, entry.TeamID, player.Server)
+			if err != nil {
+				return err
+			}
+			teams[entry.TeamID] = team
+		}
+
+		tournament, ok := tournaments[team.TournamentID]
+		if !ok {
+			continue
+		}
+		phase := firstActivePhase(tournament)
+		if phase == nil {
+			continue
+		}
+
+		registration := &models.ClashRegistration{
+			PlayerPUUID:      player.PUUID,
+			GameName:         player.GameName,
+			TagLine:          player.TagLine,
+			Server:           player.Server,
+			TournamentID:     tournament.ID,
+			TournamentName:   models.ClashTournamentName(tournament.NameKey, tournament.NameKeySecondary),
+			TeamID:           team.ID,
+			TeamName:         team.Name,
+			Position:         entry.Position,
+			RegistrationTime: time.UnixMilli(phase.RegistrationTime),
+			StartTime:        time.UnixMilli(phase.StartTime),
+		}
+		created, err := cs.clashRepo.Upsert(ctx, registration)
+		if err != nil {
+			return err
+		}
+		if created {
+			result.Added = append(result.Added, registration)
+		}
+		registered[tournament.ID] = true
+	}
+
+	// Registrations still upcoming but no longer returned were cancelled
+	now := time.Now()
+	for _, registration := range stored {
+		if registered[registration.TournamentID] || registration.StartTime.Before(now) {
+			continue
+		}
+		err := cs.clashRepo.Delete(ctx, registration.PlayerPUUID, registration.TournamentID)
+		if err != nil {
+			return err
+		}
+		result.Removed = append(result.Removed, registration)
+	}
+
+	return nil
+}
+
+// firstActivePhase returns the earliest phase of a tournament not cancelled, nil if they all are
+func firstActivePhase(tournament *ClashTournamentDTO) *ClashTournamentPhaseDTO {
+	var first *ClashTournamentPhaseDTO
+	for idx := range tournament.Schedule {
+		phase := &tournament.Schedule[idx]
+		if phase.Cancelled {
+			continue
+		}
+		if first == nil || phase.StartTime < first.StartTime {
+			first = phase
+		}
+	}
+	return first
+}
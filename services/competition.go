@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+type CompetitionService struct {
+	competitionRepo *repositories.CompetitionRepository
+	lpEventRepo     *repositories.LPEventRepository
+}
+
+func NewCompetitionService(competitionRepo *repositories.CompetitionRepository, lpEventRepo *repositories.LPEventRepository) *CompetitionService {
+	return &CompetitionService{
+		competitionRepo: competitionRepo,
+		lpEventRepo:     lpEventRepo,
+	}
+}
+
+// StartCompetition creates a competition, a guild can only run one competition at a time
+func (cs *CompetitionService) StartCompetition(ctx context.Context, competition *models.Competition) error {
+	err := competition.Validate()
+	if err != nil {
+		return err
+	}
+
+	current, err := cs.competitionRepo.FindCurrentByGuild(ctx, competition.GuildID)
+	if err != nil {
+		return fmt.Errorf("failed to check current competition: %w", err)
+	}
+	if current != nil {
+		return fmt.Errorf("competition %s is already in progress until %s", current.Name, current.EndAt.Format("2006-01-02"))
+	}
+
+	return cs.competitionRepo.Create(ctx, competition)
+}
+
+// GetCurrentCompetition returns the competition of a guild whose results are not announced yet, nil if none
+func (cs *CompetitionService) GetCurrentCompetition(ctx context.Context, guildID string) (*models.Competition, error) {
+	return cs.competitionRepo.FindCurrentByGuild(ctx, guildID)
+}
+
+// GetEndedCompetitions returns the competitions waiting for their final results announcement
+func (cs *CompetitionService) GetEndedCompetitions(ctx context.Context, now time.Time) ([]*models.Competition, error) {
+	return cs.competitionRepo.FindEndedUnannounced(ctx, now)
+}
+
+// MarkAnnounced records that the final results of a competition were announced
+func (cs *CompetitionService) MarkAnnounced(ctx context.Context, competition *models.Competition, announcedAt time.Time) error {
+	return cs.competitionRepo.MarkAnnounced(ctx, competition.ID, announcedAt)
+}
+
+// GetStandings scores the LP events recorded during the competition, best score first
+func (cs *CompetitionService) GetStandings(ctx context.Context, competition *models.Competition) ([]*models.CompetitionScore, error) {
+	end := competition.EndAt
+	if now := time.Now(); now.Before(end) {
+		end = now
+	}
+
+	events, err := cs.lpEventRepo.FindBetween(ctx, competition.StartAt, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch LP events: %w", err)
+	}
+
+	scoresByPUUID := make(map[string]*models.CompetitionScore)
+	var scores []*models.CompetitionScore
+	for _, event := range events {
+		score, ok := scoresByPUUID[event.PlayerPUUID]
+		if !ok {
+			score = &models.CompetitionScore{PlayerPUUID: event.PlayerPUUID}
+			scoresByPUUID[event.PlayerPUUID] = score
+			scores = append(scores, score)
+		}
+
+		// Events are sorted oldest first, so the last one holds the current rank
+		score.GameName = event.GameName
+		score.TagLine = event.TagLine
+		score.Tier = event.Tier
+		score.Rank = event.Rank
+		score.LPGained += event.LPDelta
+		score.Games++
+		if event.IsPromotion() {
+			score.Promotions++
+		}
+	}
+
+	for _, score := range scores {
+		score.Points = score.LPGained*competition.PointsPerLP + score.Promotions*competition.PointsPerPromotion
+	}
+
+	sort.SliceStable(scores, func(a, b int) bool {
+		return scores[a].Points > scores[b].Points
+	})
+
+	return scores, nil
+}
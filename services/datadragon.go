@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"lp_tracker/models"
+)
+
+const (
+	dataDragonBaseURL = "https://ddragon.leagueoflegends.com"
+
+	// Champion data only changes with patches, refreshing once a day is enough
+	dataDragonCacheTTL = 24 * time.Hour
+)
+
+// SupportedLanguages lists the Data Dragon locales that can be selected by guilds
+var SupportedLanguages = []string{
+	"en_US", "en_GB", "fr_FR", "de_DE", "es_ES", "es_MX", "it_IT", "pt_BR", "pl_PL", "ro_RO",
+	"cs_CZ", "el_GR", "hu_HU", "ru_RU", "tr_TR", "ja_JP", "ko_KR", "zh_CN", "zh_TW", "vi_VN",
+}
+
+// Data Dragon response structures
+type ChampionListDTO struct {
+	Version string                 `json:"version"`
+	Data    map[string]ChampionDTO `json:"data"`
+}
+
+type ChampionDTO struct {
+	ID    string `json:"id"`
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+// championCache holds the champion names of a single language
+type championCache struct {
+	names     map[string]string // Champion ID (ex: "MonkeyKing") -> localized name (ex: "Wukong")
+	ids       map[int]string    // Numeric champion key (ex: 62) -> champion ID
+	fetchedAt time.Time
+}
+
+type DataDragonService struct {
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	champions map[string]*championCache // Keyed by language
+}
+
+func NewDataDragonService() *DataDragonService {
+	return &DataDragonService{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		champions: make(map[string]*championCache),
+	}
+}
+
+// IsSupportedLanguage checks if a locale is available in Data Dragon
+func IsSupportedLanguage(language string) bool {
+	for _, supported := range SupportedLanguages {
+		if supported == language {
+			return true
+		}
+	}
+	return false
+}
+
+// LocalizedChampionName returns the champion name in the given language.
+// The champion ID is returned unchanged if the name cannot be resolved.
+func (d *DataDragonService) LocalizedChampionName(ctx context.Context, championID, language string) string {
+	names, err := d.getChampionNames(ctx, language)
+	if err != nil {
+		fmt.Printf("Failed to load %s champion names: %v\n", language, err)
+		return championID
+	}
+
+	name, ok := names[championID]
+	if !ok {
+		return championID
+	}
+	return name
+}
+
+// ChampionIDByKey converts the numeric champion key used by Match-V5 bans into a champion ID.
+// It returns an empty string if the key cannot be resolved.
+func (d *DataDragonService) ChampionIDByKey(ctx context.Context, key int) string {
+	cache, err := d.getChampions(ctx, "en_US")
+	if err != nil {
+		fmt.Printf("Failed to load champion keys: %v\n", err)
+		return ""
+	}
+	return cache.ids[key]
+}
+
+// CachedLanguages returns the languages whose champion names are cached
+func (d *DataDragonService) CachedLanguages() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	languages := make([]string, 0, len(d.champions))
+	for language := range d.champions {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// ClearCache drops the cached champion data, it is downloaded again on the next lookup
+func (d *DataDragonService) ClearCache() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.champions = make(map[string]*championCache)
+}
+
+func (d *DataDragonService) getChampionNames(ctx context.Context, language string) (map[string]string, error) {
+	cache, err := d.getChampions(ctx, language)
+	if err != nil {
+		return nil, err
+	}
+	return cache.names, nil
+}
+
+func (d *DataDragonService) getChampions(ctx context.Context, language string) (*championCache, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cache, ok := d.champions[language]
+	if ok && time.Since(cache.fetchedAt) < dataDragonCacheTTL {
+		return cache, nil
+	}
+
+	version, err := d.getLatestVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var championList ChampionListDTO
+	url := fmt.Sprintf("%s/cdn/%s/data/%s/champion.json", dataDragonBaseURL, version, language)
+	err = d.makeRequest(ctx, url, &championList)
+	if err != nil {
+		return nil, err
+	}
+
+	cache = &championCache{
+		names:     make(map[string]string, len(championList.Data)),
+		ids:       make(map[int]string, len(championList.Data)),
+		fetchedAt: time.Now(),
+	}
+	for id, champion := range championList.Data {
+		cache.names[id] = champion.Name
+		if key, err := strconv.Atoi(champion.Key); err == nil {
+			cache.ids[key] = id
+		}
+	}
+
+	d.champions[language] = cache
+	return cache, nil
+}
+
+// LatestPatch returns the current patch (ex: "14.5") from the latest Data Dragon version
+func (d *DataDragonService) LatestPatch(ctx context.Context) (string, error) {
+	version, err := d.getLatestVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	return models.PatchFromVersion(version), nil
+}
+
+// ProfileIconURL returns the image of a profile icon on the latest Data Dragon version
+func (d *DataDragonService) ProfileIconURL(ctx context.Context, iconID int) (string, error) {
+	version, err := d.getLatestVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/cdn/%s/img/profileicon/%d.png", dataDragonBaseURL, version, iconID), nil
+}
+
+func (d *DataDragonService) getLatestVersion(ctx context.Context) (string, error) {
+	var versions []string
+	err := d.makeRequest(ctx, dataDragonBaseURL+"/api/versions.json", &versions)
+	if err != nil {
+		return "", err
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no Data Dragon version available")
+	}
+
+	return versions[0], nil
+}
+
+func (d *DataDragonService) makeRequest(ctx context.Context, url string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Data Dragon request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, target)
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"lp_tracker/models"
+)
+
+const (
+	// Ranks move slowly, an opponent met in several games is only looked up once in a while
+	enemyRankCacheTTL = 6 * time.Hour
+
+	// Pause between two lookups to leave room for the player polls in the rate limits
+	enemyRankLookupDelay = 100 * time.Millisecond
+)
+
+// cachedRank is the Solo/Duo rank of a summoner at the time it was fetched
+type cachedRank struct {
+	tier         string
+	rank         string
+	leaguePoints int
+	fetchedAt    time.Time
+}
+
+// EnemyRankService resolves the average rank of the enemy team of a game. Lookups cost one Riot
+// API call per opponent, they are cached and stop as soon as the rate limit is reached.
+type EnemyRankService struct {
+	riotService *RiotService
+
+	mu    sync.Mutex
+	ranks map[string]*cachedRank // Keyed by PUUID
+}
+
+func NewEnemyRankService(riotService *RiotService) *EnemyRankService {
+	return &EnemyRankService{
+		riotService: riotService,
+		ranks:       make(map[string]*cachedRank),
+	}
+}
+
+// AverageRank returns the average rank of the ranked opponents of a game (ex: "EMERALD II").
+// It returns an empty string when no opponent rank could be resolved.
+func (s *EnemyRankService) AverageRank(ctx context.Context, match *models.MatchPlayerInfo, server string) (string, error) {
+	total, count := 0, 0
+	for idx, puuid := range match.EnemyPUUIDs {
+		rank, cached := s.cached(puuid)
+		if !cached {
+			if idx > 0 {
+				time.Sleep(enemyRankLookupDelay)
+			}
+
+			tier, division, leaguePoints, err := s.riotService.GetSoloRankByPUUID(ctx, puuid, server)
+			if IsRateLimited(err) {
+				// A partial average is better than slowing down the polls
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to fetch opponent rank: %w", err)
+			}
+
+			rank = &cachedRank{tier: tier, rank: division, leaguePoints: leaguePoints, fetchedAt: time.Now()}
+			s.store(puuid, rank)
+		}
+
+		if rank.tier == "UNRANKED" {
+			continue
+		}
+		total += models.RankValue(rank.tier, rank.rank, rank.leaguePoints)
+		count++
+	}
+
+	if count == 0 {
+		return "", nil
+	}
+
+	tier, division, _ := models.RankFromValue(total / count)
+	return models.FormatRank(tier, division), nil
+}
+
+func (s *EnemyRankService) cached(puuid string) (*cachedRank, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rank, ok := s.ranks[puuid]
+	if !ok || time.Since(rank.fetchedAt) > enemyRankCacheTTL {
+		return nil, false
+	}
+	return rank, true
+}
+
+func (s *EnemyRankService) store(puuid string, rank *cachedRank) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop expired entries from time to time to keep the cache bounded
+	if len(s.ranks) > 5000 {
+		for key, cached := range s.ranks {
+			if time.Since(cached.fetchedAt) > enemyRankCacheTTL {
+				delete(s.ranks, key)
+			}
+		}
+	}
+	s.ranks[puuid] = rank
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+// Flags are reloaded at this interval, a flag switched from the other process applies within this delay
+const featureFlagCacheTTL = time.Minute
+
+// FeatureFlagService resolves the feature flags of the guilds. Every flag value is kept in memory
+// and reloaded from Mongo once the cache expires.
+type FeatureFlagService struct {
+	flagRepo *repositories.FeatureFlagRepository
+
+	mu       sync.Mutex
+	flags    map[string]*models.FeatureFlag // Keyed by featureFlagKey
+	loadedAt time.Time
+}
+
+func NewFeatureFlagService(flagRepo *repositories.FeatureFlagRepository) *FeatureFlagService {
+	return &FeatureFlagService{
+		flagRepo: flagRepo,
+		flags:    make(map[string]*models.FeatureFlag),
+	}
+}
+
+func featureFlagKey(name, guildID string) string {
+	return name + "/" + guildID
+}
+
+// IsEnabled checks if a flag is enabled for a guild: the guild value wins over the global value,
+// which wins over the default. The cached values (or the default) are used if Mongo is unreachable.
+func (fs *FeatureFlagService) IsEnabled(ctx context.Context, name, guildID string) bool {
+	state, err := fs.state(ctx, name, guildID)
+	if err != nil {
+		fmt.Printf("Failed to resolve feature flag %s: %v\n", name, err)
+	}
+	return state.Enabled
+}
+
+// GetFlags returns the effective value of every known flag for a guild
+func (fs *FeatureFlagService) GetFlags(ctx context.Context, guildID string) ([]models.FeatureFlagState, error) {
+	states := make([]models.FeatureFlagState, 0, len(models.FeatureFlags))
+	for _, info := range models.FeatureFlags {
+		state, err := fs.state(ctx, info.Name, guildID)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// SetFlag enables or disables a flag for a guild, or globally with an empty guild ID
+func (fs *FeatureFlagService) SetFlag(ctx context.Context, name, guildID string, enabled bool, userID string) error {
+	if models.FindFeatureFlag(name) == nil {
+		return fmt.Errorf("unknown feature flag %s", name)
+	}
+
+	flag := &models.FeatureFlag{Name: name, GuildID: guildID, Enabled: enabled, UpdatedBy: userID}
+	err := fs.flagRepo.Set(ctx, flag)
+	if err != nil {
+		return err
+	}
+
+	fs.mu.Lock()
+	fs.flags[featureFlagKey(name, guildID)] = flag
+	fs.mu.Unlock()
+	return nil
+}
+
+// ClearFlag removes the value of a flag for a guild (or the global value), the next level applies again
+func (fs *FeatureFlagService) ClearFlag(ctx context.Context, name, guildID string) error {
+	if models.FindFeatureFlag(name) == nil {
+		return fmt.Errorf("unknown feature flag %s", name)
+	}
+
+	err := fs.flagRepo.Delete(ctx, name, guildID)
+	if err != nil {
+		return err
+	}
+
+	fs.mu.Lock()
+	delete(fs.flags, featureFlagKey(name, guildID))
+	fs.mu.Unlock()
+	return nil
+}
+
+// Reload reads the flags from the database again, without waiting for the cache to expire
+func (fs *FeatureFlagService) Reload(ctx context.Context) error {
+	fs.mu.Lock()
+	fs.loadedAt = time.Time{}
+	fs.mu.Unlock()
+
+	return fs.refresh(ctx)
+}
+
+// CacheSize returns the number of cached flag values
+func (fs *FeatureFlagService) CacheSize() int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return len(fs.flags)
+}
+
+func (fs *FeatureFlagService) state(ctx context.Context, name, guildID string) (models.FeatureFlagState, error) {
+	info := models.FindFeatureFlag(name)
+	if info == nil {
+		return models.FeatureFlagState{FeatureFlagInfo: models.FeatureFlagInfo{Name: name}, Source: models.FlagSourceDefault},
+			fmt.Errorf("unknown feature flag %s", name)
+	}
+	state := models.FeatureFlagState{FeatureFlagInfo: *info, Enabled: info.Default, Source: models.FlagSourceDefault}
+
+	err := fs.refresh(ctx)
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if flag, ok := fs.flags[featureFlagKey(name, guildID)]; ok && guildID != "" {
+		state.Enabled, state.Source = flag.Enabled, models.FlagSourceGuild
+	} else if flag, ok := fs.flags[featureFlagKey(name, "")]; ok {
+		state.Enabled, state.Source = flag.Enabled, models.FlagSourceGlobal
+	}
+	return state, err
+}
+
+// refresh reloads the flags once the cache expired, the previous values are kept on failure
+func (fs *FeatureFlagService) refresh(ctx context.Context) error {
+	fs.mu.Lock()
+	fresh := time.Since(fs.loadedAt) < featureFlagCacheTTL
+	fs.mu.Unlock()
+	if fresh {
+		return nil
+	}
+
+	flags, err := fs.flagRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[string]*models.FeatureFlag, len(flags))
+	for _, flag := range flags {
+		loaded[featureFlagKey(flag.Name, flag.GuildID)] = flag
+	}
+
+	fs.mu.Lock()
+	fs.flags = loaded
+	fs.loadedAt = time.Now()
+	fs.mu.Unlock()
+	return nil
+}
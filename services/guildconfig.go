@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+// The poller and the commands listener run in separate processes, so configs written by one
+// are only seen by the other once their cache entry expires
+const guildConfigCacheTTL = 2 * time.Minute
+
+type cachedGuildConfig struct {
+	config    models.GuildConfig
+	fetchedAt time.Time
+}
+
+type GuildConfigService struct {
+	guildConfigRepo *repositories.GuildConfigRepository
+
+	mu    sync.Mutex
+	cache map[string]*cachedGuildConfig // Keyed by guild ID
+}
+
+func NewGuildConfigService(guildConfigRepo *repositories.GuildConfigRepository) *GuildConfigService {
+	return &GuildConfigService{
+		guildConfigRepo: guildConfigRepo,
+		cache:           make(map[string]*cachedGuildConfig),
+	}
+}
+
+// GetConfig returns the configuration of a guild, the default one if the guild is not configured yet.
+// The returned config is a copy that can be modified freely.
+func (gs *GuildConfigService) GetConfig(ctx context.Context, guildID string) (*models.GuildConfig, error) {
+	gs.mu.Lock()
+	cached, ok := gs.cache[guildID]
+	gs.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < guildConfigCacheTTL {
+		config := cached.config
+		return &config, nil
+	}
+
+	config, err := gs.guildConfigRepo.FindByGuildID(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = models.NewGuildConfig(guildID)
+	}
+
+	gs.store(config)
+	return config, nil
+}
+
+// GetNotificationConfigs returns the configuration of every guild with a notification channel
+func (gs *GuildConfigService) GetNotificationConfigs(ctx context.Context) ([]*models.GuildConfig, error) {
+	configs, err := gs.guildConfigRepo.FindWithNotificationChannel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, config := range configs {
+		gs.store(config)
+	}
+	return configs, nil
+}
+
+// GetNotificationChannelID returns the notification channel of a guild, empty if not set
+// or if the bot left the guild
+func (gs *GuildConfigService) GetNotificationChannelID(ctx context.Context, guildID string) (string, error) {
+	config, err := gs.GetConfig(ctx, guildID)
+	if err != nil {
+		return "", err
+	}
+	if !config.IsActive() {
+		return "", nil
+	}
+	return config.NotificationChannelID, nil
+}
+
+// GetLiveLeaderboardConfigs returns the configuration of every guild with the live leaderboard enabled
+func (gs *GuildConfigService) GetLiveLeaderboardConfigs(ctx context.Context) ([]*models.GuildConfig, error) {
+	return gs.guildConfigRepo.FindWithLiveLeaderboard(ctx)
+}
+
+// SetLiveLeaderboardMessage records the message holding the live leaderboard of a guild.
+// Only this field is written, so settings changed meanwhile by commands are kept.
+func (gs *GuildConfigService) SetLiveLeaderboardMessage(ctx context.Context, guildID, messageID string) error {
+	err := gs.guildConfigRepo.SetLiveLeaderboardMessage(ctx, guildID, messageID)
+	if err != nil {
+		return err
+	}
+
+	gs.Invalidate(guildID)
+	return nil
+}
+
+// TogglePlayerMuted mutes the notifications of a player in a guild, or unmutes them if already muted.
+// It returns true if the player is now muted.
+func (gs *GuildConfigService) TogglePlayerMuted(ctx context.Context, guildID, puuid string) (bool, error) {
+	gs.Invalidate(guildID)
+	config, err := gs.GetConfig(ctx, guildID)
+	if err != nil {
+		return false, err
+	}
+
+	// Make sure the guild document exists before the targeted update
+	if config.ID.IsZero() {
+		err = gs.SaveConfig(ctx, config)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	muted := !config.IsMuted(puuid)
+	err = gs.guildConfigRepo.SetPlayerMuted(ctx, guildID, puuid, muted)
+	if err != nil {
+		return false, err
+	}
+
+	gs.Invalidate(guildID)
+	return muted, nil
+}
+
+// SaveConfig validates and saves the configuration of a guild
+func (gs *GuildConfigService) SaveConfig(ctx context.Context, config *models.GuildConfig) error {
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+	if config.Language != "" && !IsSupportedLanguage(config.Language) {
+		return fmt.Errorf("unsupported language %s", config.Language)
+	}
+
+	err = gs.guildConfigRepo.Upsert(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	gs.store(config)
+	return nil
+}
+
+// UpdateConfig applies a change to the configuration of a guild, validates and saves it.
+// The config is read from the database so fields written by the other process are not overwritten.
+func (gs *GuildConfigService) UpdateConfig(ctx context.Context, guildID string, update func(config *models.GuildConfig) error) (*models.GuildConfig, error) {
+	gs.Invalidate(guildID)
+	config, err := gs.GetConfig(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = update(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = gs.SaveConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// MarkRecapSent records when the last recap of a period was sent to a guild
+func (gs *GuildConfigService) MarkRecapSent(ctx context.Context, guildID string, period models.RecapPeriod, sentAt time.Time) error {
+	err := gs.guildConfigRepo.MarkRecapSent(ctx, guildID, period, sentAt)
+	if err != nil {
+		return err
+	}
+
+	gs.Invalidate(guildID)
+	return nil
+}
+
+// SetLastPatch records the last patch announced to a guild
+func (gs *GuildConfigService) SetLastPatch(ctx context.Context, guildID, patch string) error {
+	err := gs.guildConfigRepo.SetLastPatch(ctx, guildID, patch)
+	if err != nil {
+		return err
+	}
+
+	gs.Invalidate(guildID)
+	return nil
+}
+
+// GuildJoined makes sure a guild the bot is member of has an active configuration.
+// It returns true if a default configuration was created.
+func (gs *GuildConfigService) GuildJoined(ctx context.Context, guildID string) (bool, error) {
+	config, err := gs.guildConfigRepo.FindByGuildID(ctx, guildID)
+	if err != nil {
+		return false, err
+	}
+
+	if config == nil {
+		err = gs.SaveConfig(ctx, models.NewGuildConfig(guildID))
+		return err == nil, err
+	}
+	if !config.IsActive() {
+		err = gs.guildConfigRepo.SetLeftAt(ctx, guildID, nil)
+		if err != nil {
+			return false, err
+		}
+		gs.Invalidate(guildID)
+	}
+	return false, nil
+}
+
+// GuildLeft marks a guild inactive so nothing is sent to it anymore
+func (gs *GuildConfigService) GuildLeft(ctx context.Context, guildID string, at time.Time) error {
+	err := gs.guildConfigRepo.SetLeftAt(ctx, guildID, &at)
+	if err != nil {
+		return err
+	}
+
+	gs.Invalidate(guildID)
+	return nil
+}
+
+// ReconcileGuilds aligns the stored configurations with the guilds the bot is member of:
+// defaults are created for new guilds and departed guilds are marked inactive
+func (gs *GuildConfigService) ReconcileGuilds(ctx context.Context, guildIDs []string) (created, left int, err error) {
+	joined := make(map[string]bool, len(guildIDs))
+	for _, guildID := range guildIDs {
+		joined[guildID] = true
+
+		isNew, err := gs.GuildJoined(ctx, guildID)
+		if err != nil {
+			return created, left, fmt.Errorf("failed to reconcile guild %s: %w", guildID, err)
+		}
+		if isNew {
+			created++
+		}
+	}
+
+	configs, err := gs.guildConfigRepo.FindAll(ctx)
+	if err != nil {
+		return created, left, err
+	}
+
+	now := time.Now()
+	for _, config := range configs {
+		if joined[config.GuildID] || !config.IsActive() {
+			continue
+		}
+
+		err := gs.GuildLeft(ctx, config.GuildID, now)
+		if err != nil {
+			return created, left, fmt.Errorf("failed to reconcile guild %s: %w", config.GuildID, err)
+		}
+		left++
+	}
+
+	return created, left, nil
+}
+
+// Invalidate drops the cached configuration of a guild
+func (gs *GuildConfigService) Invalidate(guildID string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	delete(gs.cache, guildID)
+}
+
+// InvalidateAll drops every cached configuration, they are read again from the database
+func (gs *GuildConfigService) InvalidateAll() {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	gs.cache = make(map[string]*cachedGuildConfig)
+}
+
+// CacheSize returns the number of cached guild configurations
+func (gs *GuildConfigService) CacheSize() int {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	return len(gs.cache)
+}
+
+func (gs *GuildConfigService) store(config *models.GuildConfig) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	gs.cache[config.GuildID] = &cachedGuildConfig{
+		config:    *config,
+		fetchedAt: time.Now(),
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Match-V5 queue IDs of the ranked queues
+const (
+	RankedSoloQueueID = 420
+	RankedFlexQueueID = 440
+)
+
+// Queue types stored on the matches, keyed by queue ID
+var queueTypes = map[int]string{
+	RankedSoloQueueID: "RANKED_SOLO_5x5",
+	RankedFlexQueueID: "RANKED_FLEX_SR",
+}
+
+// Queue names accepted in the configuration
+var queueNames = map[string]int{
+	"solo": RankedSoloQueueID,
+	"flex": RankedFlexQueueID,
+}
+
+// MatchIngestionOptions controls which games are pulled from the Riot API and what is stored
+type MatchIngestionOptions struct {
+	MaxMatches        int   // Games ingested per player and poll
+	Queues            []int // Queue IDs ingested
+	StoreParticipants bool  // Keep the champion, team and KDA of every participant of the games
+}
+
+// DefaultMatchIngestionOptions ingests up to 20 Solo/Duo games per poll, without the participants
+func DefaultMatchIngestionOptions() MatchIngestionOptions {
+	return MatchIngestionOptions{
+		MaxMatches: 20,
+		Queues:     []int{RankedSoloQueueID},
+	}
+}
+
+// ParseQueues parses a comma separated list of queues (ex: "solo,flex")
+func ParseQueues(spec string) ([]int, error) {
+	var queues []int
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		queueID, ok := queueNames[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown queue %q (expected solo or flex)", name)
+		}
+		queues = append(queues, queueID)
+	}
+
+	if len(queues) == 0 {
+		return nil, fmt.Errorf("no queue in %q", spec)
+	}
+	return queues, nil
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JobTimeout bounds the duration of a background job
+const JobTimeout = 2 * time.Hour
+
+// JobProgress reports the progress percentage of a running job
+type JobProgress func(percent int)
+
+// JobFunc is the work of a background job, it returns a summary shown to the requester
+type JobFunc func(ctx context.Context, progress JobProgress) (string, error)
+
+// JobService runs background jobs and persists their status, progress and result
+type JobService struct {
+	jobRepo *repositories.JobRepository
+
+	mu      sync.Mutex
+	running map[primitive.ObjectID]context.CancelFunc // Jobs running in this process
+	wg      sync.WaitGroup                            // Joins the job goroutines, see Shutdown
+}
+
+func NewJobService(jobRepo *repositories.JobRepository) *JobService {
+	return &JobService{
+		jobRepo: jobRepo,
+		running: make(map[primitive.ObjectID]context.CancelFunc),
+	}
+}
+
+// Start persists the job and runs it in the background, done is called with the final state of the job
+func (s *JobService) Start(ctx context.Context, job *models.Job, run JobFunc, done func(*models.Job)) error {
+	err := s.jobRepo.Create(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	jobCtx, cancel := context.WithTimeout(context.Background(), JobTimeout)
+	s.mu.Lock()
+	s.running[job.ID] = cancel
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, job.ID)
+			s.mu.Unlock()
+			cancel()
+		}()
+
+		s.run(jobCtx, job, run)
+		if done != nil {
+			done(job)
+		}
+	}()
+
+	return nil
+}
+
+func (s *JobService) run(ctx context.Context, job *models.Job, run JobFunc) {
+	// Status updates use their own context, the job context may be cancelled
+	statusCtx := func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), 10*time.Second)
+	}
+
+	startedAt := time.Now()
+	job.Status = models.JobRunning
+	job.StartedAt = &startedAt
+	updateCtx, cancel := statusCtx()
+	if err := s.jobRepo.MarkRunning(updateCtx, job.ID); err != nil {
+		fmt.Printf("Warning: failed to mark job %s as running: %v\n", job.ShortID(), err)
+	}
+	cancel()
+
+	progress := func(percent int) {
+		percent = max(0, min(percent, 100))
+		if percent == job.Progress {
+			return
+		}
+		job.Progress = percent
+
+		updateCtx, cancel := statusCtx()
+		defer cancel()
+		if err := s.jobRepo.UpdateProgress(updateCtx, job.ID, percent); err != nil {
+			fmt.Printf("Warning: failed to save progress of job %s: %v\n", job.ShortID(), err)
+		}
+	}
+
+	result, err := run(ctx, progress)
+
+	job.Result = result
+	job.Status = models.JobSucceeded
+	switch {
+	case errors.Is(err, context.Canceled):
+		job.Status = models.JobCancelled
+		job.Error = "cancelled"
+	case err != nil:
+		job.Status = models.JobFailed
+		job.Error = err.Error()
+	default:
+		job.Progress = 100
+	}
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+
+	updateCtx, cancel = statusCtx()
+	defer cancel()
+	if err := s.jobRepo.Finish(updateCtx, job.ID, job.Status, job.Result, job.Error); err != nil {
+		fmt.Printf("Warning: failed to save result of job %s: %v\n", job.ShortID(), err)
+	}
+}
+
+// GetJob returns a job of a guild by its ID, nil if it doesn't exist
+func (s *JobService) GetJob(ctx context.Context, guildID, id string) (*models.Job, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+
+	job, err := s.jobRepo.FindByID(ctx, oid)
+	if err != nil || job == nil || job.GuildID != guildID {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetRecentJobs returns the latest jobs of a guild
+func (s *JobService) GetRecentJobs(ctx context.Context, guildID string, limit int) ([]*models.Job, error) {
+	return s.jobRepo.FindByGuild(ctx, guildID, limit)
+}
+
+// CancelJob stops a running job of a guild
+func (s *JobService) CancelJob(ctx context.Context, guildID, id string) (*models.Job, error) {
+	job, err := s.GetJob(ctx, guildID, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job %s not found", id)
+	}
+	if job.IsFinished() {
+		return nil, fmt.Errorf("job %s is already %s", id, job.Status)
+	}
+
+	s.mu.Lock()
+	cancel, ok := s.running[job.ID]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("job %s is not running on this instance", id)
+	}
+
+	cancel()
+	return job, nil
+}
+
+// Shutdown cancels the jobs running in this process and waits for them to record their final state,
+// or for ctx to expire
+func (s *JobService) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	for _, cancel := range s.running {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("jobs still running: %w", ctx.Err())
+	}
+}
+
+// FailInterruptedJobs marks the jobs left unfinished by a previous run as failed, they cannot be resumed
+func (s *JobService) FailInterruptedJobs(ctx context.Context) (int64, error) {
+	return s.jobRepo.FailUnfinished(ctx, "interrupted by a restart")
+}
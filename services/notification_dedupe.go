@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+// NotificationDedupeStore remembers which rank changes were already announced, so that a poller
+// restarted mid-cycle (or replaying missed games) doesn't announce them twice
+type NotificationDedupeStore interface {
+	IsNotified(ctx context.Context, change *models.RankChange) (bool, error)
+	MarkNotified(ctx context.Context, change *models.RankChange, at time.Time) error
+}
+
+// LedgerDedupeStore is the durable dedupe store backed by the notification dates of the stored
+// matches and of the LP ledger. A change is announced once per game, or once per ledger entry
+// when its game is unknown.
+type LedgerDedupeStore struct {
+	matchRepo   *repositories.MatchRepository
+	lpEventRepo *repositories.LPEventRepository
+}
+
+func NewLedgerDedupeStore(matchRepo *repositories.MatchRepository, lpEventRepo *repositories.LPEventRepository) *LedgerDedupeStore {
+	return &LedgerDedupeStore{
+		matchRepo:   matchRepo,
+		lpEventRepo: lpEventRepo,
+	}
+}
+
+// IsNotified checks if the game or the ledger entry of the change was already announced
+func (s *LedgerDedupeStore) IsNotified(ctx context.Context, change *models.RankChange) (bool, error) {
+	if change.Match != nil {
+		match, err := s.matchRepo.FindByPlayerAndMatch(ctx, change.Player.PUUID, change.Match.MatchID)
+		if err != nil {
+			return false, err
+		}
+		if match != nil && match.NotifiedAt != nil {
+			return true, nil
+		}
+	}
+
+	if change.EventID.IsZero() {
+		return false, nil
+	}
+
+	event, err := s.lpEventRepo.FindByID(ctx, change.EventID)
+	if err != nil {
+		return false, err
+	}
+	return event != nil && event.NotifiedAt != nil, nil
+}
+
+// MarkNotified records the announcement on the game and on the ledger entry of the change
+func (s *LedgerDedupeStore) MarkNotified(ctx context.Context, change *models.RankChange, at time.Time) error {
+	if change.Match != nil {
+		err := s.matchRepo.MarkNotified(ctx, change.Player.PUUID, change.Match.MatchID, at)
+		if err != nil {
+			return err
+		}
+		change.Match.NotifiedAt = &at
+	}
+
+	if change.EventID.IsZero() {
+		return nil
+	}
+	return s.lpEventRepo.MarkNotified(ctx, change.EventID, at)
+}
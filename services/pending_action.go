@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PendingActionHandler confirms the pending actions of a kind, each "confirm then act" flow registers one
+type PendingActionHandler interface {
+	// Check tries to confirm an action, done ends the flow (confirmed or no longer relevant).
+	// The message is sent to the user by DM when not empty.
+	Check(ctx context.Context, action *models.PendingAction) (done bool, message string, err error)
+
+	// Reminder returns the message reminding the user of a pending action
+	Reminder(ctx context.Context, action *models.PendingAction) (string, error)
+
+	// Expire closes the flow of an expired action, it returns the message sent to the user
+	Expire(ctx context.Context, action *models.PendingAction) (string, error)
+}
+
+// PendingActionMessage is a direct message to send to a user about one of their pending actions
+type PendingActionMessage struct {
+	DiscordUserID string
+	Content       string
+}
+
+// PendingActionService is the queue of the actions waiting for a user confirmation (see models.PendingAction)
+type PendingActionService struct {
+	pendingActionRepo *repositories.PendingActionRepository
+	handlers          map[models.PendingActionKind]PendingActionHandler
+}
+
+func NewPendingActionService(pendingActionRepo *repositories.PendingActionRepository) *PendingActionService {
+	return &PendingActionService{
+		pendingActionRepo: pendingActionRepo,
+		handlers:          make(map[models.PendingActionKind]PendingActionHandler),
+	}
+}
+
+// RegisterHandler sets the handler of a kind of actions, it must be called before Process
+func (ps *PendingActionService) RegisterHandler(kind models.PendingActionKind, handler PendingActionHandler) {
+	ps.handlers[kind] = handler
+}
+
+// Enqueue adds an action to the queue, replacing the pending action of the same kind of the user
+func (ps *PendingActionService) Enqueue(ctx context.Context, kind models.PendingActionKind, discordUserID string, refID primitive.ObjectID, expiresAt time.Time) error {
+	return ps.pendingActionRepo.Enqueue(ctx, models.NewPendingAction(kind, discordUserID, refID, time.Now(), expiresAt))
+}
+
+// Complete removes the actions of a flow completed outside of the queue (ex: from a command)
+func (ps *PendingActionService) Complete(ctx context.Context, kind models.PendingActionKind, refID primitive.ObjectID) error {
+	return ps.pendingActionRepo.DeleteByRef(ctx, kind, refID)
+}
+
+// Process checks the due actions: confirmed actions are removed, expired ones are closed by their handler
+// and the others are rescheduled, with a reminder when due. It returns the messages to DM.
+func (ps *PendingActionService) Process(ctx context.Context, now time.Time) ([]PendingActionMessage, error) {
+	actions, err := ps.pendingActionRepo.FindDue(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []PendingActionMessage
+	var errors []string
+	for _, action := range actions {
+		if ctx.Err() != nil {
+			return messages, ctx.Err()
+		}
+
+		message, err := ps.process(ctx, action, now)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to process %s action of user %s: %v", action.Kind, action.DiscordUserID, err)
+			errors = append(errors, errorMsg)
+			fmt.Println(errorMsg)
+		}
+		if message != "" {
+			messages = append(messages, PendingActionMessage{DiscordUserID: action.DiscordUserID, Content: message})
+		}
+	}
+
+	if len(errors) > 0 {
+		return messages, fmt.Errorf("some pending actions failed: %v", errors)
+	}
+
+	return messages, nil
+}
+
+func (ps *PendingActionService) process(ctx context.Context, action *models.PendingAction, now time.Time) (string, error) {
+	handler, ok := ps.handlers[action.Kind]
+	if !ok {
+		// Kind of a removed flow, nothing can confirm it anymore
+		return "", ps.pendingActionRepo.Delete(ctx, action.ID)
+	}
+
+	if action.IsExpired(now) {
+		message, err := handler.Expire(ctx, action)
+		if err != nil {
+			return "", err
+		}
+		return message, ps.pendingActionRepo.Delete(ctx, action.ID)
+	}
+
+	done, message, err := handler.Check(ctx, action)
+	if err != nil {
+		// Checked again on the next run, a Riot API hiccup must not end the flow
+		action.NextCheckAt = now.Add(models.PendingActionCheckInterval)
+		if rescheduleErr := ps.pendingActionRepo.Reschedule(ctx, action); rescheduleErr != nil {
+			return "", rescheduleErr
+		}
+		return "", err
+	}
+	if done {
+		return message, ps.pendingActionRepo.Delete(ctx, action.ID)
+	}
+
+	if action.ShouldPrompt(now) {
+		message, err = handler.Reminder(ctx, action)
+		if err != nil {
+			return "", err
+		}
+		action.Prompts++
+	}
+
+	action.NextCheckAt = now.Add(models.PendingActionCheckInterval)
+	return message, ps.pendingActionRepo.Reschedule(ctx, action)
+}
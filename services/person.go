@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+type PersonService struct {
+	personRepo *repositories.PersonRepository
+	playerRepo *repositories.PlayerRepository
+}
+
+func NewPersonService(personRepo *repositories.PersonRepository, playerRepo *repositories.PlayerRepository) *PersonService {
+	return &PersonService{
+		personRepo: personRepo,
+		playerRepo: playerRepo,
+	}
+}
+
+// LinkAccount links a tracked account to a person (created on first link).
+// An account belongs to a single person, it is moved if it was linked to another one.
+func (ps *PersonService) LinkAccount(ctx context.Context, name, gameName, tagLine, server string) (*models.Person, *models.Player, error) {
+	name, err := models.NormalizePersonName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	player, err := ps.trackedPlayer(ctx, gameName, tagLine, server)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = ps.personRepo.RemovePlayer(ctx, player.PUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = ps.personRepo.AddPlayer(ctx, name, player.PUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	person, err := ps.personRepo.FindByName(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return person, player, nil
+}
+
+// UnlinkAccount removes a tracked account from its person, the person is deleted with its last account
+func (ps *PersonService) UnlinkAccount(ctx context.Context, gameName, tagLine, server string) (*models.Player, error) {
+	player, err := ps.trackedPlayer(ctx, gameName, tagLine, server)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ps.personRepo.RemovePlayer(ctx, player.PUUID)
+	if err != nil {
+		return nil, err
+	}
+	return player, nil
+}
+
+// GetPersons returns all persons with their tracked accounts
+func (ps *PersonService) GetPersons(ctx context.Context) ([]*models.PersonStanding, error) {
+	persons, err := ps.personRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := ps.playerRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+	sortPlayersByRank(players)
+
+	standings := groupByPerson(persons, players)
+	var linked []*models.PersonStanding
+	for _, standing := range standings {
+		if standing.Person != nil {
+			linked = append(linked, standing)
+		}
+	}
+	return linked, nil
+}
+
+// CompareAccounts returns the accounts of a person with their ranks normalized across regions,
+// best account first
+func (ps *PersonService) CompareAccounts(ctx context.Context, name string) (*models.Person, []*models.AccountComparison, error) {
+	name, err := models.NormalizePersonName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	person, err := ps.personRepo.FindByName(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if person == nil {
+		return nil, nil, fmt.Errorf("person %s does not exist", name)
+	}
+
+	players, err := ps.playerRepo.FindByPUUIDs(ctx, person.PlayerPUUIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return person, models.CompareAccounts(players), nil
+}
+
+func (ps *PersonService) trackedPlayer(ctx context.Context, gameName, tagLine, server string) (*models.Player, error) {
+	player, err := ps.playerRepo.FindByRiotID(ctx, gameName, tagLine, server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if player == nil {
+		return nil, fmt.Errorf("player %s#%s (%s) is not tracked", gameName, tagLine, server)
+	}
+	return player, nil
+}
+
+// groupByPerson groups the players (sorted by rank, best first) by person, keeping the order of the
+// best accounts. Accounts not linked to a person stay on their own.
+func groupByPerson(persons []*models.Person, players []*models.Player) []*models.PersonStanding {
+	personByPUUID := make(map[string]*models.Person)
+	for _, person := range persons {
+		for _, puuid := range person.PlayerPUUIDs {
+			personByPUUID[puuid] = person
+		}
+	}
+
+	standingByPerson := make(map[*models.Person]*models.PersonStanding)
+	var standings []*models.PersonStanding
+	for _, player := range players {
+		person, linked := personByPUUID[player.PUUID]
+		if !linked {
+			standings = append(standings, &models.PersonStanding{Best: player, Accounts: []*models.Player{player}})
+			continue
+		}
+
+		standing, ok := standingByPerson[person]
+		if !ok {
+			standing = &models.PersonStanding{Person: person, Best: player}
+			standingByPerson[person] = standing
+			standings = append(standings, standing)
+		}
+		standing.Accounts = append(standing.Accounts, player)
+	}
+
+	return standings
+}
@@ -3,36 +3,104 @@ package services
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"lp_tracker/models"
 	"lp_tracker/repositories"
+	"lp_tracker/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Number of recent games used to compute LP averages
+const lpStatsSampleSize = 50
+
+// RefreshCooldown is how long a player refreshed (or polled) on demand is shown as is, without calling the Riot API again
+const RefreshCooldown = time.Minute
+
 type PlayerService struct {
-	playerRepo  *repositories.PlayerRepository
-	riotService *RiotService
+	playerRepo      *repositories.PlayerRepository
+	lpEventRepo     *repositories.LPEventRepository
+	matchRepo       *repositories.MatchRepository
+	sharedMatchRepo *repositories.SharedMatchRepository
+	riotService     *RiotService
+	dataDragon      *DataDragonService
+	ingestion       MatchIngestionOptions
+	writeBuffer     WriteBufferOptions
+	maxPlayers      int // 0 for no limit, see SetMaxPlayers
 }
 
-func NewPlayerService(playerRepo *repositories.PlayerRepository, riotAPIKey string) *PlayerService {
+func NewPlayerService(playerRepo *repositories.PlayerRepository, lpEventRepo *repositories.LPEventRepository, matchRepo *repositories.MatchRepository,
+	sharedMatchRepo *repositories.SharedMatchRepository, dataDragon *DataDragonService, riotService *RiotService) *PlayerService {
 	return &PlayerService{
-		playerRepo:  playerRepo,
-		riotService: NewRiotService(riotAPIKey),
+		playerRepo:      playerRepo,
+		lpEventRepo:     lpEventRepo,
+		matchRepo:       matchRepo,
+		sharedMatchRepo: sharedMatchRepo,
+		riotService:     riotService,
+		dataDragon:      dataDragon,
+		ingestion:       DefaultMatchIngestionOptions(),
+		writeBuffer:     DefaultWriteBufferOptions(),
 	}
 }
 
+// SetMatchIngestionOptions sets which games are ingested by the polls
+func (ps *PlayerService) SetMatchIngestionOptions(opts MatchIngestionOptions) {
+	ps.ingestion = opts
+}
+
+// SetWriteBufferOptions sets how the poll cycles batch their writes
+func (ps *PlayerService) SetWriteBufferOptions(opts WriteBufferOptions) {
+	ps.writeBuffer = opts
+}
+
+// SetMaxPlayers limits the players that can be tracked (ex: the quota of a tenant), 0 for no limit.
+// Paused players count, resuming one is always allowed.
+func (ps *PlayerService) SetMaxPlayers(maxPlayers int) {
+	ps.maxPlayers = maxPlayers
+}
+
 // AddPlayer adds a new player to tracking
 func (ps *PlayerService) AddPlayer(ctx context.Context, gameName, tagLine, server string) (*models.Player, error) {
+	ctx, span := tracer.Start(ctx, "PlayerService.AddPlayer", trace.WithAttributes(riotIDAttributes(gameName, tagLine, server)...))
+	player, err := ps.addPlayer(ctx, gameName, tagLine, server)
+	telemetry.End(span, err)
+	return player, err
+}
+
+func (ps *PlayerService) addPlayer(ctx context.Context, gameName, tagLine, server string) (*models.Player, error) {
 	// Check if player already exists
 	existingPlayer, err := ps.playerRepo.FindByRiotID(ctx, gameName, tagLine, server)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing player: %w", err)
 	}
 
+	if existingPlayer != nil && existingPlayer.Paused {
+		// Adding a paused player resumes their polling
+		err = ps.playerRepo.SetPaused(ctx, existingPlayer.ID, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resume player: %w", err)
+		}
+		existingPlayer.Paused = false
+		return existingPlayer, nil
+	}
 	if existingPlayer != nil {
 		return nil, fmt.Errorf("player %s#%s (%s) is already being tracked", gameName, tagLine, server)
 	}
 
+	if ps.maxPlayers > 0 {
+		count, err := ps.playerRepo.Count(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count players: %w", err)
+		}
+		if count >= int64(ps.maxPlayers) {
+			return nil, fmt.Errorf("the limit of %d tracked players is reached, remove a player first", ps.maxPlayers)
+		}
+	}
+
 	// Fetch player data from Riot API
 	player, err := ps.riotService.GetPlayerByRiotID(ctx, gameName, tagLine, server)
 	if err != nil {
@@ -48,6 +116,29 @@ func (ps *PlayerService) AddPlayer(ctx context.Context, gameName, tagLine, serve
 	return player, nil
 }
 
+// LookupPlayer fetches the current rank of a player from the Riot API without tracking them.
+// It also reports whether the player is already tracked.
+func (ps *PlayerService) LookupPlayer(ctx context.Context, gameName, tagLine, server string) (*models.Player, bool, error) {
+	ctx, span := tracer.Start(ctx, "PlayerService.LookupPlayer", trace.WithAttributes(riotIDAttributes(gameName, tagLine, server)...))
+	player, tracked, err := ps.lookupPlayer(ctx, gameName, tagLine, server)
+	telemetry.End(span, err)
+	return player, tracked, err
+}
+
+func (ps *PlayerService) lookupPlayer(ctx context.Context, gameName, tagLine, server string) (*models.Player, bool, error) {
+	player, err := ps.riotService.GetPlayerByRiotID(ctx, gameName, tagLine, server)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch player from Riot API: %w", err)
+	}
+
+	existingPlayer, err := ps.playerRepo.FindByPUUID(ctx, player.PUUID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check existing player: %w", err)
+	}
+
+	return player, existingPlayer != nil, nil
+}
+
 // GetAllPlayers returns all tracked players
 func (ps *PlayerService) GetAllPlayers(ctx context.Context) ([]*models.Player, error) {
 	return ps.playerRepo.FindAll(ctx)
@@ -58,33 +149,565 @@ func (ps *PlayerService) GetPlayerByRiotID(ctx context.Context, gameName, tagLin
 	return ps.playerRepo.FindByRiotID(ctx, gameName, tagLine, server)
 }
 
-// UpdatePlayer updates a single player's information
-func (ps *PlayerService) UpdatePlayer(ctx context.Context, player *models.Player) error {
+// GetPlayerByPUUID returns a tracked player by PUUID
+func (ps *PlayerService) GetPlayerByPUUID(ctx context.Context, puuid string) (*models.Player, error) {
+	return ps.playerRepo.FindByPUUID(ctx, puuid)
+}
+
+// GetLastPollAt returns when the poller last updated a player, nil if no player was polled yet
+func (ps *PlayerService) GetLastPollAt(ctx context.Context) (*time.Time, error) {
+	players, err := ps.playerRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+
+	var last *time.Time
+	for _, player := range players {
+		if player.LastPolledAt != nil && (last == nil || player.LastPolledAt.After(*last)) {
+			last = player.LastPolledAt
+		}
+	}
+	return last, nil
+}
+
+// GetPlayersByTag returns all tracked players with the given tag
+func (ps *PlayerService) GetPlayersByTag(ctx context.Context, tag string) ([]*models.Player, error) {
+	tag, err := models.NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	return ps.playerRepo.FindByTag(ctx, tag)
+}
+
+// SetPlayerTag adds (or removes) a tag on a tracked player
+func (ps *PlayerService) SetPlayerTag(ctx context.Context, gameName, tagLine, server, tag string, remove bool) (*models.Player, error) {
+	tag, err := models.NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := ps.playerRepo.FindByRiotID(ctx, gameName, tagLine, server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find player: %w", err)
+	}
+	if player == nil {
+		return nil, fmt.Errorf("player %s#%s (%s) is not tracked", gameName, tagLine, server)
+	}
+
+	if remove {
+		err = ps.playerRepo.RemoveTag(ctx, player.ID, tag)
+	} else {
+		err = ps.playerRepo.AddTag(ctx, player.ID, tag)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the returned player in sync with the database
+	var tags []string
+	for _, t := range player.Tags {
+		if t != tag {
+			tags = append(tags, t)
+		}
+	}
+	if !remove {
+		tags = append(tags, tag)
+	}
+	player.Tags = tags
+
+	return player, nil
+}
+
+// SetPlayerNote updates the alias and the note of a tracked player. A nil value keeps the current
+// one and an empty value clears it.
+func (ps *PlayerService) SetPlayerNote(ctx context.Context, gameName, tagLine, server string, alias, note *string) (*models.Player, error) {
+	player, err := ps.playerRepo.FindByRiotID(ctx, gameName, tagLine, server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if player == nil {
+		return nil, fmt.Errorf("player %s#%s (%s) is not tracked", gameName, tagLine, server)
+	}
+
+	if alias != nil {
+		value := strings.TrimSpace(*alias)
+		if len(value) > models.MaxAliasLength {
+			return nil, fmt.Errorf("alias cannot exceed %d characters", models.MaxAliasLength)
+		}
+		player.Alias = value
+	}
+	if note != nil {
+		value := strings.TrimSpace(*note)
+		if len(value) > models.MaxNoteLength {
+			return nil, fmt.Errorf("note cannot exceed %d characters", models.MaxNoteLength)
+		}
+		player.Note = value
+	}
+
+	err = ps.playerRepo.SetAliasAndNote(ctx, player.ID, player.Alias, player.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	return player, nil
+}
+
+// SetTwitchChannel maps a tracked player to a Twitch channel (name or URL), an empty channel removes the mapping
+func (ps *PlayerService) SetTwitchChannel(ctx context.Context, gameName, tagLine, server, channel string) (*models.Player, error) {
+	player, err := ps.playerRepo.FindByRiotID(ctx, gameName, tagLine, server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if player == nil {
+		return nil, fmt.Errorf("player %s#%s (%s) is not tracked", gameName, tagLine, server)
+	}
+
+	login := ""
+	if channel != "" {
+		login, err = models.NormalizeTwitchChannel(channel)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = ps.playerRepo.SetTwitchChannel(ctx, player.ID, login)
+	if err != nil {
+		return nil, err
+	}
+
+	player.TwitchChannel = login
+	player.TwitchLive = false
+	return player, nil
+}
+
+// GetTwitchPlayers returns the players mapped to a Twitch channel
+func (ps *PlayerService) GetTwitchPlayers(ctx context.Context) ([]*models.Player, error) {
+	return ps.playerRepo.FindWithTwitchChannel(ctx)
+}
+
+// SetTwitchLive records the stream state of a player
+func (ps *PlayerService) SetTwitchLive(ctx context.Context, player *models.Player, live bool) error {
+	err := ps.playerRepo.SetTwitchLive(ctx, player.ID, live)
+	if err != nil {
+		return err
+	}
+
+	player.TwitchLive = live
+	return nil
+}
+
+// GetActiveGame returns the game in progress of a player, nil when they are not in game
+func (ps *PlayerService) GetActiveGame(ctx context.Context, player *models.Player) (*CurrentGameInfoDTO, error) {
+	return ps.riotService.GetActiveGame(ctx, player.PUUID, player.Server)
+}
+
+// SetLiveGameID records the last live game seen for a player
+func (ps *PlayerService) SetLiveGameID(ctx context.Context, player *models.Player, gameID int64) error {
+	err := ps.playerRepo.SetLiveGameID(ctx, player.ID, gameID)
+	if err != nil {
+		return err
+	}
+
+	player.LiveGameID = gameID
+	return nil
+}
+
+// HasPlayedSince checks if a player has a stored game started after since
+func (ps *PlayerService) HasPlayedSince(ctx context.Context, player *models.Player, since time.Time) (bool, error) {
+	return ps.matchRepo.HasMatchSince(ctx, player.PUUID, since)
+}
+
+// GetInactivePlayers returns the players without ranked games since a date, least recently active first.
+// The activity is read from the stored games so the Riot API is not called.
+// Players tracked after that date are not considered inactive yet.
+func (ps *PlayerService) GetInactivePlayers(ctx context.Context, since time.Time) ([]*models.InactivePlayer, error) {
+	players, err := ps.playerRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+
+	lastGames, err := ps.matchRepo.LastGameAtByPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var inactive []*models.InactivePlayer
+	for _, player := range players {
+		lastGameAt, played := lastGames[player.PUUID]
+		if played && !lastGameAt.Before(since) {
+			continue
+		}
+		if !played && !player.CreatedAt.Before(since) {
+			continue
+		}
+
+		entry := &models.InactivePlayer{Player: player}
+		if played {
+			entry.LastGameAt = &lastGameAt
+		}
+		inactive = append(inactive, entry)
+	}
+
+	sort.SliceStable(inactive, func(a, b int) bool {
+		return lastActivity(inactive[a]).Before(lastActivity(inactive[b]))
+	})
+	return inactive, nil
+}
+
+// lastActivity returns the last game of an inactive player, or when they were added without games
+func lastActivity(inactive *models.InactivePlayer) time.Time {
+	if inactive.LastGameAt != nil {
+		return *inactive.LastGameAt
+	}
+	return inactive.Player.CreatedAt
+}
+
+// PausePlayer stops polling a player without removing them
+func (ps *PlayerService) PausePlayer(ctx context.Context, player *models.Player) error {
+	err := ps.playerRepo.SetPaused(ctx, player.ID, true)
+	if err != nil {
+		return err
+	}
+	player.Paused = true
+	return nil
+}
+
+// RemovePlayer stops tracking a player. Their stored games and LP events are kept,
+// the statistics come back if the player is added again.
+func (ps *PlayerService) RemovePlayer(ctx context.Context, player *models.Player) error {
+	return ps.playerRepo.Delete(ctx, player.ID)
+}
+
+// GetRoleStats returns the games and win rate of a player per role, most played first
+func (ps *PlayerService) GetRoleStats(ctx context.Context, player *models.Player) ([]*models.RoleStats, error) {
+	return ps.matchRepo.RoleStatsByPlayer(ctx, player.PUUID)
+}
+
+// GetRecentMatches returns the latest stored matches of a player, newest first
+func (ps *PlayerService) GetRecentMatches(ctx context.Context, player *models.Player, limit int) ([]*models.MatchPlayerInfo, error) {
+	return ps.matchRepo.FindRecentByPlayer(ctx, player.PUUID, limit)
+}
+
+// GetMatchHistory returns the latest stored matches of a player with the LP change recorded for each game, newest first
+func (ps *PlayerService) GetMatchHistory(ctx context.Context, player *models.Player, limit int) ([]*models.MatchHistoryEntry, error) {
+	matches, err := ps.matchRepo.FindRecentByPlayer(ctx, player.PUUID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	matchIDs := make([]string, 0, len(matches))
+	for _, match := range matches {
+		matchIDs = append(matchIDs, match.MatchID)
+	}
+	events, err := ps.lpEventRepo.FindByPlayerAndMatches(ctx, player.PUUID, matchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewMatchHistory(matches, events), nil
+}
+
+// GetPatchStats returns the Solo/Duo games and win rate of a player per patch, newest patch first
+func (ps *PlayerService) GetPatchStats(ctx context.Context, player *models.Player) ([]*models.PatchStats, error) {
+	stats, err := ps.matchRepo.PatchStatsByPlayer(ctx, player.PUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(stats, func(a, b int) bool {
+		return models.ComparePatches(stats[a].Patch, stats[b].Patch) > 0
+	})
+	return stats, nil
+}
+
+// GetActivityHeatmap returns when a player started their tracked games over the last HeatmapDays, in a time zone
+func (ps *PlayerService) GetActivityHeatmap(ctx context.Context, player *models.Player, loc *time.Location) (*models.ActivityHeatmap, error) {
+	end := time.Now()
+	start := end.AddDate(0, 0, -models.HeatmapDays)
+
+	matches, err := ps.matchRepo.FindByPlayerBetween(ctx, player.PUUID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewActivityHeatmap(matches, start, end, loc), nil
+}
+
+// GetLPStats returns the average LP gained and lost by a player over their latest tracked games
+func (ps *PlayerService) GetLPStats(ctx context.Context, player *models.Player) (*models.LPStats, error) {
+	events, err := ps.lpEventRepo.FindRecentByPlayer(ctx, player.PUUID, lpStatsSampleSize)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewLPStats(events), nil
+}
+
+// GetLPHistory returns the latest LP events of a player, newest first
+func (ps *PlayerService) GetLPHistory(ctx context.Context, player *models.Player, limit int) ([]*models.LPEvent, error) {
+	return ps.lpEventRepo.FindRecentByPlayer(ctx, player.PUUID, limit)
+}
+
+// GetLPTimeline returns the rank of a player over time since a date, oldest first: the rank before the first change,
+// the rank after each change, and the current rank. Empty when the rank didn't move since the date.
+func (ps *PlayerService) GetLPTimeline(ctx context.Context, player *models.Player, since time.Time) ([]models.LPPoint, error) {
+	events, err := ps.lpEventRepo.FindByPlayerSince(ctx, player.PUUID, since)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	first := events[0]
+	timeline := make([]models.LPPoint, 0, len(events)+2)
+	timeline = append(timeline, models.LPPoint{At: first.CreatedAt, Value: models.RankValue(first.PreviousTier, first.PreviousRank, first.PreviousLeaguePoints)})
+	for _, event := range events {
+		timeline = append(timeline, models.LPPoint{At: event.CreatedAt, Value: models.RankValue(event.Tier, event.Rank, event.LeaguePoints)})
+	}
+	timeline = append(timeline, models.LPPoint{At: time.Now(), Value: models.RankValue(player.Tier, player.Rank, player.LeaguePoints)})
+	return timeline, nil
+}
+
+// ProjectClimb estimates when a player will reach the target rank at their recent pace.
+// It returns nil if no projection can be made.
+func (ps *PlayerService) ProjectClimb(ctx context.Context, player *models.Player, targetTier, targetRank string) (*models.ClimbProjection, error) {
+	stats, err := ps.GetLPStats(ctx, player)
+	if err != nil {
+		return nil, err
+	}
+	return models.ProjectClimb(stats, player, targetTier, targetRank, time.Now()), nil
+}
+
+// UpdatePlayer updates a single player's information and ingests the games played since the last
+// checkpoint. The player (and their checkpoint) is saved last: when the poller stops midway, the
+// next poll detects the same change again.
+// It returns the rank change if the player's rank or LP moved, nil otherwise.
+func (ps *PlayerService) UpdatePlayer(ctx context.Context, player *models.Player) (*models.RankChange, error) {
+	change, _, err := ps.updatePlayer(ctx, player, ps.ingestion, directWriter{ps: ps})
+	return change, err
+}
+
+// updatePlayer updates a player and ingests their new games.
+// It returns the rank change (nil if the rank didn't move) and the new games, newest first.
+func (ps *PlayerService) updatePlayer(ctx context.Context, player *models.Player, opts MatchIngestionOptions, writer pollWriter) (*models.RankChange, []*models.MatchPlayerInfo, error) {
+	ctx, span := tracer.Start(ctx, "PlayerService.updatePlayer", trace.WithAttributes(
+		append(riotIDAttributes(player.GameName, player.TagLine, player.Server), attribute.Int("lol.max_matches", opts.MaxMatches))...))
+	change, matches, err := ps.ingestPlayer(ctx, player, opts, writer)
+	telemetry.End(span, err)
+	return change, matches, err
+}
+
+func (ps *PlayerService) ingestPlayer(ctx context.Context, player *models.Player, opts MatchIngestionOptions, writer pollWriter) (*models.RankChange, []*models.MatchPlayerInfo, error) {
+	change := &models.RankChange{
+		Player:               player,
+		PreviousTier:         player.Tier,
+		PreviousRank:         player.Rank,
+		PreviousLeaguePoints: player.LeaguePoints,
+	}
+
 	// Update player data from Riot API
 	err := ps.riotService.UpdatePlayerRank(ctx, player)
 	if err != nil {
-		return fmt.Errorf("failed to update player rank: %w", err)
+		return nil, nil, fmt.Errorf("failed to update player rank: %w", err)
 	}
 
-	// Save updated player to database
-	err = ps.playerRepo.Update(ctx, player)
+	// Games played since the checkpoint (best effort, notifications work without them)
+	matches, err := ps.ingestNewMatches(ctx, player, opts, writer)
 	if err != nil {
-		return fmt.Errorf("failed to save updated player: %w", err)
+		fmt.Printf("Failed to fetch new matches of %s#%s: %v\n", player.GameName, player.TagLine, err)
 	}
 
-	return nil
+	changed := change.DivisionChanged() || change.PreviousLeaguePoints != player.LeaguePoints
+	if changed {
+		// Attach the latest Solo/Duo game, the one that caused the change
+		for _, match := range matches {
+			if match.IsRankedSolo() {
+				change.Match = match
+				break
+			}
+		}
+		ps.recordLPEvent(ctx, change, writer)
+	}
+
+	// Save the polled fields only, the others may have been edited since the player was read
+	now := time.Now()
+	player.LastPolledAt = &now
+	err = ps.playerRepo.UpdatePolled(ctx, player)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to save updated player: %w", err)
+	}
+
+	if !changed {
+		return nil, matches, nil
+	}
+	return change, matches, nil
+}
+
+// ingestNewMatches stores the games played since the last seen game of the player (at most
+// opts.MaxMatches) and moves the checkpoint. The latest game only is ingested when the player
+// has no checkpoint yet. It returns the stored games, newest first.
+func (ps *PlayerService) ingestNewMatches(ctx context.Context, player *models.Player, opts MatchIngestionOptions, writer pollWriter) ([]*models.MatchPlayerInfo, error) {
+	matchIDs, err := ps.riotService.GetRankedMatchIDs(ctx, player, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var newIDs []string
+	lastMatchID := player.LastMatchID
+	for _, matchID := range matchIDs {
+		if matchID == lastMatchID {
+			break
+		}
+		newIDs = append(newIDs, matchID)
+		if lastMatchID == "" {
+			break
+		}
+	}
+
+	// Oldest first, the checkpoint only moves past stored games
+	var matches []*models.MatchPlayerInfo
+	for idx := len(newIDs) - 1; idx >= 0; idx-- {
+		match, shared, err := ps.riotService.GetRankedMatch(ctx, player, newIDs[idx], opts)
+		if err != nil {
+			return matches, err
+		}
+
+		if match.BannedChampionKey > 0 {
+			match.BannedChampion = ps.dataDragon.ChampionIDByKey(ctx, match.BannedChampionKey)
+		}
+
+		err = ps.sharedMatchRepo.Save(ctx, shared)
+		if err != nil {
+			return matches, err
+		}
+
+		err = writer.saveMatch(ctx, match)
+		if err != nil {
+			return matches, err
+		}
+
+		matches = append([]*models.MatchPlayerInfo{match}, matches...)
+		err = writer.moveCheckpoint(ctx, player, match.MatchID)
+		if err != nil {
+			return matches, err
+		}
+	}
+
+	return matches, nil
+}
+
+// recordLPEvent records the change in the LP ledger used by recaps. A change replayed after a
+// restart reuses the entry recorded for its game.
+func (ps *PlayerService) recordLPEvent(ctx context.Context, change *models.RankChange, writer pollWriter) {
+	player := change.Player
+
+	if change.Match != nil {
+		existing, err := ps.lpEventRepo.FindByPlayerAndMatch(ctx, player.PUUID, change.Match.MatchID)
+		if err != nil {
+			fmt.Printf("Failed to check LP event of %s#%s: %v\n", player.GameName, player.TagLine, err)
+		}
+		if existing != nil {
+			change.EventID = existing.ID
+			return
+		}
+	}
+
+	event := models.NewLPEvent(change)
+	err := writer.createLPEvent(ctx, event)
+	if err != nil {
+		fmt.Printf("Failed to record LP event of %s#%s: %v\n", player.GameName, player.TagLine, err)
+	}
+	change.EventID = event.ID
+}
+
+// PollPlayer updates a single player and ingests their new games outside of the poll schedule
+func (ps *PlayerService) PollPlayer(ctx context.Context, player *models.Player) (*models.CatchUp, error) {
+	change, matches, err := ps.updatePlayer(ctx, player, ps.ingestion, directWriter{ps: ps})
+	if err != nil {
+		return nil, err
+	}
+	return &models.CatchUp{Player: player, Matches: matches, Change: change}, nil
+}
+
+// Backfill ingests the games missed by the players since their checkpoint (at most maxMatches per
+// player), typically after a downtime. Players without a checkpoint have nothing to catch up.
+func (ps *PlayerService) Backfill(ctx context.Context, maxMatches int) ([]*models.CatchUp, error) {
+	players, err := ps.playerRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+
+	writer, flush := ps.cycleWriter()
+	defer func() {
+		err := flush(ctx)
+		if err != nil {
+			fmt.Printf("Failed to save the backfilled games: %v\n", err)
+		}
+	}()
+
+	var catchUps []*models.CatchUp
+	for _, player := range players {
+		if player.LastMatchID == "" || player.Paused {
+			continue
+		}
+
+		opts := ps.ingestion
+		opts.MaxMatches = maxMatches
+		change, matches, err := ps.updatePlayer(ctx, player, opts, writer)
+		if err != nil {
+			fmt.Printf("Failed to backfill player %s#%s: %v\n", player.GameName, player.TagLine, err)
+			continue
+		}
+
+		if len(matches) > 0 || change != nil {
+			catchUps = append(catchUps, &models.CatchUp{Player: player, Matches: matches, Change: change})
+		}
+
+		// Rate limiting: wait between API calls, a shutdown doesn't wait for the remaining players
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	return catchUps, nil
 }
 
-// UpdateAllPlayers updates all tracked players' information
-func (ps *PlayerService) UpdateAllPlayers(ctx context.Context) error {
+// UpdateAllPlayers updates all tracked players' information and returns the detected rank changes
+func (ps *PlayerService) UpdateAllPlayers(ctx context.Context) ([]*models.RankChange, error) {
 	players, err := ps.playerRepo.FindAll(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to fetch players: %w", err)
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
 	}
 
+	writer, flush := ps.cycleWriter()
+
+	var changes []*models.RankChange
 	var errors []string
 	for _, player := range players {
-		err := ps.UpdatePlayer(ctx, player)
+		if player.Paused {
+			continue
+		}
+
+		// The players were read at the start of the cycle, they may have been paused, removed or
+		// refreshed since: the update starts from their current state
+		current, err := ps.playerRepo.FindByPUUID(ctx, player.PUUID)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to reload player %s#%s: %v", player.GameName, player.TagLine, err)
+			errors = append(errors, errorMsg)
+			fmt.Println(errorMsg)
+			continue
+		}
+		if current == nil || current.Paused {
+			continue
+		}
+		player = current
+
+		change, _, err := ps.updatePlayer(ctx, player, ps.ingestion, writer)
 		if err != nil {
 			errorMsg := fmt.Sprintf("Failed to update player %s#%s: %v", player.GameName, player.TagLine, err)
 			errors = append(errors, errorMsg)
@@ -92,13 +715,28 @@ func (ps *PlayerService) UpdateAllPlayers(ctx context.Context) error {
 			continue
 		}
 
-		// Rate limiting: wait between API calls
-		time.Sleep(1 * time.Second)
+		if change != nil {
+			changes = append(changes, change)
+		}
+
+		// Rate limiting: wait between API calls, a shutdown doesn't wait for the remaining players
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	err = flush(ctx)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to save the games and LP changes of the cycle: %v", err)
+		errors = append(errors, errorMsg)
+		fmt.Println(errorMsg)
 	}
 
 	if len(errors) > 0 {
-		return fmt.Errorf("some players failed to update: %v", errors)
+		return changes, fmt.Errorf("some players failed to update: %v", errors)
 	}
 
-	return nil
+	return changes, nil
 }
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+// Content of the public profile pages
+const (
+	profileHistorySize   = 100
+	profileChampionsSize = 10
+	profileMatchesSize   = 20
+)
+
+// ProfileService builds the public profile pages, only the players who opted in are published
+type ProfileService struct {
+	playerRepo  *repositories.PlayerRepository
+	matchRepo   *repositories.MatchRepository
+	lpEventRepo *repositories.LPEventRepository
+	personRepo  *repositories.PersonRepository
+}
+
+func NewProfileService(playerRepo *repositories.PlayerRepository, matchRepo *repositories.MatchRepository, lpEventRepo *repositories.LPEventRepository,
+	personRepo *repositories.PersonRepository) *ProfileService {
+	return &ProfileService{
+		playerRepo:  playerRepo,
+		matchRepo:   matchRepo,
+		lpEventRepo: lpEventRepo,
+		personRepo:  personRepo,
+	}
+}
+
+// SetPublicProfile publishes or hides the public profile page of a tracked player
+func (ps *ProfileService) SetPublicProfile(ctx context.Context, gameName, tagLine, server string, public bool) (*models.Player, error) {
+	player, err := ps.playerRepo.FindByRiotID(ctx, gameName, tagLine, server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if player == nil {
+		return nil, fmt.Errorf("player %s#%s (%s) is not tracked", gameName, tagLine, server)
+	}
+
+	err = ps.playerRepo.SetPublicProfile(ctx, player.ID, public)
+	if err != nil {
+		return nil, err
+	}
+	player.PublicProfile = public
+	return player, nil
+}
+
+// GetPublicProfile returns the public profile of a player, nil if the player is not tracked or did not opt in
+func (ps *ProfileService) GetPublicProfile(ctx context.Context, server, gameName, tagLine string) (*models.PublicProfile, error) {
+	player, err := ps.playerRepo.FindByRiotID(ctx, gameName, tagLine, strings.ToLower(server))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if player == nil || !player.PublicProfile {
+		return nil, nil
+	}
+
+	events, err := ps.lpEventRepo.FindRecentByPlayer(ctx, player.PUUID, profileHistorySize)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]models.LPPoint, 0, len(events))
+	for idx := len(events) - 1; idx >= 0; idx-- {
+		event := events[idx]
+		history = append(history, models.LPPoint{At: event.CreatedAt, Value: models.RankValue(event.Tier, event.Rank, event.LeaguePoints)})
+	}
+
+	champions, err := ps.matchRepo.ChampionStatsByPlayer(ctx, player.PUUID, profileChampionsSize)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := ps.matchRepo.FindRecentByPlayer(ctx, player.PUUID, profileMatchesSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PublicProfile{
+		PlayerDetail: models.NewPlayerDetail(player),
+		LPHistory:    history,
+		Champions:    champions,
+		Matches:      matches,
+	}, nil
+}
+
+// GetPublicPerson returns the public accounts of a person, nil if the person has no public account
+func (ps *ProfileService) GetPublicPerson(ctx context.Context, name string) (*models.PublicPerson, error) {
+	person, err := ps.personRepo.FindByName(ctx, strings.ToLower(name))
+	if err != nil {
+		return nil, err
+	}
+	if person == nil {
+		return nil, nil
+	}
+
+	players, err := ps.playerRepo.FindByPUUIDs(ctx, person.PlayerPUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	sortPlayersByRank(players)
+
+	var accounts []models.PlayerSummary
+	for _, player := range players {
+		if player.PublicProfile {
+			accounts = append(accounts, models.NewPlayerSummary(player))
+		}
+	}
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+
+	return &models.PublicPerson{Name: person.Name, Accounts: accounts}, nil
+}
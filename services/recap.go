@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+type RecapService struct {
+	lpEventRepo    *repositories.LPEventRepository
+	matchRepo      *repositories.MatchRepository
+	personRepo     *repositories.PersonRepository
+	completionRepo *repositories.ChallengeCompletionRepository
+}
+
+func NewRecapService(lpEventRepo *repositories.LPEventRepository, matchRepo *repositories.MatchRepository, personRepo *repositories.PersonRepository, completionRepo *repositories.ChallengeCompletionRepository) *RecapService {
+	return &RecapService{
+		lpEventRepo:    lpEventRepo,
+		matchRepo:      matchRepo,
+		personRepo:     personRepo,
+		completionRepo: completionRepo,
+	}
+}
+
+// BuildRecap aggregates the LP events recorded between start and end per player
+func (rs *RecapService) BuildRecap(ctx context.Context, period models.RecapPeriod, start, end time.Time) (*models.Recap, error) {
+	events, err := rs.lpEventRepo.FindBetween(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch LP events: %w", err)
+	}
+
+	entriesByPUUID := make(map[string]*models.RecapEntry)
+	var entries []*models.RecapEntry
+	for _, event := range events {
+		entry, ok := entriesByPUUID[event.PlayerPUUID]
+		if !ok {
+			entry = &models.RecapEntry{PlayerPUUID: event.PlayerPUUID}
+			entriesByPUUID[event.PlayerPUUID] = entry
+			entries = append(entries, entry)
+		}
+
+		// Events are sorted oldest first, so the last one holds the current rank
+		entry.GameName = event.GameName
+		entry.TagLine = event.TagLine
+		entry.Tier = event.Tier
+		entry.Rank = event.Rank
+		entry.LeaguePoints = event.LeaguePoints
+		entry.LPDelta += event.LPDelta
+		if event.Victory {
+			entry.Wins++
+		} else {
+			entry.Losses++
+		}
+	}
+
+	for _, entry := range entries {
+		err := rs.computeMatchStats(ctx, entry, start, end)
+		if err != nil {
+			fmt.Printf("Failed to compute match stats of %s#%s: %v\n", entry.GameName, entry.TagLine, err)
+		}
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].LPDelta > entries[b].LPDelta
+	})
+
+	recap := &models.Recap{
+		Period:  period,
+		Start:   start,
+		End:     end,
+		Entries: entries,
+	}
+
+	// Challenge levels are synced a few times a day, only weekly recaps list them
+	if period == models.RecapPeriodWeekly {
+		recap.ChallengeCompletions, err = rs.completionRepo.FindBetween(ctx, start, end)
+		if err != nil {
+			fmt.Printf("Failed to fetch challenge completions: %v\n", err)
+		}
+	}
+
+	return recap, nil
+}
+
+// computeMatchStats aggregates the tracked games of the period: role drift (games played away from
+// the player's most played role), vision, objective takedowns and per-champion results
+func (rs *RecapService) computeMatchStats(ctx context.Context, entry *models.RecapEntry, start, end time.Time) error {
+	matches, err := rs.matchRepo.FindByPlayerBetween(ctx, entry.PlayerPUUID, start, end)
+	if err != nil {
+		return err
+	}
+
+	entry.Champions = make(map[string]*models.ChampionStats)
+	for _, match := range matches {
+		champion, ok := entry.Champions[match.Champion]
+		if !ok {
+			champion = &models.ChampionStats{Champion: match.Champion}
+			entry.Champions[match.Champion] = champion
+		}
+		champion.Games++
+		if match.Victory {
+			champion.Wins++
+		}
+
+		entry.MatchGames++
+		entry.VisionScore += match.VisionScore
+		entry.DragonTakedowns += match.DragonTakedowns
+		entry.BaronTakedowns += match.BaronTakedowns
+		entry.HeraldTakedowns += match.HeraldTakedowns
+		entry.TurretTakedowns += match.TurretTakedowns
+	}
+
+	roleStats, err := rs.matchRepo.RoleStatsByPlayer(ctx, entry.PlayerPUUID)
+	if err != nil {
+		return err
+	}
+	if len(roleStats) == 0 {
+		return nil
+	}
+	entry.MainRole = roleStats[0].Role
+
+	for _, match := range matches {
+		if match.Role == "" {
+			continue
+		}
+		entry.RoleGames++
+		if match.Role != entry.MainRole {
+			entry.OffRoleGames++
+		}
+	}
+
+	return nil
+}
+
+// GroupByPerson returns a copy of the recap where the entries of the accounts of a person are merged:
+// LP, games and statistics are summed, the rank is the one of the best account
+func (rs *RecapService) GroupByPerson(ctx context.Context, recap *models.Recap) (*models.Recap, error) {
+	persons, err := rs.personRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	personByPUUID := make(map[string]*models.Person)
+	for _, person := range persons {
+		for _, puuid := range person.PlayerPUUIDs {
+			personByPUUID[puuid] = person
+		}
+	}
+
+	entryByPerson := make(map[*models.Person]*models.RecapEntry)
+	var entries []*models.RecapEntry
+	for _, entry := range recap.Entries {
+		person, linked := personByPUUID[entry.PlayerPUUID]
+		if !linked {
+			entries = append(entries, entry)
+			continue
+		}
+
+		merged, ok := entryByPerson[person]
+		if !ok {
+			copied := *entry
+			copied.PersonName = person.Name
+			copied.Accounts = 1
+			copied.Champions = make(map[string]*models.ChampionStats)
+			mergeChampions(copied.Champions, entry.Champions)
+			entryByPerson[person] = &copied
+			entries = append(entries, &copied)
+			continue
+		}
+		mergeRecapEntry(merged, entry)
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].LPDelta > entries[b].LPDelta
+	})
+
+	grouped := *recap
+	grouped.Entries = entries
+	return &grouped, nil
+}
+
+// mergeRecapEntry adds the activity of another account of the same person to an entry
+func mergeRecapEntry(merged, entry *models.RecapEntry) {
+	merged.Accounts++
+	if models.RankValue(entry.Tier, entry.Rank, entry.LeaguePoints) > models.RankValue(merged.Tier, merged.Rank, merged.LeaguePoints) {
+		merged.PlayerPUUID = entry.PlayerPUUID
+		merged.GameName = entry.GameName
+		merged.TagLine = entry.TagLine
+		merged.Tier = entry.Tier
+		merged.Rank = entry.Rank
+		merged.LeaguePoints = entry.LeaguePoints
+	}
+
+	merged.LPDelta += entry.LPDelta
+	merged.Wins += entry.Wins
+	merged.Losses += entry.Losses
+
+	// The main role of the most tracked account wins, the role games of the others are not comparable
+	if entry.RoleGames > merged.RoleGames {
+		merged.MainRole = entry.MainRole
+		merged.RoleGames = entry.RoleGames
+		merged.OffRoleGames = entry.OffRoleGames
+	}
+
+	merged.MatchGames += entry.MatchGames
+	merged.VisionScore += entry.VisionScore
+	merged.DragonTakedowns += entry.DragonTakedowns
+	merged.BaronTakedowns += entry.BaronTakedowns
+	merged.HeraldTakedowns += entry.HeraldTakedowns
+	merged.TurretTakedowns += entry.TurretTakedowns
+	mergeChampions(merged.Champions, entry.Champions)
+}
+
+func mergeChampions(merged, champions map[string]*models.ChampionStats) {
+	for name, stats := range champions {
+		total, ok := merged[name]
+		if !ok {
+			total = &models.ChampionStats{Champion: name}
+			merged[name] = total
+		}
+		total.Games += stats.Games
+		total.Wins += stats.Wins
+	}
+}
@@ -3,18 +3,50 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"lp_tracker/models"
+	"lp_tracker/telemetry"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = telemetry.Tracer("lp_tracker/services")
+
+// APIError is returned when the Riot API answers with an error status
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsRateLimited checks if the request was rejected by the Riot API rate limits
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
 type RiotService struct {
-	apiKey     string
-	httpClient *http.Client
+	defaultKey   *riotKey
+	regionalKeys map[string]*riotKey // Keyed by routing group
+	httpClient   *http.Client
+	violations   schemaViolations
+	connections  connectionTracker
+	payloads     payloadTracker
+	debug        atomic.Bool
 }
 
 // Riot API response structures
@@ -31,7 +63,7 @@ type SummonerDTO struct {
 	Name          string `json:"name"`
 	ProfileIconID int    `json:"profileIconId"`
 	RevisionDate  int64  `json:"revisionDate"`
-	SummonerLevel int    `json:"summonerLevel"`
+	SummonerLevel int64  `json:"summonerLevel"`
 }
 
 type LeagueEntryDTO struct {
@@ -50,22 +82,221 @@ type LeagueEntryDTO struct {
 	Inactive     bool   `json:"inactive"`
 }
 
-func NewRiotService(apiKey string) *RiotService {
+type MatchDTO struct {
+	Metadata MatchMetadataDTO `json:"metadata"`
+	Info     MatchInfoDTO     `json:"info"`
+}
+
+type MatchMetadataDTO struct {
+	MatchID      string   `json:"matchId"`
+	Participants []string `json:"participants"`
+}
+
+type MatchInfoDTO struct {
+	GameCreation int64            `json:"gameCreation"`
+	GameDuration int64            `json:"gameDuration"`
+	GameVersion  string           `json:"gameVersion"`
+	QueueID      int              `json:"queueId"`
+	Participants []ParticipantDTO `json:"participants"`
+	Teams        []TeamDTO        `json:"teams"`
+}
+
+type TeamDTO struct {
+	TeamID int      `json:"teamId"`
+	Win    bool     `json:"win"`
+	Bans   []BanDTO `json:"bans"`
+}
+
+type BanDTO struct {
+	ChampionID int `json:"championId"` // -1 when no champion was banned
+	PickTurn   int `json:"pickTurn"`
+}
+
+type ParticipantDTO struct {
+	ParticipantID               int    `json:"participantId"` // Matches the pick turn of the participant's ban
+	PUUID                       string `json:"puuid"`
+	RiotIDGameName              string `json:"riotIdGameName"`
+	ChampionName                string `json:"championName"`
+	TeamPosition                string `json:"teamPosition"`
+	TeamID                      int    `json:"teamId"`
+	Kills                       int    `json:"kills"`
+	Deaths                      int    `json:"deaths"`
+	Assists                     int    `json:"assists"`
+	Win                         bool   `json:"win"`
+	TotalDamageDealtToChampions int    `json:"totalDamageDealtToChampions"`
+	TotalMinionsKilled          int    `json:"totalMinionsKilled"`
+	NeutralMinionsKilled        int    `json:"neutralMinionsKilled"`
+	GoldEarned                  int    `json:"goldEarned"`
+	VisionScore                 int    `json:"visionScore"`
+	DoubleKills                 int    `json:"doubleKills"`
+	TripleKills                 int    `json:"tripleKills"`
+	QuadraKills                 int    `json:"quadraKills"`
+	PentaKills                  int    `json:"pentaKills"`
+	LargestMultiKill            int    `json:"largestMultiKill"`
+	KillingSprees               int    `json:"killingSprees"`
+	LargestKillingSpree         int    `json:"largestKillingSpree"`
+	TurretTakedowns             int    `json:"turretTakedowns"`
+	TimeCCingOthers             int    `json:"timeCCingOthers"`
+	LongestTimeSpentLiving      int    `json:"longestTimeSpentLiving"`
+	TimePlayed                  int    `json:"timePlayed"`
+
+	Challenges ChallengesDTO `json:"challenges"`
+}
+
+// ChallengesDTO holds the subset of participant challenges data used by the tracker
+type ChallengesDTO struct {
+	DragonTakedowns     int `json:"dragonTakedowns"`
+	BaronTakedowns      int `json:"baronTakedowns"`
+	RiftHeraldTakedowns int `json:"riftHeraldTakedowns"`
+	HadAfkTeammate      int `json:"hadAfkTeammate"`
+}
+
+// PlatformDataDTO is the LoL-Status-V4 state of a platform
+type PlatformDataDTO struct {
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	Maintenances []StatusDTO `json:"maintenances"`
+	Incidents    []StatusDTO `json:"incidents"`
+}
+
+type StatusDTO struct {
+	ID                int    `json:"id"`
+	MaintenanceStatus string `json:"maintenance_status"`
+	IncidentSeverity  string `json:"incident_severity"`
+}
+
+// ClashPlayerDTO is a Clash-V1 registration of a player
+type ClashPlayerDTO struct {
+	PUUID    string `json:"puuid"`
+	TeamID   string `json:"teamId"`
+	Position string `json:"position"`
+	Role     string `json:"role"`
+}
+
+// ClashTeamDTO is the subset of a Clash-V1 team used by the tracker
+type ClashTeamDTO struct {
+	ID           string `json:"id"`
+	TournamentID int    `json:"tournamentId"`
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+}
+
+// ClashTournamentDTO is a Clash-V1 tournament with its phases
+type ClashTournamentDTO struct {
+	ID               int                       `json:"id"`
+	ThemeID          int                       `json:"themeId"`
+	NameKey          string                    `json:"nameKey"`
+	NameKeySecondary string                    `json:"nameKeySecondary"`
+	Schedule         []ClashTournamentPhaseDTO `json:"schedule"`
+}
+
+// ClashTournamentPhaseDTO is a phase of a Clash tournament, times are in epoch milliseconds
+type ClashTournamentPhaseDTO struct {
+	ID               int   `json:"id"`
+	RegistrationTime int64 `json:"registrationTime"`
+	StartTime        int64 `json:"startTime"`
+	Cancelled        bool  `json:"cancelled"`
+}
+
+// CurrentGameInfoDTO is a game in progress (spectator-v5)
+type CurrentGameInfoDTO struct {
+	GameID            int64                    `json:"gameId"`
+	GameQueueConfigID int                      `json:"gameQueueConfigId"`
+	GameStartTime     int64                    `json:"gameStartTime"` // Unix milliseconds, 0 while loading
+	Participants      []CurrentGameParticipant `json:"participants"`
+}
+
+type CurrentGameParticipant struct {
+	PUUID      string `json:"puuid"`
+	ChampionID int    `json:"championId"`
+	TeamID     int    `json:"teamId"`
+}
+
+// ChallengePlayerDTO is the Challenges-V1 progress of a player
+type ChallengePlayerDTO struct {
+	TotalPoints ChallengePointsDTO            `json:"totalPoints"`
+	Challenges  []ChallengeProgressDTO        `json:"challenges"`
+	Preferences ChallengePreferencesDTO       `json:"preferences"`
+	Categories  map[string]ChallengePointsDTO `json:"categoryPoints"`
+}
+
+// ChallengePointsDTO is the level and the points of a player, overall or in a category
+type ChallengePointsDTO struct {
+	Level      string  `json:"level"`
+	Current    int     `json:"current"`
+	Max        int     `json:"max"`
+	Percentile float64 `json:"percentile"`
+}
+
+// ChallengeProgressDTO is the progress of a player in a challenge, achievedTime is in epoch milliseconds
+type ChallengeProgressDTO struct {
+	ChallengeID  int64   `json:"challengeId"`
+	Level        string  `json:"level"`
+	Value        float64 `json:"value"`
+	Percentile   float64 `json:"percentile"`
+	AchievedTime int64   `json:"achievedTime"`
+}
+
+// ChallengePreferencesDTO holds the title displayed by a player
+type ChallengePreferencesDTO struct {
+	Title string `json:"title"`
+}
+
+// ChallengeConfigDTO is the definition of a challenge, names are keyed by locale (ex: en_US)
+type ChallengeConfigDTO struct {
+	ID             int64                                `json:"id"`
+	LocalizedNames map[string]ChallengeLocalizedNameDTO `json:"localizedNames"`
+	State          string                               `json:"state"`
+	Thresholds     map[string]float64                   `json:"thresholds"`
+}
+
+// ChallengeLocalizedNameDTO is the name of a challenge in a locale
+type ChallengeLocalizedNameDTO struct {
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	ShortDescription string `json:"shortDescription"`
+}
+
+func NewRiotService(apiKey string, config RiotClientConfig) *RiotService {
 	if apiKey == "" {
 		panic("Riot API key is required")
 	}
 
+	regionalKeys := make(map[string]*riotKey, len(config.RegionalAPIKeys))
+	for group, key := range config.RegionalAPIKeys {
+		regionalKeys[group] = newRiotKey(key)
+	}
+
 	return &RiotService{
-		apiKey: apiKey,
+		defaultKey:   newRiotKey(apiKey),
+		regionalKeys: regionalKeys,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   valueOrDefault(config.Timeout, DefaultRiotClientConfig().Timeout),
+			Transport: otelhttp.NewTransport(config.transport(), otelhttp.WithSpanNameFormatter(riotSpanName)),
 		},
 	}
 }
 
+// riotSpanName names the spans of Riot API calls after the endpoint, without the IDs of the path
+// (ex: "GET /lol/match/v5/matches")
+func riotSpanName(_ string, req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(segments) > 4 {
+		segments = segments[:4]
+	}
+	return req.Method + " /" + strings.Join(segments, "/")
+}
+
 func (r *RiotService) GetPlayerByRiotID(ctx context.Context, gameName, tagLine, server string) (*models.Player, error) {
+	ctx, span := tracer.Start(ctx, "RiotService.GetPlayerByRiotID", trace.WithAttributes(riotIDAttributes(gameName, tagLine, server)...))
+	player, err := r.getPlayerByRiotID(ctx, gameName, tagLine, server)
+	telemetry.End(span, err)
+	return player, err
+}
+
+func (r *RiotService) getPlayerByRiotID(ctx context.Context, gameName, tagLine, server string) (*models.Player, error) {
 	// Step 1: Get account by Riot ID
-	account, err := r.getAccountByRiotID(ctx, gameName, tagLine)
+	account, err := r.getAccountByRiotID(ctx, gameName, tagLine, server)
 	if err != nil {
 		return nil, fmt.Errorf("player not found: %w", err)
 	}
@@ -81,6 +312,9 @@ func (r *RiotService) GetPlayerByRiotID(ctx context.Context, gameName, tagLine,
 	var leaguePoints, wins, losses int
 
 	leagueEntries, err := r.getLeagueEntriesBySummonerID(ctx, summoner.ID, server)
+	if errors.Is(err, ErrUnexpectedResponse) {
+		return nil, fmt.Errorf("failed to get league entries: %w", err)
+	}
 	if err != nil {
 		// Continue with default values (unranked)
 		tierStr = "UNRANKED"
@@ -151,17 +385,253 @@ func (r *RiotService) UpdatePlayerRank(ctx context.Context, player *models.Playe
 		player.Losses = 0
 	}
 
+	player.SummonerID = summoner.ID
 	player.SummonerLevel = summoner.SummonerLevel
+	player.ProfileIconID = summoner.ProfileIconID
 	return nil
 }
 
+// GetProfileIconID returns the current profile icon of a summoner, used to verify the ownership of an account
+func (r *RiotService) GetProfileIconID(ctx context.Context, puuid, server string) (int, error) {
+	summoner, err := r.getSummonerByPUUID(ctx, puuid, server)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get summoner: %w", err)
+	}
+	return summoner.ProfileIconID, nil
+}
+
+// GetSoloRankByPUUID returns the Solo/Duo rank of any summoner, UNRANKED if they have none
+func (r *RiotService) GetSoloRankByPUUID(ctx context.Context, puuid, server string) (tier, rank string, leaguePoints int, err error) {
+	entries, err := r.getLeagueEntriesByPUUID(ctx, puuid, server)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to get league entries: %w", err)
+	}
+
+	entry := r.findRankedSoloEntry(entries)
+	if entry == nil {
+		return "UNRANKED", "", 0, nil
+	}
+	return entry.Tier, entry.Rank, entry.LeaguePoints, nil
+}
+
+// GetLatestRankedMatch returns the player's most recent ranked Solo/Duo match, nil if none
+func (r *RiotService) GetLatestRankedMatch(ctx context.Context, player *models.Player) (*models.MatchPlayerInfo, error) {
+	opts := DefaultMatchIngestionOptions()
+	opts.MaxMatches = 1
+
+	matchIDs, err := r.GetRankedMatchIDs(ctx, player, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(matchIDs) == 0 {
+		return nil, nil
+	}
+
+	info, _, err := r.GetRankedMatch(ctx, player, matchIDs[0], opts)
+	return info, err
+}
+
+// GetRankedMatchIDs returns the IDs of the latest games of a player in the ingested queues, newest first
+func (r *RiotService) GetRankedMatchIDs(ctx context.Context, player *models.Player, opts MatchIngestionOptions) ([]string, error) {
+	var matchIDs []string
+	for _, queueID := range opts.Queues {
+		ids, err := r.getMatchIDsByPUUID(ctx, player.PUUID, player.Server, queueID, opts.MaxMatches)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get match IDs: %w", err)
+		}
+		matchIDs = append(matchIDs, ids...)
+	}
+
+	// Merge the queues, match IDs grow with time within a platform (ex: EUW1_7012345678)
+	if len(opts.Queues) > 1 {
+		sort.Slice(matchIDs, func(a, b int) bool {
+			return matchSequence(matchIDs[a]) > matchSequence(matchIDs[b])
+		})
+		if len(matchIDs) > opts.MaxMatches {
+			matchIDs = matchIDs[:opts.MaxMatches]
+		}
+	}
+
+	return matchIDs, nil
+}
+
+// matchSequence returns the numeric part of a match ID
+func matchSequence(matchID string) int64 {
+	_, number, _ := strings.Cut(matchID, "_")
+	sequence, _ := strconv.ParseInt(number, 10, 64)
+	return sequence
+}
+
+// GetRankedMatch fetches a game and extracts the statistics of a player, along with the data
+// shared by all participants
+func (r *RiotService) GetRankedMatch(ctx context.Context, player *models.Player, matchID string, opts MatchIngestionOptions) (*models.MatchPlayerInfo, *models.Match, error) {
+	match, err := r.getMatchByID(ctx, matchID, player.Server)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get match %s: %w", matchID, err)
+	}
+
+	for _, participant := range match.Info.Participants {
+		if participant.PUUID != player.PUUID {
+			continue
+		}
+
+		// Team totals used by the derived metrics
+		var teamKills, teamDamage int
+		for _, teammate := range match.Info.Participants {
+			if teammate.TeamID == participant.TeamID {
+				teamKills += teammate.Kills
+				teamDamage += teammate.TotalDamageDealtToChampions
+			}
+		}
+
+		info := &models.MatchPlayerInfo{
+			PlayerPUUID:         player.PUUID,
+			MatchID:             match.Metadata.MatchID,
+			Pseudo:              player.GameName,
+			Victory:             participant.Win,
+			Rank:                strings.TrimSpace(player.Tier + " " + player.Rank),
+			LeaguePoints:        player.LeaguePoints,
+			QueueType:           queueTypes[match.Info.QueueID],
+			GameVersion:         match.Info.GameVersion,
+			Patch:               models.PatchFromVersion(match.Info.GameVersion),
+			Kills:               participant.Kills,
+			Deaths:              participant.Deaths,
+			Assists:             participant.Assists,
+			Champion:            participant.ChampionName,
+			Role:                participant.TeamPosition,
+			DamageToChamps:      participant.TotalDamageDealtToChampions,
+			CreepScore:          participant.TotalMinionsKilled + participant.NeutralMinionsKilled,
+			GoldEarned:          participant.GoldEarned,
+			VisionScore:         participant.VisionScore,
+			DoubleKills:         participant.DoubleKills,
+			TripleKills:         participant.TripleKills,
+			QuadraKills:         participant.QuadraKills,
+			PentaKills:          participant.PentaKills,
+			LargestMultiKill:    participant.LargestMultiKill,
+			KillingSprees:       participant.KillingSprees,
+			LargestKillingSpree: participant.LargestKillingSpree,
+			DragonTakedowns:     participant.Challenges.DragonTakedowns,
+			BaronTakedowns:      participant.Challenges.BaronTakedowns,
+			HeraldTakedowns:     participant.Challenges.RiftHeraldTakedowns,
+			TurretTakedowns:     participant.TurretTakedowns,
+			GameDuration:        match.Info.GameDuration,
+			CreatedAt:           time.UnixMilli(match.Info.GameCreation),
+			ProcessedAt:         time.Now(),
+		}
+		info.ComputeDerivedMetrics(teamKills, teamDamage)
+		info.SuspectedAFK = isSuspectedAFK(participant, match.Info.GameDuration)
+		info.AFKTeammate = participant.Challenges.HadAfkTeammate > 0
+		info.BannedChampionKey = participantBan(match, participant)
+		info.EnemyChampion = enemyLaner(match, participant)
+		for _, opponent := range match.Info.Participants {
+			if opponent.TeamID != participant.TeamID {
+				info.EnemyPUUIDs = append(info.EnemyPUUIDs, opponent.PUUID)
+			}
+		}
+
+		return info, sharedMatch(match, opts), nil
+	}
+
+	return nil, nil, fmt.Errorf("player %s not found in match %s", player.PUUID, match.Metadata.MatchID)
+}
+
+// participantBan returns the key of the champion banned by a participant, 0 if they didn't ban
+func participantBan(match *MatchDTO, participant ParticipantDTO) int {
+	for _, team := range match.Info.Teams {
+		if team.TeamID != participant.TeamID {
+			continue
+		}
+		for _, ban := range team.Bans {
+			if ban.PickTurn == participant.ParticipantID && ban.ChampionID > 0 {
+				return ban.ChampionID
+			}
+		}
+	}
+	return 0
+}
+
+// enemyLaner returns the champion played by the opponent on the same position, empty if unknown
+func enemyLaner(match *MatchDTO, participant ParticipantDTO) string {
+	if participant.TeamPosition == "" {
+		return ""
+	}
+	for _, opponent := range match.Info.Participants {
+		if opponent.TeamID != participant.TeamID && opponent.TeamPosition == participant.TeamPosition {
+			return opponent.ChampionName
+		}
+	}
+	return ""
+}
+
+// sharedMatch extracts the data of a game shared by all its participants
+func sharedMatch(match *MatchDTO, opts MatchIngestionOptions) *models.Match {
+	shared := &models.Match{
+		MatchID:      match.Metadata.MatchID,
+		QueueID:      match.Info.QueueID,
+		QueueType:    queueTypes[match.Info.QueueID],
+		GameVersion:  match.Info.GameVersion,
+		Patch:        models.PatchFromVersion(match.Info.GameVersion),
+		GameDuration: match.Info.GameDuration,
+		PlayedAt:     time.UnixMilli(match.Info.GameCreation),
+	}
+
+	for _, team := range match.Info.Teams {
+		matchTeam := models.MatchTeam{TeamID: team.TeamID, Victory: team.Win}
+
+		sort.Slice(team.Bans, func(a, b int) bool { return team.Bans[a].PickTurn < team.Bans[b].PickTurn })
+		for _, ban := range team.Bans {
+			if ban.ChampionID > 0 {
+				matchTeam.Bans = append(matchTeam.Bans, ban.ChampionID)
+			}
+		}
+
+		if opts.StoreParticipants {
+			for _, participant := range match.Info.Participants {
+				if participant.TeamID != team.TeamID {
+					continue
+				}
+				matchTeam.Participants = append(matchTeam.Participants, models.MatchParticipant{
+					PUUID:    participant.PUUID,
+					GameName: participant.RiotIDGameName,
+					Champion: participant.ChampionName,
+					Role:     participant.TeamPosition,
+					Victory:  participant.Win,
+					Kills:    participant.Kills,
+					Deaths:   participant.Deaths,
+					Assists:  participant.Assists,
+				})
+			}
+		}
+
+		shared.Teams = append(shared.Teams, matchTeam)
+	}
+
+	return shared
+}
+
+// isSuspectedAFK flags players who barely took part in a game long enough to have played:
+// no kill participation, no crowd control, almost no farm and damage
+func isSuspectedAFK(participant ParticipantDTO, gameDuration int64) bool {
+	if gameDuration < 15*60 {
+		return false
+	}
+
+	minutes := float64(gameDuration) / 60
+	creepScore := participant.TotalMinionsKilled + participant.NeutralMinionsKilled
+	return participant.Kills+participant.Assists == 0 &&
+		participant.TimeCCingOthers == 0 &&
+		float64(creepScore)/minutes < 1 &&
+		participant.TotalDamageDealtToChampions < 1500
+}
+
 // Helper methods for direct API calls
 
-func (r *RiotService) getAccountByRiotID(ctx context.Context, gameName, tagLine string) (*AccountDTO, error) {
+func (r *RiotService) getAccountByRiotID(ctx context.Context, gameName, tagLine, server string) (*AccountDTO, error) {
 	url := fmt.Sprintf("https://europe.api.riotgames.com/riot/account/v1/accounts/by-riot-id/%s/%s", gameName, tagLine)
 
+	// The PUUID is encrypted with the key of the request, it must be the key of the player's server
 	var account AccountDTO
-	err := r.makeAPIRequest(ctx, url, &account)
+	err := r.makeAPIRequest(withKeyOf(ctx, server), url, &account)
 	if err != nil {
 		return nil, err
 	}
@@ -203,32 +673,281 @@ func (r *RiotService) getLeagueEntriesBySummonerID(ctx context.Context, summoner
 	return entries, nil
 }
 
+func (r *RiotService) getLeagueEntriesByPUUID(ctx context.Context, puuid, server string) ([]LeagueEntryDTO, error) {
+	baseURL, err := r.getAPIBaseURL(server)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/lol/league/v4/entries/by-puuid/%s", baseURL, puuid)
+
+	var entries []LeagueEntryDTO
+	err = r.makeAPIRequest(ctx, url, &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *RiotService) getMatchIDsByPUUID(ctx context.Context, puuid, server string, queueID, count int) ([]string, error) {
+	baseURL, err := r.getRegionalBaseURL(server)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/lol/match/v5/matches/by-puuid/%s/ids?queue=%d&start=0&count=%d", baseURL, puuid, queueID, count)
+
+	var matchIDs []string
+	err = r.makeAPIRequest(ctx, url, &matchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return matchIDs, nil
+}
+
+func (r *RiotService) getMatchByID(ctx context.Context, matchID, server string) (*MatchDTO, error) {
+	baseURL, err := r.getRegionalBaseURL(server)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/lol/match/v5/matches/%s", baseURL, matchID)
+
+	var match MatchDTO
+	err = r.makeAPIRequest(ctx, url, &match)
+	if err != nil {
+		return nil, err
+	}
+
+	return &match, nil
+}
+
+// riotIDAttributes describes the player targeted by a span
+func riotIDAttributes(gameName, tagLine, server string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("lol.riot_id", gameName+"#"+tagLine),
+		attribute.String("lol.server", server),
+	}
+}
+
+// GetPlatformStatus fetches the maintenances and incidents of a platform. It is a cheap call
+// (no player data) used to check the API key.
+func (r *RiotService) GetPlatformStatus(ctx context.Context, server string) (*PlatformDataDTO, error) {
+	baseURL, err := r.getAPIBaseURL(server)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/lol/status/v4/platform-data", baseURL)
+
+	var status PlatformDataDTO
+	err = r.makeAPIRequest(ctx, url, &status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// GetClashRegistrations returns the active Clash registrations of a player, empty when not registered
+func (r *RiotService) GetClashRegistrations(ctx context.Context, puuid, server string) ([]ClashPlayerDTO, error) {
+	baseURL, err := r.getAPIBaseURL(server)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/lol/clash/v1/players/by-puuid/%s", baseURL, puuid)
+
+	var registrations []ClashPlayerDTO
+	err = r.makeAPIRequest(ctx, url, &registrations)
+	if err != nil {
+		return nil, err
+	}
+
+	return registrations, nil
+}
+
+// GetClashTeam fetches a Clash team
+func (r *RiotService) GetClashTeam(ctx context.Context, teamID, server string) (*ClashTeamDTO, error) {
+	baseURL, err := r.getAPIBaseURL(server)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/lol/clash/v1/teams/%s", baseURL, teamID)
+
+	var team ClashTeamDTO
+	err = r.makeAPIRequest(ctx, url, &team)
+	if err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
+
+// GetClashTournaments fetches the active and upcoming Clash tournaments of a platform
+func (r *RiotService) GetClashTournaments(ctx context.Context, server string) ([]ClashTournamentDTO, error) {
+	baseURL, err := r.getAPIBaseURL(server)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/lol/clash/v1/tournaments", baseURL)
+
+	var tournaments []ClashTournamentDTO
+	err = r.makeAPIRequest(ctx, url, &tournaments)
+	if err != nil {
+		return nil, err
+	}
+
+	return tournaments, nil
+}
+
+// GetActiveGame returns the game in progress of a player, nil when they are not in game
+func (r *RiotService) GetActiveGame(ctx context.Context, puuid, server string) (*CurrentGameInfoDTO, error) {
+	baseURL, err := r.getAPIBaseURL(server)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/lol/spectator/v5/active-games/by-summoner/%s", baseURL, puuid)
+
+	var game CurrentGameInfoDTO
+	err = r.makeAPIRequest(ctx, url, &game)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &game, nil
+}
+
+// GetChallengeProgress fetches the challenge levels and the points of a player
+func (r *RiotService) GetChallengeProgress(ctx context.Context, puuid, server string) (*ChallengePlayerDTO, error) {
+	baseURL, err := r.getAPIBaseURL(server)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/lol/challenges/v1/player-data/%s", baseURL, puuid)
+
+	var progress ChallengePlayerDTO
+	err = r.makeAPIRequest(ctx, url, &progress)
+	if err != nil {
+		return nil, err
+	}
+
+	return &progress, nil
+}
+
+// GetChallengeConfigs fetches the definitions of the challenges of a platform
+func (r *RiotService) GetChallengeConfigs(ctx context.Context, server string) ([]ChallengeConfigDTO, error) {
+	baseURL, err := r.getAPIBaseURL(server)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/lol/challenges/v1/challenges/config", baseURL)
+
+	var configs []ChallengeConfigDTO
+	err = r.makeAPIRequest(ctx, url, &configs)
+	if err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
 func (r *RiotService) makeAPIRequest(ctx context.Context, url string, target interface{}) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	req, err := http.NewRequestWithContext(r.connections.trace(ctx), "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("X-Riot-Token", r.apiKey)
+	key := r.keyFor(ctx, req.URL)
+	if until, blocked := key.rateLimits.blocked(time.Now()); blocked {
+		return errRateLimited(until)
+	}
+
+	req.Header.Set("X-Riot-Token", key.get())
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
 
+	start := time.Now()
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
+		if r.Debug() {
+			fmt.Printf("🐞 Riot %s %s -> %v\n", req.Method, sanitizeURL(req.URL), err)
+		}
 		return err
 	}
 	defer resp.Body.Close()
+	key.rateLimits.record(resp)
+
+	encoding := resp.Header.Get("Content-Encoding")
+	wire := &countingReader{reader: resp.Body}
+	body, err := decompress(encoding, wire)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	decoded := &countingReader{reader: body}
+	defer func() {
+		r.payloads.record(riotSpanName("", req), wire.count, decoded.count, encoding != "")
+	}()
+
+	if r.Debug() {
+		capture := &debugCapture{}
+		decoded.reader = io.TeeReader(body, capture)
+		defer func() {
+			logDebug(req, resp, time.Since(start), capture)
+		}()
+	}
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return &APIError{StatusCode: resp.StatusCode, Body: readErrorBody(decoded)}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	// Decoding from the stream avoids holding the whole payload (large for matches) in memory
+	err = json.NewDecoder(decoded).Decode(target)
 	if err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+			return r.schemaViolation(target, fmt.Errorf("%w: %v", ErrUnexpectedResponse, err))
+		}
 		return err
 	}
 
-	return json.Unmarshal(body, target)
+	// Drain the rest of the body so the connection goes back to the idle pool
+	_, err = io.Copy(io.Discard, decoded)
+	if err != nil {
+		return err
+	}
+
+	err = validateResponse(target)
+	if err != nil {
+		return r.schemaViolation(target, err)
+	}
+	return nil
+}
+
+// schemaViolation counts and logs a response that doesn't have the expected shape
+func (r *RiotService) schemaViolation(target interface{}, err error) error {
+	responseType := fmt.Sprintf("%T", target)
+	count := r.violations.record(responseType)
+	fmt.Printf("⚠️ Riot API response shape changed (%s, %d times so far): %v\n", responseType, count, err)
+	return err
+}
+
+// SchemaViolations returns the number of responses rejected by the schema guard, per response type
+func (r *RiotService) SchemaViolations() map[string]int64 {
+	return r.violations.snapshot()
 }
 
 func (r *RiotService) getAPIBaseURL(server string) (string, error) {
@@ -262,6 +981,24 @@ func (r *RiotService) getAPIBaseURL(server string) (string, error) {
 	}
 }
 
+// getRegionalBaseURL returns the regional routing value used by Match-V5 for a platform
+func (r *RiotService) getRegionalBaseURL(server string) (string, error) {
+	server = strings.ToLower(server)
+
+	switch server {
+	case "euw1", "euw", "eun1", "eune", "tr1", "tr", "ru":
+		return "https://europe.api.riotgames.com", nil
+	case "na1", "na", "br1", "br", "la1", "lan", "la2", "las":
+		return "https://americas.api.riotgames.com", nil
+	case "kr", "jp1", "jp":
+		return "https://asia.api.riotgames.com", nil
+	case "oc1", "oce":
+		return "https://sea.api.riotgames.com", nil
+	default:
+		return "", fmt.Errorf("unsupported server: %s", server)
+	}
+}
+
 func (r *RiotService) findRankedSoloEntry(entries []LeagueEntryDTO) *LeagueEntryDTO {
 	for _, entry := range entries {
 		if entry.QueueType == "RANKED_SOLO_5x5" {
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync/atomic"
+)
+
+// ConnectionStats counts how the Riot API requests of this process got their connection. A low
+// reuse ratio means the idle pool is too small for the roster: every new connection costs a TCP
+// and TLS handshake, and a local port left in TIME_WAIT once closed.
+type ConnectionStats struct {
+	Connections   int64 // Requests that obtained a connection
+	Reused        int64 // Connections taken from the idle pool or shared (HTTP/2)
+	New           int64 // Connections dialed for the request
+	TLSHandshakes int64
+	HTTP2         int64 // Connections negotiated as HTTP/2, shared by concurrent requests
+}
+
+// ReuseRatio returns the share of requests served on a reused connection, in percent
+func (s ConnectionStats) ReuseRatio() float64 {
+	if s.Connections == 0 {
+		return 0
+	}
+	return float64(s.Reused) / float64(s.Connections) * 100
+}
+
+type connectionTracker struct {
+	connections   atomic.Int64
+	reused        atomic.Int64
+	tlsHandshakes atomic.Int64
+	http2         atomic.Int64
+}
+
+// trace attaches the connection hooks to the context of a request
+func (t *connectionTracker) trace(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.connections.Add(1)
+			if info.Reused {
+				t.reused.Add(1)
+			}
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err == nil {
+				t.tlsHandshakes.Add(1)
+			}
+			if state.NegotiatedProtocol == "h2" {
+				t.http2.Add(1)
+			}
+		},
+	})
+}
+
+func (t *connectionTracker) snapshot() ConnectionStats {
+	connections, reused := t.connections.Load(), t.reused.Load()
+	return ConnectionStats{
+		Connections:   connections,
+		Reused:        reused,
+		New:           connections - reused,
+		TLSHandshakes: t.tlsHandshakes.Load(),
+		HTTP2:         t.http2.Load(),
+	}
+}
+
+// ConnectionStats returns the connection reuse counters of the Riot API client of this process
+func (r *RiotService) ConnectionStats() ConnectionStats {
+	return r.connections.snapshot()
+}
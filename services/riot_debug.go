@@ -0,0 +1,69 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Bytes of the response bodies written in the debug logs
+const debugBodySize = 512
+
+// SetDebug enables or disables the logging of every Riot API call (URL, status, rate limit headers
+// and the beginning of the body), for troubleshooting failed lookups. The API key is never logged.
+func (r *RiotService) SetDebug(enabled bool) {
+	r.debug.Store(enabled)
+	if enabled {
+		fmt.Println("🐞 Riot API debug logging enabled")
+	} else {
+		fmt.Println("🐞 Riot API debug logging disabled")
+	}
+}
+
+// Debug checks if the Riot API calls are logged
+func (r *RiotService) Debug() bool {
+	return r.debug.Load()
+}
+
+// debugCapture keeps the beginning of a response body for the debug logs
+type debugCapture struct {
+	data []byte
+}
+
+func (c *debugCapture) Write(p []byte) (int, error) {
+	if room := debugBodySize - len(c.data); room > 0 {
+		c.data = append(c.data, p[:min(room, len(p))]...)
+	}
+	return len(p), nil
+}
+
+func (c *debugCapture) String() string {
+	// The capture may end in the middle of a character
+	body := strings.ToValidUTF8(string(c.data), "")
+	if len(c.data) == debugBodySize {
+		body += "…"
+	}
+	return body
+}
+
+// logDebug writes a Riot API call to the logs
+func logDebug(req *http.Request, resp *http.Response, elapsed time.Duration, body *debugCapture) {
+	fmt.Printf("🐞 Riot %s %s -> %d (%v) app=%s/%s method=%s/%s retry-after=%s body=%q\n",
+		req.Method, sanitizeURL(req.URL), resp.StatusCode, elapsed.Round(time.Millisecond),
+		resp.Header.Get("X-App-Rate-Limit-Count"), resp.Header.Get("X-App-Rate-Limit"),
+		resp.Header.Get("X-Method-Rate-Limit-Count"), resp.Header.Get("X-Method-Rate-Limit"),
+		resp.Header.Get("Retry-After"), body.String())
+}
+
+// sanitizeURL drops the api_key query parameter, in case a key is ever passed in the URL instead of the header
+func sanitizeURL(u *url.URL) string {
+	sanitized := *u
+	query := sanitized.Query()
+	if query.Has("api_key") {
+		query.Set("api_key", "REDACTED")
+		sanitized.RawQuery = query.Encode()
+	}
+	return sanitized.String()
+}
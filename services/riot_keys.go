@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Regional routing groups of the Riot API, a platform (ex: euw1) belongs to one of them
+var routingGroups = []string{"americas", "asia", "europe", "sea"}
+
+// defaultKeyGroup names the RIOT_API_KEY in the rate limit states
+const defaultKeyGroup = "default"
+
+// riotKey is an API key with its own rate limit state. The value can be rotated at runtime.
+type riotKey struct {
+	value      atomic.Pointer[string]
+	rateLimits rateLimitTracker
+}
+
+func newRiotKey(value string) *riotKey {
+	key := &riotKey{}
+	key.value.Store(&value)
+	return key
+}
+
+func (k *riotKey) get() string {
+	return *k.value.Load()
+}
+
+// SetAPIKey replaces RIOT_API_KEY without a restart (ex: a key rotated in Vault). The requests
+// already sent finish with the previous key.
+func (r *RiotService) SetAPIKey(apiKey string) {
+	if apiKey == "" || apiKey == r.defaultKey.get() {
+		return
+	}
+	r.defaultKey.value.Store(&apiKey)
+	fmt.Println("🔑 Riot API key replaced")
+}
+
+// parseRegionalKeys reads the per routing group keys (ex: "europe=RGAPI-1,americas=RGAPI-2")
+func parseRegionalKeys(value string) (map[string]string, error) {
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		group, key, ok := strings.Cut(entry, "=")
+		group = strings.ToLower(strings.TrimSpace(group))
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%q is not a group=key pair", entry)
+		}
+		if !isRoutingGroup(group) {
+			return nil, fmt.Errorf("unknown routing group %q (%s)", group, strings.Join(routingGroups, ", "))
+		}
+		keys[group] = key
+	}
+	return keys, nil
+}
+
+func isRoutingGroup(group string) bool {
+	for _, known := range routingGroups {
+		if known == group {
+			return true
+		}
+	}
+	return false
+}
+
+// routingGroup returns the routing group of a Riot API URL, from its platform or regional host
+// (ex: https://euw1.api.riotgames.com -> europe)
+func (r *RiotService) routingGroup(u *url.URL) string {
+	host, _, _ := strings.Cut(u.Hostname(), ".")
+	if isRoutingGroup(host) {
+		return host
+	}
+
+	regionalURL, err := r.getRegionalBaseURL(host)
+	if err != nil {
+		return ""
+	}
+	group, _, _ := strings.Cut(strings.TrimPrefix(regionalURL, "https://"), ".")
+	return group
+}
+
+// keyServerContextKey carries the server whose key signs a request sent to another host
+type keyServerContextKey struct{}
+
+// withKeyOf makes the requests of the context use the key of a server. Encrypted IDs (PUUIDs,
+// summoner IDs) are specific to a key, calls returning IDs of a player must use the key of their server.
+func withKeyOf(ctx context.Context, server string) context.Context {
+	return context.WithValue(ctx, keyServerContextKey{}, server)
+}
+
+// keyFor selects the API key of a request, RIOT_API_KEY when its group has no dedicated key
+func (r *RiotService) keyFor(ctx context.Context, u *url.URL) *riotKey {
+	group := r.routingGroup(u)
+	if server, ok := ctx.Value(keyServerContextKey{}).(string); ok {
+		group = r.routingGroup(&url.URL{Host: server})
+	}
+
+	if key, ok := r.regionalKeys[group]; ok {
+		return key
+	}
+	return r.defaultKey
+}
+
+// RateLimitStates returns the Riot API usage of this process per API key, keyed by routing group
+// ("default" for RIOT_API_KEY)
+func (r *RiotService) RateLimitStates() map[string]RateLimitState {
+	states := map[string]RateLimitState{defaultKeyGroup: r.defaultKey.rateLimits.snapshot()}
+	for group, key := range r.regionalKeys {
+		states[group] = key.rateLimits.snapshot()
+	}
+	return states
+}
+
+// KeyGroups returns the routing groups with a dedicated API key, sorted
+func (r *RiotService) KeyGroups() []string {
+	groups := make([]string, 0, len(r.regionalKeys))
+	for group := range r.regionalKeys {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// errRateLimited is returned without calling the Riot API while a key waits for its Retry-After
+func errRateLimited(until time.Time) error {
+	return &APIError{
+		StatusCode: 429,
+		Body:       fmt.Sprintf("rate limited, requests paused until %s", until.Format(time.RFC3339)),
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PayloadStats is the volume of the Riot API responses of an endpoint seen by this process
+type PayloadStats struct {
+	Endpoint     string // ex: "GET /lol/match/v5/matches"
+	Responses    int64
+	Compressed   int64 // Responses sent gzip or deflate encoded
+	WireBytes    int64 // Bytes read from the network
+	DecodedBytes int64 // Bytes after decompression
+}
+
+// CompressionRatio returns the decoded size over the size on the wire (ex: 5 for a 5x smaller transfer)
+func (s PayloadStats) CompressionRatio() float64 {
+	if s.WireBytes == 0 {
+		return 0
+	}
+	return float64(s.DecodedBytes) / float64(s.WireBytes)
+}
+
+type payloadTracker struct {
+	mu    sync.Mutex
+	stats map[string]*PayloadStats
+}
+
+func (t *payloadTracker) record(endpoint string, wireBytes, decodedBytes int64, compressed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stats == nil {
+		t.stats = make(map[string]*PayloadStats)
+	}
+	stats, ok := t.stats[endpoint]
+	if !ok {
+		stats = &PayloadStats{Endpoint: endpoint}
+		t.stats[endpoint] = stats
+	}
+
+	stats.Responses++
+	stats.WireBytes += wireBytes
+	stats.DecodedBytes += decodedBytes
+	if compressed {
+		stats.Compressed++
+	}
+}
+
+// snapshot returns the stats of every endpoint, largest decoded volume first
+func (t *payloadTracker) snapshot() []PayloadStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]PayloadStats, 0, len(t.stats))
+	for _, endpoint := range t.stats {
+		stats = append(stats, *endpoint)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].DecodedBytes > stats[j].DecodedBytes
+	})
+	return stats
+}
+
+// PayloadStats returns the response sizes per Riot API endpoint of this process
+func (r *RiotService) PayloadStats() []PayloadStats {
+	return r.payloads.snapshot()
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// decompress wraps a response body according to its Content-Encoding. Setting Accept-Encoding
+// ourselves disables the transparent gzip support of net/http, so both encodings are handled here.
+func decompress(encoding string, body io.Reader) (io.ReadCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return io.NopCloser(body), nil
+	case "gzip":
+		return newPooledGzipReader(body)
+	case "deflate":
+		return zlib.NewReader(body)
+	default:
+		return nil, fmt.Errorf("unsupported response encoding %q", encoding)
+	}
+}
+
+// gzipReaders recycles the gzip readers (and their 32 KiB window) across responses, a poll cycle
+// decodes several requests per player
+var gzipReaders sync.Pool
+
+// pooledGzipReader returns its gzip reader to the pool when closed
+type pooledGzipReader struct {
+	*gzip.Reader
+}
+
+func newPooledGzipReader(body io.Reader) (io.ReadCloser, error) {
+	reader, ok := gzipReaders.Get().(*gzip.Reader)
+	if !ok {
+		reader, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		return &pooledGzipReader{reader}, nil
+	}
+
+	err := reader.Reset(body)
+	if err != nil {
+		gzipReaders.Put(reader)
+		return nil, err
+	}
+	return &pooledGzipReader{reader}, nil
+}
+
+func (p *pooledGzipReader) Close() error {
+	err := p.Reader.Close()
+	gzipReaders.Put(p.Reader)
+	return err
+}
+
+// Error bodies are only kept for the error message, larger ones are cut
+const maxErrorBodySize = 64 * 1024
+
+// errorBodies recycles the buffers the error responses are read into
+var errorBodies = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// readErrorBody reads the beginning of an error response with a pooled buffer
+func readErrorBody(body io.Reader) string {
+	buffer := errorBodies.Get().(*bytes.Buffer)
+	defer func() {
+		buffer.Reset()
+		errorBodies.Put(buffer)
+	}()
+
+	_, _ = buffer.ReadFrom(io.LimitReader(body, maxErrorBodySize))
+	return buffer.String()
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// riotFixture serves the same payload to every request, encoded like the Riot API does
+type riotFixture struct {
+	status   int
+	encoding string
+	body     []byte
+}
+
+func (f *riotFixture) RoundTrip(req *http.Request) (*http.Response, error) {
+	header := http.Header{"Content-Type": []string{"application/json;charset=utf-8"}}
+	if f.encoding != "" {
+		header.Set("Content-Encoding", f.encoding)
+	}
+	return &http.Response{
+		StatusCode: f.status,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(f.body)),
+		Request:    req,
+	}, nil
+}
+
+// matchPayload returns a Match-V5 response of 10 participants. Riot sends ~150 fields per
+// participant, the ones the tracker doesn't decode are padded to a realistic size (~100 KiB).
+func matchPayload(tb testing.TB) []byte {
+	tb.Helper()
+
+	participants := make([]map[string]any, 0, 10)
+	for idx := range 10 {
+		participant := map[string]any{
+			"participantId":  idx + 1,
+			"puuid":          fmt.Sprintf("puuid-%02d-%s", idx, strings.Repeat("x", 66)),
+			"riotIdGameName": fmt.Sprintf("Player%d", idx),
+			"championName":   "Ahri",
+			"teamPosition":   "MIDDLE",
+			"teamId":         100 + 100*(idx/5),
+			"kills":          idx,
+			"deaths":         3,
+			"assists":        7,
+			"win":            idx < 5,
+			"challenges":     map[string]any{"dragonTakedowns": 2, "baronTakedowns": 1},
+		}
+		for field := range 140 {
+			participant[fmt.Sprintf("unusedStat%03d", field)] = field * 1234
+		}
+		participant["perks"] = strings.Repeat(`{"perk": 8112, "var1": 1234}`, 100)
+		participants = append(participants, participant)
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"matchId": "EUW1_1234567890", "participants": []string{}},
+		"info": map[string]any{
+			"gameCreation": 1710000000000,
+			"gameDuration": 1800,
+			"gameVersion":  "14.5.567.1234",
+			"queueId":      420,
+			"participants": participants,
+			"teams":        []map[string]any{{"teamId": 100, "win": true}, {"teamId": 200, "win": false}},
+		},
+	})
+	if err != nil {
+		tb.Fatalf("failed to encode the match: %v", err)
+	}
+	return payload
+}
+
+func encodePayload(tb testing.TB, encoding string, payload []byte) []byte {
+	tb.Helper()
+
+	var buffer bytes.Buffer
+	var writer io.WriteCloser
+	switch encoding {
+	case "":
+		return payload
+	case "gzip":
+		writer = gzip.NewWriter(&buffer)
+	case "deflate":
+		writer = zlib.NewWriter(&buffer)
+	}
+	if _, err := writer.Write(payload); err != nil {
+		tb.Fatalf("failed to encode the payload: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		tb.Fatalf("failed to encode the payload: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func newFixtureRiotService(fixture *riotFixture) *RiotService {
+	r := NewRiotService("test", DefaultRiotClientConfig())
+	r.httpClient = &http.Client{Transport: fixture}
+	return r
+}
+
+const benchmarkMatchURL = "https://europe.api.riotgames.com/lol/match/v5/matches/EUW1_1234567890"
+
+func TestMakeAPIRequestDecodesEncodings(t *testing.T) {
+	payload := matchPayload(t)
+	for _, encoding := range []string{"", "gzip", "deflate"} {
+		r := newFixtureRiotService(&riotFixture{status: http.StatusOK, encoding: encoding, body: encodePayload(t, encoding, payload)})
+
+		// Twice, the second gzip response reuses the pooled reader
+		for range 2 {
+			var match MatchDTO
+			if err := r.makeAPIRequest(context.Background(), benchmarkMatchURL, &match); err != nil {
+				t.Fatalf("encoding %q: %v", encoding, err)
+			}
+			if len(match.Info.Participants) != 10 || match.Info.Participants[9].Kills != 9 {
+				t.Fatalf("encoding %q: decoded %d participants, want the 10 of the payload", encoding, len(match.Info.Participants))
+			}
+		}
+
+		stats := r.PayloadStats()
+		if len(stats) != 1 || stats[0].DecodedBytes != 2*int64(len(payload)) {
+			t.Errorf("encoding %q: payload stats %+v, want %d decoded bytes", encoding, stats, 2*len(payload))
+		}
+	}
+}
+
+func BenchmarkMakeAPIRequest(b *testing.B) {
+	payload := matchPayload(b)
+	for _, encoding := range []string{"identity", "gzip", "deflate"} {
+		b.Run(encoding, func(b *testing.B) {
+			if encoding == "identity" {
+				encoding = ""
+			}
+			r := newFixtureRiotService(&riotFixture{status: http.StatusOK, encoding: encoding, body: encodePayload(b, encoding, payload)})
+			ctx := context.Background()
+
+			b.SetBytes(int64(len(payload)))
+			b.ReportAllocs()
+			for b.Loop() {
+				var match MatchDTO
+				if err := r.makeAPIRequest(ctx, benchmarkMatchURL, &match); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDecodeMatch compares the streaming decoder of makeAPIRequest with reading the whole body first
+func BenchmarkDecodeMatch(b *testing.B) {
+	payload := matchPayload(b)
+
+	b.Run("stream", func(b *testing.B) {
+		b.SetBytes(int64(len(payload)))
+		b.ReportAllocs()
+		for b.Loop() {
+			var match MatchDTO
+			if err := json.NewDecoder(bytes.NewReader(payload)).Decode(&match); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("read_all", func(b *testing.B) {
+		b.SetBytes(int64(len(payload)))
+		b.ReportAllocs()
+		for b.Loop() {
+			body, err := io.ReadAll(bytes.NewReader(payload))
+			if err != nil {
+				b.Fatal(err)
+			}
+			var match MatchDTO
+			if err := json.Unmarshal(body, &match); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkGzipReader compares the pooled gzip readers with a new reader (and window) per response
+func BenchmarkGzipReader(b *testing.B) {
+	body := encodePayload(b, "gzip", matchPayload(b))
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			reader, err := decompress("gzip", bytes.NewReader(body))
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := io.Copy(io.Discard, reader); err != nil {
+				b.Fatal(err)
+			}
+			reader.Close()
+		}
+	})
+
+	b.Run("unpooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			reader, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := io.Copy(io.Discard, reader); err != nil {
+				b.Fatal(err)
+			}
+			reader.Close()
+		}
+	})
+}
+
+func BenchmarkReadErrorBody(b *testing.B) {
+	body := []byte(`{"status": {"message": "Rate limit exceeded", "status_code": 429}}` + strings.Repeat(" ", 4096))
+
+	b.ReportAllocs()
+	for b.Loop() {
+		readErrorBody(bytes.NewReader(body))
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitState is the Riot API usage seen by this process, from the rate limit headers of the
+// latest response (ex: "20:1,100:120" limits and their current counts)
+type RateLimitState struct {
+	Requests          int64
+	RateLimited       int64 // Responses with a 429 status
+	LastRateLimitedAt time.Time
+	RetryAfter        string // Retry-After of the latest 429
+	AppLimit          string
+	AppCount          string
+	MethodLimit       string
+	MethodCount       string
+	UpdatedAt         time.Time
+}
+
+// rateLimitTracker follows the usage of an API key. After a 429, the requests of the key are
+// rejected locally until the Retry-After delay is over, so they don't extend the penalty.
+type rateLimitTracker struct {
+	mu           sync.Mutex
+	state        RateLimitState
+	blockedUntil time.Time
+}
+
+// record reads the rate limit headers of a Riot API response
+func (t *rateLimitTracker) record(resp *http.Response) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.state.Requests++
+	t.state.UpdatedAt = time.Now()
+	if limit := resp.Header.Get("X-App-Rate-Limit"); limit != "" {
+		t.state.AppLimit = limit
+		t.state.AppCount = resp.Header.Get("X-App-Rate-Limit-Count")
+	}
+	if limit := resp.Header.Get("X-Method-Rate-Limit"); limit != "" {
+		t.state.MethodLimit = limit
+		t.state.MethodCount = resp.Header.Get("X-Method-Rate-Limit-Count")
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		t.state.RateLimited++
+		t.state.LastRateLimitedAt = t.state.UpdatedAt
+		t.state.RetryAfter = resp.Header.Get("Retry-After")
+
+		// Riot sends Retry-After in seconds, 1s is assumed when it is missing
+		delay := time.Second
+		if seconds, err := strconv.Atoi(t.state.RetryAfter); err == nil && seconds > 0 {
+			delay = time.Duration(seconds) * time.Second
+		}
+		t.blockedUntil = t.state.UpdatedAt.Add(delay)
+	}
+}
+
+// blocked returns the end of the Retry-After delay when the key is rate limited
+func (t *rateLimitTracker) blocked(now time.Time) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.blockedUntil, now.Before(t.blockedUntil)
+}
+
+func (t *rateLimitTracker) snapshot() RateLimitState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.state
+}
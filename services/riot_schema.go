@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrUnexpectedResponse is returned when a Riot API response lacks fields the tracker relies on,
+// usually because Riot changed the shape of an endpoint. Failing loudly avoids saving zero-valued
+// players. Unknown fields are ignored.
+var ErrUnexpectedResponse = errors.New("unexpected Riot API response")
+
+// schemaValidator is implemented by the DTOs with required fields
+type schemaValidator interface {
+	validate() error
+}
+
+func (a *AccountDTO) validate() error {
+	if a.PUUID == "" {
+		return missingField("puuid")
+	}
+	return nil
+}
+
+func (s *SummonerDTO) validate() error {
+	if s.PUUID == "" {
+		return missingField("puuid")
+	}
+	return nil
+}
+
+func (e *LeagueEntryDTO) validate() error {
+	switch {
+	case e.QueueType == "":
+		return missingField("queueType")
+	case e.Tier == "":
+		return missingField("tier")
+	}
+	return nil
+}
+
+func (m *MatchDTO) validate() error {
+	switch {
+	case m.Metadata.MatchID == "":
+		return missingField("metadata.matchId")
+	case len(m.Info.Participants) == 0:
+		return missingField("info.participants")
+	case m.Info.GameCreation == 0:
+		return missingField("info.gameCreation")
+	}
+
+	for idx, participant := range m.Info.Participants {
+		if participant.PUUID == "" {
+			return missingField(fmt.Sprintf("info.participants[%d].puuid", idx))
+		}
+	}
+	return nil
+}
+
+func (t *ClashTeamDTO) validate() error {
+	if t.ID == "" {
+		return missingField("id")
+	}
+	return nil
+}
+
+func (t *ClashTournamentDTO) validate() error {
+	if t.ID == 0 {
+		return missingField("id")
+	}
+	return nil
+}
+
+func (c *ChallengePlayerDTO) validate() error {
+	if c.TotalPoints.Level == "" {
+		return missingField("totalPoints.level")
+	}
+	return nil
+}
+
+func (c *ChallengeConfigDTO) validate() error {
+	if c.ID == 0 {
+		return missingField("id")
+	}
+	return nil
+}
+
+func (g *CurrentGameInfoDTO) validate() error {
+	if g.GameID == 0 {
+		return missingField("gameId")
+	}
+	return nil
+}
+
+func missingField(name string) error {
+	return fmt.Errorf("%w: missing %s", ErrUnexpectedResponse, name)
+}
+
+// validateResponse checks the required fields of a decoded response
+func validateResponse(target interface{}) error {
+	switch response := target.(type) {
+	case schemaValidator:
+		return response.validate()
+	case *[]LeagueEntryDTO:
+		for idx := range *response {
+			if err := (*response)[idx].validate(); err != nil {
+				return err
+			}
+		}
+	case *[]ClashTournamentDTO:
+		for idx := range *response {
+			if err := (*response)[idx].validate(); err != nil {
+				return err
+			}
+		}
+	case *[]ChallengeConfigDTO:
+		for idx := range *response {
+			if err := (*response)[idx].validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// schemaViolations counts the responses rejected by the schema guard, per response type
+type schemaViolations struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func (v *schemaViolations) record(responseType string) int64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.counts == nil {
+		v.counts = make(map[string]int64)
+	}
+	v.counts[responseType]++
+	return v.counts[responseType]
+}
+
+func (v *schemaViolations) snapshot() map[string]int64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	counts := make(map[string]int64, len(v.counts))
+	for responseType, count := range v.counts {
+		counts[responseType] = count
+	}
+	return counts
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RiotClientConfig holds the HTTP settings of the Riot API client, for self-hosters routing the
+// traffic through a proxy or tuning the connections. The zero values fall back to the defaults.
+type RiotClientConfig struct {
+	ProxyURL            *url.URL      // http://, https:// or socks5:// proxy, HTTPS_PROXY is used when nil
+	Timeout             time.Duration // Whole request, including reading the body
+	DialTimeout         time.Duration
+	KeepAlive           time.Duration // TCP keep-alive probes interval
+	TLSHandshakeTimeout time.Duration
+	MaxIdleConns        int           // Idle connections kept open across all hosts
+	MaxIdleConnsPerHost int           // Idle connections kept open per Riot host (platform or regional route)
+	IdleConnTimeout     time.Duration // Idle connections are closed after this delay
+
+	// Routing group (americas, asia, europe, sea) -> API key, for operators holding several keys.
+	// Each key has its own rate limit state, RIOT_API_KEY is used for the other groups.
+	RegionalAPIKeys map[string]string
+}
+
+// DefaultRiotClientConfig returns the settings used when nothing is configured
+func DefaultRiotClientConfig() RiotClientConfig {
+	return RiotClientConfig{
+		Timeout:             30 * time.Second,
+		DialTimeout:         30 * time.Second,
+		KeepAlive:           30 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConns:        100,
+		// Go keeps 2 idle connections per host by default, the poller and the enemy rank
+		// lookups need more to avoid dialing (and negotiating TLS) for most requests
+		MaxIdleConnsPerHost: 32,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// RiotClientConfigFromEnv reads the client settings from the RIOT_HTTP_* and RIOT_API_KEYS environment variables
+func RiotClientConfigFromEnv() (RiotClientConfig, error) {
+	config := DefaultRiotClientConfig()
+
+	if value := os.Getenv("RIOT_HTTP_PROXY"); value != "" {
+		proxyURL, err := url.Parse(value)
+		if err != nil || proxyURL.Host == "" {
+			return config, fmt.Errorf("invalid RIOT_HTTP_PROXY: %q is not a valid URL", value)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return config, fmt.Errorf("invalid RIOT_HTTP_PROXY: unsupported scheme %q (http, https or socks5)", proxyURL.Scheme)
+		}
+		config.ProxyURL = proxyURL
+	}
+
+	durations := map[string]*time.Duration{
+		"RIOT_HTTP_TIMEOUT":               &config.Timeout,
+		"RIOT_HTTP_DIAL_TIMEOUT":          &config.DialTimeout,
+		"RIOT_HTTP_KEEP_ALIVE":            &config.KeepAlive,
+		"RIOT_HTTP_TLS_HANDSHAKE_TIMEOUT": &config.TLSHandshakeTimeout,
+		"RIOT_HTTP_IDLE_CONN_TIMEOUT":     &config.IdleConnTimeout,
+	}
+	for key, target := range durations {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return config, fmt.Errorf("invalid %s: %q is not a valid duration", key, value)
+		}
+		*target = duration
+	}
+
+	counts := map[string]*int{
+		"RIOT_HTTP_MAX_IDLE_CONNS":          &config.MaxIdleConns,
+		"RIOT_HTTP_MAX_IDLE_CONNS_PER_HOST": &config.MaxIdleConnsPerHost,
+	}
+	for key, target := range counts {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 0 {
+			return config, fmt.Errorf("invalid %s: %q is not a valid number", key, value)
+		}
+		*target = count
+	}
+
+	if value := os.Getenv("RIOT_API_KEYS"); value != "" {
+		keys, err := parseRegionalKeys(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid RIOT_API_KEYS: %w", err)
+		}
+		config.RegionalAPIKeys = keys
+	}
+
+	return config, nil
+}
+
+// transport builds the HTTP transport of the client
+func (c RiotClientConfig) transport() *http.Transport {
+	defaults := DefaultRiotClientConfig()
+
+	proxy := http.ProxyFromEnvironment
+	if c.ProxyURL != nil {
+		proxy = http.ProxyURL(c.ProxyURL)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   valueOrDefault(c.DialTimeout, defaults.DialTimeout),
+		KeepAlive: valueOrDefault(c.KeepAlive, defaults.KeepAlive),
+	}
+
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          valueOrDefault(c.MaxIdleConns, defaults.MaxIdleConns),
+		MaxIdleConnsPerHost:   valueOrDefault(c.MaxIdleConnsPerHost, defaults.MaxIdleConnsPerHost),
+		IdleConnTimeout:       valueOrDefault(c.IdleConnTimeout, defaults.IdleConnTimeout),
+		TLSHandshakeTimeout:   valueOrDefault(c.TLSHandshakeTimeout, defaults.TLSHandshakeTimeout),
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// valueOrDefault returns the value, or the fallback when it is the zero value
+func valueOrDefault[T comparable](value, fallback T) T {
+	var zero T
+	if value == zero {
+		return fallback
+	}
+	return value
+}
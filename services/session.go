@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+const (
+	// Games read to rebuild the last session of a player
+	sessionLookback = 24 * time.Hour
+
+	// Sessions ended longer ago (ex: during a downtime) are skipped instead of summarized late
+	sessionSummaryMaxDelay = 3 * time.Hour
+)
+
+// SessionService groups the tracked games into play sessions and summarizes the ended ones
+type SessionService struct {
+	playerRepo  *repositories.PlayerRepository
+	matchRepo   *repositories.MatchRepository
+	lpEventRepo *repositories.LPEventRepository
+}
+
+func NewSessionService(playerRepo *repositories.PlayerRepository, matchRepo *repositories.MatchRepository, lpEventRepo *repositories.LPEventRepository) *SessionService {
+	return &SessionService{
+		playerRepo:  playerRepo,
+		matchRepo:   matchRepo,
+		lpEventRepo: lpEventRepo,
+	}
+}
+
+// SummarizeEndedSessions returns the summaries of the sessions ended since the last call, each session
+// is summarized once. It must run after the player updates, the players are saved as a whole.
+func (ss *SessionService) SummarizeEndedSessions(ctx context.Context, now time.Time) ([]*models.SessionSummary, error) {
+	players, err := ss.playerRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+
+	var summaries []*models.SessionSummary
+	var errors []string
+	for _, player := range players {
+		if player.Paused {
+			continue
+		}
+
+		summary, err := ss.summarize(ctx, player, now)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to summarize session of %s#%s: %v", player.GameName, player.TagLine, err)
+			errors = append(errors, errorMsg)
+			fmt.Println(errorMsg)
+			continue
+		}
+		if summary != nil {
+			summaries = append(summaries, summary)
+		}
+	}
+
+	if len(errors) > 0 {
+		return summaries, fmt.Errorf("some sessions failed to be summarized: %v", errors)
+	}
+
+	return summaries, nil
+}
+
+func (ss *SessionService) summarize(ctx context.Context, player *models.Player, now time.Time) (*models.SessionSummary, error) {
+	matches, err := ss.matchRepo.FindByPlayerBetween(ctx, player.PUUID, now.Add(-sessionLookback), now)
+	if err != nil {
+		return nil, err
+	}
+	sessions := models.SplitSessions(matches, models.SessionGap)
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+
+	session := sessions[len(sessions)-1]
+	end := session.End()
+	if !session.IsOver(now) || (player.LastSessionSummaryAt != nil && !player.LastSessionSummaryAt.Before(end)) {
+		return nil, nil
+	}
+
+	err = ss.playerRepo.SetLastSessionSummaryAt(ctx, player.ID, end)
+	if err != nil {
+		return nil, err
+	}
+	player.LastSessionSummaryAt = &end
+
+	if len(session.Matches) < models.MinSessionSummaryGames || now.Sub(end) > sessionSummaryMaxDelay {
+		return nil, nil
+	}
+
+	events, err := ss.lpEventRepo.FindRecentByPlayer(ctx, player.PUUID, len(session.Matches)+10)
+	if err != nil {
+		return nil, err
+	}
+	lpDeltas := make(map[string]int, len(events))
+	for _, event := range events {
+		if event.MatchID != "" {
+			lpDeltas[event.MatchID] = event.LPDelta
+		}
+	}
+
+	return models.NewSessionSummary(player, session, lpDeltas), nil
+}
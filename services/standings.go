@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+type StandingsService struct {
+	playerRepo   *repositories.PlayerRepository
+	lpEventRepo  *repositories.LPEventRepository
+	snapshotRepo *repositories.SnapshotRepository
+	personRepo   *repositories.PersonRepository
+	cycleRepo    *repositories.CycleSnapshotRepository
+}
+
+func NewStandingsService(playerRepo *repositories.PlayerRepository, lpEventRepo *repositories.LPEventRepository, snapshotRepo *repositories.SnapshotRepository,
+	personRepo *repositories.PersonRepository, cycleRepo *repositories.CycleSnapshotRepository) *StandingsService {
+	return &StandingsService{
+		playerRepo:   playerRepo,
+		lpEventRepo:  lpEventRepo,
+		snapshotRepo: snapshotRepo,
+		personRepo:   personRepo,
+		cycleRepo:    cycleRepo,
+	}
+}
+
+// GetLeaderboard returns all tracked players sorted by rank (best first), with their ranks at the end of the last poll cycle
+func (ss *StandingsService) GetLeaderboard(ctx context.Context) ([]*models.Player, error) {
+	players, err := ss.playerRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+
+	err = ss.applyLastCycle(ctx, players)
+	if err != nil {
+		return nil, err
+	}
+
+	sortPlayersByRank(players)
+	return players, nil
+}
+
+// GetPersonLeaderboard returns the leaderboard by person: the accounts of a person are grouped
+// and ranked by the best one, accounts not linked to a person stay on their own
+func (ss *StandingsService) GetPersonLeaderboard(ctx context.Context) ([]*models.PersonStanding, error) {
+	players, err := ss.GetLeaderboard(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	persons, err := ss.personRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupByPerson(persons, players), nil
+}
+
+// GetTagLeaderboard returns the tracked players with a tag sorted by rank (best first)
+func (ss *StandingsService) GetTagLeaderboard(ctx context.Context, tag string) ([]*models.Player, error) {
+	tag, err := models.NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := ss.playerRepo.FindByTag(ctx, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+
+	err = ss.applyLastCycle(ctx, players)
+	if err != nil {
+		return nil, err
+	}
+
+	sortPlayersByRank(players)
+	return players, nil
+}
+
+// GetTagPersonLeaderboard returns the leaderboard by person of the accounts with a tag
+func (ss *StandingsService) GetTagPersonLeaderboard(ctx context.Context, tag string) ([]*models.PersonStanding, error) {
+	players, err := ss.GetTagLeaderboard(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	persons, err := ss.personRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupByPerson(persons, players), nil
+}
+
+// CompleteCycle records the ranks of the players at the end of a poll cycle, shown by the leaderboards until
+// the next cycle completes. The players must be read from the primary, after the writes of the cycle.
+func (ss *StandingsService) CompleteCycle(ctx context.Context, players []*models.Player) (*models.CycleSnapshot, error) {
+	last, err := ss.cycleRepo.FindLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.CycleSnapshot{Cycle: 1}
+	if last != nil {
+		snapshot.Cycle = last.Cycle + 1
+	}
+
+	sortPlayersByRank(players)
+	snapshot.Entries = snapshotEntries(players)
+
+	err = ss.cycleRepo.Create(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	// The previous cycle is kept for the reads started before this one completed
+	err = ss.cycleRepo.DeleteBefore(ctx, snapshot.Cycle-1)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// applyLastCycle replaces the ranks of the players by their ranks at the end of the last poll cycle.
+// The players added since keep their current rank, all the players do before the first cycle.
+func (ss *StandingsService) applyLastCycle(ctx context.Context, players []*models.Player) error {
+	snapshot, err := ss.cycleRepo.FindLatest(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the last poll cycle: %w", err)
+	}
+	if snapshot == nil {
+		return nil
+	}
+
+	entriesByPUUID := make(map[string]models.SnapshotEntry, len(snapshot.Entries))
+	for _, entry := range snapshot.Entries {
+		entriesByPUUID[entry.PUUID] = entry
+	}
+
+	for _, player := range players {
+		entry, ok := entriesByPUUID[player.PUUID]
+		if !ok {
+			continue
+		}
+		player.Tier = entry.Tier
+		player.Rank = entry.Rank
+		player.LeaguePoints = entry.LeaguePoints
+		player.Wins = entry.Wins
+		player.Losses = entry.Losses
+	}
+
+	return nil
+}
+
+// sortPlayersByRank sorts players by rank, best first
+func sortPlayersByRank(players []*models.Player) {
+	sort.SliceStable(players, func(a, b int) bool {
+		return models.CompareRanks(players[a], players[b]) < 0
+	})
+}
+
+// TakeSnapshot freezes the current leaderboard under a name
+func (ss *StandingsService) TakeSnapshot(ctx context.Context, guildID, name, createdBy string) (*models.LeaderboardSnapshot, error) {
+	name, err := models.NormalizeSnapshotName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := ss.GetLeaderboard(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.LeaderboardSnapshot{
+		GuildID:   guildID,
+		Name:      name,
+		CreatedBy: createdBy,
+		Entries:   snapshotEntries(players),
+	}
+
+	err = ss.snapshotRepo.Create(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// snapshotEntries freezes the state of players sorted by rank
+func snapshotEntries(players []*models.Player) []models.SnapshotEntry {
+	entries := make([]models.SnapshotEntry, 0, len(players))
+	for idx, player := range players {
+		entries = append(entries, models.SnapshotEntry{
+			Position:     idx + 1,
+			PUUID:        player.PUUID,
+			GameName:     player.GameName,
+			TagLine:      player.TagLine,
+			Server:       player.Server,
+			Tier:         player.Tier,
+			Rank:         player.Rank,
+			LeaguePoints: player.LeaguePoints,
+			Wins:         player.Wins,
+			Losses:       player.Losses,
+		})
+	}
+	return entries
+}
+
+// GetSnapshots returns the snapshots of a guild, newest first
+func (ss *StandingsService) GetSnapshots(ctx context.Context, guildID string) ([]*models.LeaderboardSnapshot, error) {
+	return ss.snapshotRepo.FindByGuild(ctx, guildID)
+}
+
+// CompareSnapshot diffs the current leaderboard against a named snapshot
+func (ss *StandingsService) CompareSnapshot(ctx context.Context, guildID, name string) (*models.LeaderboardSnapshot, []*models.SnapshotDiff, error) {
+	name, err := models.NormalizeSnapshotName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshot, err := ss.snapshotRepo.FindByName(ctx, guildID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if snapshot == nil {
+		return nil, nil, fmt.Errorf("snapshot %s not found", name)
+	}
+
+	players, err := ss.GetLeaderboard(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entriesByPUUID := make(map[string]models.SnapshotEntry, len(snapshot.Entries))
+	for _, entry := range snapshot.Entries {
+		entriesByPUUID[entry.PUUID] = entry
+	}
+
+	diffs := make([]*models.SnapshotDiff, 0, len(players))
+	for idx, player := range players {
+		diff := &models.SnapshotDiff{
+			Player:   player,
+			Position: idx + 1,
+		}
+
+		if entry, ok := entriesByPUUID[player.PUUID]; ok {
+			diff.PreviousPosition = entry.Position
+			diff.GamesPlayed = (player.Wins + player.Losses) - (entry.Wins + entry.Losses)
+			if entry.Tier != "UNRANKED" && player.Tier != "UNRANKED" {
+				diff.LPDelta = models.RankValue(player.Tier, player.Rank, player.LeaguePoints) -
+					models.RankValue(entry.Tier, entry.Rank, entry.LeaguePoints)
+			}
+		}
+
+		diffs = append(diffs, diff)
+	}
+
+	return snapshot, diffs, nil
+}
+
+// GetTeamStandings aggregates tracked players per tag, sorted by average rank (best first)
+func (ss *StandingsService) GetTeamStandings(ctx context.Context) ([]*models.TeamStanding, error) {
+	players, err := ss.playerRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch players: %w", err)
+	}
+
+	err = ss.applyLastCycle(ctx, players)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	events, err := ss.lpEventRepo.FindBetween(ctx, now.AddDate(0, 0, -7), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch LP events: %w", err)
+	}
+
+	weeklyDeltas := make(map[string]int)
+	for _, event := range events {
+		weeklyDeltas[event.PlayerPUUID] += event.LPDelta
+	}
+
+	standingsByTag := make(map[string]*models.TeamStanding)
+	rankTotals := make(map[string]int)
+	for _, player := range players {
+		for _, tag := range player.Tags {
+			standing, ok := standingsByTag[tag]
+			if !ok {
+				standing = &models.TeamStanding{Tag: tag}
+				standingsByTag[tag] = standing
+			}
+
+			standing.Players++
+			standing.WeeklyLPDelta += weeklyDeltas[player.PUUID]
+			standing.Wins += player.Wins
+			standing.Losses += player.Losses
+
+			if player.Tier != "UNRANKED" {
+				standing.RankedPlayers++
+				rankTotals[tag] += models.RankValue(player.Tier, player.Rank, player.LeaguePoints)
+			}
+		}
+	}
+
+	standings := make([]*models.TeamStanding, 0, len(standingsByTag))
+	for tag, standing := range standingsByTag {
+		if standing.RankedPlayers > 0 {
+			standing.AverageRankValue = rankTotals[tag] / standing.RankedPlayers
+		}
+		standings = append(standings, standing)
+	}
+
+	sort.Slice(standings, func(a, b int) bool {
+		if standings[a].AverageRankValue != standings[b].AverageRankValue {
+			return standings[a].AverageRankValue > standings[b].AverageRankValue
+		}
+		return standings[a].Tag < standings[b].Tag
+	})
+
+	return standings, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+const (
+	// Games read to rebuild the current session, longer than any realistic session
+	tiltLookback = 24 * time.Hour
+
+	// Games before the session giving the usual level of a player
+	tiltBaselineGames    = 30
+	tiltMinBaselineGames = 5
+)
+
+// TiltService detects the tilt sessions of the tracked players, at most one alert per session
+type TiltService struct {
+	playerRepo *repositories.PlayerRepository
+	matchRepo  *repositories.MatchRepository
+}
+
+func NewTiltService(playerRepo *repositories.PlayerRepository, matchRepo *repositories.MatchRepository) *TiltService {
+	return &TiltService{
+		playerRepo: playerRepo,
+		matchRepo:  matchRepo,
+	}
+}
+
+// EvaluateRankChanges checks the sessions of the players who just lost a game
+func (ts *TiltService) EvaluateRankChanges(ctx context.Context, changes []*models.RankChange) ([]*models.TiltAlert, error) {
+	var alerts []*models.TiltAlert
+	var errors []string
+	evaluated := make(map[string]bool)
+	for _, change := range changes {
+		player := change.Player
+		if change.Match == nil || change.Match.Victory || player.TiltAlertsOptOut || evaluated[player.PUUID] {
+			continue
+		}
+		evaluated[player.PUUID] = true
+
+		alert, err := ts.evaluate(ctx, player)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to evaluate tilt of %s#%s: %v", player.GameName, player.TagLine, err)
+			errors = append(errors, errorMsg)
+			fmt.Println(errorMsg)
+			continue
+		}
+		if alert != nil {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	if len(errors) > 0 {
+		return alerts, fmt.Errorf("some tilt evaluations failed: %v", errors)
+	}
+
+	return alerts, nil
+}
+
+func (ts *TiltService) evaluate(ctx context.Context, player *models.Player) (*models.TiltAlert, error) {
+	now := time.Now()
+	matches, err := ts.matchRepo.FindByPlayerBetween(ctx, player.PUUID, now.Add(-tiltLookback), now)
+	if err != nil {
+		return nil, err
+	}
+	sessions := models.SplitSessions(matches, models.SessionGap)
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+	session := sessions[len(sessions)-1]
+
+	// Already alerted during this session
+	if player.LastTiltAlertAt != nil && !player.LastTiltAlertAt.Before(session.Start()) {
+		return nil, nil
+	}
+
+	recent, err := ts.matchRepo.FindRecentByPlayer(ctx, player.PUUID, tiltBaselineGames+len(session.Matches))
+	if err != nil {
+		return nil, err
+	}
+	var baseline []*models.MatchPlayerInfo
+	for _, match := range recent {
+		if match.CreatedAt.Before(session.Start()) {
+			baseline = append(baseline, match)
+		}
+	}
+	if len(baseline) < tiltMinBaselineGames {
+		return nil, nil
+	}
+
+	alert := models.DetectTilt(player, session, models.AveragePerformance(baseline))
+	if alert == nil {
+		return nil, nil
+	}
+
+	err = ts.playerRepo.SetLastTiltAlertAt(ctx, player.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	player.LastTiltAlertAt = &now
+	return alert, nil
+}
+
+// SetTiltAlerts enables or disables the tilt alerts of a tracked player
+func (ts *TiltService) SetTiltAlerts(ctx context.Context, gameName, tagLine, server string, enabled bool) (*models.Player, error) {
+	player, err := ts.playerRepo.FindByRiotID(ctx, gameName, tagLine, server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if player == nil {
+		return nil, fmt.Errorf("player %s#%s (%s) is not tracked", gameName, tagLine, server)
+	}
+
+	err = ts.playerRepo.SetTiltAlertsOptOut(ctx, player.ID, !enabled)
+	if err != nil {
+		return nil, err
+	}
+	player.TiltAlertsOptOut = !enabled
+	return player, nil
+}
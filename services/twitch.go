@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	twitchTokenURL   = "https://id.twitch.tv/oauth2/token"
+	twitchStreamsURL = "https://api.twitch.tv/helix/streams"
+
+	// Maximum number of channels per Get Streams request
+	twitchStreamsBatchSize = 100
+)
+
+// Twitch response structures
+type twitchTokenDTO struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"` // Seconds
+}
+
+type twitchStreamsDTO struct {
+	Data []TwitchStreamDTO `json:"data"`
+}
+
+// TwitchStreamDTO is a live stream
+type TwitchStreamDTO struct {
+	UserLogin string    `json:"user_login"`
+	UserName  string    `json:"user_name"`
+	GameName  string    `json:"game_name"`
+	Title     string    `json:"title"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// TwitchService checks which channels are live with the Helix API, authenticated with an app
+// access token (client credentials flow) renewed before it expires
+type TwitchService struct {
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func NewTwitchService(clientID, clientSecret string) *TwitchService {
+	return &TwitchService{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetLiveStreams returns the live streams of the given channels, keyed by lower case login
+func (t *TwitchService) GetLiveStreams(ctx context.Context, logins []string) (map[string]*TwitchStreamDTO, error) {
+	streams := make(map[string]*TwitchStreamDTO)
+	for start := 0; start < len(logins); start += twitchStreamsBatchSize {
+		end := min(start+twitchStreamsBatchSize, len(logins))
+
+		query := url.Values{}
+		for _, login := range logins[start:end] {
+			query.Add("user_login", login)
+		}
+		query.Set("first", fmt.Sprint(twitchStreamsBatchSize))
+
+		var response twitchStreamsDTO
+		err := t.makeRequest(ctx, twitchStreamsURL+"?"+query.Encode(), &response)
+		if err != nil {
+			return nil, err
+		}
+
+		for idx := range response.Data {
+			stream := &response.Data[idx]
+			streams[strings.ToLower(stream.UserLogin)] = stream
+		}
+	}
+
+	return streams, nil
+}
+
+func (t *TwitchService) makeRequest(ctx context.Context, endpoint string, target interface{}) error {
+	token, err := t.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Client-Id", t.clientID)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// The token was revoked, a new one is requested on the next call
+	if resp.StatusCode == http.StatusUnauthorized {
+		t.mu.Lock()
+		t.token = ""
+		t.mu.Unlock()
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Twitch request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// accessToken returns the cached app access token, or requests a new one when it is about to expire
+func (t *TwitchService) accessToken(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && time.Now().Before(t.tokenExpiry) {
+		return t.token, nil
+	}
+
+	form := url.Values{
+		"client_id":     {t.clientID},
+		"client_secret": {t.clientSecret},
+		"grant_type":    {"client_credentials"},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", twitchTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("Twitch authentication failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var token twitchTokenDTO
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", err
+	}
+
+	// Renew a minute early so a request never starts with an expired token
+	t.token = token.AccessToken
+	t.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return t.token, nil
+}
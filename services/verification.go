@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/repositories"
+)
+
+// VerificationService links Discord users to the Riot accounts they own. Ownership is proven by switching
+// the profile icon of the account to the one requested, checked with summoner-v4 by /link verify or by
+// the pending actions queue of the poller.
+type VerificationService struct {
+	verificationRepo *repositories.AccountVerificationRepository
+	playerRepo       *repositories.PlayerRepository
+	riotService      *RiotService
+	pendingActions   *PendingActionService
+}
+
+func NewVerificationService(verificationRepo *repositories.AccountVerificationRepository, playerRepo *repositories.PlayerRepository, riotService *RiotService, pendingActions *PendingActionService) *VerificationService {
+	vs := &VerificationService{
+		verificationRepo: verificationRepo,
+		playerRepo:       playerRepo,
+		riotService:      riotService,
+		pendingActions:   pendingActions,
+	}
+	pendingActions.RegisterHandler(models.PendingAccountVerification, vs)
+	return vs
+}
+
+// StartVerification asks a user to prove they own a tracked account, the previous pending verification
+// of the user is replaced
+func (vs *VerificationService) StartVerification(ctx context.Context, discordUserID, gameName, tagLine, server string) (*models.AccountVerification, error) {
+	player, err := vs.playerRepo.FindByRiotID(ctx, gameName, tagLine, server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if player == nil {
+		return nil, fmt.Errorf("player %s#%s (%s) is not tracked", gameName, tagLine, server)
+	}
+
+	owner, err := vs.verificationRepo.FindVerifiedByPlayer(ctx, player.PUUID)
+	if err != nil {
+		return nil, err
+	}
+	if owner != nil {
+		if owner.DiscordUserID == discordUserID {
+			return nil, fmt.Errorf("%s#%s is already linked to your account", player.GameName, player.TagLine)
+		}
+		return nil, fmt.Errorf("%s#%s is already linked to another Discord user", player.GameName, player.TagLine)
+	}
+
+	iconID, err := vs.riotService.GetProfileIconID(ctx, player.PUUID, player.Server)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := models.NewAccountVerification(discordUserID, player, iconID, time.Now())
+	err = vs.verificationRepo.Create(ctx, verification)
+	if err != nil {
+		return nil, err
+	}
+
+	// The poller keeps checking the icon, so the user doesn't have to come back to /link verify
+	err = vs.pendingActions.Enqueue(ctx, models.PendingAccountVerification, discordUserID, verification.ID, verification.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return verification, nil
+}
+
+// CheckVerification checks the profile icon of the account of the pending verification of a user.
+// It returns the verification with its new status, still pending when the icon doesn't match yet.
+func (vs *VerificationService) CheckVerification(ctx context.Context, discordUserID string) (*models.AccountVerification, error) {
+	verification, err := vs.verificationRepo.FindPending(ctx, discordUserID)
+	if err != nil {
+		return nil, err
+	}
+	if verification == nil {
+		return nil, fmt.Errorf("no pending verification, start one with /link start")
+	}
+
+	err = vs.check(ctx, verification, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if verification.Status != models.VerificationPending {
+		err = vs.pendingActions.Complete(ctx, models.PendingAccountVerification, verification.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return verification, nil
+}
+
+// check moves a pending verification to verified when the icon matches, or to expired
+func (vs *VerificationService) check(ctx context.Context, verification *models.AccountVerification, now time.Time) error {
+	if verification.IsExpired(now) {
+		return vs.verificationRepo.UpdateStatus(ctx, verification, models.VerificationExpired, now)
+	}
+
+	iconID, err := vs.riotService.GetProfileIconID(ctx, verification.PlayerPUUID, verification.Server)
+	if err != nil {
+		return err
+	}
+	if iconID != verification.IconID {
+		return nil
+	}
+
+	return vs.verificationRepo.UpdateStatus(ctx, verification, models.VerificationVerified, now)
+}
+
+// Check implements PendingActionHandler, the user is told by DM once the account is linked
+func (vs *VerificationService) Check(ctx context.Context, action *models.PendingAction) (bool, string, error) {
+	verification, err := vs.verificationRepo.FindByID(ctx, action.RefID)
+	if err != nil {
+		return false, "", err
+	}
+	if verification == nil || verification.Status != models.VerificationPending {
+		return true, "", nil
+	}
+
+	err = vs.check(ctx, verification, time.Now())
+	if err != nil {
+		return false, "", err
+	}
+
+	switch verification.Status {
+	case models.VerificationVerified:
+		return true, fmt.Sprintf("✅ **%s#%s** is now linked to your Discord account, you can switch back to your previous icon",
+			verification.GameName, verification.TagLine), nil
+	case models.VerificationExpired:
+		return true, expiredVerificationMessage(verification), nil
+	}
+	return false, "", nil
+}
+
+// Reminder implements PendingActionHandler
+func (vs *VerificationService) Reminder(ctx context.Context, action *models.PendingAction) (string, error) {
+	verification, err := vs.verificationRepo.FindByID(ctx, action.RefID)
+	if err != nil || verification == nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("⏳ Still waiting for the profile icon **%d** on **%s#%s**, switch to it in the League client before <t:%d:t> to link the account",
+		verification.IconID, verification.GameName, verification.TagLine, verification.ExpiresAt.Unix()), nil
+}
+
+// Expire implements PendingActionHandler
+func (vs *VerificationService) Expire(ctx context.Context, action *models.PendingAction) (string, error) {
+	verification, err := vs.verificationRepo.FindByID(ctx, action.RefID)
+	if err != nil || verification == nil {
+		return "", err
+	}
+	if verification.Status != models.VerificationPending {
+		return "", nil
+	}
+
+	err = vs.verificationRepo.UpdateStatus(ctx, verification, models.VerificationExpired, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return expiredVerificationMessage(verification), nil
+}
+
+// CleanupExpired removes the verifications expired for more than the retention, the pending ones past their
+// expiry are closed by the pending actions queue first
+func (vs *VerificationService) CleanupExpired(ctx context.Context, retention time.Duration) (int64, error) {
+	return vs.verificationRepo.DeleteExpiredBefore(ctx, time.Now().Add(-retention))
+}
+
+func expiredVerificationMessage(verification *models.AccountVerification) string {
+	return fmt.Sprintf("⌛ The verification of **%s#%s** expired, start a new one with `/link start`",
+		verification.GameName, verification.TagLine)
+}
+
+// GetLinkedAccounts returns the accounts verified by a user
+func (vs *VerificationService) GetLinkedAccounts(ctx context.Context, discordUserID string) ([]*models.AccountVerification, error) {
+	return vs.verificationRepo.FindVerifiedByUser(ctx, discordUserID)
+}
+
+// IsOwner checks if a user verified the ownership of a player
+func (vs *VerificationService) IsOwner(ctx context.Context, discordUserID, puuid string) (bool, error) {
+	verification, err := vs.verificationRepo.FindVerifiedByPlayer(ctx, puuid)
+	if err != nil {
+		return false, err
+	}
+	return verification != nil && verification.DiscordUserID == discordUserID, nil
+}
+
+// Unlink removes the link between a user and one of their accounts
+func (vs *VerificationService) Unlink(ctx context.Context, discordUserID, gameName, tagLine, server string) (*models.Player, error) {
+	player, err := vs.playerRepo.FindByRiotID(ctx, gameName, tagLine, server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player: %w", err)
+	}
+	if player == nil {
+		return nil, fmt.Errorf("player %s#%s (%s) is not tracked", gameName, tagLine, server)
+	}
+
+	deleted, err := vs.verificationRepo.DeleteVerified(ctx, discordUserID, player.PUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, fmt.Errorf("%s#%s is not linked to your account", player.GameName, player.TagLine)
+	}
+	return player, nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lp_tracker/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Time allowed to the last flush of a poll cycle, even when the cycle was cancelled by a shutdown
+const writeBufferFlushTimeout = 30 * time.Second
+
+// WriteBufferOptions controls the batching of the writes of the poll cycles
+type WriteBufferOptions struct {
+	MaxWrites     int           // Pending writes triggering a flush, 0 to write every game and LP change right away
+	FlushInterval time.Duration // Longest delay of a pending write while the cycle runs
+}
+
+// DefaultWriteBufferOptions flushes every 100 writes or 10 seconds
+func DefaultWriteBufferOptions() WriteBufferOptions {
+	return WriteBufferOptions{
+		MaxWrites:     100,
+		FlushInterval: 10 * time.Second,
+	}
+}
+
+// pollWriter saves what a player update produces: their new games, LP ledger entry and match checkpoint
+type pollWriter interface {
+	saveMatch(ctx context.Context, match *models.MatchPlayerInfo) error
+	createLPEvent(ctx context.Context, event *models.LPEvent) error
+	moveCheckpoint(ctx context.Context, player *models.Player, matchID string) error
+}
+
+// directWriter writes right away, for the updates of a single player (ex: /refresh)
+type directWriter struct {
+	ps *PlayerService
+}
+
+func (w directWriter) saveMatch(ctx context.Context, match *models.MatchPlayerInfo) error {
+	return w.ps.matchRepo.Upsert(ctx, match)
+}
+
+func (w directWriter) createLPEvent(ctx context.Context, event *models.LPEvent) error {
+	return w.ps.lpEventRepo.Create(ctx, event)
+}
+
+// moveCheckpoint moves the checkpoint of the player, saved with the player
+func (w directWriter) moveCheckpoint(ctx context.Context, player *models.Player, matchID string) error {
+	player.LastMatchID = matchID
+	return nil
+}
+
+// writeBuffer batches the writes of a poll cycle: games and LP ledger entries are sent in bulk when
+// MaxWrites are pending or FlushInterval elapsed, and by flush at the end of the cycle.
+//
+// The checkpoints of the players only move once their games are flushed, so the games of a crashed
+// poller are ingested again by the next cycle. The LP changes pending in a crash are not recorded in
+// the ledger (the rank of the player is saved right away), a graceful shutdown flushes them.
+type writeBuffer struct {
+	ps          *PlayerService
+	opts        WriteBufferOptions
+	matches     []*models.MatchPlayerInfo
+	events      []*models.LPEvent
+	checkpoints map[primitive.ObjectID]checkpoint
+	lastFlush   time.Time
+}
+
+// checkpoint is the last game of a player waiting for the flush of their games
+type checkpoint struct {
+	player  *models.Player
+	matchID string
+}
+
+func (ps *PlayerService) newWriteBuffer() *writeBuffer {
+	return &writeBuffer{
+		ps:          ps,
+		opts:        ps.writeBuffer,
+		checkpoints: make(map[primitive.ObjectID]checkpoint),
+		lastFlush:   time.Now(),
+	}
+}
+
+func (b *writeBuffer) saveMatch(ctx context.Context, match *models.MatchPlayerInfo) error {
+	b.matches = append(b.matches, match)
+	return b.flushIfDue(ctx)
+}
+
+// createLPEvent gives its ID to the event right away, so the notifications can refer to it before the flush
+func (b *writeBuffer) createLPEvent(ctx context.Context, event *models.LPEvent) error {
+	event.ID = primitive.NewObjectID()
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	b.events = append(b.events, event)
+	return b.flushIfDue(ctx)
+}
+
+// moveCheckpoint keeps the checkpoint of the player until their games are flushed
+func (b *writeBuffer) moveCheckpoint(ctx context.Context, player *models.Player, matchID string) error {
+	b.checkpoints[player.ID] = checkpoint{player: player, matchID: matchID}
+	return nil
+}
+
+func (b *writeBuffer) pending() int {
+	return len(b.matches) + len(b.events)
+}
+
+func (b *writeBuffer) flushIfDue(ctx context.Context) error {
+	if b.pending() < b.opts.MaxWrites && time.Since(b.lastFlush) < b.opts.FlushInterval {
+		return nil
+	}
+	return b.flush(ctx)
+}
+
+// flush sends the pending writes, the games first and the checkpoints last. Failed writes stay
+// pending and are sent again by the next flush.
+func (b *writeBuffer) flush(ctx context.Context) error {
+	b.lastFlush = time.Now()
+
+	err := b.ps.matchRepo.UpsertMany(ctx, b.matches)
+	if err != nil {
+		return fmt.Errorf("failed to flush %d games: %w", len(b.matches), err)
+	}
+	b.matches = nil
+
+	err = b.ps.lpEventRepo.CreateMany(ctx, b.events)
+	if err != nil {
+		return fmt.Errorf("failed to flush %d LP events: %w", len(b.events), err)
+	}
+	b.events = nil
+
+	lastMatchIDs := make(map[primitive.ObjectID]string, len(b.checkpoints))
+	for id, checkpoint := range b.checkpoints {
+		lastMatchIDs[id] = checkpoint.matchID
+	}
+	err = b.ps.playerRepo.SetLastMatchIDs(ctx, lastMatchIDs)
+	if err != nil {
+		return fmt.Errorf("failed to flush %d match checkpoints: %w", len(b.checkpoints), err)
+	}
+	// The players saved later in the cycle keep the flushed checkpoints
+	for _, checkpoint := range b.checkpoints {
+		checkpoint.player.LastMatchID = checkpoint.matchID
+	}
+	b.checkpoints = make(map[primitive.ObjectID]checkpoint)
+
+	return nil
+}
+
+// close flushes the writes left at the end of the cycle, even if the cycle was cancelled
+func (b *writeBuffer) close(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), writeBufferFlushTimeout)
+	defer cancel()
+	return b.flush(ctx)
+}
+
+// cycleWriter returns the writer of a poll cycle and the function flushing it at the end of the cycle
+func (ps *PlayerService) cycleWriter() (pollWriter, func(ctx context.Context) error) {
+	if ps.writeBuffer.MaxWrites <= 0 {
+		return directWriter{ps: ps}, func(ctx context.Context) error { return nil }
+	}
+	buffer := ps.newWriteBuffer()
+	return buffer, buffer.close
+}
@@ -0,0 +1,79 @@
+// Package version describes the running build, set at link time:
+//
+//	go build -ldflags="-X lp_tracker/version.Version=v1.4.0 -X lp_tracker/version.Commit=$(git rev-parse HEAD) -X lp_tracker/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Set with -ldflags "-X", the VCS stamp of the Go toolchain fills Commit and BuildDate (commit date) otherwise
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// Info is the build description served by /version and the web server
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+	Modified  bool   `json:"modified,omitempty"` // Built from a tree with uncommitted changes
+}
+
+// Get returns the build description, unknown fields are set to "unknown"
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+
+	// go build stamps the VCS revision when built from a git checkout (not with go run)
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+	return info
+}
+
+// ShortCommit returns the first 7 characters of the commit hash
+func (i Info) ShortCommit() string {
+	if len(i.Commit) > 7 {
+		return i.Commit[:7]
+	}
+	return i.Commit
+}
+
+// String formats the build for logs and reports, ex: v1.4.0 (3f2a9c1, built 2026-03-01T10:00:00Z, go1.24.4)
+func (i Info) String() string {
+	commit := i.ShortCommit()
+	if i.Modified {
+		commit += "-dirty"
+	}
+	return fmt.Sprintf("%s (%s, built %s, %s)", i.Version, commit, i.BuildDate, i.GoVersion)
+}
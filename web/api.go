@@ -0,0 +1,264 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/version"
+)
+
+// apiEndpoint describes an API endpoint: the same description registers the handler and documents it
+// in the OpenAPI document, so the documentation can't drift from the routes
+type apiEndpoint struct {
+	method      string
+	path        string // Go ServeMux pattern, the {wildcards} are the path parameters
+	operationID string // Method name of the generated clients
+	scope       models.APIKeyScope
+	summary     string
+	handler     http.HandlerFunc
+	query       []apiParam
+	request     any // Example value of the JSON body, nil without body
+	response    any // Example value of the data returned, nil without content
+	status      int // Success status
+	paginated   bool
+	description string
+}
+
+// apiParam is a query parameter of an endpoint
+type apiParam struct {
+	name        string
+	kind        string // OpenAPI type (string, integer)
+	description string
+}
+
+var (
+	fieldsParam = apiParam{name: "fields", kind: "string", description: "Comma separated fields to return (ex: gameName,tagLine,tier)"}
+	limitParam  = apiParam{name: "limit", kind: "integer", description: fmt.Sprintf("Page size, %d by default and at most %d", defaultAPIPageSize, maxAPIPageSize)}
+	cursorParam = apiParam{name: "cursor", kind: "string", description: "nextCursor of the previous page"}
+)
+
+// apiEndpoints lists the endpoints of the API
+func (s *Server) apiEndpoints() []apiEndpoint {
+	return []apiEndpoint{
+		{
+			method:      http.MethodGet,
+			path:        "/api/v1/players",
+			scope:       models.APIKeyScopeRead,
+			operationID: "listPlayers",
+			summary:     "List the tracked players, best rank first",
+			handler:     s.handleAPIPlayers,
+			query:       []apiParam{limitParam, cursorParam, fieldsParam},
+			response:    []models.PlayerSummary{},
+			status:      http.StatusOK,
+			paginated:   true,
+		},
+		{
+			method:      http.MethodGet,
+			path:        "/api/v1/players/{server}/{gameName}/{tagLine}",
+			scope:       models.APIKeyScopeRead,
+			operationID: "getPlayer",
+			summary:     "Get a tracked player",
+			handler:     s.handleAPIPlayer,
+			query:       []apiParam{fieldsParam},
+			response:    models.PlayerDetail{},
+			status:      http.StatusOK,
+		},
+		{
+			method:      http.MethodPost,
+			path:        "/api/v1/players",
+			scope:       models.APIKeyScopeAdmin,
+			operationID: "addPlayer",
+			summary:     "Track a player",
+			description: "Looks the player up on the Riot API, a paused player is resumed.",
+			handler:     s.handleAPIAddPlayer,
+			request:     apiPlayerRequest{},
+			response:    models.PlayerDetail{},
+			status:      http.StatusCreated,
+		},
+		{
+			method:      http.MethodDelete,
+			path:        "/api/v1/players/{server}/{gameName}/{tagLine}",
+			scope:       models.APIKeyScopeAdmin,
+			operationID: "removePlayer",
+			summary:     "Stop tracking a player",
+			description: "The stored games and LP history are kept.",
+			handler:     s.handleAPIRemovePlayer,
+			status:      http.StatusNoContent,
+		},
+		{
+			method:      http.MethodPost,
+			path:        "/api/v1/refresh/{puuid}",
+			scope:       models.APIKeyScopeRefresh,
+			operationID: "refreshPlayer",
+			summary:     "Update a tracked player now",
+			description: "Starts a background job polling the player on the Riot API, followed with getJob. A refresh already running for the player is returned instead, a player updated less than a minute ago answers 429 with Retry-After.",
+			handler:     s.handleAPIRefresh,
+			response:    models.Job{},
+			status:      http.StatusAccepted,
+		},
+		{
+			method:      http.MethodGet,
+			path:        "/api/v1/jobs/{id}",
+			scope:       models.APIKeyScopeRefresh,
+			operationID: "getJob",
+			summary:     "Get a job started through the API",
+			handler:     s.handleAPIJob,
+			query:       []apiParam{fieldsParam},
+			response:    models.Job{},
+			status:      http.StatusOK,
+		},
+	}
+}
+
+// registerAPI adds the JSON API to the dashboard, every endpoint requires an API key (see /admin api_key_create).
+// The OpenAPI document and its Swagger UI are public.
+func (s *Server) registerAPI(mux *http.ServeMux) {
+	endpoints := s.apiEndpoints()
+	for _, endpoint := range endpoints {
+		mux.HandleFunc(endpoint.method+" "+endpoint.path, s.apiRoute(endpoint.scope, endpoint.handler))
+	}
+
+	document := buildOpenAPIDocument(endpoints, version.Get().Version)
+	mux.HandleFunc("GET /api/openapi.json", s.withCORS(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, document)
+	}))
+	mux.HandleFunc("GET /api/docs", s.handleSwaggerUI)
+
+	// Preflight requests and unknown endpoints, answered in JSON like the rest of the API
+	mux.HandleFunc("/api/", s.withCORS(handleAPINotFound))
+}
+
+// apiRoute wraps an endpoint with the CORS headers and the key check
+func (s *Server) apiRoute(scope models.APIKeyScope, handler http.HandlerFunc) http.HandlerFunc {
+	return s.withCORS(s.requireKey(scope, handler))
+}
+
+func handleAPINotFound(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		// Preflight from an origin that is not allowed, the browser blocks the request without CORS headers
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeAPIError(w, http.StatusNotFound, fmt.Sprintf("unknown endpoint %s %s", r.Method, r.URL.Path))
+}
+
+// handleAPIPlayers lists the tracked players, best rank first, by pages of ?limit= players.
+// The nextCursor of a page is passed as ?cursor= to get the following one.
+func (s *Server) handleAPIPlayers(w http.ResponseWriter, r *http.Request) {
+	limit, err := pageSize(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var after *apiCursor
+	if value := r.URL.Query().Get("cursor"); value != "" {
+		after, err = decodeAPICursor(value)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	players, err := s.standings.GetLeaderboard(ctx)
+	if err != nil {
+		log.Printf("Error fetching players for the API: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	page, nextCursor := paginatePlayers(players, after, limit)
+	writeAPIData(w, r, http.StatusOK, models.NewPlayerSummaries(page), nextCursor)
+}
+
+func (s *Server) handleAPIPlayer(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	player, ok := s.findAPIPlayer(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	writeAPIData(w, r, http.StatusOK, models.NewPlayerDetail(player), "")
+}
+
+// apiPlayerRequest is the body of POST /api/v1/players
+type apiPlayerRequest struct {
+	GameName string `json:"gameName"`
+	TagLine  string `json:"tagLine"`
+	Server   string `json:"server"`
+}
+
+// handleAPIAddPlayer starts tracking a player, like /add
+func (s *Server) handleAPIAddPlayer(w http.ResponseWriter, r *http.Request) {
+	var request apiPlayerRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&request)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if request.GameName == "" || request.TagLine == "" || request.Server == "" {
+		writeAPIError(w, http.StatusBadRequest, "gameName, tagLine and server are required")
+		return
+	}
+
+	// Adding a player calls the Riot API
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	player, err := s.players.AddPlayer(ctx, request.GameName, request.TagLine, strings.ToLower(request.Server))
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	log.Printf("➕ %s#%s added through the API by key %s", player.GameName, player.TagLine, apiKeyFromContext(r.Context()).Name)
+	writeAPIData(w, r, http.StatusCreated, models.NewPlayerDetail(player), "")
+}
+
+// handleAPIRemovePlayer stops tracking a player, like /remove
+func (s *Server) handleAPIRemovePlayer(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	player, ok := s.findAPIPlayer(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	err := s.players.RemovePlayer(ctx, player)
+	if err != nil {
+		log.Printf("Error removing player %s#%s through the API: %v", player.GameName, player.TagLine, err)
+		writeAPIError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	log.Printf("➖ %s#%s removed through the API by key %s", player.GameName, player.TagLine, apiKeyFromContext(r.Context()).Name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findAPIPlayer returns the tracked player of the request path, the error response is written when not found
+func (s *Server) findAPIPlayer(ctx context.Context, w http.ResponseWriter, r *http.Request) (*models.Player, bool) {
+	player, err := s.players.GetPlayerByRiotID(ctx, r.PathValue("gameName"), r.PathValue("tagLine"), strings.ToLower(r.PathValue("server")))
+	if err != nil {
+		log.Printf("Error fetching player for the API: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "internal error")
+		return nil, false
+	}
+	if player == nil {
+		writeAPIError(w, http.StatusNotFound, "player not tracked")
+		return nil, false
+	}
+
+	return player, true
+}
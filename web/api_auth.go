@@ -0,0 +1,100 @@
+package web
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"lp_tracker/models"
+)
+
+type apiKeyContextKey struct{}
+
+// requireKey authenticates the API requests with an API key allowing the scope, and applies the rate limit of the key.
+// The key is sent as "Authorization: Bearer <key>" or as the X-API-Key header.
+func (s *Server) requireKey(scope models.APIKeyScope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		key, err := s.apiKeys.Authenticate(ctx, requestAPIKey(r))
+		cancel()
+		if err != nil {
+			log.Printf("Error authenticating API request: %v", err)
+			writeAPIError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		if key == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="lp_tracker"`)
+			writeAPIError(w, http.StatusUnauthorized, "missing or invalid API key")
+			return
+		}
+		if !key.Allows(scope) {
+			writeAPIError(w, http.StatusForbidden, "the API key does not have the "+string(scope)+" scope")
+			return
+		}
+
+		remaining, retryAfter := s.rateLimiter.take(key, time.Now())
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(key.RateLimit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second).Seconds())))
+			writeAPIError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+	}
+}
+
+// requestAPIKey reads the key of a request, empty when missing
+func requestAPIKey(r *http.Request) string {
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(bearer)
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
+
+// apiKeyFromContext returns the key authenticating the request
+func apiKeyFromContext(ctx context.Context) *models.APIKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*models.APIKey)
+	return key
+}
+
+// apiRateLimiter counts the requests of each key per one minute window.
+// The counters are in memory: they restart with the process, which is fine for abuse protection.
+type apiRateLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*apiRateWindow
+}
+
+type apiRateWindow struct {
+	start    time.Time
+	requests int
+}
+
+func newAPIRateLimiter() *apiRateLimiter {
+	return &apiRateLimiter{windows: make(map[string]*apiRateWindow)}
+}
+
+// take counts a request of the key. It returns the requests left in the window,
+// or how long to wait when the limit is reached.
+func (l *apiRateLimiter) take(key *models.APIKey, now time.Time) (int, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	id := key.ID.Hex()
+	window, ok := l.windows[id]
+	if !ok || now.Sub(window.start) >= time.Minute {
+		window = &apiRateWindow{start: now}
+		l.windows[id] = window
+	}
+
+	if window.requests >= key.RateLimit {
+		return 0, window.start.Add(time.Minute).Sub(now)
+	}
+	window.requests++
+	return key.RateLimit - window.requests, 0
+}
@@ -0,0 +1,150 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+)
+
+// Bounds the Riot API calls and the ingestion of the new games of a refresh
+const apiRefreshTimeout = time.Minute
+
+// SetNotifier sets the notifier of the rank changes detected by the refreshes, the poller doesn't see them again
+func (s *Server) SetNotifier(notifier RankChangeNotifier) {
+	s.notifier = notifier
+}
+
+// handleAPIRefresh updates a tracked player now, like /refresh, for automations such as stream overlays.
+// The update runs as a background job followed with GET /api/v1/jobs/{id}, so slow Riot API calls don't hold
+// the request. A refresh already running for the player is returned instead of starting another one.
+func (s *Server) handleAPIRefresh(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	player, err := s.players.GetPlayerByPUUID(ctx, r.PathValue("puuid"))
+	if err != nil {
+		log.Printf("Error fetching player for the API: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if player == nil {
+		writeAPIError(w, http.StatusNotFound, "player not tracked")
+		return
+	}
+
+	// Held until the job is registered, so concurrent requests for the player start a single job
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	if jobID, ok := s.refreshing[player.PUUID]; ok {
+		job, err := s.jobs.GetJob(ctx, "", jobID)
+		if err != nil {
+			log.Printf("Error fetching refresh job %s: %v", jobID, err)
+			writeAPIError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		if job != nil {
+			writeAPIData(w, r, http.StatusAccepted, job, "")
+			return
+		}
+	}
+
+	if player.LastPolledAt != nil {
+		if wait := services.RefreshCooldown - time.Since(*player.LastPolledAt); wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait.Round(time.Second).Seconds())))
+			writeAPIError(w, http.StatusTooManyRequests, fmt.Sprintf("player updated less than %s ago", services.RefreshCooldown))
+			return
+		}
+	}
+
+	// The player is updated by the job, the request only reads these copies
+	puuid, riotID := player.PUUID, player.GameName+"#"+player.TagLine
+
+	key := apiKeyFromContext(r.Context())
+	job := &models.Job{
+		Type:        models.JobTypeRefresh,
+		Name:        "Refresh of " + riotID,
+		RequestedBy: "api:" + key.Name,
+	}
+	run := func(ctx context.Context, progress services.JobProgress) (string, error) {
+		ctx, cancel := context.WithTimeout(ctx, apiRefreshTimeout)
+		defer cancel()
+
+		catchUp, err := s.players.PollPlayer(ctx, player)
+		if err != nil {
+			return "", err
+		}
+		if catchUp.Change != nil && s.notifier != nil {
+			err = s.notifier.NotifyRankChanges(ctx, []*models.RankChange{catchUp.Change})
+			if err != nil {
+				log.Printf("Error notifying rank change of %s: %v", riotID, err)
+			}
+		}
+		return refreshJobResult(catchUp), nil
+	}
+
+	err = s.jobs.Start(ctx, job, run, func(job *models.Job) {
+		s.refreshMu.Lock()
+		delete(s.refreshing, puuid)
+		s.refreshMu.Unlock()
+
+		if job.Status != models.JobSucceeded {
+			log.Printf("Error refreshing %s through the API: %s", riotID, job.Error)
+		}
+	})
+	if err != nil {
+		log.Printf("Error starting refresh of %s: %v", riotID, err)
+		writeAPIError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	s.refreshing[puuid] = job.ShortID()
+
+	log.Printf("🔄 Refresh of %s requested through the API by key %s (job %s)", riotID, key.Name, job.ShortID())
+
+	// The job is already running in the background, its progress is read with GET /api/v1/jobs/{id}
+	writeAPIData(w, r, http.StatusAccepted, &models.Job{
+		ID:          job.ID,
+		Type:        job.Type,
+		Name:        job.Name,
+		RequestedBy: job.RequestedBy,
+		Status:      models.JobPending,
+		CreatedAt:   job.CreatedAt,
+	}, "")
+}
+
+// refreshJobResult summarizes a refresh for the job result (ex: "Gold II • 45 LP, 2 new games")
+func refreshJobResult(catchUp *models.CatchUp) string {
+	summary := models.NewPlayerSummary(catchUp.Player)
+
+	rank := "Unranked"
+	if summary.IsRanked() {
+		rank = fmt.Sprintf("%s • %d LP", summary.RankLabel(), summary.LeaguePoints)
+	}
+	return fmt.Sprintf("%s, %d new games", rank, len(catchUp.Matches))
+}
+
+// handleAPIJob returns the state of a job started through the API
+func (s *Server) handleAPIJob(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	// The jobs of the API have no guild, the ones started from Discord stay private
+	job, err := s.jobs.GetJob(ctx, "", r.PathValue("id"))
+	if err != nil {
+		log.Printf("Error fetching job for the API: %v", err)
+		writeAPIError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if job == nil {
+		writeAPIError(w, http.StatusNotFound, "job not found")
+		return
+	}
+
+	writeAPIData(w, r, http.StatusOK, job, "")
+}
@@ -0,0 +1,248 @@
+package web
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"lp_tracker/models"
+)
+
+const (
+	defaultAPIPageSize = 50
+	maxAPIPageSize     = 200
+)
+
+// apiResponse is the envelope of the successful API responses
+type apiResponse struct {
+	Data       any    `json:"data"`
+	NextCursor string `json:"nextCursor,omitempty"` // Lists only, empty on the last page
+}
+
+// apiErrorResponse is the envelope of the failed API responses
+type apiErrorResponse struct {
+	Error apiError `json:"error"`
+}
+
+type apiError struct {
+	Code    string `json:"code"` // Stable identifier for clients (ex: "not_found")
+	Message string `json:"message"`
+}
+
+// Error codes by HTTP status
+var apiErrorCodes = map[int]string{
+	http.StatusBadRequest:          "bad_request",
+	http.StatusUnauthorized:        "unauthorized",
+	http.StatusForbidden:           "forbidden",
+	http.StatusNotFound:            "not_found",
+	http.StatusMethodNotAllowed:    "method_not_allowed",
+	http.StatusUnprocessableEntity: "unprocessable",
+	http.StatusTooManyRequests:     "rate_limited",
+	http.StatusInternalServerError: "internal",
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(value)
+	if err != nil {
+		log.Printf("Error encoding API response: %v", err)
+	}
+}
+
+// writeAPIData writes a successful response, keeping the fields selected with ?fields= when set
+func writeAPIData(w http.ResponseWriter, r *http.Request, status int, data any, nextCursor string) {
+	fields, err := requestFields(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if fields != nil {
+		data, err = filterFields(data, fields)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	writeJSON(w, status, apiResponse{Data: data, NextCursor: nextCursor})
+}
+
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	code, ok := apiErrorCodes[status]
+	if !ok {
+		code = strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+	}
+	writeJSON(w, status, apiErrorResponse{Error: apiError{Code: code, Message: message}})
+}
+
+// requestFields returns the fields selected with ?fields=a,b, nil to keep every field
+func requestFields(r *http.Request) ([]string, error) {
+	value := r.URL.Query().Get("fields")
+	if value == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			return nil, fmt.Errorf("empty field in fields")
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// filterFields keeps the selected top-level fields of an object, or of every object of a list.
+// Unknown fields are rejected so typos don't silently return empty objects.
+func filterFields(data any, fields []string) (any, error) {
+	known := jsonFieldNames(reflect.TypeOf(data))
+	for _, field := range fields {
+		if !known[field] {
+			return nil, fmt.Errorf("unknown field %s", field)
+		}
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if reflect.TypeOf(data).Kind() == reflect.Slice {
+		var objects []map[string]json.RawMessage
+		err = json.Unmarshal(raw, &objects)
+		if err != nil {
+			return nil, err
+		}
+		for idx := range objects {
+			objects[idx] = selectFields(objects[idx], fields)
+		}
+		return objects, nil
+	}
+
+	var object map[string]json.RawMessage
+	err = json.Unmarshal(raw, &object)
+	if err != nil {
+		return nil, err
+	}
+	return selectFields(object, fields), nil
+}
+
+// selectFields keeps the selected fields of an object, the empty optional fields stay omitted
+func selectFields(object map[string]json.RawMessage, fields []string) map[string]json.RawMessage {
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := object[field]; ok {
+			selected[field] = value
+		}
+	}
+	return selected
+}
+
+// jsonFieldNames returns the JSON names of the fields of a struct (or of the elements of a slice), embedded structs included
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	names := make(map[string]bool)
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+	for idx := 0; idx < t.NumField(); idx++ {
+		field := t.Field(idx)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" {
+			for embedded := range jsonFieldNames(field.Type) {
+				names[embedded] = true
+			}
+			continue
+		}
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// apiCursor is the position after the last item of a page of players, in rank order.
+// Keyed on the rank and the PUUID, pages stay consistent when players are added or removed between requests.
+type apiCursor struct {
+	RankValue int    `json:"r"`
+	PUUID     string `json:"p"`
+}
+
+func (c apiCursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeAPICursor(value string) (*apiCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var cursor apiCursor
+	err = json.Unmarshal(raw, &cursor)
+	if err != nil || cursor.PUUID == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+// pageSize reads ?limit=, defaultAPIPageSize when missing
+func pageSize(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return defaultAPIPageSize, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxAPIPageSize {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxAPIPageSize)
+	}
+	return limit, nil
+}
+
+// paginatePlayers returns the page of players after the cursor (best rank first, then by PUUID) and the cursor of the next page
+func paginatePlayers(players []*models.Player, after *apiCursor, limit int) ([]*models.Player, string) {
+	rankValue := func(player *models.Player) int {
+		return models.RankValue(player.Tier, player.Rank, player.LeaguePoints)
+	}
+	sort.SliceStable(players, func(a, b int) bool {
+		rankA, rankB := rankValue(players[a]), rankValue(players[b])
+		if rankA != rankB {
+			return rankA > rankB
+		}
+		return players[a].PUUID < players[b].PUUID
+	})
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(players), func(idx int) bool {
+			rank := rankValue(players[idx])
+			return rank < after.RankValue || (rank == after.RankValue && players[idx].PUUID > after.PUUID)
+		})
+	}
+
+	end := min(start+limit, len(players))
+	page := players[start:end]
+	if end == len(players) {
+		return page, ""
+	}
+
+	last := page[len(page)-1]
+	return page, apiCursor{RankValue: rankValue(last), PUUID: last.PUUID}.encode()
+}
@@ -0,0 +1,58 @@
+package web
+
+import (
+	"fmt"
+	"strings"
+
+	"lp_tracker/models"
+)
+
+// Size of the LP chart (SVG user units)
+const (
+	chartWidth   = 600
+	chartHeight  = 200
+	chartPadding = 10
+)
+
+// lpChart is the LP history drawn as an SVG polyline, rendered without JavaScript
+type lpChart struct {
+	Width  int
+	Height int
+	Points string // Polyline points ("x,y x,y ...")
+	Low    string // Lowest rank of the period
+	High   string // Highest rank of the period
+}
+
+func newLPChart(history []models.LPPoint) lpChart {
+	chart := lpChart{Width: chartWidth, Height: chartHeight}
+	if len(history) < 2 {
+		return chart
+	}
+
+	low, high := history[0].Value, history[0].Value
+	for _, point := range history {
+		low = min(low, point.Value)
+		high = max(high, point.Value)
+	}
+	span := max(high-low, 1)
+
+	points := make([]string, 0, len(history))
+	for idx, point := range history {
+		x := chartPadding + idx*(chartWidth-2*chartPadding)/(len(history)-1)
+		y := chartHeight - chartPadding - (point.Value-low)*(chartHeight-2*chartPadding)/span
+		points = append(points, fmt.Sprintf("%d,%d", x, y))
+	}
+
+	chart.Points = strings.Join(points, " ")
+	chart.Low = rankLabel(low)
+	chart.High = rankLabel(high)
+	return chart
+}
+
+func rankLabel(value int) string {
+	tier, rank, leaguePoints := models.RankFromValue(value)
+	if tier == "UNRANKED" {
+		return "Unranked"
+	}
+	return fmt.Sprintf("%s %d LP", models.FormatRank(tier, rank), leaguePoints)
+}
@@ -0,0 +1,75 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Headers of the API responses readable by the browsers
+var apiExposedHeaders = []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "Retry-After"}
+
+// SetCORSOrigins sets the origins allowed to call the API from a browser (ex: "https://app.example.com"), "*" allows any origin.
+// Without origins the browsers block the cross-origin calls, the other clients are not affected.
+func (s *Server) SetCORSOrigins(origins []string) {
+	s.corsOrigins = origins
+}
+
+// ParseCORSOrigins reads a comma separated list of origins (ex: "https://app.example.com,http://localhost:3000", or "*")
+func ParseCORSOrigins(spec string) ([]string, error) {
+	var origins []string
+	for _, origin := range strings.Split(spec, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+		if origin != "*" {
+			parsed, err := url.Parse(origin)
+			if err != nil || parsed.Scheme == "" || parsed.Host == "" || (parsed.Path != "" && parsed.Path != "/") {
+				return nil, fmt.Errorf("invalid origin %q, expected scheme://host[:port]", origin)
+			}
+			origin = strings.TrimSuffix(origin, "/")
+		}
+		origins = append(origins, origin)
+	}
+	return origins, nil
+}
+
+// withCORS adds the CORS headers to the API responses of the allowed origins and answers the preflight requests.
+// The keys are sent in headers, never in cookies, so credentials are not allowed.
+func (s *Server) withCORS(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && s.corsAllowed(origin) {
+			header := w.Header()
+			if slices.Contains(s.corsOrigins, "*") {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Set("Access-Control-Allow-Origin", origin)
+				header.Add("Vary", "Origin")
+			}
+			header.Set("Access-Control-Expose-Headers", strings.Join(apiExposedHeaders, ", "))
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				header.Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+				header.Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-API-Key")
+				header.Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Server) corsAllowed(origin string) bool {
+	for _, allowed := range s.corsOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"lp_tracker/models"
+)
+
+const icsTimeFormat = "20060102T150405Z"
+
+// clashEvent is a tournament of a server with the tracked players registered in it
+type clashEvent struct {
+	tournamentID   int
+	tournamentName string
+	server         string
+	start          time.Time
+	registrations  []*models.ClashRegistration
+}
+
+// writeClashCalendar writes the registrations as an iCalendar feed (RFC 5545), one event per tournament and server
+func writeClashCalendar(w io.Writer, registrations []*models.ClashRegistration, now time.Time) error {
+	events := groupClashEvents(registrations)
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//lp_tracker//Clash registrations//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Clash registrations",
+	}
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:clash-%s-%d@lp_tracker", event.server, event.tournamentID),
+			"DTSTAMP:"+now.UTC().Format(icsTimeFormat),
+			"DTSTART:"+event.start.UTC().Format(icsTimeFormat),
+			"DTEND:"+event.start.Add(models.ClashTournamentDuration).UTC().Format(icsTimeFormat),
+			"SUMMARY:"+escapeICSText(fmt.Sprintf("%s (%s)", event.tournamentName, strings.ToUpper(event.server))),
+			"DESCRIPTION:"+escapeICSText(clashEventDescription(event)),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		_, err := io.WriteString(w, foldICSLine(line)+"\r\n")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func groupClashEvents(registrations []*models.ClashRegistration) []*clashEvent {
+	byKey := make(map[string]*clashEvent)
+	var events []*clashEvent
+	for _, registration := range registrations {
+		key := fmt.Sprintf("%s/%d", registration.Server, registration.TournamentID)
+		event, ok := byKey[key]
+		if !ok {
+			event = &clashEvent{
+				tournamentID:   registration.TournamentID,
+				tournamentName: registration.TournamentName,
+				server:         registration.Server,
+				start:          registration.StartTime,
+			}
+			byKey[key] = event
+			events = append(events, event)
+		}
+		event.registrations = append(event.registrations, registration)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].start.Before(events[j].start)
+	})
+	return events
+}
+
+func clashEventDescription(event *clashEvent) string {
+	lines := []string{"Registered players:"}
+	for _, registration := range event.registrations {
+		line := fmt.Sprintf("- %s#%s", registration.GameName, registration.TagLine)
+		if registration.TeamName != "" {
+			line += " with " + registration.TeamName
+		}
+		if registration.Position != "" && registration.Position != "UNSELECTED" {
+			line += fmt.Sprintf(" (%s)", registration.Position)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// escapeICSText escapes a TEXT value, newlines are written as the \n escape sequence
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// foldICSLine splits the lines longer than 75 octets, continuation lines start with a space
+func foldICSLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+
+	var folded strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			folded.WriteString("\r\n ")
+			width = 1
+		}
+		folded.WriteRune(r)
+		width += size
+	}
+	return folded.String()
+}
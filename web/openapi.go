@@ -0,0 +1,229 @@
+package web
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Path parameters of a ServeMux pattern (ex: {server})
+var pathParamPattern = regexp.MustCompile(`\{([a-zA-Z]+)\}`)
+
+// buildOpenAPIDocument describes the API endpoints as an OpenAPI 3 document.
+// The schemas are read from the JSON tags of the request and response types.
+func buildOpenAPIDocument(endpoints []apiEndpoint, apiVersion string) map[string]any {
+	schemas := map[string]any{
+		"Error": map[string]any{
+			"type":     "object",
+			"required": []string{"error"},
+			"properties": map[string]any{
+				"error": map[string]any{
+					"type":     "object",
+					"required": []string{"code", "message"},
+					"properties": map[string]any{
+						"code":    map[string]any{"type": "string", "example": "not_found"},
+						"message": map[string]any{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+
+	paths := make(map[string]any)
+	for _, endpoint := range endpoints {
+		item, ok := paths[endpoint.path].(map[string]any)
+		if !ok {
+			item = make(map[string]any)
+			paths[endpoint.path] = item
+		}
+		item[strings.ToLower(endpoint.method)] = openAPIOperation(endpoint, schemas)
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "LP Tracker API",
+			"version":     apiVersion,
+			"description": "Tracked League of Legends players. Every endpoint needs an API key created by the bot owner with /admin api_key_create.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"},
+				"apiKeyHeader": map[string]any{
+					"type": "apiKey",
+					"in":   "header",
+					"name": "X-API-Key",
+				},
+			},
+			"responses": map[string]any{
+				"Error": map[string]any{
+					"description": "Error",
+					"content":     jsonContent(map[string]any{"$ref": "#/components/schemas/Error"}),
+				},
+			},
+		},
+		"security": []any{
+			map[string]any{"bearerAuth": []string{}},
+			map[string]any{"apiKeyHeader": []string{}},
+		},
+	}
+}
+
+func openAPIOperation(endpoint apiEndpoint, schemas map[string]any) map[string]any {
+	var parameters []any
+	for _, match := range pathParamPattern.FindAllStringSubmatch(endpoint.path, -1) {
+		parameters = append(parameters, map[string]any{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		})
+	}
+	for _, param := range endpoint.query {
+		parameters = append(parameters, map[string]any{
+			"name":        param.name,
+			"in":          "query",
+			"description": param.description,
+			"schema":      map[string]any{"type": param.kind},
+		})
+	}
+
+	description := "Requires a key with the " + string(endpoint.scope) + " scope."
+	if endpoint.description != "" {
+		description = endpoint.description + " " + description
+	}
+
+	success := map[string]any{"description": http.StatusText(endpoint.status)}
+	if endpoint.response != nil {
+		properties := map[string]any{"data": openAPISchema(reflect.TypeOf(endpoint.response), schemas)}
+		if endpoint.paginated {
+			properties["nextCursor"] = map[string]any{"type": "string", "description": "Cursor of the next page, missing on the last page"}
+		}
+		success["content"] = jsonContent(map[string]any{
+			"type":       "object",
+			"required":   []string{"data"},
+			"properties": properties,
+		})
+	}
+
+	responses := map[string]any{strconv.Itoa(endpoint.status): success}
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests} {
+		responses[strconv.Itoa(status)] = map[string]any{"$ref": "#/components/responses/Error"}
+	}
+	if len(pathParamPattern.FindAllString(endpoint.path, -1)) > 0 {
+		responses[strconv.Itoa(http.StatusNotFound)] = map[string]any{"$ref": "#/components/responses/Error"}
+	}
+	if endpoint.request != nil {
+		responses[strconv.Itoa(http.StatusUnprocessableEntity)] = map[string]any{"$ref": "#/components/responses/Error"}
+	}
+
+	operation := map[string]any{
+		"summary":          endpoint.summary,
+		"description":      description,
+		"operationId":      endpoint.operationID,
+		"responses":        responses,
+		"x-required-scope": endpoint.scope,
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	if endpoint.request != nil {
+		operation["requestBody"] = map[string]any{
+			"required": true,
+			"content":  jsonContent(openAPISchema(reflect.TypeOf(endpoint.request), schemas)),
+		}
+	}
+	return operation
+}
+
+// openAPISchema returns the schema of a type, the structs are added to the components and referenced
+func openAPISchema(t reflect.Type, schemas map[string]any) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": openAPISchema(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": openAPISchema(t.Elem(), schemas)}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Struct:
+		name := openAPISchemaName(t)
+		if _, ok := schemas[name]; !ok {
+			// Registered before the fields are read, for the recursive types
+			schemas[name] = map[string]any{}
+			schemas[name] = openAPIStruct(t, schemas)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+// openAPIStruct describes the JSON fields of a struct, the fields without omitempty are required
+func openAPIStruct(t reflect.Type, schemas map[string]any) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for idx := 0; idx < t.NumField(); idx++ {
+			field := t.Field(idx)
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if field.Anonymous && name == "" {
+				collect(field.Type)
+				continue
+			}
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			properties[name] = openAPISchema(field.Type, schemas)
+			if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Pointer {
+				required = append(required, name)
+			}
+		}
+	}
+	collect(t)
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// openAPISchemaName names the schema of a struct (ex: models.PlayerSummary -> PlayerSummary, apiPlayerRequest -> PlayerRequest)
+func openAPISchemaName(t reflect.Type) string {
+	name := strings.TrimPrefix(t.Name(), "api")
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// handleSwaggerUI serves a Swagger UI page reading the OpenAPI document
+func (s *Server) handleSwaggerUI(w http.ResponseWriter, r *http.Request) {
+	s.render(w, http.StatusOK, "api_docs.html", "/api/openapi.json")
+}
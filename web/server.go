@@ -0,0 +1,187 @@
+package web
+
+import (
+	"context"
+	"crypto/subtle"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"lp_tracker/models"
+	"lp_tracker/services"
+	"lp_tracker/version"
+)
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+// Server is the public web dashboard, it only serves the profiles of the players who opted in
+// and the calendar of the Clash registrations. The JSON API under /api/v1 requires an API key.
+type Server struct {
+	server         *http.Server
+	profiles       *services.ProfileService
+	clash          *services.ClashService
+	clashFeedToken string // Required as ?token= on the Clash calendar when set
+	templates      *template.Template
+
+	// JSON API
+	players     *services.PlayerService
+	standings   *services.StandingsService
+	apiKeys     *services.APIKeyService
+	jobs        *services.JobService
+	rateLimiter *apiRateLimiter
+	corsOrigins []string // See SetCORSOrigins
+
+	refreshMu  sync.Mutex
+	refreshing map[string]string  // Job ID of the refresh running for a PUUID
+	notifier   RankChangeNotifier // See SetNotifier
+}
+
+// RankChangeNotifier announces the rank changes detected by the API refreshes to the guilds
+type RankChangeNotifier interface {
+	NotifyRankChanges(ctx context.Context, changes []*models.RankChange) error
+}
+
+// NewServer creates the dashboard listening on addr (ex: ":8080")
+func NewServer(addr string, profiles *services.ProfileService, clash *services.ClashService, clashFeedToken string,
+	players *services.PlayerService, standings *services.StandingsService, apiKeys *services.APIKeyService, jobs *services.JobService) *Server {
+	templates := template.Must(template.New("").Funcs(template.FuncMap{
+		"rank":       models.FormatRank,
+		"lower":      strings.ToLower,
+		"date":       func(t time.Time) string { return t.Format("02 Jan 2006") },
+		"percent":    func(value float64) string { return fmt.Sprintf("%.1f%%", value) },
+		"profileURL": models.PublicProfilePath,
+	}).ParseFS(templatesFS, "templates/*.html"))
+
+	s := &Server{
+		profiles:       profiles,
+		clash:          clash,
+		clashFeedToken: clashFeedToken,
+		templates:      templates,
+		players:        players,
+		standings:      standings,
+		apiKeys:        apiKeys,
+		jobs:           jobs,
+		rateLimiter:    newAPIRateLimiter(),
+		refreshing:     make(map[string]string),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /players/{server}/{gameName}/{tagLine}", s.handlePlayer)
+	mux.HandleFunc("GET /persons/{name}", s.handlePerson)
+	mux.HandleFunc("GET /clash.ics", s.handleClashCalendar)
+	mux.HandleFunc("GET /version", handleVersion)
+	s.registerAPI(mux)
+
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Start serves the dashboard in the background
+func (s *Server) Start() {
+	go func() {
+		log.Printf("🌐 Web dashboard listening on %s", s.server.Addr)
+		err := s.server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Web dashboard stopped: %v", err)
+		}
+	}()
+}
+
+// Shutdown stops the dashboard
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+func (s *Server) handlePlayer(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	profile, err := s.profiles.GetPublicProfile(ctx, r.PathValue("server"), r.PathValue("gameName"), r.PathValue("tagLine"))
+	if err != nil {
+		log.Printf("Error building public profile: %v", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	// Players who did not opt in look like unknown players
+	if profile == nil {
+		s.render(w, http.StatusNotFound, "not_found.html", nil)
+		return
+	}
+
+	s.render(w, http.StatusOK, "player.html", struct {
+		*models.PublicProfile
+		Chart lpChart
+	}{profile, newLPChart(profile.LPHistory)})
+}
+
+func (s *Server) handlePerson(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	person, err := s.profiles.GetPublicPerson(ctx, r.PathValue("name"))
+	if err != nil {
+		log.Printf("Error building public person: %v", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	if person == nil {
+		s.render(w, http.StatusNotFound, "not_found.html", nil)
+		return
+	}
+
+	s.render(w, http.StatusOK, "person.html", person)
+}
+
+func (s *Server) handleClashCalendar(w http.ResponseWriter, r *http.Request) {
+	if s.clashFeedToken != "" && subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(s.clashFeedToken)) != 1 {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	registrations, err := s.clash.GetUpcomingRegistrations(ctx)
+	if err != nil {
+		log.Printf("Error fetching clash registrations: %v", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="clash.ics"`)
+	err = writeClashCalendar(w, registrations, time.Now())
+	if err != nil {
+		log.Printf("Error writing clash calendar: %v", err)
+	}
+}
+
+// handleVersion serves the build description as JSON
+func handleVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(version.Get())
+	if err != nil {
+		log.Printf("Error encoding version: %v", err)
+	}
+}
+
+func (s *Server) render(w http.ResponseWriter, status int, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	err := s.templates.ExecuteTemplate(w, name, data)
+	if err != nil {
+		log.Printf("Error rendering %s: %v", name, err)
+	}
+}